@@ -0,0 +1,314 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"math"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/mod/module"
+)
+
+// Component returns a snapshot of the component id.
+func (f *Flo) Component(id uuid.UUID) (ComponentView, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, found := f.Components[id]
+	if !found {
+		return ComponentView{}, false
+	}
+
+	return c.view(), true
+}
+
+// IO returns a snapshot of the io ioID of the component componentID, or of
+// the flo when componentID is the id of the flo.
+func (f *Flo) IO(componentID, ioID uuid.UUID) (IOView, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ios := f.IOs
+	if componentID != f.ID {
+		c, found := f.Components[componentID]
+		if !found {
+			return IOView{}, false
+		}
+		ios = c.IOs
+	}
+	io, found := ios.GetByID(ioID)
+	if !found {
+		return IOView{}, false
+	}
+
+	return viewIOs(IOs{io})[0], true
+}
+
+// Connection returns a snapshot of the connection id.
+func (f *Flo) Connection(id uuid.UUID) (ConnectionView, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	conn, found := f.connectionIndex[id]
+	if !found {
+		return ConnectionView{}, false
+	}
+
+	return conn.view(), true
+}
+
+// SetName renames the flo, hence the generated function.
+func (f *Flo) SetName(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+
+	return f.setFlo(func() { f.Name = name })
+}
+
+// SetLabel sets the label of the flo.
+func (f *Flo) SetLabel(label string) error {
+	if label == "" {
+		return errors.New("missing label")
+	}
+
+	return f.setFlo(func() { f.Label = label })
+}
+
+// SetDescription sets the description of the flo.
+func (f *Flo) SetDescription(description string) error {
+	if description == "" {
+		return errors.New("missing description")
+	}
+
+	return f.setFlo(func() { f.Description = description })
+}
+
+// SetPkgName sets the name of the package the flo is rendered in.
+func (f *Flo) SetPkgName(name string) error {
+	if !token.IsIdentifier(name) || name == "_" {
+		return fmt.Errorf("invalid package name %q", name)
+	}
+
+	return f.setFlo(func() { f.PkgName = name })
+}
+
+// SetPkgDescription sets the package doc of the rendered file. An empty
+// description renders none.
+func (f *Flo) SetPkgDescription(description string) error {
+	if !utf8.ValidString(description) {
+		return errors.New("package description is not valid UTF-8")
+	}
+
+	return f.setFlo(func() { f.PkgDescription = description })
+}
+
+func (f *Flo) setFlo(set func()) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	set()
+	f.markAllDirty()
+
+	return nil
+}
+
+// SetComponentLabel sets the label of the component id, overriding the one
+// of its definition if any.
+func (f *Flo) SetComponentLabel(id uuid.UUID, label string) error {
+	return f.setComponent(id, func(c *Component) {
+		c.Label = label
+		c.override(definitionLabel)
+	})
+}
+
+// SetComponentDescription sets the description of the component id,
+// commented above its call, overriding the one of its definition if any.
+func (f *Flo) SetComponentDescription(id uuid.UUID, description string) error {
+	return f.setComponent(id, func(c *Component) {
+		c.Description = description
+		c.override(definitionDescription)
+	})
+}
+
+// SetComponentName sets the name of the function, or constant, the component
+// id refers to. Sub-flos are named after their flo, see SetName.
+func (f *Flo) SetComponentName(id uuid.UUID, name string) error {
+	if !token.IsIdentifier(name) || name == "_" {
+		return fmt.Errorf("invalid component name %q", name)
+	}
+
+	return f.updateComponent(id, func(c *Component) error {
+		if c.Kind == ComponentKindFlo {
+			return fmt.Errorf("component id %q runs a sub-flo named after its flo", id)
+		}
+		c.Name = name
+
+		return nil
+	})
+}
+
+// SetComponentPkgPath sets the import path of the package of the component
+// id. An empty path refers to the package the flo is rendered in.
+func (f *Flo) SetComponentPkgPath(id uuid.UUID, pkgPath string) error {
+	if pkgPath != "" {
+		if err := module.CheckImportPath(pkgPath); err != nil {
+			return fmt.Errorf("invalid package path: %v", err)
+		}
+	}
+
+	return f.updateComponent(id, func(c *Component) error {
+		if c.Kind == ComponentKindFlo {
+			return fmt.Errorf("component id %q runs a sub-flo, see SetPkgName", id)
+		}
+		c.PkgPath = pkgPath
+
+		return nil
+	})
+}
+
+// SetIOName renames the io ioID of the component componentID, or of the flo
+// when componentID is the id of the flo, along with the in ios it feeds.
+// Renaming an out io of the flo sets the name of its result. In ios of
+// components are named after the io feeding them so they cannot be renamed.
+func (f *Flo) SetIOName(componentID, ioID uuid.UUID, name string) error {
+	if componentID == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+	if ioID == uuid.Nil {
+		return errors.New("invalid io id")
+	}
+	if !token.IsIdentifier(name) || name == "_" {
+		return fmt.Errorf("invalid io name %q", name)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	io, found := f.componentIO(componentID, ioID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on component id %q", ioID, componentID)
+	}
+
+	if componentID == f.ID && io.Type == ComponentIOTypeOUT {
+		for _, out := range f.IOs {
+			if out != io && out.Type == ComponentIOTypeOUT && out.ResultName == name {
+				return fmt.Errorf("result name %q is already taken", name)
+			}
+		}
+		io.ResultName = name
+		f.markAllDirty()
+
+		return nil
+	}
+	if componentID != f.ID && io.Type == ComponentIOTypeIN {
+		return fmt.Errorf("in io id %q is named after the io feeding it", ioID)
+	}
+	if name == io.Name {
+		return nil
+	}
+	taken := f.varNames()
+	for _, out := range f.IOs {
+		if out.Type == ComponentIOTypeOUT {
+			taken[out.ResultName] = struct{}{}
+		}
+	}
+	if _, found := taken[name]; found {
+		return fmt.Errorf("io name %q is already taken", name)
+	}
+
+	io.Name = name
+	for _, conn := range io.Connections {
+		if in, found := f.componentIO(conn.InComponentID, conn.InComponentIOID); found && !in.Multi {
+			in.Name = name
+		}
+	}
+	if f.ioNames != nil {
+		f.ioNames[name] = struct{}{}
+	}
+	f.markAllDirty()
+
+	return nil
+}
+
+// SetEffects sets the side effects of the component id, overriding the
+// inferred ones and the ones of its definition if any.
+func (f *Flo) SetEffects(id uuid.UUID, effects Effects) error {
+	if effects.Has(EffectPure) && effects != EffectPure {
+		return fmt.Errorf("pure effects cannot be combined with %s", effects&^EffectPure)
+	}
+	if effects > EffectPure|EffectReadsState|EffectWritesState|EffectNetwork|EffectFilesystem {
+		return fmt.Errorf("unknown effects %d", effects)
+	}
+
+	return f.setComponent(id, func(c *Component) {
+		c.Effects = effects
+		c.override(definitionEffects)
+	})
+}
+
+// SetAllowReorder lets the writer component id be reordered relative to the
+// other writers, or not.
+func (f *Flo) SetAllowReorder(id uuid.UUID, allow bool) error {
+	return f.setComponent(id, func(c *Component) {
+		c.AllowReorder = allow
+	})
+}
+
+// SetPosition sets where editors draw the component id, or the flo io id.
+func (f *Flo) SetPosition(id uuid.UUID, p Position) error {
+	if id == uuid.Nil {
+		return errors.New("invalid id")
+	}
+	if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+		return fmt.Errorf("invalid position %v", p)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Positions are not rendered.
+	if io, found := f.IOs.GetByID(id); found {
+		io.Position = p
+		return nil
+	}
+	c, found := f.Components[id]
+	if !found {
+		return fmt.Errorf("no component or io id %q found in flo", id)
+	}
+	c.Position = p
+
+	return nil
+}
+
+func (f *Flo) setComponent(id uuid.UUID, set func(c *Component)) error {
+	return f.updateComponent(id, func(c *Component) error {
+		set(c)
+		return nil
+	})
+}
+
+// updateComponent is setComponent for changes that can be refused.
+func (f *Flo) updateComponent(id uuid.UUID, update func(c *Component) error) error {
+	if id == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	c, found := f.Components[id]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", id)
+	}
+	if err := update(c); err != nil {
+		return err
+	}
+	f.markDirty(id)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessors(t *testing.T) {
+	f := newTestFlo(t)
+
+	var c *flo.Component
+	for _, comp := range f.Components {
+		if comp.Name == "CompA" {
+			c = comp
+		}
+	}
+	require.NotNil(t, c)
+
+	t.Run("Getters", func(t *testing.T) {
+		v, found := f.Component(c.ID)
+		require.True(t, found)
+		require.Equal(t, "CompA", v.Name)
+		_, found = f.Component(uuid.New())
+		require.False(t, found)
+
+		io, found := f.IO(c.ID, c.IOs[1].ID)
+		require.True(t, found)
+		require.Equal(t, c.IOs[1].Name, io.Name)
+		io, found = f.IO(f.ID, f.IOs[0].ID)
+		require.True(t, found)
+		require.Equal(t, f.IOs[0].Name, io.Name)
+		_, found = f.IO(c.ID, f.IOs[0].ID)
+		require.False(t, found)
+
+		connID := c.IOs[1].Connections[0].ID
+		conn, found := f.Connection(connID)
+		require.True(t, found)
+		require.Equal(t, c.ID, conn.InComponentID)
+	})
+
+	t.Run("Flo setters", func(t *testing.T) {
+		require.ErrorContains(t, f.SetName(""), "missing name")
+		require.NoError(t, f.SetName("Renamed"))
+		require.NoError(t, f.SetLabel("Renamed Label"))
+		require.NoError(t, f.SetDescription("Renamed Description"))
+		require.ErrorContains(t, f.SetLabel(""), "missing label")
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "func Renamed(")
+	})
+
+	t.Run("Component setters", func(t *testing.T) {
+		require.NoError(t, f.SetComponentLabel(c.ID, "Label"))
+		require.NoError(t, f.SetComponentDescription(c.ID, "Description"))
+		require.NoError(t, f.SetAllowReorder(c.ID, true))
+		require.NoError(t, f.SetEffects(c.ID, flo.EffectReadsState|flo.EffectNetwork))
+		require.NoError(t, f.SetPosition(c.ID, flo.Position{X: 1, Y: 2}))
+		require.NoError(t, f.SetPosition(f.IOs[0].ID, flo.Position{X: 3}))
+
+		v, _ := f.Component(c.ID)
+		require.Equal(t, "Label", v.Label)
+		require.Equal(t, "Description", v.Description)
+		require.True(t, v.AllowReorder)
+		require.Equal(t, flo.EffectReadsState|flo.EffectNetwork, v.Effects)
+		require.Equal(t, flo.Position{X: 1, Y: 2}, v.Position)
+		io, _ := f.IO(f.ID, f.IOs[0].ID)
+		require.Equal(t, flo.Position{X: 3}, io.Position)
+
+		require.ErrorContains(t, f.SetEffects(c.ID, flo.EffectPure|flo.EffectNetwork), "cannot be combined")
+		require.ErrorContains(t, f.SetPosition(c.ID, flo.Position{X: math.NaN()}), "invalid position")
+		require.ErrorContains(t, f.SetComponentLabel(uuid.New(), "Label"), "no component id")
+		require.ErrorContains(t, f.SetPosition(uuid.New(), flo.Position{}), "no component or io id")
+		require.NoError(t, f.Invariants())
+	})
+	t.Run("Names", func(t *testing.T) {
+		f := newTestFlo(t)
+		compA := componentNamed(f, "CompA")
+
+		require.NoError(t, f.SetPkgName("renamed"))
+		require.NoError(t, f.SetPkgDescription("Renamed package."))
+		require.NoError(t, f.SetComponentName(compA.ID, "CompZ"))
+		require.NoError(t, f.SetComponentPkgPath(compA.ID, "githab.com/testuf/terz"))
+		require.NoError(t, f.SetIOName(f.ID, f.IOs[1].ID, "amount"))
+		require.NoError(t, f.SetIOName(f.ID, f.IOs[3].ID, "total"))
+
+		in, _ := f.IO(compA.ID, compA.IOs[1].ID)
+		require.Equal(t, "amount", in.Name)
+		out, _ := f.IO(f.ID, f.IOs[3].ID)
+		require.Equal(t, "total", out.ResultName)
+
+		code := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), code))
+		require.Contains(t, code.String(), "package renamed")
+		require.Contains(t, code.String(), `terz "githab.com/testuf/terz"`)
+		require.Contains(t, code.String(), "terz.CompZ(")
+		require.Contains(t, code.String(), "amount int")
+
+		require.ErrorContains(t, f.SetPkgName("1pkg"), "invalid package name")
+		require.ErrorContains(t, f.SetPkgDescription("\xff"), "not valid UTF-8")
+		require.ErrorContains(t, f.SetComponentName(compA.ID, "Comp Z"), "invalid component name")
+		require.ErrorContains(t, f.SetComponentName(uuid.New(), "CompZ"), "no component id")
+		require.ErrorContains(t, f.SetComponentPkgPath(compA.ID, "githab.com/bad path"), "invalid package path")
+		require.ErrorContains(t, f.SetIOName(f.ID, f.IOs[1].ID, "_"), "invalid io name")
+		require.ErrorContains(t, f.SetIOName(f.ID, f.IOs[1].ID, "unused"), "already taken")
+		require.ErrorContains(t, f.SetIOName(f.ID, f.IOs[4].ID, "total"), "already taken")
+		require.ErrorContains(t, f.SetIOName(compA.ID, compA.IOs[1].ID, "other"), "named after the io feeding it")
+		require.ErrorContains(t, f.SetIOName(compA.ID, uuid.New(), "other"), "no component io id")
+		require.NoError(t, f.Invariants())
+	})
+}
@@ -0,0 +1,95 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Bind registers the fields of the struct pointed to by v tagged with
+// `flo:"name"`, so that services can be wired in one call:
+//
+//	type Services struct {
+//		Hash  func([]byte) string `flo:"Hash"`
+//		Users *Users              `flo:"Users"`
+//	}
+//
+// A function field is registered as name. Any other field has each of its
+// exported methods registered as name.Method, e.g. "Users.Get".
+// They are registered under the package path of the struct unless the tag
+// sets another one, e.g. `flo:"Hash,pkg=example.com/hash"`.
+func (r *ComponentRegistry) Bind(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("value of type %T is not a pointer to a struct", v)
+	}
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag, found := field.Tag.Lookup("flo")
+		if !found || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("field %s is not exported", field.Name)
+		}
+
+		name, pkgPath, err := parseFloTag(tag)
+		if err != nil {
+			return fmt.Errorf("invalid tag of field %s: %v", field.Name, err)
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if pkgPath == "" {
+			pkgPath = rv.Type().PkgPath()
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Func {
+			if fv.IsNil() {
+				return fmt.Errorf("field %s is nil", field.Name)
+			}
+			if err := r.Register(pkgPath, name, fv.Interface()); err != nil {
+				return fmt.Errorf("cannot register field %s: %v", field.Name, err)
+			}
+			continue
+		}
+
+		if fv.NumMethod() == 0 {
+			return fmt.Errorf("field %s is neither a function nor has exported methods", field.Name)
+		}
+		for j := 0; j < fv.NumMethod(); j++ {
+			method := fv.Type().Method(j).Name
+			if err := r.Register(pkgPath, name+"."+method, fv.Method(j).Interface()); err != nil {
+				return fmt.Errorf("cannot register method %s of field %s: %v", method, field.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseFloTag splits a tag like "name,pkg=path".
+func parseFloTag(tag string) (name, pkgPath string, err error) {
+	name, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "pkg":
+			if value == "" {
+				return "", "", errors.New("missing pkg path")
+			}
+			pkgPath = value
+		default:
+			return "", "", fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	return name, pkgPath, nil
+}
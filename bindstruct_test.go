@@ -0,0 +1,83 @@
+package flo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type users struct {
+	prefix string
+}
+
+func (u *users) Get(id string) string {
+	return u.prefix + id
+}
+
+func (u *users) Count() int {
+	return 42
+}
+
+type services struct {
+	Upper  func(string) string `flo:"Upper"`
+	Lower  func(string) string `flo:",pkg=example.com/text"`
+	Users  *users              `flo:"Users"`
+	Ignore func()              `flo:"-"`
+	Plain  int
+}
+
+func TestRegistryBind(t *testing.T) {
+	registry := flo.NewComponentRegistry()
+	require.NoError(t, registry.Bind(&services{
+		Upper: strings.ToUpper,
+		Lower: strings.ToLower,
+		Users: &users{prefix: "user-"},
+	}))
+
+	const pkgPath = "github.com/mgjules/flo_test"
+
+	fn, err := registry.Resolve(pkgPath, "Upper")
+	require.NoError(t, err)
+	require.Equal(t, "A", fn.(func(string) string)("a"))
+
+	fn, err = registry.Resolve("example.com/text", "Lower")
+	require.NoError(t, err)
+	require.Equal(t, "a", fn.(func(string) string)("A"))
+
+	fn, err = registry.Resolve(pkgPath, "Users.Get")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", fn.(func(string) string)("1"))
+
+	_, err = registry.Resolve(pkgPath, "Users.Count")
+	require.NoError(t, err)
+
+	_, err = registry.Resolve(pkgPath, "Ignore")
+	require.ErrorContains(t, err, "is not registered")
+
+	t.Run("Bound components", func(t *testing.T) {
+		c, err := flo.NewHeadlessComponent("Users.Get", pkgPath, "Get User", "", "func(string) string", nil)
+		require.NoError(t, err)
+
+		f, err := flo.NewFlo("TestBind", "Test Bind", "Test Bind Description", "flo", "Test Package")
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+
+		require.NoError(t, registry.BindComponents(f))
+		require.True(t, c.IsBound())
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		require.ErrorContains(t, registry.Bind(services{}), "is not a pointer to a struct")
+		require.ErrorContains(t, flo.NewComponentRegistry().Bind(&services{}), "field Upper is nil")
+		require.ErrorContains(t, flo.NewComponentRegistry().Bind(&struct {
+			Fn func() `flo:"Fn,nope"`
+		}{Fn: func() {}}), `unknown option "nope"`)
+		require.ErrorContains(t, registry.Bind(&services{
+			Upper: strings.ToUpper,
+			Lower: strings.ToLower,
+			Users: &users{},
+		}), "already registered")
+	})
+}
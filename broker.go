@@ -0,0 +1,93 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mgjules/flo/flobroker"
+)
+
+// flobrokerPkg holds the functions the broker components render calls to.
+const flobrokerPkg = "github.com/mgjules/flo/flobroker"
+
+var brokerRType = reflect.TypeFor[flobroker.Broker]()
+
+// NewPublishComponent creates a component publishing messages of type t to
+// topic through an injected broker, i.e.
+//
+//	func(ctx context.Context, broker flobroker.Broker, topic string, msg t) error
+func NewPublishComponent(topic string, t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newBrokerComponent("Publish", topic, label, description, t)
+}
+
+// NewConsumeComponent creates a component consuming the next message of
+// type t of topic through an injected broker, i.e.
+//
+//	func(ctx context.Context, broker flobroker.Broker, topic string) (t, error)
+func NewConsumeComponent(topic string, t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newBrokerComponent("Consume", topic, label, description, t)
+}
+
+func newBrokerComponent(name, topic, label, description string, t reflect.Type) (*Component, error) {
+	if topic == "" {
+		return nil, errors.New("missing topic")
+	}
+
+	fn, err := brokerFunc(name, []reflect.Type{t})
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewComponent(name, flobrokerPkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = []reflect.Type{t}
+	c.IOs[2].Literal = reflect.ValueOf(topic)
+
+	return c, nil
+}
+
+// brokerFunc rebuilds the function backing a broker component.
+func brokerFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	if len(typeArgs) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s expects 1 type argument but got %d", name, len(typeArgs))
+	}
+
+	t := typeArgs[0]
+	switch name {
+	case "Publish":
+		return makeFunc([]reflect.Type{contextRType, brokerRType, stringRType, t}, []reflect.Type{errorRType},
+			func(args []reflect.Value) []reflect.Value {
+				err := flobroker.PublishValue(argContext(args[0]), argBroker(args[1]), args[2].String(), args[3].Interface())
+				return []reflect.Value{errorValue(err)}
+			}), nil
+	case "Consume":
+		return makeFunc([]reflect.Type{contextRType, brokerRType, stringRType}, []reflect.Type{t, errorRType},
+			func(args []reflect.Value) []reflect.Value {
+				v := reflect.New(t)
+				if err := flobroker.ConsumeTo(argContext(args[0]), argBroker(args[1]), args[2].String(), v.Interface()); err != nil {
+					return []reflect.Value{reflect.Zero(t), errorValue(err)}
+				}
+				return []reflect.Value{v.Elem(), errorValue(nil)}
+			}), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown broker component %q", name)
+	}
+}
+
+func argBroker(v reflect.Value) flobroker.Broker {
+	if b, ok := v.Interface().(flobroker.Broker); ok {
+		return b
+	}
+
+	return flobroker.Default
+}
@@ -0,0 +1,77 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flobroker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerComponents(t *testing.T) {
+	t.Run("Invalid components", func(t *testing.T) {
+		_, err := flo.NewPublishComponent("", reflect.TypeFor[string](), "Publish", "Publish")
+		require.ErrorContains(t, err, "missing topic")
+
+		_, err = flo.NewConsumeComponent("orders", nil, "Consume", "Consume")
+		require.ErrorContains(t, err, "missing type")
+	})
+
+	consume, err := flo.NewConsumeComponent("orders", reflect.TypeFor[int](), "Consume", "Consume an order")
+	require.NoError(t, err)
+	require.Equal(t, flo.EffectNetwork, flo.InferEffects(consume)&flo.EffectNetwork)
+
+	publish, err := flo.NewPublishComponent("shipments", reflect.TypeFor[int](), "Publish", "Publish a shipment")
+	require.NoError(t, err)
+
+	t.Run("Call", func(t *testing.T) {
+		b := flobroker.NewMemory()
+		require.NoError(t, flobroker.Publish(context.Background(), b, "orders", 7))
+
+		args := []reflect.Value{reflect.ValueOf(context.Background()), reflect.ValueOf(flobroker.Broker(b))}
+		res := consume.Value.Call(append(args, consume.IOs[2].Literal))
+		require.True(t, res[1].IsNil())
+		require.Equal(t, 7, res[0].Interface())
+
+		res = publish.Value.Call(append(args, publish.IOs[2].Literal, res[0]))
+		require.True(t, res[0].IsNil())
+
+		shipment, err := flobroker.Consume[int](context.Background(), b, "shipments")
+		require.NoError(t, err)
+		require.Equal(t, 7, shipment)
+	})
+
+	f, err := flo.NewFlo("TestBroker", "Test Broker", "Test Broker Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, broker, rErr := flo.In[context.Context]("ctx"), flo.In[flobroker.Broker]("broker"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, broker, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+	for _, c := range []*flo.Component{consume, publish} {
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.ConnectComponent(f.ID, ctx.ID, c.ID, c.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(f.ID, broker.ID, c.ID, c.IOs[1].ID))
+	}
+	require.NoError(t, f.ConnectComponent(consume.ID, consume.IOs[3].ID, publish.ID, publish.IOs[3].ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `:= flobroker.Consume[int](ctx, broker, "orders")`)
+		require.Contains(t, out.String(), `flobroker.Publish[int](ctx, broker, "shipments", io`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.True(t, decoded.Components[consume.ID].IsBound())
+		require.True(t, decoded.Components[publish.ID].IsBound())
+	})
+}
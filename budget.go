@@ -0,0 +1,146 @@
+package flo
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flobudget"
+	"github.com/samber/lo"
+)
+
+const flobudgetPkg = "github.com/mgjules/flo/flobudget"
+
+// WithDeadlineBudget gives the generated function at most d, divided
+// between the components taking a context in proportion to the latency of
+// their cost, see SetCost. Each one is given a context whose deadline is its
+// share of the time left, through flobudget.
+func WithDeadlineBudget(d time.Duration) RenderOption {
+	return func(o *renderOptions) {
+		o.budget = d
+	}
+}
+
+// WithBudget gives the execution at most d, divided between the components
+// taking a context the same way as WithDeadlineBudget does.
+func WithBudget(d time.Duration) ExecuteOption {
+	return func(o *executeOptions) {
+		o.budget = d
+	}
+}
+
+// budgetSteps returns the components given a share of a deadline budget,
+// those called with a context, by step, along with their weights.
+func (f *Flo) budgetSteps() (map[uuid.UUID]int, []time.Duration) {
+	steps := make(map[uuid.UUID]int)
+	var weights []time.Duration
+	for _, c := range f.orderedComponents() {
+		if budgetContextIn(c) < 0 {
+			continue
+		}
+		steps[c.ID] = len(weights)
+		weights = append(weights, c.Cost.Latency)
+	}
+
+	return steps, weights
+}
+
+// budgetContextIn returns the index, among the in ios of c, of the context
+// given a share of a deadline budget, -1 when c takes none.
+func budgetContextIn(c *Component) int {
+	// Sources keep running after their call so they get no deadline.
+	if (c.Kind != ComponentKindFunc && c.Kind != ComponentKindFlo) || c.IsPlaceholder() {
+		return -1
+	}
+
+	ins, _ := c.IOs.SeparateINsOUTs()
+	_, i, _ := lo.FindIndexOf(ins, func(in *ComponentIO) bool {
+		return in.RType == contextRType && !in.IsSignal
+	})
+
+	return i
+}
+
+// renderBudget declares the budget of the flo, when rendered with one and
+// some components take a context.
+func (f *Flo) renderBudget(ctx context.Context, g *jen.Group) context.Context {
+	o := renderOptionsFrom(ctx)
+	if o.budget <= 0 {
+		return ctx
+	}
+
+	steps, weights := f.budgetSteps()
+	if len(steps) == 0 {
+		return ctx
+	}
+	o.budgetSteps = steps
+	o.budgetVar = uniqueName("budget", f.varNames())
+
+	g.Id(o.budgetVar).Op(":=").Qual(flobudgetPkg, "New").CallFunc(func(g *jen.Group) {
+		g.Add(f.paramContext())
+		g.Add(typeCode(durationRType).Call(jen.Lit(int(o.budget))))
+		for _, w := range weights {
+			g.Add(typeCode(durationRType).Call(jen.Lit(int(w))))
+		}
+	}).Line()
+
+	return withRenderOptions(ctx, o)
+}
+
+// budgetStepCode derives the context of c from the budget of the flo,
+// replacing it in args. It returns nil when c gets no share of a budget.
+func budgetStepCode(c *Component, o renderOptions, args []jen.Code) jen.Code {
+	step, found := o.budgetSteps[c.ID]
+	if !found {
+		return nil
+	}
+	i := budgetContextIn(c)
+
+	data := sha1.Sum([]byte(fmt.Sprintf("budget-%s-%s-%d", c.PkgPath, c.Name, step)))
+	stepCtx := lo.CamelCase(fmt.Sprintf("ctx%x", data))
+	cancel := lo.CamelCase(fmt.Sprintf("cancel%x", data))
+	parent := args[i]
+	args[i] = jen.Id(stepCtx)
+
+	return jen.
+		List(jen.Id(stepCtx), jen.Id(cancel)).
+		Op(":=").
+		Id(o.budgetVar).Dot("Step").Call(parent, jen.Lit(step)).
+		Line().
+		Defer().Id(cancel).Call().
+		Line()
+}
+
+// budgetStep derives the context of c, in args, from the budget of the
+// execution. The returned function releases it.
+func (e *execution) budgetStep(c *Component, args []reflect.Value) context.CancelFunc {
+	step, found := e.budgetSteps[c.ID]
+	if !found {
+		return func() {}
+	}
+
+	i := budgetContextIn(c)
+	parent, ok := args[i].Interface().(context.Context)
+	if !ok {
+		parent = e.ctx.Interface().(context.Context)
+	}
+	stepCtx, cancel := e.budget.Step(parent, step)
+	args[i] = reflect.ValueOf(&stepCtx).Elem()
+
+	return cancel
+}
+
+// newBudget sets up the budget of the execution, when given one.
+func (e *execution) newBudget(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	steps, weights := e.f.budgetSteps()
+	e.budget = flobudget.New(ctx, d, weights...)
+	e.budgetSteps = steps
+}
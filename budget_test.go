@@ -0,0 +1,93 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	f, err := flo.NewFlo("Fetch", "Fetch", "Fetch Description", "flo", "Fetch Package")
+	require.NoError(t, err)
+
+	pCtx, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pCtx))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[time.Duration](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	// Reports the time left before the deadline of its context.
+	timeLeft := func(ctx context.Context) time.Duration {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return -1
+		}
+		return time.Until(deadline)
+	}
+
+	fast, err := flo.NewComponent("Fast", "githab.com/testuf/tera", "Fast", "Fast Description", timeLeft)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(fast))
+	require.NoError(t, f.SetCost(fast.ID, flo.Cost{Latency: time.Millisecond}))
+	require.NoError(t, f.ConnectComponent(f.ID, pCtx.ID, fast.ID, fast.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(fast.ID, fast.IOs[1].ID, f.ID, rOut.ID))
+
+	// Fed the context of the flo implicitly.
+	slow, err := flo.NewComponent("Slow", "githab.com/testuf/tera", "Slow", "Slow Description", timeLeft)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(slow))
+	require.NoError(t, f.SetCost(slow.ID, flo.Cost{Latency: 3 * time.Millisecond}))
+
+	t.Run("Execute", func(t *testing.T) {
+		outputs, err := f.Execute(context.Background(), nil, flo.WithBudget(time.Second))
+		require.NoError(t, err)
+		require.InDelta(t, 250*time.Millisecond, outputs["out"], float64(50*time.Millisecond))
+
+		outputs, err = f.Execute(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(-1), outputs["out"])
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithDeadlineBudget(time.Second)))
+		require.Contains(t, out.String(),
+			"budget := flobudget.New(ctx, time.Duration(1000000000), time.Duration(1000000), time.Duration(3000000))")
+		require.Contains(t, out.String(), ":= budget.Step(ctx, 0)")
+		require.Contains(t, out.String(), ":= budget.Step(ctx, 1)")
+		require.NotContains(t, out.String(), "tera.Fast(ctx)")
+	})
+
+	t.Run("Compiles", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("runs the go tool")
+		}
+
+		f, err := flo.NewFlo("Cause", "Cause", "Cause Description", "flo", "Cause Package")
+		require.NoError(t, err)
+
+		pCtx, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pCtx))
+
+		rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rErr))
+
+		cause, err := flo.NewComponent("Cause", "context", "Cause", "Cause Description", context.Cause)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(cause))
+		require.NoError(t, f.ConnectComponent(f.ID, pCtx.ID, cause.ID, cause.IOs[0].ID))
+
+		res, err := f.CompileCheck(context.Background(), flo.WithDeadlineBudget(time.Second), flo.WithParallel())
+		require.NoError(t, err)
+		require.True(t, res.OK(), res.Build.Output+res.Vet.Output)
+	})
+}
@@ -0,0 +1,205 @@
+package flo
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Files of a bundle.
+const (
+	bundleManifest = "manifest.json"
+	bundleSnapshot = "flo.bin"
+	bundleSource   = "flo.go"
+	bundleDocs     = "README.md"
+)
+
+// bundleVersion is bumped whenever the layout of bundles changes.
+const bundleVersion = 1
+
+type bundleManifestData struct {
+	Version     int
+	Name        string
+	Fingerprint string
+	Failure     *bundleFailure `json:",omitempty"` // Set in debug bundles.
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// ExportBundle writes a self-contained zip of the flo: its snapshot, with
+// the layout of the components, its rendered source and its docs, to share
+// it between repositories.
+// All the named types used by the flo must be known to types.
+// A nil types uses DefaultTypeRegistry.
+func (f *Flo) ExportBundle(w io.Writer, types *TypeRegistry) error {
+	return f.exportBundle(w, types, nil)
+}
+
+// exportBundle writes the bundle of the flo along with extra files. The
+// failure of debug bundles is recorded in their manifest.
+func (f *Flo) exportBundle(w io.Writer, types *TypeRegistry, failure *bundleFailure, extra ...bundleFile) error {
+	snapshot := &bytes.Buffer{}
+	if err := f.EncodeBinary(snapshot, types); err != nil {
+		return err
+	}
+
+	source := &bytes.Buffer{}
+	if err := f.Render(context.Background(), source); err != nil {
+		return fmt.Errorf("cannot render flo: %v", err)
+	}
+
+	manifest, err := json.MarshalIndent(bundleManifestData{
+		Version:     bundleVersion,
+		Name:        f.Name,
+		Fingerprint: f.Fingerprint(),
+		Failure:     failure,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range append([]bundleFile{
+		{bundleManifest, manifest},
+		{bundleSnapshot, snapshot.Bytes()},
+		{bundleSource, source.Bytes()},
+		{bundleDocs, f.docs()},
+	}, extra...) {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ImportBundle reads a bundle written by ExportBundle and checks that the
+// flo is the one that was exported.
+// Component and transform functions are rebound using resolve, as in
+// DecodeBinary.
+func ImportBundle(r io.Reader, types *TypeRegistry, resolve ResolveFunc) (*Flo, error) {
+	f, _, _, err := importBundle(r, types, resolve)
+	return f, err
+}
+
+// importBundle reads a bundle, returning its flo, files and manifest.
+func importBundle(r io.Reader, types *TypeRegistry, resolve ResolveFunc) (*Flo, *zip.Reader, bundleManifestData, error) {
+	var manifest bundleManifestData
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, manifest, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, nil, manifest, fmt.Errorf("invalid bundle: %v", err)
+	}
+
+	if err := readBundleFile(zr, bundleManifest, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&manifest)
+	}); err != nil {
+		return nil, nil, manifest, err
+	}
+	if manifest.Version != bundleVersion {
+		return nil, nil, manifest, fmt.Errorf("unsupported bundle version %d", manifest.Version)
+	}
+
+	var f *Flo
+	if err := readBundleFile(zr, bundleSnapshot, func(r io.Reader) error {
+		f, err = DecodeBinary(r, types, resolve)
+		return err
+	}); err != nil {
+		return nil, nil, manifest, err
+	}
+
+	if fp := f.Fingerprint(); fp != manifest.Fingerprint {
+		return nil, nil, manifest, fmt.Errorf("bundle of flo %q is corrupted: fingerprint %s, expected %s", f.Name, fp, manifest.Fingerprint)
+	}
+
+	return f, zr, manifest, nil
+}
+
+func readBundleFile(zr *zip.Reader, name string, read func(r io.Reader) error) error {
+	file, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("invalid bundle: missing %s", name)
+	}
+	defer file.Close()
+
+	if err := read(file); err != nil {
+		return fmt.Errorf("invalid bundle %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// docs describes the flo in markdown.
+func (f *Flo) docs() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.syncDefinitions()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", f.Label)
+	if f.Description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", f.Description)
+	}
+	fmt.Fprintf(&sb, "`%s.%s`\n", f.PkgName, f.Name)
+
+	ins, outs := f.IOs.SeparateINsOUTs()
+	for _, section := range []struct {
+		title string
+		ios   IOs
+	}{
+		{"Params", ins},
+		{"Results", outs},
+	} {
+		if len(section.ios) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\n", section.title)
+		for _, io := range section.ios {
+			if io.Type == ComponentIOTypeIN {
+				fmt.Fprintf(&sb, "- `%s` `%s`%s\n", io.Name, TypeName(io.RType), ioDocSuffix(io))
+				continue
+			}
+			fmt.Fprintf(&sb, "- `%s`%s\n", TypeName(io.RType), ioDocSuffix(io))
+		}
+	}
+
+	if components := f.orderedComponents(); len(components) > 0 {
+		sb.WriteString("\n## Components\n\n")
+		for _, c := range components {
+			fmt.Fprintf(&sb, "- **%s** `%s.%s`", c.Label, c.PkgPath, c.Name)
+			if c.Description != "" {
+				fmt.Fprintf(&sb, ": %s", c.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Only when some costs are known.
+	if e, err := f.estimateCost(); err == nil && len(e.Unknown) < len(f.Components) {
+		sb.WriteString("\n## Cost\n\nEstimated per run:\n\n")
+		fmt.Fprintf(&sb, "- Latency: %s, %s along the critical path\n", e.Total.Latency, e.CriticalPath.Latency)
+		fmt.Fprintf(&sb, "- Price: %g, %g along the critical path\n", e.Total.Price, e.CriticalPath.Price)
+		if len(e.Unknown) > 0 {
+			fmt.Fprintf(&sb, "- %d of %d components have no known cost\n", len(e.Unknown), len(f.Components))
+		}
+	}
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,69 @@
+package flo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	f := newTestFlo(t)
+	f.Layout(flo.LayoutOptions{})
+
+	want := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), want))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, f.ExportBundle(buf, nil))
+
+	t.Run("Contents", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		files := make(map[string]string)
+		for _, file := range zr.File {
+			r, err := file.Open()
+			require.NoError(t, err)
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			files[file.Name] = string(b)
+		}
+
+		require.Len(t, files, 4)
+		require.Contains(t, files["manifest.json"], f.Fingerprint())
+		require.Equal(t, want.String(), files["flo.go"])
+		require.Contains(t, files["README.md"], "# "+f.Label)
+		require.Contains(t, files["README.md"], "## Components")
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		imported, err := flo.ImportBundle(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.ID, imported.ID)
+		require.Equal(t, f.Fingerprint(), imported.Fingerprint())
+
+		for id, c := range imported.Components {
+			require.Equal(t, f.Components[id].Position, c.Position)
+		}
+
+		got := &bytes.Buffer{}
+		require.NoError(t, imported.Render(context.Background(), got))
+		require.Equal(t, want.String(), got.String())
+	})
+
+	t.Run("Invalid bundles", func(t *testing.T) {
+		_, err := flo.ImportBundle(bytes.NewReader([]byte("nope")), nil, nil)
+		require.ErrorContains(t, err, "invalid bundle")
+
+		empty := &bytes.Buffer{}
+		require.NoError(t, zip.NewWriter(empty).Close())
+		_, err = flo.ImportBundle(empty, nil, nil)
+		require.ErrorContains(t, err, "missing manifest.json")
+	})
+}
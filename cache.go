@@ -0,0 +1,303 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flocache"
+	"github.com/samber/lo"
+)
+
+// flocachePkg holds the functions the cache components render calls to.
+const flocachePkg = "github.com/mgjules/flo/flocache"
+
+var (
+	cacheRType    = reflect.TypeFor[flocache.Cache]()
+	durationRType = reflect.TypeFor[time.Duration]()
+)
+
+// CacheAside caches the results of a component, see NewCacheAsideComponent.
+type CacheAside struct {
+	TTL     time.Duration // Forever when 0.
+	NoError bool          // The cached function returns no error.
+}
+
+// NewCacheGetComponent creates a component getting the value of a key from
+// an injected cache, i.e.
+//
+//	func(ctx context.Context, cache flocache.Cache, key string) (t, bool, error)
+func NewCacheGetComponent(t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newCacheComponent("Get", label, description, t)
+}
+
+// NewCacheSetComponent creates a component setting the value of a key in an
+// injected cache for ttl, forever when 0, i.e.
+//
+//	func(ctx context.Context, cache flocache.Cache, key string, value t, ttl time.Duration) error
+func NewCacheSetComponent(t reflect.Type, ttl time.Duration, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+	if ttl < 0 {
+		return nil, errors.New("ttl cannot be negative")
+	}
+
+	c, err := newCacheComponent("Set", label, description, t)
+	if err != nil {
+		return nil, err
+	}
+	c.IOs[4].Literal = reflect.ValueOf(ttl)
+
+	return c, nil
+}
+
+func newCacheComponent(name, label, description string, t reflect.Type) (*Component, error) {
+	fn, err := cacheFunc(name, []reflect.Type{t})
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewComponent(name, flocachePkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = []reflect.Type{t}
+
+	return c, nil
+}
+
+// cacheFunc rebuilds the function backing a cache component.
+func cacheFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	if len(typeArgs) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s expects 1 type argument but got %d", name, len(typeArgs))
+	}
+
+	t := typeArgs[0]
+	switch name {
+	case "Get":
+		return makeFunc([]reflect.Type{contextRType, cacheRType, stringRType}, []reflect.Type{t, boolRType, errorRType},
+			func(args []reflect.Value) []reflect.Value {
+				v := reflect.New(t)
+				found, err := flocache.GetTo(argContext(args[0]), argCache(args[1]), args[2].String(), v.Interface())
+				if err != nil || !found {
+					return []reflect.Value{reflect.Zero(t), reflect.ValueOf(false), errorValue(err)}
+				}
+				return []reflect.Value{v.Elem(), reflect.ValueOf(true), errorValue(nil)}
+			}), nil
+	case "Set":
+		return makeFunc([]reflect.Type{contextRType, cacheRType, stringRType, t, durationRType}, []reflect.Type{errorRType},
+			func(args []reflect.Value) []reflect.Value {
+				err := flocache.SetValue(argContext(args[0]), argCache(args[1]), args[2].String(), args[3].Interface(), time.Duration(args[4].Int()))
+				return []reflect.Value{errorValue(err)}
+			}), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown cache component %q", name)
+	}
+}
+
+// NewCacheAsideComponent wraps c, a function or a sub-flo which must be
+// pure and return a single value and optionally an error, so that its
+// results are cached for ttl, forever when 0, in an injected cache:
+//
+//	v, err := flocache.Aside(ctx, cache, flocache.Key("pkg.Fn", a, b), ttl, func() (T, error) {
+//		return pkg.Fn(a, b)
+//	})
+//
+// The component takes a context and the cache before the ins of c, and is
+// keyed by the values of the latter but contexts, which must pass
+// flocache.CheckKey.
+func NewCacheAsideComponent(c *Component, ttl time.Duration) (*Component, error) {
+	if c == nil {
+		return nil, errors.New("missing component")
+	}
+	if (c.Kind != ComponentKindFunc && c.Kind != ComponentKindFlo) || c.Cache != nil || c.IsPlaceholder() {
+		return nil, fmt.Errorf("component id %q cannot be cached", c.ID)
+	}
+	if c.Effects.IsKnown() && !c.Effects.IsPure() {
+		return nil, fmt.Errorf("component id %q is not pure", c.ID)
+	}
+	if ttl < 0 {
+		return nil, errors.New("ttl cannot be negative")
+	}
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	aside := &CacheAside{TTL: ttl, NoError: len(outs) == 1}
+	if len(outs) == 0 || len(outs) > 2 || outs[0].IsError || (len(outs) == 2 && !outs[1].IsError) {
+		return nil, fmt.Errorf("component id %q must return a value and optionally an error", c.ID)
+	}
+	for i, in := range ins {
+		if in.RType == contextRType {
+			continue
+		}
+		if err := flocache.CheckKey(in.RType); err != nil {
+			return nil, fmt.Errorf("component id %q cannot be cached: argument %d: %v", c.ID, i+1, err)
+		}
+	}
+
+	cached := &Component{
+		ID:          uuid.New(),
+		Name:        c.Name,
+		PkgPath:     c.PkgPath,
+		Label:       c.Label,
+		Description: c.Description,
+		Kind:        c.Kind,
+		TypeArgs:    c.TypeArgs,
+		Sub:         c.Sub,
+		// The cache is state.
+		Effects: EffectReadsState | EffectWritesState,
+		Cache:   aside,
+	}
+	fnType := reflect.FuncOf(
+		append([]reflect.Type{contextRType, cacheRType}, ioTypes(ins)...),
+		[]reflect.Type{outs[0].RType, errorRType},
+		false,
+	)
+	if err := newComponentIOsFromType(cached, fnType); err != nil {
+		return nil, err
+	}
+
+	if c.IsBound() {
+		if err := cached.bindValue(cacheAsideFunc(cached, c.Value)); err != nil {
+			return nil, err
+		}
+	}
+
+	return cached, nil
+}
+
+// cacheAsideCall calls fn, the callee of c, a cache-aside component, with
+// args.
+func cacheAsideCall(c *Component, fn *jen.Statement, args []jen.Code) *jen.Statement {
+	ins, outs := c.IOs.SeparateINsOUTs()
+	call := fn.Call(args[2:]...)
+
+	keyArgs := []jen.Code{jen.Lit(cacheKeyPrefix(c))}
+	for i, in := range ins[2:] {
+		if in.RType != contextRType {
+			keyArgs = append(keyArgs, args[i+2])
+		}
+	}
+
+	return jen.Qual(flocachePkg, "Aside").Call(
+		args[0], args[1],
+		jen.Qual(flocachePkg, "Key").Call(keyArgs...),
+		typeCode(durationRType).Call(jen.Lit(int(c.Cache.TTL))),
+		jen.Func().Params().Params(typeCode(outs[0].RType), jen.Error()).Block(
+			jen.ReturnFunc(func(g *jen.Group) {
+				g.Add(call)
+				if c.Cache.NoError {
+					g.Nil()
+				}
+			}),
+		),
+	)
+}
+
+// cachedFunc checks fn matches the function cached by c, a cache-aside
+// component, and wraps it with the cache.
+func cachedFunc(c *Component, fn reflect.Value) (reflect.Value, error) {
+	ins, outs := c.IOs.SeparateINsOUTs()
+	want := []reflect.Type{outs[0].RType}
+	if !c.Cache.NoError {
+		want = append(want, errorRType)
+	}
+	if !fn.IsValid() || fn.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("component id %q must be backed by a function", c.ID)
+	}
+	if fnType := reflect.FuncOf(ioTypes(ins[2:]), want, false); fn.Type() != fnType {
+		return reflect.Value{}, fmt.Errorf("function %s does not match cached %s", fn.Type(), fnType)
+	}
+
+	return cacheAsideFunc(c, fn), nil
+}
+
+// cacheAsideFunc backs c, a cache-aside component, with the cached
+// function fn.
+func cacheAsideFunc(c *Component, fn reflect.Value) reflect.Value {
+	ins, outs := c.IOs.SeparateINsOUTs()
+	key := cacheKeyPrefix(c)
+	t := outs[0].RType
+
+	return makeFunc(ioTypes(ins), []reflect.Type{t, errorRType},
+		func(args []reflect.Value) []reflect.Value {
+			keyArgs := make([]any, 0, len(args)-2)
+			for i, arg := range args[2:] {
+				if ins[i+2].RType != contextRType {
+					keyArgs = append(keyArgs, arg.Interface())
+				}
+			}
+
+			v := reflect.New(t)
+			err := flocache.AsideTo(argContext(args[0]), argCache(args[1]), flocache.Key(key, keyArgs...), c.Cache.TTL, v.Interface(),
+				func() (any, error) {
+					res := fn.Call(args[2:])
+					if !c.Cache.NoError && !res[1].IsNil() {
+						return nil, res[1].Interface().(error)
+					}
+					return res[0].Interface(), nil
+				})
+			if err != nil {
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+			return []reflect.Value{v.Elem(), errorValue(nil)}
+		})
+}
+
+// cacheKeyPrefix returns the prefix of the cache keys of c: the
+// package-qualified name of the function it caches, with its type
+// arguments, and for sub-flos the fingerprint of their body, so that keys
+// change along with it.
+func cacheKeyPrefix(c *Component) string {
+	pkg := c.PkgPath
+	if pkg == "" && c.Sub != nil {
+		pkg = c.Sub.PkgName
+	}
+
+	var b strings.Builder
+	if pkg != "" {
+		b.WriteString(pkg + ".")
+	}
+	b.WriteString(c.Name)
+	if len(c.TypeArgs) > 0 {
+		b.WriteString("[" + strings.Join(lo.Map(c.TypeArgs, func(t reflect.Type, _ int) string { return TypeName(t) }), ",") + "]")
+	}
+	if c.Sub != nil {
+		b.WriteString("@" + c.Sub.Fingerprint())
+	}
+
+	return b.String()
+}
+
+func ioTypes(ios IOs) []reflect.Type {
+	types := make([]reflect.Type, 0, len(ios))
+	for _, io := range ios {
+		types = append(types, io.RType)
+	}
+
+	return types
+}
+
+func argContext(v reflect.Value) context.Context {
+	if ctx, ok := v.Interface().(context.Context); ok {
+		return ctx
+	}
+
+	return context.Background()
+}
+
+func argCache(v reflect.Value) flocache.Cache {
+	if c, ok := v.Interface().(flocache.Cache); ok {
+		return c
+	}
+
+	return flocache.Default
+}
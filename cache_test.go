@@ -0,0 +1,199 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flocache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheComponents(t *testing.T) {
+	ctx := reflect.ValueOf(context.Background())
+	cache := reflect.ValueOf(flocache.Cache(flocache.NewMemory()))
+	key := reflect.ValueOf("key")
+
+	get, err := flo.NewCacheGetComponent(reflect.TypeFor[int](), "Get", "Get the count")
+	require.NoError(t, err)
+
+	set, err := flo.NewCacheSetComponent(reflect.TypeFor[int](), time.Minute, "Set", "Set the count")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, set.IOs[4].Literal.Interface())
+
+	res := get.Value.Call([]reflect.Value{ctx, cache, key})
+	require.False(t, res[1].Bool())
+
+	res = set.Value.Call([]reflect.Value{ctx, cache, key, reflect.ValueOf(42), set.IOs[4].Literal})
+	require.True(t, res[0].IsNil())
+
+	res = get.Value.Call([]reflect.Value{ctx, cache, key})
+	require.True(t, res[1].Bool())
+	require.Equal(t, 42, res[0].Interface())
+}
+
+func TestCacheAsideComponent(t *testing.T) {
+	calls := 0
+	square := func(n int) int {
+		calls++
+		return n * n
+	}
+	c, err := flo.NewComponent("Square", "githab.com/testuf/tera", "Square", "Square n", square)
+	require.NoError(t, err)
+
+	t.Run("Invalid components", func(t *testing.T) {
+		impure, err := flo.NewComponent("Square", "githab.com/testuf/tera", "Square", "Square n", square)
+		require.NoError(t, err)
+		impure.Effects = flo.EffectNetwork
+		_, err = flo.NewCacheAsideComponent(impure, 0)
+		require.ErrorContains(t, err, "is not pure")
+
+		noValue, err := flo.NewComponent("CompE", "gitlub.com/testing/teag", "Comp E", "Comp E", compEFn)
+		require.NoError(t, err)
+		_, err = flo.NewCacheAsideComponent(noValue, 0)
+		require.ErrorContains(t, err, "must return a value")
+
+		unkeyable, err := flo.NewComponent("Apply", "githab.com/testuf/tera", "Apply", "Apply fn", func(fn func() int) int { return fn() })
+		require.NoError(t, err)
+		_, err = flo.NewCacheAsideComponent(unkeyable, 0)
+		require.ErrorContains(t, err, "argument 1: func() int cannot be part of a key")
+	})
+
+	aside, err := flo.NewCacheAsideComponent(c, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, aside.IOs, 5)
+
+	t.Run("Call", func(t *testing.T) {
+		args := []reflect.Value{
+			reflect.ValueOf(context.Background()),
+			reflect.ValueOf(flocache.Cache(flocache.NewMemory())),
+			reflect.ValueOf(3),
+		}
+		for range 2 {
+			res := aside.Value.Call(args)
+			require.True(t, res[1].IsNil())
+			require.Equal(t, 9, res[0].Interface())
+		}
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Type arguments", func(t *testing.T) {
+		generic, err := flo.NewComponent("Square", "githab.com/testuf/tera", "Square", "Square n", square)
+		require.NoError(t, err)
+		generic.TypeArgs = []reflect.Type{reflect.TypeFor[int]()}
+		genericAside, err := flo.NewCacheAsideComponent(generic, time.Minute)
+		require.NoError(t, err)
+
+		memory := flocache.Cache(flocache.NewMemory())
+		args := []reflect.Value{reflect.ValueOf(context.Background()), reflect.ValueOf(memory), reflect.ValueOf(4)}
+		before := calls
+		aside.Value.Call(args)
+		genericAside.Value.Call(args)
+		require.Equal(t, before+2, calls)
+	})
+
+	f, err := flo.NewFlo("TestCache", "Test Cache", "Test Cache Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, cache, n := flo.In[context.Context]("ctx"), flo.In[flocache.Cache]("cache"), flo.In[int]("n")
+	result, rErr := flo.Out[int]("result"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, cache, n, result, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+	require.NoError(t, f.AddComponent(aside))
+	for i, in := range []*flo.ComponentIO{ctx, cache, n} {
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, aside.ID, aside.IOs[i].ID))
+	}
+	require.NoError(t, f.ConnectComponent(aside.ID, aside.IOs[3].ID, f.ID, result.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `, err := flocache.Aside(ctx, cache, flocache.Key("githab.com/testuf/tera.Square", n), time.Duration(60000000000), func() (int, error) {
+		return tera.Square(n), nil
+	})
+`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+
+		require.ErrorContains(t, decoded.Components[aside.ID].Bind(func(string) int { return 0 }), "does not match cached")
+		require.NoError(t, decoded.Components[aside.ID].Bind(square))
+	})
+}
+
+func TestCacheAsideSubFlo(t *testing.T) {
+	calls := 0
+	square := func(ctx context.Context, n int) int {
+		calls++
+		return n * n
+	}
+
+	sub, err := flo.NewFlo("Square", "Square", "Square Description", "flo", "")
+	require.NoError(t, err)
+	subCtx, subN, subResult := flo.In[context.Context]("ctx"), flo.In[int]("n"), flo.Out[int]("result")
+	for _, io := range []*flo.ComponentIO{subCtx, subN, subResult} {
+		require.NoError(t, sub.AddIO(io))
+	}
+	c, err := flo.NewComponent("Square", "githab.com/testuf/tera", "Square", "Square n", square)
+	require.NoError(t, err)
+	require.NoError(t, sub.AddComponent(c))
+	require.NoError(t, sub.ConnectComponent(sub.ID, subCtx.ID, c.ID, c.IOs[0].ID))
+	require.NoError(t, sub.ConnectComponent(sub.ID, subN.ID, c.ID, c.IOs[1].ID))
+	require.NoError(t, sub.ConnectComponent(c.ID, c.IOs[2].ID, sub.ID, subResult.ID))
+
+	f, err := flo.NewFlo("TestCache", "Test Cache", "Test Cache Description", "flo", "Test Package")
+	require.NoError(t, err)
+	ctx, cache, n := flo.In[context.Context]("ctx"), flo.In[flocache.Cache]("cache"), flo.In[int]("n")
+	result, rErr := flo.Out[int]("result"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, cache, n, result, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	subC, err := f.AddFlo(sub)
+	require.NoError(t, err)
+	aside, err := flo.NewCacheAsideComponent(subC, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.DeleteComponent(subC.ID))
+	require.NoError(t, f.AddComponent(aside))
+	for i, in := range []*flo.ComponentIO{ctx, cache, ctx, n} {
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, aside.ID, aside.IOs[i].ID))
+	}
+	require.NoError(t, f.ConnectComponent(aside.ID, aside.IOs[4].ID, f.ID, result.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), fmt.Sprintf(`, err := flocache.Aside(ctx, cache, flocache.Key("flo.Square@%s", n), time.Duration(0), func() (int, error) {
+		return Square(ctx, n), nil
+	})
+`, aside.Sub.Fingerprint()))
+	})
+
+	t.Run("Execute", func(t *testing.T) {
+		memory := flocache.NewMemory()
+		for range 2 {
+			outputs, err := f.Execute(context.Background(), map[string]any{"cache": memory, "n": 3})
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{"result": 9}, outputs)
+		}
+		require.Equal(t, 1, calls)
+
+		// Keys change along with the body of the sub-flo.
+		require.NoError(t, aside.Sub.SetComponentDescription(c.ID, "Square n again"))
+		outputs, err := f.Execute(context.Background(), map[string]any{"cache": memory, "n": 3})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 9}, outputs)
+		require.Equal(t, 2, calls)
+	})
+}
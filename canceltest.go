@@ -0,0 +1,261 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// RenderCancellationTests writes a test file checking that the flo returns
+// promptly when its context is canceled while any of its components runs.
+//
+// The file holds a copy of the flo function, rendered with opts, calling
+// stubs instead of the components. For each component, the test blocks its
+// stub until the context is canceled and then expects the function to
+// return within a second, with context.Canceled when the flo returns an
+// error. Components called after the cancellation are logged.
+func (f *Flo) RenderCancellationTests(
+	ctx context.Context,
+	w io.Writer,
+	opts ...RenderOption,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.syncDefinitions()
+	defer f.orderComponents()()
+
+	floINs, floOUTs := f.IOs.SeparateINsOUTs()
+	ctxParam := f.contextParam()
+	if !f.usesContextParam(ctxParam) {
+		return fmt.Errorf("flo %q has no context param to cancel", f.Name)
+	}
+
+	var (
+		fn    = "cancel" + f.Name
+		state = "cancel" + f.Name + "State"
+		point = "cancel" + f.Name + "Point"
+	)
+
+	// Stubs are numbered, and components tested, in the order they are
+	// called.
+	var called []*Component
+	o := newRenderOptions(opts)
+	if err := f.resolvePkgPath(&o); err != nil {
+		return err
+	}
+	o.stubs = make(map[uuid.UUID]string)
+	o.beforeComponent = append(o.beforeComponent, func(_ context.Context, c *Component, _ *jen.Group) error {
+		if c.Kind != ComponentKindJoin && c.Kind != ComponentKindGuard && c.Kind != ComponentKindLiteral {
+			o.stubs[c.ID] = fmt.Sprintf("cancel%sStub%d", f.Name, len(called))
+			called = append(called, c)
+		}
+		return nil
+	})
+
+	code := jen.NewFile(f.PkgName)
+	if o.pkgPath != "" {
+		code = jen.NewFilePathName(o.pkgPath, f.PkgName)
+	}
+	code.HeaderComment("Code generated by flo. Do not edit!")
+
+	if err := f.renderFunc(withRenderOptions(ctx, o), code, fn, false); err != nil {
+		return err
+	}
+
+	code.Line()
+	for i, c := range called {
+		code.Add(f.cancellationStub(c, o.stubs[c.ID], i, point))
+	}
+
+	code.Comment(state + " drives the stubs of " + fn + ".")
+	code.Var().Id(state).Struct(
+		jen.Id("at").Int(),
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("blocked").Chan().Struct(),
+		jen.Id("after").Int(),
+	)
+
+	code.Comment(point + " blocks the stub at until the context is canceled.")
+	code.Func().Id(point).Params(jen.Id("i").Int()).Error().Block(
+		jen.Id("s").Op(":=").Op("&").Id(state),
+		jen.If(jen.Id("i").Op("==").Id("s").Dot("at")).Block(
+			jen.Close(jen.Id("s").Dot("blocked")),
+			jen.Op("<-").Id("s").Dot("ctx").Dot("Done").Call(),
+			jen.Return(jen.Id("s").Dot("ctx").Dot("Err").Call()),
+		),
+		jen.If(
+			jen.Err().Op(":=").Id("s").Dot("ctx").Dot("Err").Call(),
+			jen.Err().Op("!=").Nil(),
+		).Block(
+			jen.Id("s").Dot("after").Op("++"),
+			jen.Return(jen.Err()),
+		),
+		jen.Return(jen.Nil()),
+	).Line()
+
+	hasError := false
+	for _, out := range floOUTs {
+		hasError = hasError || out.IsError
+	}
+
+	code.Func().Id("Test" + f.Name + "Cancellation").Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+		jen.For(jen.List(jen.Id("_"), jen.Id("tc")).Op(":=").Range().Index().Struct(
+			jen.Id("name").String(),
+			jen.Id("at").Int(),
+		).ValuesFunc(func(g *jen.Group) {
+			for i, c := range called {
+				g.Line().Values(jen.Lit(c.Label), jen.Lit(i))
+			}
+			g.Line()
+		})).Block(
+			jen.Id("t").Dot("Run").Call(jen.Id("tc").Dot("name"), jen.Func().Params(
+				jen.Id("t").Op("*").Qual("testing", "T"),
+			).BlockFunc(func(g *jen.Group) {
+				g.List(jen.Id("ctx"), jen.Id("cancel")).Op(":=").
+					Qual("context", "WithCancel").Call(jen.Qual("context", "Background").Call())
+				g.Defer().Id("cancel").Call()
+				g.Line()
+				g.Id(state).Dot("at").Op("=").Id("tc").Dot("at")
+				g.Id(state).Dot("ctx").Op("=").Id("ctx")
+				g.Id(state).Dot("blocked").Op("=").Make(jen.Chan().Struct())
+				g.Id(state).Dot("after").Op("=").Lit(0)
+				g.Line()
+				g.Id("done").Op(":=").Make(jen.Chan().Error(), jen.Lit(1))
+				g.Go().Func().Params().BlockFunc(func(g *jen.Group) {
+					call := jen.Id(fn).CallFunc(func(g *jen.Group) {
+						for _, in := range floINs {
+							if in == ctxParam {
+								g.Id("ctx")
+								continue
+							}
+							g.Add(zeroCode(in.RType))
+						}
+						for _, r := range f.receiverParams() {
+							g.Add(zeroCode(r.Type))
+						}
+					})
+					if !hasError {
+						g.Add(call)
+						g.Id("done").Op("<-").Nil()
+						return
+					}
+					g.ListFunc(func(g *jen.Group) {
+						for _, out := range floOUTs {
+							if out.IsError {
+								g.Err()
+								continue
+							}
+							g.Id("_")
+						}
+					}).Op(":=").Add(call)
+					g.Id("done").Op("<-").Err()
+				}).Call()
+				g.Line()
+				g.Select().Block(
+					jen.Case(jen.Op("<-").Id(state).Dot("blocked")),
+					jen.Case(jen.Op("<-").Id("done")),
+					jen.Id("t").Dot("Skip").Call(jen.Lit("component not reached")),
+					jen.Case(jen.Op("<-").Qual("time", "After").Call(jen.Qual("time", "Second"))),
+					jen.Id("t").Dot("Fatal").Call(jen.Lit("component not reached in time")),
+				)
+				g.Id("cancel").Call()
+				g.Line()
+				g.Select().Block(
+					jen.Case(jen.Err().Op(":=").Op("<-").Id("done")),
+					jen.Do(func(s *jen.Statement) {
+						if !hasError {
+							s.Id("_").Op("=").Err()
+							return
+						}
+						s.If(jen.Op("!").Qual("errors", "Is").Call(jen.Err(), jen.Qual("context", "Canceled"))).Block(
+							jen.Id("t").Dot("Errorf").Call(jen.Lit("got error %v, want %v"), jen.Err(), jen.Qual("context", "Canceled")),
+						)
+					}),
+					jen.Case(jen.Op("<-").Qual("time", "After").Call(jen.Qual("time", "Second"))),
+					jen.Id("t").Dot("Fatal").Call(jen.Lit("did not return after its context was canceled")),
+				)
+				g.If(jen.Id("n").Op(":=").Id(state).Dot("after"), jen.Id("n").Op(">").Lit(0)).Block(
+					jen.Id("t").Dot("Logf").Call(jen.Lit("%d components were called after the cancellation"), jen.Id("n")),
+				)
+			})),
+		),
+	)
+
+	buf := &bytes.Buffer{}
+	if err := code.Render(buf); err != nil {
+		return err
+	}
+
+	return o.postProcess(w, f.Name+"_cancellation_test.go", buf.Bytes())
+}
+
+// cancellationStub writes the stub called instead of c, which is the i-th
+// component.
+func (f *Flo) cancellationStub(c *Component, name string, i int, point string) jen.Code {
+	ins, outs := c.IOs.SeparateINsOUTs()
+
+	hasError := false
+	for _, out := range outs {
+		hasError = hasError || out.IsError
+	}
+
+	results := func(err jen.Code) []jen.Code {
+		var values []jen.Code
+		for j, out := range outs {
+			switch {
+			case out.IsError:
+				values = append(values, err)
+			case j == 0 && c.Kind == ComponentKindSource:
+				values = append(values, jen.Id("stream"))
+			default:
+				values = append(values, zeroCode(out.RType))
+			}
+		}
+		return values
+	}
+
+	return jen.Func().Id(name).
+		ParamsFunc(func(g *jen.Group) {
+			for _, in := range ins {
+				g.Add(typeCode(in.RType))
+			}
+		}).
+		Do(func(s *jen.Statement) {
+			switch len(outs) {
+			case 0:
+			case 1:
+				s.Add(typeCode(outs[0].RType))
+			default:
+				s.Parens(jen.ListFunc(func(g *jen.Group) {
+					for _, out := range outs {
+						g.Add(typeCode(out.RType))
+					}
+				}))
+			}
+		}).
+		BlockFunc(func(g *jen.Group) {
+			if c.Kind == ComponentKindSource {
+				// Streams end right away.
+				g.Id("stream").Op(":=").Make(jen.Chan().Add(typeCode(outs[0].RType.Elem())))
+				g.Close(jen.Id("stream"))
+			}
+			if !hasError {
+				g.Id("_").Op("=").Id(point).Call(jen.Lit(i))
+				if len(outs) > 0 {
+					g.Return(results(jen.Nil())...)
+				}
+				return
+			}
+			g.If(
+				jen.Err().Op(":=").Id(point).Call(jen.Lit(i)),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(results(jen.Err())...))
+			g.Return(results(jen.Nil())...)
+		}).
+		Line()
+}
@@ -0,0 +1,58 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"go/format"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCancellationTests(t *testing.T) {
+	f := newTestFlo(t)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.RenderCancellationTests(context.Background(), out))
+
+	formatted, err := format.Source(out.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), out.String())
+
+	// The flo calls stubs, numbered in calling order, instead of components.
+	require.Contains(t, out.String(), `func cancelTestSync(ctx context.Context, in int, _ int) (int, error) {
+	// Test Comp A Description
+	ioff39613112342A272B0Edf2D60F8Cedd6Da8A1A0 := cancelTestSyncStub0(ctx, in)
+`)
+	require.NotContains(t, out.String(), "tera.CompA")
+
+	require.Contains(t, out.String(), `func cancelTestSyncStub2(int, bool) (int, error) {
+	if err := cancelTestSyncPoint(2); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+`)
+	require.Contains(t, out.String(), `func cancelTestSyncStub4() {
+	_ = cancelTestSyncPoint(4)
+}
+`)
+	require.Contains(t, out.String(), `func TestTestSyncCancellation(t *testing.T) {`)
+	require.Contains(t, out.String(), `		{"Test Comp E Label", 4},`)
+	require.Contains(t, out.String(), `				_, err := cancelTestSync(ctx, 0, 0)`)
+	require.Contains(t, out.String(), `if !errors.Is(err, context.Canceled) {`)
+
+	t.Run("Without context", func(t *testing.T) {
+		f, err := flo.NewFlo("TestNoCtx", "Test No Ctx", "Test No Ctx Description", "flo", "Test Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		err = f.RenderCancellationTests(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, `flo "TestNoCtx" has no context param to cancel`)
+	})
+}
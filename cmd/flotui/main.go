@@ -0,0 +1,41 @@
+// Command flotui shows a flo snapshot, as written by Flo.EncodeBinary, in
+// the terminal.
+//
+//	flotui path/to/snapshot
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flotui"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "flotui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: flotui <snapshot>")
+	}
+
+	r, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Components are left unbound as their functions are not linked in.
+	f, err := flo.DecodeBinary(r, nil, nil)
+	if err != nil {
+		return fmt.Errorf("cannot decode snapshot: %v", err)
+	}
+	f.Layout(flo.LayoutOptions{})
+
+	return flotui.Run(f)
+}
@@ -0,0 +1,98 @@
+package flo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// flocodecPkg holds the helpers the codec components render calls to.
+const flocodecPkg = "github.com/mgjules/flo/flocodec"
+
+var (
+	bytesRType = reflect.TypeFor[[]byte]()
+	errorRType = reflect.TypeFor[error]()
+)
+
+// NewJSONEncodeComponent creates a component marshaling a value of type t to
+// JSON, i.e. func(t) ([]byte, error).
+func NewJSONEncodeComponent(t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newCodecComponent("EncodeJSON", label, description, jsonEncodeFunc(t), t)
+}
+
+// NewJSONDecodeComponent creates a component unmarshaling JSON to a value of
+// type t, i.e. func([]byte) (t, error).
+func NewJSONDecodeComponent(t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newCodecComponent("DecodeJSON", label, description, jsonDecodeFunc(t), t)
+}
+
+// codecFunc rebuilds the function backing a codec component.
+func codecFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	if len(typeArgs) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s expects 1 type argument but got %d", name, len(typeArgs))
+	}
+
+	switch name {
+	case "EncodeJSON":
+		return jsonEncodeFunc(typeArgs[0]), nil
+	case "DecodeJSON":
+		return jsonDecodeFunc(typeArgs[0]), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown codec %q", name)
+	}
+}
+
+func jsonEncodeFunc(t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{t}, []reflect.Type{bytesRType, errorRType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			data, err := json.Marshal(args[0].Interface())
+			return []reflect.Value{reflect.ValueOf(data), errorValue(err)}
+		},
+	)
+}
+
+func jsonDecodeFunc(t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{bytesRType}, []reflect.Type{t, errorRType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			v := reflect.New(t)
+			if err := json.Unmarshal(args[0].Bytes(), v.Interface()); err != nil {
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+			return []reflect.Value{v.Elem(), errorValue(nil)}
+		},
+	)
+}
+
+func newCodecComponent(
+	name string,
+	label, description string,
+	fn reflect.Value,
+	t reflect.Type,
+) (*Component, error) {
+	c, err := NewComponent(name, flocodecPkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = []reflect.Type{t}
+
+	return c, nil
+}
+
+func errorValue(err error) reflect.Value {
+	if err == nil {
+		return reflect.Zero(errorRType)
+	}
+
+	return reflect.ValueOf(&err).Elem()
+}
@@ -0,0 +1,84 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONComponents(t *testing.T) {
+	typ := reflect.TypeFor[map[string]int]()
+
+	decode, err := flo.NewJSONDecodeComponent(typ, "Decode", "Decode counts")
+	require.NoError(t, err)
+
+	encode, err := flo.NewJSONEncodeComponent(typ, "Encode", "Encode counts")
+	require.NoError(t, err)
+
+	t.Run("Call", func(t *testing.T) {
+		res := decode.Value.Call([]reflect.Value{reflect.ValueOf([]byte(`{"a":1}`))})
+		require.True(t, res[1].IsNil())
+		require.Equal(t, map[string]int{"a": 1}, res[0].Interface())
+
+		res = decode.Value.Call([]reflect.Value{reflect.ValueOf([]byte(`nope`))})
+		require.Error(t, res[1].Interface().(error))
+
+		res = encode.Value.Call([]reflect.Value{reflect.ValueOf(map[string]int{"b": 2})})
+		require.True(t, res[1].IsNil())
+		require.Equal(t, []byte(`{"b":2}`), res[0].Interface())
+	})
+
+	f, err := flo.NewFlo("TestJSON", "Test JSON", "Test JSON Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pData, err := flo.NewComponentIO("data", flo.ComponentIOTypeIN, reflect.TypeFor[[]byte](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pData))
+
+	rData, err := flo.NewComponentIO("data", flo.ComponentIOTypeOUT, reflect.TypeFor[[]byte](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rData))
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	require.NoError(t, f.AddComponent(decode))
+	require.NoError(t, f.AddComponent(encode))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pData.ID, decode.ID, decode.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(decode.ID, decode.IOs[1].ID, encode.ID, encode.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(encode.ID, encode.IOs[1].ID, f.ID, rData.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import flocodec "github.com/mgjules/flo/flocodec"
+
+func TestJSON(data []byte) ([]byte, error) {
+	// Decode counts
+	io2F9429E5B3F29616Fee1A4B95C25556312258C8D, err := flocodec.DecodeJSON[map[string]int](data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode counts
+	io07B6Eed14B587406Bc24A3A5261D6890040C88E4, err := flocodec.EncodeJSON[map[string]int](io2F9429E5B3F29616Fee1A4B95C25556312258C8D)
+	if err != nil {
+		return nil, err
+	}
+
+	return io07B6Eed14B587406Bc24A3A5261D6890040C88E4, nil
+}
+`, out.String())
+	})
+}
@@ -0,0 +1,62 @@
+package flo
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// CommentData is the data given to the template of WithCommentTemplate.
+type CommentData struct {
+	*Component
+	// File and Line locate the function of the component, when bound.
+	File string
+	Line int
+}
+
+// WithCommentTemplate replaces the description comment written above each
+// component call with the output of tmpl, e.g. to link back to the source
+// of the component:
+//
+//	{{.Label}}: {{.Description}}
+//	{{if .File}}See {{.File}}:{{.Line}}{{end}}
+//
+// Each line of the output becomes a line comment; blank lines are dropped.
+func WithCommentTemplate(tmpl *template.Template) RenderOption {
+	return func(o *renderOptions) {
+		o.commentTemplate = tmpl
+	}
+}
+
+// comment writes the comment above the call of c.
+func comment(c *Component, o renderOptions) (*jen.Statement, error) {
+	if o.commentTemplate == nil {
+		return jen.Comment(c.Description).Line(), nil
+	}
+
+	data := CommentData{Component: c}
+	if c.IsBound() {
+		if fn := runtime.FuncForPC(c.Value.Pointer()); fn != nil {
+			data.File, data.Line = fn.FileLine(fn.Entry())
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := o.commentTemplate.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("component id %q comment: %v", c.ID, err)
+	}
+
+	s := jen.Null()
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.Comment(strings.TrimRight(line, " \t")).Line()
+	}
+
+	return s, nil
+}
@@ -0,0 +1,51 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWithCommentTemplate(t *testing.T) {
+	f, err := flo.NewFlo("TestComment", "Test Comment", "Test Comment Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rOut.ID))
+
+	tmpl := template.Must(template.New("comment").Funcs(template.FuncMap{"base": filepath.Base}).Parse(
+		"{{.Label}}: {{.Description}}\n\n{{if .File}}See {{base .File}}:{{.Line}}{{end}}",
+	))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithCommentTemplate(tmpl)))
+	require.Contains(t, out.String(), `
+	// Len: Len Description
+	// See transform_test.go:15
+	io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd := tera.Len(in)
+`)
+
+	t.Run("Template error", func(t *testing.T) {
+		tmpl := template.Must(template.New("comment").Parse(`{{.Nope}}`))
+		err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithCommentTemplate(tmpl))
+		require.ErrorContains(t, err, "comment:")
+	})
+}
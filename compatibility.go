@@ -0,0 +1,132 @@
+package flo
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// CompatibilityKind tells how an out io can connect to an in io.
+type CompatibilityKind int
+
+const (
+	// CompatibilityAssignable connects with ConnectComponent.
+	CompatibilityAssignable CompatibilityKind = iota
+	// CompatibilityConvertible connects with ConnectComponentWithTransform
+	// and a conversion, e.g. NewExprTransform("float64($)", in, out).
+	CompatibilityConvertible
+	// CompatibilityAdapter connects with ConnectComponentWithTransform and
+	// the adapter.
+	CompatibilityAdapter
+)
+
+func (k CompatibilityKind) String() string {
+	switch k {
+	case CompatibilityAssignable:
+		return "ASSIGNABLE"
+	case CompatibilityConvertible:
+		return "CONVERTIBLE"
+	case CompatibilityAdapter:
+		return "ADAPTER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Compatibility is an in io an out io can legally connect to.
+type Compatibility struct {
+	ComponentID uuid.UUID // The flo id for flo outs.
+	IOID        uuid.UUID
+	Kind        CompatibilityKind
+	Adapter     *Transform // The first matching adapter, for CompatibilityAdapter.
+}
+
+// CompatibilityMatrix returns, for every out io of the components and every
+// in io of the flo, the in ios of the components and the out ios of the flo
+// it could connect to right now, so that editors can grey out the invalid
+// ones without trying to connect them.
+//
+// Ios are connected as is when possible, through a conversion otherwise,
+// then through the first of adapters accepting them, e.g. transforms
+// parsing or formatting values.
+func (f *Flo) CompatibilityMatrix(adapters ...*Transform) map[uuid.UUID][]Compatibility {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	type end struct {
+		componentID uuid.UUID
+		io          *ComponentIO
+	}
+	var outs, ins []end
+	floINs, floOUTs := f.IOs.SeparateINsOUTs()
+	for _, in := range floINs {
+		outs = append(outs, end{f.ID, in})
+	}
+	for _, c := range f.orderedComponents() {
+		cINs, cOUTs := c.IOs.SeparateINsOUTs()
+		for _, out := range cOUTs {
+			outs = append(outs, end{c.ID, out})
+		}
+		for _, in := range cINs {
+			ins = append(ins, end{c.ID, in})
+		}
+	}
+	for _, out := range floOUTs {
+		ins = append(ins, end{f.ID, out})
+	}
+
+	matrix := make(map[uuid.UUID][]Compatibility, len(outs))
+	for _, out := range outs {
+		compatible := make([]Compatibility, 0)
+		for _, in := range ins {
+			if out.componentID == f.ID && in.componentID == f.ID {
+				continue
+			}
+			if _, _, err := f.connectionEnds(out.componentID, out.io.ID, in.componentID, in.io.ID); err != nil {
+				continue
+			}
+
+			c := Compatibility{ComponentID: in.componentID, IOID: in.io.ID}
+			switch {
+			case in.io.IsSignal, out.io.RType.AssignableTo(in.io.acceptedType()):
+				c.Kind = CompatibilityAssignable
+			case convertible(out.io.RType, in.io.acceptedType()):
+				c.Kind = CompatibilityConvertible
+			default:
+				c.Kind = CompatibilityAdapter
+				for _, a := range adapters {
+					if a != nil && a.check(out.io, in.io) == nil {
+						c.Adapter = a
+						break
+					}
+				}
+				if c.Adapter == nil {
+					continue
+				}
+			}
+			compatible = append(compatible, c)
+		}
+		matrix[out.io.ID] = compatible
+	}
+
+	return matrix
+}
+
+// convertible reports whether values of from can be converted to to
+// without surprises: neither integers turned into runes nor slices into
+// arrays, which panics when too short.
+func convertible(from, to reflect.Type) bool {
+	if !from.ConvertibleTo(to) {
+		return false
+	}
+
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return to.Kind() != reflect.String
+	case reflect.Slice:
+		return to.Kind() != reflect.Array && (to.Kind() != reflect.Pointer || to.Elem().Kind() != reflect.Array)
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+package flo_test
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatibilityMatrix(t *testing.T) {
+	f, err := flo.NewFlo("Compat", "Compat", "Compat Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	n, s, res := flo.In[int]("n"), flo.In[string]("s"), flo.Out[string]("res")
+	for _, io := range []*flo.ComponentIO{n, s, res} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	newComponent := func(name, pkgPath string, fn any) *flo.Component {
+		c, err := flo.NewComponent(name, pkgPath, name, name+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		return c
+	}
+	sqrt := newComponent("Sqrt", "math", math.Sqrt)
+	trim := newComponent("TrimSpace", "strings", strings.TrimSpace)
+	upper := newComponent("ToUpper", "strings", strings.ToUpper)
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, upper.ID, upper.IOs[0].ID))
+
+	itoa, err := flo.NewTransform("Itoa", "strconv", strconv.Itoa)
+	require.NoError(t, err)
+	matrix := f.CompatibilityMatrix(nil, itoa)
+	require.Len(t, matrix, 5)
+
+	targets := func(out *flo.ComponentIO) map[uuid.UUID]flo.CompatibilityKind {
+		res := make(map[uuid.UUID]flo.CompatibilityKind)
+		for _, c := range matrix[out.ID] {
+			res[c.IOID] = c.Kind
+			if c.Kind == flo.CompatibilityAdapter {
+				require.Same(t, itoa, c.Adapter)
+			}
+		}
+		return res
+	}
+
+	require.Equal(t, map[uuid.UUID]flo.CompatibilityKind{
+		sqrt.IOs[0].ID: flo.CompatibilityConvertible,
+		trim.IOs[0].ID: flo.CompatibilityAdapter,
+	}, targets(n))
+	// The in io of upper is already connected.
+	require.Equal(t, map[uuid.UUID]flo.CompatibilityKind{
+		trim.IOs[0].ID: flo.CompatibilityAssignable,
+	}, targets(s))
+	// trim can't depend on upper which depends on it.
+	require.Equal(t, map[uuid.UUID]flo.CompatibilityKind{
+		res.ID: flo.CompatibilityAssignable,
+	}, targets(upper.IOs[1]))
+	require.Empty(t, targets(sqrt.IOs[1]))
+
+	// Compatible ios connect as told.
+	require.Error(t, f.ConnectComponent(f.ID, n.ID, sqrt.ID, sqrt.IOs[0].ID))
+	toFloat, err := flo.NewExprTransform("float64($)", reflect.TypeFor[int](), reflect.TypeFor[float64]())
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectComponentWithTransform(f.ID, n.ID, sqrt.ID, sqrt.IOs[0].ID, toFloat))
+	require.NoError(t, f.ConnectComponentWithTransform(f.ID, n.ID, trim.ID, trim.IOs[0].ID, itoa))
+	require.Equal(t, "ADAPTER", flo.CompatibilityAdapter.String())
+}
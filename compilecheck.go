@@ -0,0 +1,274 @@
+package flo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// CompileResult is the outcome of a CompileCheck.
+type CompileResult struct {
+	Build CompileStep
+	Vet   CompileStep
+}
+
+// OK reports whether the flo both builds and passes vet.
+func (r *CompileResult) OK() bool {
+	return r.Build.OK && r.Vet.OK
+}
+
+// CompileStep is the outcome of a single go command.
+type CompileStep struct {
+	Ran         bool
+	OK          bool
+	Output      string
+	Diagnostics []CompileDiagnostic
+}
+
+// CompileDiagnostic is a positioned message reported by the go tool.
+type CompileDiagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d CompileDiagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// CompileCheck renders the flo with opts and runs `go build` then `go vet`
+// on it. Vet only runs when the build succeeds.
+//
+// The flo is built in a temporary module outside of the working directory.
+// Inside a module, it requires the module, replaced by its directory, so
+// that the flo can use the dependencies and packages of the module.
+//
+// An error is returned when the check itself cannot run, e.g. the go tool is
+// missing; compilation failures are reported in the result.
+func (f *Flo) CompileCheck(ctx context.Context, opts ...RenderOption) (*CompileResult, error) {
+	if f.PkgName == "" {
+		return nil, errors.New("missing package name")
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil, fmt.Errorf("cannot find go tool: %v", err)
+	}
+
+	src := &bytes.Buffer{}
+	if err := f.Render(ctx, src, opts...); err != nil {
+		return nil, fmt.Errorf("cannot render flo: %v", err)
+	}
+
+	dir, err := checkDir(ctx, goBin)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "flo.go"), src.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("cannot write rendered flo: %v", err)
+	}
+
+	var res CompileResult
+
+	res.Build, err = runGoStep(ctx, goBin, dir, "build", ".")
+	if err != nil {
+		return nil, err
+	}
+	if !res.Build.OK {
+		return &res, nil
+	}
+
+	res.Vet, err = runGoStep(ctx, goBin, dir, "vet", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// checkDir creates the temporary directory the flo is checked in. Nothing is
+// written to the module of the working directory, if any.
+//
+// Inside a module, it is a module requiring the module, replaced by its
+// directory, and its dependencies, with a copy of its go.sum. Otherwise, it
+// is a bare module.
+func checkDir(ctx context.Context, goBin string) (string, error) {
+	goMod, err := goEnv(ctx, goBin, "GOMOD")
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "flo-check-*")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temp dir: %v", err)
+	}
+
+	var modFile []byte
+	if goMod != "" && goMod != os.DevNull {
+		modFile, err = replacingModFile(goMod)
+		if err == nil {
+			sumFile := strings.TrimSuffix(goMod, ".mod") + ".sum"
+			err = copyFile(sumFile, filepath.Join(dir, "go.sum"))
+			if errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		}
+	} else {
+		modFile, err = bareModFile(ctx, goBin)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), modFile, 0o600); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("cannot write go.mod: %v", err)
+	}
+
+	return dir, nil
+}
+
+// replacingModFile returns the go.mod of the check of a flo in the module of
+// goMod: a module nested in its path, so that the flo can use its internal
+// packages, requiring it, replaced by its directory, and its dependencies.
+func replacingModFile(goMod string) ([]byte, error) {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read go.mod: %v", err)
+	}
+	mf, err := modfile.Parse(goMod, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse go.mod: %v", err)
+	}
+	if mf.Module == nil {
+		return nil, errors.New("cannot parse go.mod: missing module directive")
+	}
+
+	modDir := filepath.Dir(goMod)
+	modPath := mf.Module.Mod.Path
+	if err := mf.AddModuleStmt(modPath + "/_flocheck"); err != nil {
+		return nil, err
+	}
+	// Local replacements are relative to the directory of the module.
+	for _, r := range mf.Replace {
+		if r.New.Version != "" || filepath.IsAbs(r.New.Path) {
+			continue
+		}
+		if err := mf.AddReplace(r.Old.Path, r.Old.Version, filepath.Join(modDir, r.New.Path), ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := mf.AddRequire(modPath, "v0.0.0-00010101000000-000000000000"); err != nil {
+		return nil, err
+	}
+	if err := mf.AddReplace(modPath, "", modDir, ""); err != nil {
+		return nil, err
+	}
+	mf.Cleanup()
+
+	return mf.Format()
+}
+
+// bareModFile returns the go.mod of the check of a flo outside of any
+// module.
+func bareModFile(ctx context.Context, goBin string) ([]byte, error) {
+	version, err := goEnv(ctx, goBin, "GOVERSION")
+	if err != nil {
+		return nil, err
+	}
+	modFile := "module flocheck\n"
+	// Development versions, e.g. "devel go1.23-abc", have no go directive.
+	if v, ok := strings.CutPrefix(version, "go"); ok {
+		modFile += fmt.Sprintf("\ngo %s\n", v)
+	}
+
+	return []byte(modFile), nil
+}
+
+// copyFile copies the file src to dst.
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", filepath.Base(src), err)
+	}
+	if err := os.WriteFile(dst, data, 0o600); err != nil {
+		return fmt.Errorf("cannot write %s: %v", filepath.Base(dst), err)
+	}
+
+	return nil
+}
+
+// goEnv returns the value of the go environment variable name.
+func goEnv(ctx context.Context, goBin, name string) (string, error) {
+	cmd := exec.CommandContext(ctx, goBin, "env", name)
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot run go env: %v", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func runGoStep(ctx context.Context, goBin, dir string, args ...string) (CompileStep, error) {
+	cmd := exec.CommandContext(ctx, goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+
+	out, err := cmd.CombinedOutput()
+	step := CompileStep{
+		Ran:         true,
+		OK:          err == nil,
+		Output:      string(out),
+		Diagnostics: parseDiagnostics(out),
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return step, fmt.Errorf("cannot run go %s: %v", args[0], err)
+	}
+
+	return step, nil
+}
+
+// parseDiagnostics extracts the "file:line:col: message" lines of out.
+func parseDiagnostics(out []byte) []CompileDiagnostic {
+	var diags []CompileDiagnostic
+
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(sc.Text()), ":", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		line, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		col, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+		diags = append(diags, CompileDiagnostic{
+			File:    strings.TrimPrefix(parts[0], "./"),
+			Line:    line,
+			Column:  col,
+			Message: strings.TrimSpace(parts[3]),
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,122 @@
+package flo_test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flosecret"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go tool")
+	}
+
+	newFlo := func(t *testing.T) (*flo.Flo, *flo.ComponentIO) {
+		f, err := flo.NewFlo("TestCheck", "Test Check", "Test Check Description", "flo", "Test Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rOut))
+
+		return f, pIn
+	}
+
+	t.Run("Builds", func(t *testing.T) {
+		f, pIn := newFlo(t)
+
+		itoa, err := flo.NewComponent("Itoa", "strconv", "Itoa", "Itoa Description", strconv.Itoa)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(itoa))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, itoa.ID, itoa.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(itoa.ID, itoa.IOs[1].ID, f.ID, f.IOs[1].ID))
+
+		res, err := f.CompileCheck(context.Background())
+		require.NoError(t, err)
+		require.True(t, res.OK(), res.Build.Output+res.Vet.Output)
+		require.True(t, res.Vet.Ran)
+	})
+
+	t.Run("Builds with a third-party package", func(t *testing.T) {
+		f, _ := newFlo(t)
+
+		newString, err := flo.NewComponent("NewString", "github.com/google/uuid", "NewString", "NewString Description", uuid.NewString)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(newString))
+		require.NoError(t, f.ConnectComponent(newString.ID, newString.IOs[0].ID, f.ID, f.IOs[1].ID))
+
+		res, err := f.CompileCheck(context.Background(), flo.WithParallel())
+		require.NoError(t, err)
+		require.True(t, res.OK(), res.Build.Output+res.Vet.Output)
+	})
+
+	t.Run("Builds with a package of the module", func(t *testing.T) {
+		f, err := flo.NewFlo("TestCheck", "Test Check", "Test Check Description", "flo", "Test Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+		rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[flosecret.Secret](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rOut))
+
+		secret, err := flo.NewComponent("New", "github.com/mgjules/flo/flosecret", "New", "New Description", flosecret.New)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(secret))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, secret.ID, secret.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(secret.ID, secret.IOs[1].ID, f.ID, rOut.ID))
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		before, err := os.ReadDir(wd)
+		require.NoError(t, err)
+
+		res, err := f.CompileCheck(context.Background())
+		require.NoError(t, err)
+		require.True(t, res.OK(), res.Build.Output+res.Vet.Output)
+
+		// Nothing is left in the module.
+		after, err := os.ReadDir(wd)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
+
+	t.Run("Missing package name", func(t *testing.T) {
+		f, _ := newFlo(t)
+		f.PkgName = ""
+
+		_, err := f.CompileCheck(context.Background())
+		require.ErrorContains(t, err, "missing package name")
+	})
+
+	t.Run("Fails to build", func(t *testing.T) {
+		f, pIn := newFlo(t)
+
+		// Formats an int into a string but the package does not exist.
+		format, err := flo.NewHeadlessComponent("Format", "githab.com/testuf/tera", "Format", "", "func(int) string", nil)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(format))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, format.ID, format.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(format.ID, format.IOs[1].ID, f.ID, f.IOs[1].ID))
+
+		res, err := f.CompileCheck(context.Background())
+		require.NoError(t, err)
+		require.False(t, res.OK())
+		require.False(t, res.Build.OK)
+		require.False(t, res.Vet.Ran)
+		require.NotEmpty(t, res.Build.Diagnostics)
+		require.Equal(t, "flo.go", res.Build.Diagnostics[0].File)
+	})
+}
@@ -0,0 +1,44 @@
+package flo
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/samber/lo"
+)
+
+// contextParam returns the first context.Context in io of the flo, if any.
+func (f *Flo) contextParam() *ComponentIO {
+	ins, _ := f.IOs.SeparateINsOUTs()
+	param, _ := lo.Find(ins, func(in *ComponentIO) bool { return in.RType == contextRType })
+
+	return param
+}
+
+// isImplicitContext reports whether in is a context.Context in io neither
+// connected nor set, fed with contextValue.
+func isImplicitContext(in *ComponentIO) bool {
+	return in.Type == ComponentIOTypeIN && in.RType == contextRType &&
+		!in.Multi && !in.Literal.IsValid() && len(in.Connections) == 0
+}
+
+// usesImplicitContext reports whether some component is fed contextValue.
+func (f *Flo) usesImplicitContext() bool {
+	return lo.SomeBy(lo.Values(f.Components), func(c *Component) bool {
+		return lo.SomeBy(c.IOs, isImplicitContext)
+	})
+}
+
+// usesContextParam reports whether the body of the flo uses its context
+// param, which is declared as "_" otherwise.
+func (f *Flo) usesContextParam(param *ComponentIO) bool {
+	return param != nil && (len(param.Connections) > 0 || param == f.contextParam() && f.usesImplicitContext())
+}
+
+// contextValue is the context of the flo: its context param, or
+// context.Background() when it has none.
+func (f *Flo) contextValue() jen.Code {
+	if param := f.contextParam(); param != nil {
+		return jen.Id(param.Name)
+	}
+
+	return jen.Qual("context", "Background").Call()
+}
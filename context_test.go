@@ -0,0 +1,85 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplicitContext(t *testing.T) {
+	newFlo := func(t *testing.T, withCtx bool, fn any) *flo.Flo {
+		t.Helper()
+
+		f, err := flo.NewFlo("Check", "Check", "Check Description", "flo", "")
+		require.NoError(t, err)
+		if withCtx {
+			ctxIO, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+			require.NoError(t, err)
+			require.NoError(t, f.AddIO(ctxIO))
+		}
+		in, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(in))
+		result, err := flo.NewComponentIO("result", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(result))
+		errIO, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(errIO))
+
+		c, err := flo.NewComponent("Comp", "githab.com/testuf/tera", "Comp", "Comp Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+
+		ins, outs := c.IOs.SeparateINsOUTs()
+		for _, cin := range ins {
+			if cin.RType == reflect.TypeFor[int]() {
+				require.NoError(t, f.ConnectComponent(f.ID, in.ID, c.ID, cin.ID))
+			}
+		}
+		require.NoError(t, f.ConnectComponent(c.ID, outs[0].ID, f.ID, result.ID))
+
+		return f
+	}
+
+	t.Run("Context of the flo", func(t *testing.T) {
+		f := newFlo(t, true, compCFn)
+		require.Empty(t, f.Validate(context.Background()))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "func Check(ctx context.Context, in int) (int, error) {\n")
+		require.Contains(t, out.String(), " := tera.Comp(ctx, in, in)\n")
+
+		outputs, err := f.Execute(context.Background(), map[string]any{"in": 2})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 4}, outputs)
+	})
+
+	t.Run("Background context", func(t *testing.T) {
+		f := newFlo(t, false, compCFn)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "\t\"context\"\n")
+		require.Contains(t, out.String(), "func Check(in int) (int, error) {\n")
+		require.Contains(t, out.String(), " := tera.Comp(context.Background(), in, in)\n")
+	})
+
+	t.Run("Unused context", func(t *testing.T) {
+		f := newFlo(t, true, compBFn)
+		comp := componentNamed(f, "Comp")
+		require.NoError(t, f.SetLiteral(comp.ID, comp.IOs[1].ID, true))
+		require.Empty(t, f.Validate(context.Background()))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "\t\"context\"\n")
+		require.Contains(t, out.String(), "func Check(_ context.Context, in int) (int, error) {\n")
+		require.Contains(t, out.String(), " := tera.Comp(in, true)\n")
+	})
+}
@@ -0,0 +1,100 @@
+package flo
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cost is what a call of a component costs.
+type Cost struct {
+	Latency time.Duration
+	Price   float64 // Monetary cost, in the currency of choice.
+}
+
+// Add returns the sum of c and o.
+func (c Cost) Add(o Cost) Cost {
+	return Cost{Latency: c.Latency + o.Latency, Price: c.Price + o.Price}
+}
+
+// CostEstimate estimates what a run of a flo costs.
+type CostEstimate struct {
+	// Total adds up every component as the generated code runs them one
+	// after the other.
+	Total Cost
+	// CriticalPath adds up the longest chain, by latency, of components
+	// depending on each other, i.e. the latency of the flo were the
+	// independent components run concurrently.
+	CriticalPath Cost
+	// CriticalPathIDs are the components of the critical path, in execution
+	// order.
+	CriticalPathIDs []uuid.UUID
+	// Unknown are the components without cost, counted as free.
+	Unknown []uuid.UUID
+}
+
+// SetCost sets what a call of the component id costs.
+func (f *Flo) SetCost(id uuid.UUID, cost Cost) error {
+	if cost.Latency < 0 {
+		return fmt.Errorf("invalid latency %s", cost.Latency)
+	}
+	if cost.Price < 0 || math.IsNaN(cost.Price) || math.IsInf(cost.Price, 0) {
+		return fmt.Errorf("invalid price %v", cost.Price)
+	}
+
+	return f.setComponent(id, func(c *Component) {
+		c.Cost = cost
+	})
+}
+
+// EstimateCost estimates what a run of the flo costs from the costs of its
+// components. Every component is assumed to run, whatever the guards and
+// feature flags.
+func (f *Flo) EstimateCost() (CostEstimate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.estimateCost()
+}
+
+func (f *Flo) estimateCost() (CostEstimate, error) {
+	order, err := f.executionOrder()
+	if err != nil {
+		return CostEstimate{}, err
+	}
+
+	var e CostEstimate
+	// Most expensive chain ending with each component.
+	chains := make(map[uuid.UUID]Cost, len(order))
+	prev := make(map[uuid.UUID]uuid.UUID, len(order))
+	var last uuid.UUID
+	for _, c := range order {
+		if c.Cost == (Cost{}) && c.Kind != ComponentKindJoin && c.Kind != ComponentKindGuard && c.Kind != ComponentKindLiteral {
+			e.Unknown = append(e.Unknown, c.ID)
+		}
+		e.Total = e.Total.Add(c.Cost)
+
+		var chain Cost
+		for _, id := range f.predecessors(c) {
+			if pc := chains[id]; pc.Latency > chain.Latency || (pc.Latency == chain.Latency && pc.Price > chain.Price) {
+				chain = pc
+				prev[c.ID] = id
+			}
+		}
+		chains[c.ID] = chain.Add(c.Cost)
+
+		if cc := chains[c.ID]; last == uuid.Nil || cc.Latency > e.CriticalPath.Latency ||
+			(cc.Latency == e.CriticalPath.Latency && cc.Price > e.CriticalPath.Price) {
+			e.CriticalPath = cc
+			last = c.ID
+		}
+	}
+
+	for id := last; id != uuid.Nil; id = prev[id] {
+		e.CriticalPathIDs = append([]uuid.UUID{id}, e.CriticalPathIDs...)
+	}
+
+	return e, nil
+}
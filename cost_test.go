@@ -0,0 +1,81 @@
+package flo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCost(t *testing.T) {
+	f, err := flo.NewFlo("Shout", "Shout", "Shout Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, out := flo.In[string]("s"), flo.Out[string]("res")
+	require.NoError(t, f.AddIO(in))
+	require.NoError(t, f.AddIO(out))
+
+	newComponent := func(name string, fn any, cost flo.Cost) *flo.Component {
+		c, err := flo.NewComponent(name, "strings", name, name+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.SetCost(c.ID, cost))
+		return c
+	}
+	trim := newComponent("TrimSpace", strings.TrimSpace, flo.Cost{Latency: 10 * time.Millisecond, Price: 1})
+	upper := newComponent("ToUpper", strings.ToUpper, flo.Cost{Latency: 50 * time.Millisecond, Price: 2})
+	lower := newComponent("ToLower", strings.ToLower, flo.Cost{Latency: 20 * time.Millisecond, Price: 0.5})
+	title := newComponent("ToTitle", strings.ToTitle, flo.Cost{Latency: 40 * time.Millisecond, Price: 0.5})
+
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, trim.ID, trim.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, upper.ID, upper.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(upper.ID, upper.IOs[1].ID, f.ID, out.ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, lower.ID, lower.IOs[0].ID))
+	require.NoError(t, f.ConnectSequence(lower.ID, title.ID))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, title.ID, title.IOs[0].ID))
+
+	require.ErrorContains(t, f.SetCost(trim.ID, flo.Cost{Latency: -time.Second}), "invalid latency")
+	require.ErrorContains(t, f.SetCost(trim.ID, flo.Cost{Price: -1}), "invalid price")
+	require.ErrorContains(t, f.SetCost(uuid.New(), flo.Cost{}), "no component id")
+
+	t.Run("Estimate", func(t *testing.T) {
+		e, err := f.EstimateCost()
+		require.NoError(t, err)
+		require.Equal(t, flo.Cost{Latency: 120 * time.Millisecond, Price: 4}, e.Total)
+		require.Equal(t, flo.Cost{Latency: 70 * time.Millisecond, Price: 2}, e.CriticalPath)
+		require.Equal(t, []uuid.UUID{trim.ID, lower.ID, title.ID}, e.CriticalPathIDs)
+		require.Empty(t, e.Unknown)
+
+		require.NoError(t, f.SetCost(title.ID, flo.Cost{}))
+		e, err = f.EstimateCost()
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{trim.ID, upper.ID}, e.CriticalPathIDs)
+		require.Equal(t, []uuid.UUID{title.ID}, e.Unknown)
+		require.NoError(t, f.SetCost(title.ID, flo.Cost{Latency: 40 * time.Millisecond, Price: 0.5}))
+	})
+
+	t.Run("Docs", func(t *testing.T) {
+		r, err := flo.NewTemplateRenderer("cost", `{{.Cost.Total.Latency}} {{.Cost.CriticalPath.Price}}`)
+		require.NoError(t, err)
+		buf := &bytes.Buffer{}
+		require.NoError(t, r.Render(context.Background(), f, buf))
+		require.Equal(t, "120ms 2", buf.String())
+
+		buf.Reset()
+		require.NoError(t, f.ExportBundle(buf, nil))
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		readme, err := zr.Open("README.md")
+		require.NoError(t, err)
+		docs, err := io.ReadAll(readme)
+		require.NoError(t, err)
+		require.Contains(t, string(docs), "## Cost\n\nEstimated per run:\n\n- Latency: 120ms, 70ms along the critical path\n- Price: 4, 2 along the critical path\n")
+	})
+}
@@ -0,0 +1,127 @@
+package flo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ComponentCoverage tells whether the tests ran the code of a component.
+type ComponentCoverage struct {
+	ComponentID uuid.UUID
+	Label       string
+	Covered     bool
+	Count       int // Highest hit count of the code of the component.
+}
+
+// coverBlock is a block of a cover profile.
+type coverBlock struct {
+	startLine, endLine int
+	count              int
+}
+
+// Coverage reports, in rendering order, which components were run by the
+// tests from profile, as written by `go test -coverprofile`.
+// filename is the path of the generated file as it appears in the profile,
+// e.g. "example.com/app/flows/checkout.go", and opts the options it was
+// rendered with.
+func (f *Flo) Coverage(
+	ctx context.Context,
+	profile io.Reader,
+	filename string,
+	opts ...RenderOption,
+) ([]ComponentCoverage, error) {
+	if filename == "" {
+		return nil, errors.New("missing filename")
+	}
+
+	blocks, err := parseCoverProfile(profile, filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no coverage of %s found in profile", filename)
+	}
+
+	spans, err := f.SourceMap(ctx, io.Discard, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	coverage := make([]ComponentCoverage, 0, len(spans))
+	for _, span := range spans {
+		cc := ComponentCoverage{ComponentID: span.ComponentID}
+		if c, found := f.Components[span.ComponentID]; found {
+			cc.Label = c.Label
+		}
+		for _, b := range blocks {
+			if b.startLine > span.EndLine || b.endLine < span.StartLine {
+				continue
+			}
+			cc.Count = max(cc.Count, b.count)
+		}
+		cc.Covered = cc.Count > 0
+		coverage = append(coverage, cc)
+	}
+
+	return coverage, nil
+}
+
+// parseCoverProfile returns the blocks of filename in profile. Lines look
+// like "example.com/app/flows/checkout.go:12.34,15.2 3 1".
+func parseCoverProfile(profile io.Reader, filename string) ([]coverBlock, error) {
+	var blocks []coverBlock
+
+	scanner := bufio.NewScanner(profile)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		file, rest, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid cover profile line %d", n)
+		}
+		if file != filename && !strings.HasSuffix(file, "/"+filename) {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid cover profile line %d", n)
+		}
+		start, end, found := strings.Cut(fields[0], ",")
+		if !found {
+			return nil, fmt.Errorf("invalid cover profile line %d", n)
+		}
+
+		var (
+			b    coverBlock
+			errs []error
+			err  error
+		)
+		b.startLine, err = strconv.Atoi(strings.Split(start, ".")[0])
+		errs = append(errs, err)
+		b.endLine, err = strconv.Atoi(strings.Split(end, ".")[0])
+		errs = append(errs, err)
+		b.count, err = strconv.Atoi(fields[2])
+		errs = append(errs, err)
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("invalid cover profile line %d: %v", n, err)
+		}
+
+		blocks = append(blocks, b)
+	}
+
+	return blocks, scanner.Err()
+}
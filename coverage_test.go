@@ -0,0 +1,44 @@
+package flo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	f := newTestFlo(t)
+
+	// CompA, CompD and CompB run but CompB always fails.
+	profile := `mode: count
+example.com/app/flows/test_sync.go:14.66,23.16 4 3
+example.com/app/flows/test_sync.go:23.16,25.3 1 3
+example.com/app/flows/test_sync.go:28.2,29.16 2 0
+example.com/app/flows/test_sync.go:29.16,31.3 1 0
+example.com/app/flows/test_sync.go:34.2,36.2 2 0
+example.com/app/flows/other.go:1.1,99.2 9 5
+`
+
+	coverage, err := f.Coverage(context.Background(), strings.NewReader(profile), "flows/test_sync.go")
+	require.NoError(t, err)
+	require.Len(t, coverage, 5)
+
+	covered := make(map[string]int)
+	for _, cc := range coverage {
+		require.Equal(t, cc.Count > 0, cc.Covered)
+		covered[f.Components[cc.ComponentID].Name] = cc.Count
+	}
+	require.Equal(t, map[string]int{"CompA": 3, "CompD": 3, "CompB": 3, "CompC": 0, "CompE": 0}, covered)
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := f.Coverage(context.Background(), strings.NewReader(profile), "flows/nope.go")
+		require.ErrorContains(t, err, "no coverage of flows/nope.go found")
+	})
+
+	t.Run("Invalid profile", func(t *testing.T) {
+		_, err := f.Coverage(context.Background(), strings.NewReader("flows/test_sync.go:nope"), "flows/test_sync.go")
+		require.ErrorContains(t, err, "invalid cover profile line 1")
+	})
+}
@@ -0,0 +1,6 @@
+//go:build flodebug
+
+package flo
+
+// debug enables the internal consistency checks.
+const debug = true
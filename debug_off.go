@@ -0,0 +1,5 @@
+//go:build !flodebug
+
+package flo
+
+const debug = false
@@ -0,0 +1,165 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Files of a debug bundle, on top of the ones of a bundle.
+const (
+	bundleInputs = "inputs.json"
+	bundleTrace  = "trace.json"
+)
+
+// ExecutionStep is a component call of an execution.
+type ExecutionStep struct {
+	ComponentID uuid.UUID
+	Label       string
+	Duration    time.Duration
+	Err         string `json:",omitempty"` // Error returned or panic.
+}
+
+type bundleFailure struct {
+	Err         string
+	ComponentID uuid.UUID // Nil when the failure is not about a component.
+}
+
+// WithDebugBundle writes a debug bundle to w when the execution fails: the
+// bundle of the flo, see ExportBundle, along with its inputs, the
+// components called up to the failure and the error, to attach to bug
+// reports. It is read back with ImportDebugBundle.
+// Inputs that can't be encoded to JSON, e.g. contexts, are left out.
+// All the named types used by the flo must be known to types.
+// A nil types uses DefaultTypeRegistry.
+func WithDebugBundle(w io.Writer, types *TypeRegistry) ExecuteOption {
+	return func(o *executeOptions) {
+		o.debugBundle = w
+		o.debugTypes = types
+	}
+}
+
+// writeDebugBundle writes the debug bundle of f, the flo executed with
+// inputs, that failed with err.
+func (f *Flo) writeDebugBundle(o executeOptions, inputs map[string]any, trace []ExecutionStep, err error) error {
+	failure := &bundleFailure{Err: err.Error()}
+	var cerr *ComponentError
+	if errors.As(err, &cerr) {
+		failure.ComponentID = cerr.ComponentID
+	}
+
+	encoded := make(map[string]json.RawMessage, len(inputs))
+	for name, v := range inputs {
+		if _, ok := v.(context.Context); ok {
+			continue
+		}
+		if raw, err := json.Marshal(v); err == nil {
+			encoded[name] = raw
+		}
+	}
+	inputsData, err := json.MarshalIndent(encoded, "", "\t")
+	if err != nil {
+		return err
+	}
+	traceData, err := json.MarshalIndent(trace, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := f.exportBundle(buf, o.debugTypes, failure,
+		bundleFile{bundleInputs, inputsData},
+		bundleFile{bundleTrace, traceData},
+	); err != nil {
+		return err
+	}
+	_, err = o.debugBundle.Write(buf.Bytes())
+
+	return err
+}
+
+// record accounts a call of c that took d and failed with err, if not nil,
+// to the metrics and trace of the execution.
+func (e *execution) record(c *Component, d time.Duration, err error) {
+	e.metrics.record(c.ID, d, err != nil)
+	if e.trace == nil {
+		return
+	}
+
+	step := ExecutionStep{ComponentID: c.ID, Label: c.Label, Duration: d}
+	if err != nil {
+		step.Err = err.Error()
+	}
+	*e.trace = append(*e.trace, step)
+}
+
+// DebugBundle is a bundle written after a failed execution, see
+// WithDebugBundle.
+type DebugBundle struct {
+	Flo         *Flo
+	Inputs      map[string]json.RawMessage // Inputs that could be encoded, by name.
+	Trace       []ExecutionStep            // Components called up to the failure.
+	Err         string
+	ComponentID uuid.UUID // Component that failed, nil when unknown.
+}
+
+// ImportDebugBundle reads a debug bundle written by WithDebugBundle.
+// Component and transform functions are rebound using resolve, as in
+// DecodeBinary.
+func ImportDebugBundle(r io.Reader, types *TypeRegistry, resolve ResolveFunc) (*DebugBundle, error) {
+	f, zr, manifest, err := importBundle(r, types, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Failure == nil {
+		return nil, errors.New("invalid debug bundle: missing failure")
+	}
+
+	b := &DebugBundle{
+		Flo:         f,
+		Err:         manifest.Failure.Err,
+		ComponentID: manifest.Failure.ComponentID,
+	}
+	if err := readBundleFile(zr, bundleInputs, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&b.Inputs)
+	}); err != nil {
+		return nil, err
+	}
+	if err := readBundleFile(zr, bundleTrace, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&b.Trace)
+	}); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Replay executes the flo of the bundle again with its inputs, decoded
+// into the types of the in ios of the flo.
+func (b *DebugBundle) Replay(ctx context.Context, opts ...ExecuteOption) (map[string]any, error) {
+	b.Flo.mu.Lock()
+	ins, _ := b.Flo.IOs.SeparateINsOUTs()
+	b.Flo.mu.Unlock()
+
+	inputs := make(map[string]any, len(b.Inputs))
+	for _, in := range ins {
+		raw, found := b.Inputs[in.Name]
+		if !found {
+			continue
+		}
+		v := reflect.New(in.RType)
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return nil, fmt.Errorf("input %q: %v", in.Name, err)
+		}
+		inputs[in.Name] = v.Elem().Interface()
+	}
+
+	return b.Flo.Execute(ctx, inputs, opts...)
+}
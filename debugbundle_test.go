@@ -0,0 +1,67 @@
+package flo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugBundle(t *testing.T) {
+	f := newTestFlo(t)
+	compB := componentNamed(f, "CompB")
+
+	buf := &bytes.Buffer{}
+	_, execErr := f.Execute(context.Background(), map[string]any{"in": -1, "unused": 0}, flo.WithDebugBundle(buf, nil))
+	require.ErrorContains(t, execErr, "f1 is less than zero")
+
+	t.Run("Contents", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(zr.File))
+		for _, file := range zr.File {
+			names = append(names, file.Name)
+		}
+		require.ElementsMatch(t, []string{"manifest.json", "flo.bin", "flo.go", "README.md", "inputs.json", "trace.json"}, names)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		b, err := flo.ImportDebugBundle(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.Fingerprint(), b.Flo.Fingerprint())
+		require.Equal(t, execErr.Error(), b.Err)
+		require.Equal(t, compB.ID, b.ComponentID)
+		require.Equal(t, map[string]json.RawMessage{"in": json.RawMessage("-1"), "unused": json.RawMessage("0")}, b.Inputs)
+
+		require.NotEmpty(t, b.Trace)
+		last := b.Trace[len(b.Trace)-1]
+		require.Equal(t, compB.ID, last.ComponentID)
+		require.Equal(t, compB.Label, last.Label)
+		require.Contains(t, last.Err, "f1 is less than zero")
+		for _, step := range b.Trace[:len(b.Trace)-1] {
+			require.Empty(t, step.Err)
+		}
+
+		_, err = b.Replay(context.Background())
+		require.EqualError(t, err, execErr.Error())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		_, err := f.Execute(context.Background(), map[string]any{"in": 5, "unused": 0}, flo.WithDebugBundle(buf, nil))
+		require.NoError(t, err)
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("Not a debug bundle", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.ExportBundle(buf, nil))
+		_, err := flo.ImportDebugBundle(buf, nil, resolveTestFunc)
+		require.EqualError(t, err, "invalid debug bundle: missing failure")
+	})
+}
@@ -0,0 +1,232 @@
+package flo
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// ComponentDefinition is a function and its metadata, reflected once and
+// instantiated as many components as needed, in one or several flos.
+//
+// Metadata updates done through the definition propagate to all its
+// instances the next time their flo is rendered, encoded, fingerprinted or
+// compared, except for the metadata set on an instance, e.g. with
+// SetComponentLabel, which overrides the one of the definition.
+type ComponentDefinition struct {
+	mu          sync.RWMutex
+	name        string
+	pkgPath     string
+	label       string
+	description string
+	effects     Effects
+	value       reflect.Value
+	ios         IOs // Prototypes copied into every instance.
+}
+
+// NewComponentDefinition reflects fn into a definition.
+func NewComponentDefinition(
+	name, pkgPath string,
+	label, description string,
+	fn any,
+) (*ComponentDefinition, error) {
+	// Reflect through a throwaway component to share the io generation.
+	c, err := NewComponent(name, pkgPath, label, description, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComponentDefinition{
+		name:        c.Name,
+		pkgPath:     c.PkgPath,
+		label:       c.Label,
+		description: c.Description,
+		value:       c.Value,
+		ios:         c.IOs,
+	}, nil
+}
+
+// Name of the function.
+func (d *ComponentDefinition) Name() string {
+	return d.name
+}
+
+// PkgPath of the function.
+func (d *ComponentDefinition) PkgPath() string {
+	return d.pkgPath
+}
+
+// Label of the instances.
+func (d *ComponentDefinition) Label() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.label
+}
+
+// SetLabel updates the label of all the instances.
+func (d *ComponentDefinition) SetLabel(label string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.label = label
+}
+
+// Description of the instances.
+func (d *ComponentDefinition) Description() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.description
+}
+
+// SetDescription updates the description of all the instances.
+func (d *ComponentDefinition) SetDescription(description string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.description = description
+}
+
+// Effects of the function.
+func (d *ComponentDefinition) Effects() Effects {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.effects
+}
+
+// SetEffects updates the effects of all the instances.
+func (d *ComponentDefinition) SetEffects(effects Effects) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.effects = effects
+}
+
+// Instantiate creates a new component from the definition, ready to be
+// added to a flo.
+func (d *ComponentDefinition) Instantiate() *Component {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	c := &Component{
+		ID:          uuid.New(),
+		Name:        d.name,
+		PkgPath:     d.pkgPath,
+		Label:       d.label,
+		Description: d.description,
+		Value:       d.value,
+		Effects:     d.effects,
+		IOs:         make(IOs, 0, len(d.ios)),
+		def:         d,
+	}
+	for _, proto := range d.ios {
+		c.IOs = append(c.IOs, &ComponentIO{
+			ID:       uuid.New(),
+			Name:     proto.Name,
+			Type:     proto.Type,
+			RType:    proto.RType,
+			IsError:  proto.IsError,
+			IsSignal: proto.IsSignal,
+			ParentID: c.ID,
+		})
+	}
+
+	return c
+}
+
+// Definition returns the definition c was instantiated from, if any.
+func (c *Component) Definition() *ComponentDefinition {
+	return c.def
+}
+
+// definitionFields are the metadata of a definition an instance can
+// override.
+type definitionFields uint8
+
+const (
+	definitionLabel definitionFields = 1 << iota
+	definitionDescription
+	definitionEffects
+)
+
+// override keeps fields of c from being pulled from its definition.
+func (c *Component) override(fields definitionFields) {
+	if c.def != nil {
+		c.overrides |= fields
+	}
+}
+
+// syncDefinitions pulls the metadata of the definitions into their
+// instances, unless overridden.
+func (f *Flo) syncDefinitions() {
+	for _, c := range f.Components {
+		d := c.def
+		if d == nil {
+			continue
+		}
+
+		d.mu.RLock()
+		label, description, effects := d.label, d.description, d.effects
+		d.mu.RUnlock()
+
+		if c.overrides&definitionLabel != 0 {
+			label = c.Label
+		}
+		if c.overrides&definitionDescription != 0 {
+			description = c.Description
+		}
+		if c.overrides&definitionEffects != 0 {
+			effects = c.Effects
+		}
+
+		if c.Label == label && c.Description == description && c.Effects == effects {
+			continue
+		}
+		c.Label, c.Description, c.Effects = label, description, effects
+		f.markDirty(c.ID)
+	}
+}
+
+// uniqueIONames renames the out ios of c clashing with the variables of the
+// flo, e.g. when the same function is called several times.
+func (f *Flo) uniqueIONames(c *Component) {
+	if f.ioNames == nil {
+		f.ioNames = make(map[string]struct{})
+		// Out ios of the flo take the name of what feeds them.
+		for _, io := range f.IOs {
+			if io.Type == ComponentIOTypeIN {
+				f.ioNames[io.Name] = struct{}{}
+			}
+		}
+		for _, other := range f.Components {
+			for _, io := range other.IOs {
+				if io.Type == ComponentIOTypeOUT {
+					f.ioNames[io.Name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, io := range c.IOs {
+		if io.Type != ComponentIOTypeOUT {
+			continue
+		}
+
+		// Connected ios already lent their name.
+		name := io.Name
+		for i := 1; len(io.Connections) == 0; i++ {
+			if _, found := f.ioNames[name]; !found {
+				break
+			}
+			name = lo.CamelCase(fmt.Sprintf("io%x", sha1.Sum([]byte(fmt.Sprintf("%s-%d", io.Name, i)))))
+		}
+		io.Name = name
+		f.ioNames[name] = struct{}{}
+	}
+}
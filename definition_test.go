@@ -0,0 +1,96 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentDefinition(t *testing.T) {
+	_, err := flo.NewComponentDefinition("Len", "githab.com/testuf/tera", "Len", "Len Description", 42)
+	require.ErrorContains(t, err, "not a function")
+
+	def, err := flo.NewComponentDefinition("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+
+	f, err := flo.NewFlo("TestDefinition", "Test Definition", "Test Definition Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pA, err := flo.NewComponentIO("a", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pA))
+
+	pB, err := flo.NewComponentIO("b", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pB))
+
+	rA, err := flo.NewComponentIO("lenA", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rA))
+
+	rB, err := flo.NewComponentIO("lenB", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rB))
+
+	first, second := def.Instantiate(), def.Instantiate()
+	require.NotEqual(t, first.ID, second.ID)
+	require.NotEqual(t, first.IOs[0].ID, second.IOs[0].ID)
+	require.Equal(t, second.ID, second.IOs[0].ParentID)
+	require.Same(t, def, first.Definition())
+	require.True(t, first.Equal(second))
+
+	require.NoError(t, f.AddComponent(first))
+	require.NoError(t, f.AddComponent(second))
+	require.NoError(t, f.ConnectComponent(f.ID, pA.ID, first.ID, first.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pB.ID, second.ID, second.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(first.ID, first.IOs[1].ID, f.ID, rA.ID))
+	require.NoError(t, f.ConnectComponent(second.ID, second.IOs[1].ID, f.ID, rB.ID))
+
+	t.Run("Distinct variables", func(t *testing.T) {
+		require.NotEqual(t, first.IOs[1].Name, second.IOs[1].Name)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "return "+first.IOs[1].Name+", "+second.IOs[1].Name+"\n")
+	})
+
+	t.Run("Metadata propagates", func(t *testing.T) {
+		fingerprint := f.Fingerprint()
+		out := &bytes.Buffer{}
+		require.NoError(t, f.RenderIncremental(context.Background(), out))
+
+		def.SetDescription("Length of the string")
+		require.Equal(t, "Length of the string", def.Description())
+
+		out.Reset()
+		require.NoError(t, f.RenderIncremental(context.Background(), out))
+		require.Equal(t, 2, strings.Count(out.String(), "// Length of the string\n"))
+		require.Equal(t, "Length of the string", second.Description)
+		require.NotEqual(t, fingerprint, f.Fingerprint())
+	})
+
+	t.Run("Instance overrides", func(t *testing.T) {
+		require.NoError(t, f.SetComponentLabel(first.ID, "Custom"))
+		require.NoError(t, f.SetComponentDescription(first.ID, "Custom length"))
+		require.NoError(t, f.SetEffects(first.ID, flo.EffectPure))
+
+		def.SetLabel("Length")
+		def.SetDescription("Counts the bytes")
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, "Custom", first.Label)
+		require.Equal(t, "Custom length", first.Description)
+		require.Equal(t, flo.EffectPure, first.Effects)
+		require.Contains(t, out.String(), "// Custom length\n")
+
+		require.Equal(t, "Length", second.Label)
+		require.Equal(t, "Counts the bytes", second.Description)
+		require.Contains(t, out.String(), "// Counts the bytes\n")
+	})
+}
@@ -0,0 +1,262 @@
+package flo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TraceStep records the run of a component.
+type TraceStep struct {
+	ComponentID uuid.UUID
+	Duration    time.Duration
+	Err         string // Empty unless the component failed.
+}
+
+// Trace records an execution of a flo, e.g. by hooks added with
+// WithBeforeComponent and WithAfterComponent, in execution order.
+type Trace []TraceStep
+
+// traceNode sums up the steps of a component.
+type traceNode struct {
+	duration time.Duration
+	err      string
+}
+
+func (t Trace) nodes() map[uuid.UUID]traceNode {
+	nodes := make(map[uuid.UUID]traceNode, len(t))
+	for _, step := range t {
+		n := nodes[step.ComponentID]
+		n.duration += step.Duration
+		if step.Err != "" {
+			n.err = step.Err
+		}
+		nodes[step.ComponentID] = n
+	}
+
+	return nodes
+}
+
+// diagramNode is a node of a diagram of a flo.
+type diagramNode struct {
+	id          string
+	label       string
+	description string
+	io          bool // A param or a result of the flo.
+	state       string
+}
+
+// Diagram node states, when drawn along a trace.
+const (
+	stateExecuted = "executed"
+	stateFailed   = "failed"
+	stateSkipped  = "skipped"
+)
+
+type diagramEdge struct {
+	from, to string
+	label    string
+	sequence bool
+}
+
+// diagram lays out the flo as nodes and edges, along trace when not nil.
+func (f *Flo) diagram(trace Trace) (meta diagramNode, nodes []diagramNode, edges []diagramEdge) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make(map[uuid.UUID]string, len(f.Components)+len(f.IOs))
+	ins, outs := f.IOs.SeparateINsOUTs()
+	for i, in := range ins {
+		ids[in.ID] = "in" + strconv.Itoa(i)
+		nodes = append(nodes, diagramNode{id: ids[in.ID], label: in.Name + " " + in.RType.String(), description: in.Description, io: true})
+	}
+
+	var steps map[uuid.UUID]traceNode
+	if trace != nil {
+		steps = trace.nodes()
+	}
+	for i, c := range f.orderedComponents() {
+		ids[c.ID] = "c" + strconv.Itoa(i)
+		n := diagramNode{id: ids[c.ID], label: c.Label, description: c.Description}
+		switch {
+		case c.Label == "":
+			n.label = c.Name
+		case c.Kind == ComponentKindFunc && c.PkgPath != "":
+			n.label += "\n" + packageName(c.PkgPath) + "." + c.Name
+		}
+		if steps != nil {
+			step, found := steps[c.ID]
+			switch {
+			case !found:
+				n.state = stateSkipped
+			case step.err != "":
+				n.state = stateFailed
+				n.label += "\n" + step.duration.String() + "\nerror: " + step.err
+			default:
+				n.state = stateExecuted
+				n.label += "\n" + step.duration.String()
+			}
+		}
+		nodes = append(nodes, n)
+	}
+
+	for i, out := range outs {
+		ids[out.ID] = "out" + strconv.Itoa(i)
+		nodes = append(nodes, diagramNode{id: ids[out.ID], label: out.RType.String(), description: out.Description, io: true})
+	}
+
+	from := func(conn *ComponentConnection) string {
+		if conn.OutComponentID == f.ID {
+			return ids[conn.OutComponentIOID]
+		}
+		return ids[conn.OutComponentID]
+	}
+	to := func(conn *ComponentConnection) string {
+		if conn.InComponentID == f.ID {
+			return ids[conn.InComponentIOID]
+		}
+		return ids[conn.InComponentID]
+	}
+	visit := func(io *ComponentIO) {
+		for _, conn := range io.Connections {
+			edges = append(edges, diagramEdge{from: from(conn), to: to(conn), label: io.RType.String()})
+		}
+	}
+	for _, in := range ins {
+		visit(in)
+	}
+	for _, c := range f.orderedComponents() {
+		_, outs := c.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			visit(out)
+		}
+	}
+	for _, conn := range f.Sequences {
+		edges = append(edges, diagramEdge{from: from(conn), to: to(conn), sequence: true})
+	}
+
+	return diagramNode{id: f.Name, label: f.Label, description: f.Description}, nodes, edges
+}
+
+// DOTRenderer renders flos as Graphviz DOT diagrams. Along a Trace, the
+// executed components are highlighted with their duration, the failed ones
+// marked with their error and the others greyed out.
+type DOTRenderer struct {
+	Trace Trace
+}
+
+var _ Renderer = DOTRenderer{}
+
+// Render implements Renderer.
+func (r DOTRenderer) Render(_ context.Context, f *Flo, w io.Writer) error {
+	meta, nodes, edges := f.diagram(r.Trace)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n\trankdir=LR;\n", strconv.Quote(meta.id))
+	if meta.label != "" {
+		fmt.Fprintf(&sb, "\tlabel=%s;\n\tlabelloc=t;\n", strconv.Quote(meta.label))
+	}
+	if meta.description != "" {
+		fmt.Fprintf(&sb, "\ttooltip=%s;\n", strconv.Quote(meta.description))
+	}
+	sb.WriteString("\tnode [shape=box];\n")
+	for _, n := range nodes {
+		attrs := []string{"label=" + strconv.Quote(n.label)}
+		if n.description != "" {
+			// Shown on hover by the SVG output.
+			attrs = append(attrs, "tooltip="+strconv.Quote(n.description))
+		}
+		if n.io {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		switch n.state {
+		case stateExecuted:
+			attrs = append(attrs, "style=filled", "fillcolor=palegreen")
+		case stateFailed:
+			attrs = append(attrs, "style=filled", "fillcolor=salmon", "penwidth=2")
+		case stateSkipped:
+			attrs = append(attrs, "color=gray", "fontcolor=gray")
+		}
+		fmt.Fprintf(&sb, "\t%s [%s];\n", n.id, strings.Join(attrs, ", "))
+	}
+	for _, e := range edges {
+		if e.sequence {
+			fmt.Fprintf(&sb, "\t%s -> %s [style=dashed];\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(&sb, "\t%s -> %s [label=%s];\n", e.from, e.to, strconv.Quote(e.label))
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderDOT writes the Graphviz digraph of the flo to w, e.g. to inspect
+// large flos before generating their code:
+//
+//	dot -Tsvg shout.dot > shout.svg
+func (f *Flo) RenderDOT(w io.Writer) error {
+	return DOTRenderer{}.Render(context.Background(), f, w)
+}
+
+// MermaidRenderer renders flos as Mermaid flowcharts, highlighting a Trace
+// like DOTRenderer.
+type MermaidRenderer struct {
+	Trace Trace
+}
+
+var _ Renderer = MermaidRenderer{}
+
+// Render implements Renderer.
+func (r MermaidRenderer) Render(_ context.Context, f *Flo, w io.Writer) error {
+	_, nodes, edges := f.diagram(r.Trace)
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, n := range nodes {
+		if n.io {
+			fmt.Fprintf(&sb, "\t%s([%s])\n", n.id, mermaidLabel(n.label))
+			continue
+		}
+		fmt.Fprintf(&sb, "\t%s[%s]\n", n.id, mermaidLabel(n.label))
+	}
+	for _, e := range edges {
+		if e.sequence {
+			fmt.Fprintf(&sb, "\t%s -.-> %s\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(&sb, "\t%s -->|%s| %s\n", e.from, mermaidLabel(e.label), e.to)
+	}
+
+	if r.Trace != nil {
+		sb.WriteString("\tclassDef executed fill:#9f9\n")
+		sb.WriteString("\tclassDef failed fill:#f99,stroke:#c00,stroke-width:2px\n")
+		sb.WriteString("\tclassDef skipped color:#999,stroke:#999\n")
+		for _, state := range []string{stateExecuted, stateFailed, stateSkipped} {
+			var ids []string
+			for _, n := range nodes {
+				if n.state == state {
+					ids = append(ids, n.id)
+				}
+			}
+			if len(ids) > 0 {
+				fmt.Fprintf(&sb, "\tclass %s %s\n", strings.Join(ids, ","), state)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidLabel quotes label for Mermaid.
+func mermaidLabel(label string) string {
+	label = strings.ReplaceAll(label, `"`, "#quot;")
+	return `"` + strings.ReplaceAll(label, "\n", "<br/>") + `"`
+}
@@ -0,0 +1,93 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagrams(t *testing.T) {
+	f, err := flo.NewFlo("Shout", "Shout", "Shout Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, out := flo.In[string]("s"), flo.Out[string]("res")
+	require.NoError(t, f.AddIO(in))
+	require.NoError(t, f.AddIO(out))
+
+	newComponent := func(name string, fn any) *flo.Component {
+		c, err := flo.NewComponent(name, "strings", name, name+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		return c
+	}
+	trim, upper, lower := newComponent("TrimSpace", strings.TrimSpace), newComponent("ToUpper", strings.ToUpper), newComponent("ToLower", strings.ToLower)
+
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, trim.ID, trim.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, upper.ID, upper.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(upper.ID, upper.IOs[1].ID, f.ID, out.ID))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, lower.ID, lower.IOs[0].ID))
+	require.NoError(t, f.ConnectSequence(upper.ID, lower.ID))
+
+	trace := flo.Trace{
+		{ComponentID: trim.ID, Duration: 2 * time.Millisecond},
+		{ComponentID: upper.ID, Duration: 5 * time.Millisecond, Err: `bad "input"`},
+	}
+
+	t.Run("DOT", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.RenderDOT(buf))
+		require.Equal(t, `digraph "Shout" {
+	rankdir=LR;
+	label="Shout";
+	labelloc=t;
+	tooltip="Shout Description";
+	node [shape=box];
+	in0 [label="s string", shape=ellipse];
+	c0 [label="TrimSpace\nstrings.TrimSpace", tooltip="TrimSpace Description"];
+	c1 [label="ToUpper\nstrings.ToUpper", tooltip="ToUpper Description"];
+	c2 [label="ToLower\nstrings.ToLower", tooltip="ToLower Description"];
+	out0 [label="string", shape=ellipse];
+	in0 -> c0 [label="string"];
+	in0 -> c2 [label="string"];
+	c0 -> c1 [label="string"];
+	c1 -> out0 [label="string"];
+	c1 -> c2 [style=dashed];
+}
+`, buf.String())
+
+		buf.Reset()
+		require.NoError(t, flo.DOTRenderer{Trace: trace}.Render(context.Background(), f, buf))
+		dot := buf.String()
+		require.Contains(t, dot, `c0 [label="TrimSpace\nstrings.TrimSpace\n2ms", tooltip="TrimSpace Description", style=filled, fillcolor=palegreen];`)
+		require.Contains(t, dot, `c1 [label="ToUpper\nstrings.ToUpper\n5ms\nerror: bad \"input\"", tooltip="ToUpper Description", style=filled, fillcolor=salmon, penwidth=2];`)
+		require.Contains(t, dot, `c2 [label="ToLower\nstrings.ToLower", tooltip="ToLower Description", color=gray, fontcolor=gray];`)
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, flo.MermaidRenderer{Trace: trace}.Render(context.Background(), f, buf))
+		require.Equal(t, `flowchart LR
+	in0(["s string"])
+	c0["TrimSpace<br/>strings.TrimSpace<br/>2ms"]
+	c1["ToUpper<br/>strings.ToUpper<br/>5ms<br/>error: bad #quot;input#quot;"]
+	c2["ToLower<br/>strings.ToLower"]
+	out0(["string"])
+	in0 -->|"string"| c0
+	in0 -->|"string"| c2
+	c0 -->|"string"| c1
+	c1 -->|"string"| out0
+	c1 -.-> c2
+	classDef executed fill:#9f9
+	classDef failed fill:#f99,stroke:#c00,stroke-width:2px
+	classDef skipped color:#999,stroke:#999
+	class c0 executed
+	class c1 failed
+	class c2 skipped
+`, buf.String())
+	})
+}
@@ -0,0 +1,399 @@
+package flo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flosecret"
+)
+
+// DumpOption configures PrettyDump.
+type DumpOption func(*dumpOptions)
+
+type dumpOptions struct {
+	maxDepth       int
+	redactedFields map[string]struct{}
+	redactedTags   []string
+	omitInternals  bool
+	readable       bool
+}
+
+// WithMaxDepth collapses the structs, maps, slices and arrays nested more
+// than n levels deep.
+func WithMaxDepth(n int) DumpOption {
+	return func(o *dumpOptions) {
+		o.maxDepth = n
+	}
+}
+
+// WithRedactedFields redacts the struct fields named after names, at any
+// depth, e.g. "Literal" and "Fallback" to hide the constants of a flo.
+// Secrets are always redacted.
+func WithRedactedFields(names ...string) DumpOption {
+	return func(o *dumpOptions) {
+		for _, name := range names {
+			o.redactedFields[name] = struct{}{}
+		}
+	}
+}
+
+// WithRedactedTags redacts the struct fields whose tag has one of keys, at
+// any depth, e.g. "secret" for the fields of literals tagged `secret:""`.
+func WithRedactedTags(keys ...string) DumpOption {
+	return func(o *dumpOptions) {
+		o.redactedTags = append(o.redactedTags, keys...)
+	}
+}
+
+// WithoutInternals dumps reflect.Type and reflect.Value as the types and
+// values they stand for, and omits unexported fields such as locks and
+// caches.
+func WithoutInternals() DumpOption {
+	return func(o *dumpOptions) {
+		o.omitInternals = true
+	}
+}
+
+// WithReadableValues dumps ids as strings and named values implementing
+// fmt.Stringer, e.g. enums, as their String along with their type.
+func WithReadableValues() DumpOption {
+	return func(o *dumpOptions) {
+		o.readable = true
+	}
+}
+
+// PrettyDump writes a human readable dump of the flo to w. Without options,
+// the dump is the one of godump, map entries being sorted by key and secrets
+// redacted.
+func (f *Flo) PrettyDump(w io.Writer, opts ...DumpOption) error {
+	o := dumpOptions{
+		redactedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	f.mu.Lock()
+	d := dumper{o: o, ptrs: make(map[uintptr]int)}
+	d.dump(reflect.ValueOf(f), 0)
+	f.mu.Unlock()
+
+	_, err := d.buf.WriteTo(w)
+	return err
+}
+
+const dumpIndent = "   "
+
+var (
+	uuidRType         = reflect.TypeFor[uuid.UUID]()
+	reflectTypeRType  = reflect.TypeFor[reflect.Type]()
+	reflectValueRType = reflect.TypeFor[reflect.Value]()
+)
+
+type dumper struct {
+	o    dumpOptions
+	buf  bytes.Buffer
+	ptrs map[uintptr]int // Pointers already dumped, by tag.
+	tag  int             // Tag of the pointer being dumped, if any.
+}
+
+func (d *dumper) dump(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		d.buf.WriteString("nil")
+		return
+	}
+
+	t := v.Type()
+	switch {
+	case t == secretRType:
+		d.buf.WriteString(flosecret.Redacted)
+		return
+	case d.o.readable && t == uuidRType:
+		// Read byte by byte as v may be an unexported field.
+		var id uuid.UUID
+		for i := range id {
+			id[i] = byte(v.Index(i).Uint())
+		}
+		fmt.Fprintf(&d.buf, "uuid.UUID(%q)", id)
+		return
+	case d.o.omitInternals && t == reflectTypeRType:
+		if v.IsNil() {
+			d.buf.WriteString("reflect.Type(nil)")
+			return
+		}
+		fmt.Fprintf(&d.buf, "reflect.Type(%s)", v.Interface())
+		return
+	case d.o.omitInternals && t == reflectValueRType && v.CanInterface():
+		rv := v.Interface().(reflect.Value)
+		if rv.IsValid() && rv.Kind() == reflect.Func {
+			fmt.Fprintf(&d.buf, "reflect.Value(%s)", rv.Type())
+			return
+		}
+		d.buf.WriteString("reflect.Value(")
+		d.dump(rv, depth)
+		d.buf.WriteString(")")
+		return
+	}
+
+	if s, ok := basicStringer(v); ok && d.o.readable {
+		fmt.Fprintf(&d.buf, "%s(%s)", t, s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		d.buf.WriteString(`"` + v.String() + `"`)
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		fmt.Fprint(&d.buf, v)
+	case reflect.Uintptr:
+		fmt.Fprintf(&d.buf, "0x%x", v.Uint())
+	case reflect.Func:
+		d.buf.WriteString(t.String())
+		if v.IsNil() {
+			d.buf.WriteString("(nil)")
+		}
+	case reflect.Chan:
+		d.buf.WriteString(t.String())
+		if v.IsNil() {
+			d.buf.WriteString("(nil)")
+		}
+		if c := v.Cap(); c > 0 {
+			fmt.Fprintf(&d.buf, "<%d>", c)
+		}
+	case reflect.UnsafePointer:
+		fmt.Fprintf(&d.buf, "%s(0x%x)", t, v.Pointer())
+	case reflect.Interface:
+		d.dump(v.Elem(), depth)
+	case reflect.Pointer:
+		d.dumpPointer(v, depth)
+	case reflect.Struct:
+		d.dumpStruct(v, depth)
+	case reflect.Map:
+		d.dumpMap(v, depth)
+	case reflect.Slice, reflect.Array:
+		d.dumpSlice(v, depth)
+	}
+}
+
+func (d *dumper) dumpPointer(v reflect.Value, depth int) {
+	if v.IsNil() {
+		fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+		return
+	}
+
+	// Pointers to basic values are not tagged.
+	if isBasic(v.Elem()) {
+		d.buf.WriteString("&")
+		d.dump(v.Elem(), depth)
+		return
+	}
+
+	addr := v.Pointer()
+	if tag, found := d.ptrs[addr]; found {
+		fmt.Fprintf(&d.buf, "&@%d", tag)
+		return
+	}
+	d.buf.WriteString("&")
+	// Collapsed values are not tagged so that they are dumped in full
+	// wherever they are not too deep.
+	if !d.tooDeep(depth) {
+		d.ptrs[addr] = len(d.ptrs) + 1
+		d.tag = d.ptrs[addr]
+	}
+	d.dump(v.Elem(), depth)
+	d.tag = 0
+}
+
+// isBasic reports whether v is neither a struct, map, slice nor array, nor
+// an interface or pointer to one.
+func isBasic(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+
+	return true
+}
+
+func (d *dumper) tooDeep(depth int) bool {
+	return d.o.maxDepth > 0 && depth >= d.o.maxDepth
+}
+
+// basicStringer returns the String of v when v is of a named basic type,
+// e.g. an enum, implementing fmt.Stringer.
+func basicStringer(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Struct, reflect.Map, reflect.Slice, reflect.Array,
+		reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return "", false
+	}
+	if v.Type().PkgPath() == "" || !v.CanInterface() {
+		return "", false
+	}
+	s, ok := v.Interface().(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+
+	return s.String(), true
+}
+
+// open writes the opening brace of a struct, map, slice or array along with
+// the tag of the pointer to it, if any.
+func (d *dumper) open() {
+	d.buf.WriteString(" {")
+	d.writeTag()
+}
+
+func (d *dumper) writeTag() {
+	if d.tag != 0 {
+		fmt.Fprintf(&d.buf, "#%d", d.tag)
+		d.tag = 0
+	}
+}
+
+// collapsed writes the summary of v when too deep.
+func (d *dumper) collapsed(v reflect.Value, depth int) bool {
+	if !d.tooDeep(depth) {
+		return false
+	}
+
+	d.buf.WriteString(v.Type().String())
+	if k := v.Kind(); k == reflect.Map || k == reflect.Slice {
+		fmt.Fprintf(&d.buf, ":%d", v.Len())
+	}
+	d.buf.WriteString(" {…}")
+	d.tag = 0
+
+	return true
+}
+
+func (d *dumper) dumpStruct(v reflect.Value, depth int) {
+	if d.collapsed(v, depth) {
+		return
+	}
+
+	t := v.Type()
+	if t.Name() == "" {
+		d.buf.WriteString("struct")
+	} else {
+		d.buf.WriteString(t.String())
+	}
+	d.open()
+	var hasFields bool
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && d.o.omitInternals {
+			continue
+		}
+		hasFields = true
+
+		d.line(depth + 1)
+		d.buf.WriteString(field.Name)
+		d.buf.WriteString(": ")
+		if d.redacted(field) {
+			d.buf.WriteString(flosecret.Redacted)
+		} else {
+			d.dump(v.Field(i), depth+1)
+		}
+		d.buf.WriteString(",")
+	}
+	if hasFields {
+		d.line(depth)
+	}
+	d.buf.WriteString("}")
+}
+
+// redacted reports whether field is redacted by name or tag.
+func (d *dumper) redacted(field reflect.StructField) bool {
+	if _, found := d.o.redactedFields[field.Name]; found {
+		return true
+	}
+
+	return slices.ContainsFunc(d.o.redactedTags, func(key string) bool {
+		_, found := field.Tag.Lookup(key)
+		return found
+	})
+}
+
+func (d *dumper) dumpMap(v reflect.Value, depth int) {
+	if v.IsNil() {
+		fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+		d.writeTag()
+		return
+	}
+	if d.collapsed(v, depth) {
+		return
+	}
+
+	// Entries are sorted by key for dumps to be stable.
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		kd := dumper{o: d.o, ptrs: d.ptrs}
+		kd.dump(iter.Key(), depth+1)
+		entries = append(entries, entry{key: kd.buf.String(), value: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	fmt.Fprintf(&d.buf, "%s:%d", v.Type(), v.Len())
+	d.open()
+	for _, e := range entries {
+		d.line(depth + 1)
+		d.buf.WriteString(e.key)
+		d.buf.WriteString(": ")
+		d.dump(e.value, depth+1)
+		d.buf.WriteString(",")
+	}
+	if len(entries) > 0 {
+		d.line(depth)
+	}
+	d.buf.WriteString("}")
+}
+
+func (d *dumper) dumpSlice(v reflect.Value, depth int) {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+		d.writeTag()
+		return
+	}
+	if d.collapsed(v, depth) {
+		return
+	}
+
+	d.buf.WriteString(v.Type().String())
+	if v.Kind() == reflect.Slice {
+		fmt.Fprintf(&d.buf, ":%d:%d", v.Len(), v.Cap())
+	}
+	d.open()
+	for i := range v.Len() {
+		d.line(depth + 1)
+		d.dump(v.Index(i), depth+1)
+		d.buf.WriteString(",")
+	}
+	if v.Len() > 0 {
+		d.line(depth)
+	}
+	d.buf.WriteString("}")
+}
+
+func (d *dumper) line(depth int) {
+	d.buf.WriteString("\n")
+	d.buf.WriteString(strings.Repeat(dumpIndent, depth))
+}
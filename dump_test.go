@@ -0,0 +1,81 @@
+package flo_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type DumpCredentials struct {
+	User     string
+	Password string `secret:""`
+}
+
+func TestPrettyDump(t *testing.T) {
+	f := newTestFlo(t)
+
+	dump := func(opts ...flo.DumpOption) string {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.PrettyDump(out, opts...))
+		return out.String()
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		// Same as godump, map entries aside.
+		out := dump()
+		require.True(t, strings.HasPrefix(out, "&flo.Flo {#1\n   mu: sync.Mutex {\n"))
+		require.Contains(t, out, `Name: "TestSync",`)
+		require.Contains(t, out, fmt.Sprintf("ID: uuid.UUID {\n      %d,\n", f.ID[0]))
+		require.Contains(t, out, "Kind: FUNC,")
+		require.Regexp(t, `\n   IOs: flo.IOs:5:\d+ \{\n`, out)
+		require.Equal(t, out, dump(), "dumps must be stable")
+	})
+
+	t.Run("Readable values", func(t *testing.T) {
+		out := dump(flo.WithReadableValues())
+		require.Contains(t, out, `ID: uuid.UUID("`+f.ID.String()+`"),`)
+		require.Contains(t, out, "Kind: flo.ComponentKind(FUNC),")
+	})
+
+	t.Run("Without internals", func(t *testing.T) {
+		out := dump(flo.WithoutInternals())
+		require.NotContains(t, out, "mu:")
+		require.Contains(t, out, "RType: reflect.Type(context.Context),")
+		require.Contains(t, out, "Value: reflect.Value(func(context.Context, int) int),")
+		require.Less(t, len(out), len(dump()))
+	})
+
+	t.Run("Max depth", func(t *testing.T) {
+		out := dump(flo.WithMaxDepth(1))
+		require.Contains(t, out, `Name: "TestSync",`)
+		require.Contains(t, out, "Components: map[uuid.UUID]*flo.Component:5 {…},")
+		require.NotContains(t, out, "CompA")
+	})
+
+	t.Run("Redacted fields", func(t *testing.T) {
+		out := dump(flo.WithRedactedFields("Description", "PkgDescription"))
+		require.Contains(t, out, "Description: [REDACTED],")
+		require.Contains(t, out, "PkgDescription: [REDACTED],")
+		require.NotContains(t, out, "Test Flo Description")
+		require.NotContains(t, out, "Test Comp A Description")
+	})
+
+	t.Run("Redacted tags", func(t *testing.T) {
+		g, err := flo.NewFlo("Login", "Login", "Login", "flo", "Login")
+		require.NoError(t, err)
+		c, err := flo.NewComponent("Login", "githab.com/testuf/tera", "Login", "Login", func(DumpCredentials) bool { return true })
+		require.NoError(t, err)
+		require.NoError(t, g.AddComponent(c))
+		require.NoError(t, g.SetLiteral(c.ID, c.IOs[0].ID, DumpCredentials{User: "jdoe", Password: "hunter2"}))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, g.PrettyDump(out, flo.WithoutInternals(), flo.WithRedactedTags("secret")))
+		require.Contains(t, out.String(), `User: "jdoe",`)
+		require.Contains(t, out.String(), "Password: [REDACTED],")
+		require.NotContains(t, out.String(), "hunter2")
+	})
+}
@@ -0,0 +1,178 @@
+package flo
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Effects describes the side effects of a component. The zero value means
+// the effects are unknown.
+type Effects uint8
+
+const (
+	// EffectPure components only compute their results from their inputs.
+	EffectPure Effects = 1 << iota
+	EffectReadsState
+	EffectWritesState
+	EffectNetwork
+	EffectFilesystem
+)
+
+// IsKnown reports whether the effects were set or inferred.
+func (e Effects) IsKnown() bool {
+	return e != 0
+}
+
+// IsPure reports whether the component is known to be pure.
+func (e Effects) IsPure() bool {
+	return e == EffectPure
+}
+
+// Has reports whether all the effects of o are part of e.
+func (e Effects) Has(o Effects) bool {
+	return e&o == o
+}
+
+// mayAssumePure reports whether optimizations assuming purity may apply:
+// either the component is pure, or nothing is known about it and the
+// caller opted in.
+func (e Effects) mayAssumePure() bool {
+	return !e.IsKnown() || e.IsPure()
+}
+
+func (e Effects) String() string {
+	if !e.IsKnown() {
+		return "UNKNOWN"
+	}
+
+	var names []string
+	for _, effect := range []struct {
+		e    Effects
+		name string
+	}{
+		{EffectPure, "PURE"},
+		{EffectReadsState, "READS_STATE"},
+		{EffectWritesState, "WRITES_STATE"},
+		{EffectNetwork, "NETWORK"},
+		{EffectFilesystem, "FILESYSTEM"},
+	} {
+		if e.Has(effect.e) {
+			names = append(names, effect.name)
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+// purePkgs hold functions without side effects.
+var purePkgs = map[string]struct{}{
+	"bytes":                              {},
+	"encoding/base64":                    {},
+	"encoding/hex":                       {},
+	"encoding/json":                      {},
+	"math":                               {},
+	"path":                               {},
+	"regexp":                             {},
+	"slices":                             {},
+	"sort":                               {},
+	"strconv":                            {},
+	"strings":                            {},
+	"unicode":                            {},
+	"unicode/utf8":                       {},
+	"github.com/mgjules/flo/flocodec":    {},
+	"github.com/mgjules/flo/flovalidate": {},
+}
+
+// pkgEffects are the effects of functions of well known packages.
+var pkgEffects = map[string]Effects{
+	"net":           EffectNetwork,
+	"net/http":      EffectNetwork,
+	"net/smtp":      EffectNetwork,
+	flohttpPkg:      EffectNetwork,
+	flobrokerPkg:    EffectNetwork,
+	flosecretPkg:    EffectReadsState,
+	"os":            EffectFilesystem | EffectReadsState | EffectWritesState,
+	"io/fs":         EffectFilesystem | EffectReadsState,
+	"io/ioutil":     EffectFilesystem | EffectReadsState | EffectWritesState,
+	"path/filepath": EffectFilesystem | EffectReadsState,
+	"database/sql":  EffectReadsState | EffectWritesState,
+	flosqlPkg:       EffectReadsState | EffectWritesState,
+	"math/rand":     EffectReadsState | EffectWritesState,
+	"time":          EffectReadsState,
+}
+
+var (
+	httpClientRType = reflect.TypeFor[*http.Client]()
+	osFileRType     = reflect.TypeFor[*os.File]()
+	fsRType         = reflect.TypeFor[fs.FS]()
+	readerRType     = reflect.TypeFor[io.Reader]()
+	writerRType     = reflect.TypeFor[io.Writer]()
+	contextRType    = reflect.TypeFor[context.Context]()
+)
+
+// InferEffects guesses the effects of c from its package and the types of
+// its inputs. Effects can't be inferred for every component in which case
+// they stay unknown.
+func InferEffects(c *Component) Effects {
+	switch c.Kind {
+	case ComponentKindJoin, ComponentKindGuard, ComponentKindLiteral:
+		return EffectPure
+	case ComponentKindSource:
+		// Sources produce values out of nowhere.
+		return EffectReadsState
+	}
+
+	var effects Effects
+	if e, found := pkgEffects[c.PkgPath]; found {
+		effects |= e
+	}
+
+	ins, _ := c.IOs.SeparateINsOUTs()
+	for _, in := range ins {
+		t := in.RType
+		switch {
+		case t == httpClientRType:
+			effects |= EffectNetwork
+		case t == osFileRType, t.Implements(fsRType):
+			effects |= EffectFilesystem
+		case t.Kind() == reflect.Chan:
+			effects |= EffectReadsState | EffectWritesState
+		case t.Kind() == reflect.Interface && t.Implements(writerRType):
+			effects |= EffectWritesState
+		case t.Kind() == reflect.Interface && t.Implements(readerRType):
+			effects |= EffectReadsState
+		case t == contextRType:
+			// Taking a context hints at I/O of some sort.
+			effects |= EffectReadsState
+		}
+	}
+	if effects.IsKnown() {
+		return effects
+	}
+
+	if _, found := purePkgs[c.PkgPath]; found {
+		return EffectPure
+	}
+
+	return 0
+}
+
+// InferEffects annotates the components whose effects are unknown with
+// their inferred effects.
+func (f *Flo) InferEffects() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.Components {
+		if !c.Effects.IsKnown() {
+			c.Effects = InferEffects(c)
+			c.override(definitionEffects)
+		}
+	}
+	f.markAllDirty()
+}
@@ -0,0 +1,74 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffects(t *testing.T) {
+	require.Equal(t, "UNKNOWN", flo.Effects(0).String())
+	require.Equal(t, "READS_STATE|NETWORK", (flo.EffectReadsState | flo.EffectNetwork).String())
+
+	t.Run("Infer", func(t *testing.T) {
+		for _, tc := range []struct {
+			name, pkgPath string
+			fn            any
+			want          flo.Effects
+		}{
+			{"TrimSpace", "strings", strings.TrimSpace, flo.EffectPure},
+			{"Get", "net/http", http.Get, flo.EffectNetwork},
+			{"ReadFile", "os", os.ReadFile, flo.EffectFilesystem | flo.EffectReadsState | flo.EffectWritesState},
+			{"Fprint", "fmt", fmt.Fprint, flo.EffectWritesState},
+			{"ReadAll", "io", io.ReadAll, flo.EffectReadsState},
+			{"Len", "githab.com/testuf/tera", lenFn, 0},
+		} {
+			c, err := flo.NewComponent(tc.name, tc.pkgPath, tc.name, tc.name, tc.fn)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, flo.InferEffects(c), tc.name)
+		}
+	})
+
+	f, err := flo.NewFlo("TestEffects", "Test Effects", "Test Effects Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	var lens []*flo.Component
+	for range 2 {
+		c, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		c.Effects = flo.EffectWritesState
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[0].ID, "hello"))
+		lens = append(lens, c)
+	}
+
+	t.Run("Not merged", func(t *testing.T) {
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+	})
+
+	t.Run("Not folded", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithConstantFolding()))
+		require.Contains(t, out.String(), `tera.Len("hello")`)
+	})
+
+	t.Run("Pure", func(t *testing.T) {
+		for _, c := range lens {
+			c.Effects = flo.EffectPure
+		}
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+	})
+}
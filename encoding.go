@@ -0,0 +1,661 @@
+package flo
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flosecret"
+)
+
+// ResolveFunc returns the function backing the component or transform
+// identified by pkgPath and name.
+type ResolveFunc func(pkgPath, name string) (any, error)
+
+// floData is the serializable form of a flo.
+// Types are interned in Types and referenced by index to keep snapshots of
+// large graphs small.
+type floData struct {
+	ID             uuid.UUID
+	Name           string
+	Label          string
+	Description    string
+	PkgName        string
+	PkgDescription string
+	Owner          string
+	Team           string
+	Types          []string
+	IOs            []ioData
+	Components     []componentData
+	Connections    []connectionData
+}
+
+type componentData struct {
+	ID           uuid.UUID
+	Name         string
+	PkgPath      string
+	Label        string
+	Description  string
+	Kind         ComponentKind
+	Branches     int
+	Message      string
+	ErrorPolicy  ErrorPolicy
+	Cache        *CacheAside
+	Effects      Effects
+	AllowReorder bool
+	Position     Position
+	Variants     []string
+	Flag         *FlagGate
+	Owner        string
+	Team         string
+	Cost         Cost
+	Receiver     *receiverData
+	Sub          *floData
+	TypeArgs     []int
+	IOs          []ioData
+}
+
+type receiverData struct {
+	Name        string
+	Type        int
+	Constructor string
+}
+
+type ioData struct {
+	ID      uuid.UUID
+	Name    string
+	Type    ComponentIOType
+	RType   int
+	Literal json.RawMessage `json:",omitempty"`
+	Owns    bool
+	Defer   bool
+	Multi   bool
+	Max     int
+	Order   []uuid.UUID // Connections of a multi io, in order.
+	Pos     Position
+	Label   string
+	Desc    string
+	Result  string
+}
+
+type connectionData struct {
+	ID               uuid.UUID
+	Kind             ComponentConnectionKind
+	OutComponentID   uuid.UUID
+	OutComponentIOID uuid.UUID
+	InComponentID    uuid.UUID
+	InComponentIOID  uuid.UUID
+	Transform        *transformData
+	Order            int
+	Fallback         json.RawMessage `json:",omitempty"`
+}
+
+type transformData struct {
+	Name    string
+	PkgPath string
+	Expr    string
+	Imports []string `json:",omitempty"`
+	In      int
+	Out     int
+}
+
+// EncodeBinary writes a compact binary snapshot of the flo, meant to be
+// taken frequently, e.g. for undo history or checkpoints.
+// All the named types used by the flo must be known to types.
+// A nil types uses DefaultTypeRegistry.
+func (f *Flo) EncodeBinary(w io.Writer, types *TypeRegistry) error {
+	data, err := f.data(types)
+	if err != nil {
+		return err
+	}
+
+	return gob.NewEncoder(w).Encode(data)
+}
+
+// EncodeJSON writes the flo as indented JSON, meant to persist the work of
+// users, e.g. in files diffed by version control.
+// All the named types used by the flo must be known to types.
+// A nil types uses DefaultTypeRegistry.
+func (f *Flo) EncodeJSON(w io.Writer, types *TypeRegistry) error {
+	data, err := f.data(types)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	return enc.Encode(data)
+}
+
+// DecodeJSON reads a flo written by EncodeJSON.
+// Component and transform functions are rebound using resolve; with a nil
+// resolve components are left unbound.
+// A nil types uses DefaultTypeRegistry.
+func DecodeJSON(r io.Reader, types *TypeRegistry, resolve ResolveFunc) (*Flo, error) {
+	var data floData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("cannot decode flo: %v", err)
+	}
+
+	return data.flo(types, resolve)
+}
+
+// DecodeBinary reads a snapshot written by EncodeBinary.
+// Component and transform functions are rebound using resolve; with a nil
+// resolve components are left unbound.
+// A nil types uses DefaultTypeRegistry.
+func DecodeBinary(r io.Reader, types *TypeRegistry, resolve ResolveFunc) (*Flo, error) {
+	var data floData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("cannot decode flo: %v", err)
+	}
+
+	return data.flo(types, resolve)
+}
+
+func (f *Flo) data(types *TypeRegistry) (*floData, error) {
+	if types == nil {
+		types = DefaultTypeRegistry
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.syncDefinitions()
+	typeIndex := make(map[reflect.Type]int)
+	data := floData{
+		ID:             f.ID,
+		Name:           f.Name,
+		Label:          f.Label,
+		Description:    f.Description,
+		PkgName:        f.PkgName,
+		PkgDescription: f.PkgDescription,
+		Owner:          f.Owner,
+		Team:           f.Team,
+	}
+
+	internType := func(t reflect.Type) (int, error) {
+		if i, found := typeIndex[t]; found {
+			return i, nil
+		}
+		name, err := types.Name(t)
+		if err != nil {
+			return 0, err
+		}
+		typeIndex[t] = len(data.Types)
+		data.Types = append(data.Types, name)
+		return typeIndex[t], nil
+	}
+
+	iosData := func(ios IOs) ([]ioData, error) {
+		res := make([]ioData, 0, len(ios))
+		for _, io := range ios {
+			rType, err := internType(io.RType)
+			if err != nil {
+				return nil, fmt.Errorf("io id %q: %v", io.ID, err)
+			}
+			literal, err := encodeLiteral(io)
+			if err != nil {
+				return nil, fmt.Errorf("io id %q: cannot encode literal: %v", io.ID, err)
+			}
+			res = append(res, ioData{
+				ID:      io.ID,
+				Name:    io.Name,
+				Type:    io.Type,
+				RType:   rType,
+				Literal: literal,
+				Owns:    io.Owns,
+				Defer:   io.DeferRelease,
+				Multi:   io.Multi,
+				Max:     io.MaxConnections,
+				Pos:     io.Position,
+				Label:   io.Label,
+				Desc:    io.Description,
+				Result:  io.ResultName,
+			})
+			if io.Multi {
+				for _, conn := range io.Connections {
+					res[len(res)-1].Order = append(res[len(res)-1].Order, conn.ID)
+				}
+			}
+		}
+		return res, nil
+	}
+
+	var err error
+	if data.IOs, err = iosData(f.IOs); err != nil {
+		return nil, err
+	}
+
+	// Connections are recorded from their outgoing side only.
+	var conns []*ComponentConnection
+	floINs, _ := f.IOs.SeparateINsOUTs()
+	for _, in := range floINs {
+		conns = append(conns, in.Connections...)
+	}
+
+	for _, c := range f.orderedComponents() {
+		cd := componentData{
+			ID:           c.ID,
+			Name:         c.Name,
+			PkgPath:      c.PkgPath,
+			Label:        c.Label,
+			Description:  c.Description,
+			Kind:         c.Kind,
+			Branches:     c.Branches,
+			Message:      c.Message,
+			ErrorPolicy:  c.ErrorPolicy,
+			Cache:        c.Cache,
+			Effects:      c.Effects,
+			AllowReorder: c.AllowReorder,
+			Position:     c.Position,
+			Variants:     c.Variants,
+			Flag:         c.Flag,
+			Owner:        c.Owner,
+			Team:         c.Team,
+			Cost:         c.Cost,
+		}
+		for _, t := range c.TypeArgs {
+			i, err := internType(t)
+			if err != nil {
+				return nil, fmt.Errorf("component id %q: %v", c.ID, err)
+			}
+			cd.TypeArgs = append(cd.TypeArgs, i)
+		}
+		if r := c.Receiver; r != nil {
+			i, err := internType(r.Type)
+			if err != nil {
+				return nil, fmt.Errorf("component id %q receiver: %v", c.ID, err)
+			}
+			cd.Receiver = &receiverData{Name: r.Name, Type: i, Constructor: r.Constructor}
+		}
+		if c.Sub != nil {
+			if cd.Sub, err = c.Sub.data(types); err != nil {
+				return nil, fmt.Errorf("component id %q sub-flo: %v", c.ID, err)
+			}
+		}
+		if cd.IOs, err = iosData(c.IOs); err != nil {
+			return nil, fmt.Errorf("component id %q: %v", c.ID, err)
+		}
+		data.Components = append(data.Components, cd)
+
+		_, outs := c.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			conns = append(conns, out.Connections...)
+		}
+	}
+	conns = append(conns, f.Sequences...)
+
+	for _, conn := range conns {
+		cd := connectionData{
+			ID:               conn.ID,
+			Kind:             conn.Kind,
+			OutComponentID:   conn.OutComponentID,
+			OutComponentIOID: conn.OutComponentIOID,
+			InComponentID:    conn.InComponentID,
+			InComponentIOID:  conn.InComponentIOID,
+			Order:            conn.Order,
+		}
+		if cd.Fallback, err = encodeFallback(conn); err != nil {
+			return nil, fmt.Errorf("connection id %q: cannot encode fallback: %v", conn.ID, err)
+		}
+		if t := conn.Transform; t != nil {
+			in, err := internType(t.In)
+			if err != nil {
+				return nil, fmt.Errorf("connection id %q: %v", conn.ID, err)
+			}
+			out, err := internType(t.Out)
+			if err != nil {
+				return nil, fmt.Errorf("connection id %q: %v", conn.ID, err)
+			}
+			cd.Transform = &transformData{
+				Name:    t.Name,
+				PkgPath: t.PkgPath,
+				Expr:    t.Expr,
+				Imports: t.Imports,
+				In:      in,
+				Out:     out,
+			}
+		}
+		data.Connections = append(data.Connections, cd)
+	}
+
+	return &data, nil
+}
+
+func (data *floData) flo(types *TypeRegistry, resolve ResolveFunc) (*Flo, error) {
+	if types == nil {
+		types = DefaultTypeRegistry
+	}
+
+	rTypes := make([]reflect.Type, 0, len(data.Types))
+	for _, name := range data.Types {
+		t, err := types.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		rTypes = append(rTypes, t)
+	}
+	rType := func(i int) (reflect.Type, error) {
+		if i < 0 || i >= len(rTypes) {
+			return nil, fmt.Errorf("invalid type index %d", i)
+		}
+		return rTypes[i], nil
+	}
+
+	// Connections are restored from their outgoing side so multi ios need
+	// their order back.
+	orders := make(map[*ComponentIO][]uuid.UUID)
+	ios := func(parentID uuid.UUID, iosData []ioData) (IOs, error) {
+		res := make(IOs, 0, len(iosData))
+		for _, d := range iosData {
+			t, err := rType(d.RType)
+			if err != nil {
+				return nil, fmt.Errorf("io id %q: %v", d.ID, err)
+			}
+			if d.ID == uuid.Nil {
+				return nil, errors.New("invalid io id")
+			}
+			if d.Type == ComponentIOTypeUnknown {
+				return nil, fmt.Errorf("io id %q: unknown component io type", d.ID)
+			}
+			literal, err := decodeLiteral(d.Literal, t)
+			if err != nil {
+				return nil, fmt.Errorf("io id %q: cannot decode literal: %v", d.ID, err)
+			}
+			// Not going through NewComponentIO as the name is already
+			// normalized, or rather is the one of the connected io.
+			res = append(res, &ComponentIO{
+				ID:             d.ID,
+				Name:           d.Name,
+				Type:           d.Type,
+				RType:          t,
+				IsError:        t.Implements(errorRType),
+				IsSignal:       t == signalRType,
+				Literal:        literal,
+				Owns:           d.Owns,
+				DeferRelease:   d.Defer,
+				Multi:          d.Multi,
+				MaxConnections: d.Max,
+				Position:       d.Pos,
+				Label:          d.Label,
+				Description:    d.Desc,
+				ResultName:     d.Result,
+				ParentID:       parentID,
+			})
+			if d.Multi {
+				orders[res[len(res)-1]] = d.Order
+			}
+		}
+		return res, nil
+	}
+
+	if data.ID == uuid.Nil {
+		return nil, errors.New("invalid flo id")
+	}
+
+	f, err := NewFlo(
+		data.Name, data.Label, data.Description,
+		data.PkgName, data.PkgDescription,
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.ID = data.ID
+	f.Owner, f.Team = data.Owner, data.Team
+
+	if f.IOs, err = ios(f.ID, data.IOs); err != nil {
+		return nil, err
+	}
+
+	for _, cd := range data.Components {
+		c := &Component{
+			ID:           cd.ID,
+			Name:         cd.Name,
+			PkgPath:      cd.PkgPath,
+			Label:        cd.Label,
+			Description:  cd.Description,
+			Kind:         cd.Kind,
+			Branches:     cd.Branches,
+			Message:      cd.Message,
+			ErrorPolicy:  cd.ErrorPolicy,
+			Cache:        cd.Cache,
+			Effects:      cd.Effects,
+			AllowReorder: cd.AllowReorder,
+			Position:     cd.Position,
+			Variants:     cd.Variants,
+			Flag:         cd.Flag,
+			Owner:        cd.Owner,
+			Team:         cd.Team,
+			Cost:         cd.Cost,
+		}
+		for _, i := range cd.TypeArgs {
+			t, err := rType(i)
+			if err != nil {
+				return nil, fmt.Errorf("component id %q: %v", cd.ID, err)
+			}
+			c.TypeArgs = append(c.TypeArgs, t)
+		}
+		if rd := cd.Receiver; rd != nil {
+			t, err := rType(rd.Type)
+			if err != nil {
+				return nil, fmt.Errorf("component id %q receiver: %v", cd.ID, err)
+			}
+			c.Receiver = &Receiver{Name: rd.Name, Type: t, Constructor: rd.Constructor}
+		}
+		if cd.Sub != nil {
+			if c.Sub, err = cd.Sub.flo(types, resolve); err != nil {
+				return nil, fmt.Errorf("component id %q sub-flo: %v", cd.ID, err)
+			}
+		}
+		if c.IOs, err = ios(c.ID, cd.IOs); err != nil {
+			return nil, fmt.Errorf("component id %q: %v", cd.ID, err)
+		}
+
+		if err := c.resolve(resolve); err != nil {
+			return nil, fmt.Errorf("cannot resolve component id %q: %v", c.ID, err)
+		}
+
+		if err := f.AddComponent(c); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cd := range data.Connections {
+		conn := &ComponentConnection{
+			ID:               cd.ID,
+			Kind:             cd.Kind,
+			OutComponentID:   cd.OutComponentID,
+			OutComponentIOID: cd.OutComponentIOID,
+			InComponentID:    cd.InComponentID,
+			InComponentIOID:  cd.InComponentIOID,
+			Order:            cd.Order,
+		}
+
+		if cd.Transform != nil {
+			if conn.Transform, err = cd.Transform.transform(rType, resolve); err != nil {
+				return nil, fmt.Errorf("connection id %q: %v", cd.ID, err)
+			}
+		}
+
+		if conn.Kind == ComponentConnectionKindSequence {
+			f.Sequences = append(f.Sequences, conn)
+			f.connectionIndex[conn.ID] = conn
+			continue
+		}
+
+		outIO, found := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
+		if !found {
+			return nil, fmt.Errorf("connection id %q: missing out io %q", conn.ID, conn.OutComponentIOID)
+		}
+		inIO, found := f.componentIO(conn.InComponentID, conn.InComponentIOID)
+		if !found {
+			return nil, fmt.Errorf("connection id %q: missing in io %q", conn.ID, conn.InComponentIOID)
+		}
+
+		if conn.Fallback, err = decodeLiteral(cd.Fallback, outIO.RType); err != nil {
+			return nil, fmt.Errorf("connection id %q: cannot decode fallback: %v", conn.ID, err)
+		}
+
+		outIO.Connections = append(outIO.Connections, conn)
+		inIO.Connections = append(inIO.Connections, conn)
+		f.connectionIndex[conn.ID] = conn
+		if !inIO.Multi {
+			// The out io may have been renamed when added.
+			inIO.Name = outIO.Name
+		}
+	}
+
+	for io, order := range orders {
+		sort.SliceStable(io.Connections, func(i, j int) bool {
+			return slices.Index(order, io.Connections[i].ID) < slices.Index(order, io.Connections[j].ID)
+		})
+	}
+
+	return f, nil
+}
+
+func (td *transformData) transform(
+	rType func(int) (reflect.Type, error),
+	resolve ResolveFunc,
+) (*Transform, error) {
+	in, err := rType(td.In)
+	if err != nil {
+		return nil, err
+	}
+	out, err := rType(td.Out)
+	if err != nil {
+		return nil, err
+	}
+
+	if td.Expr != "" {
+		return NewExprTransform(td.Expr, in, out, td.Imports...)
+	}
+
+	t := &Transform{
+		Name:    td.Name,
+		PkgPath: td.PkgPath,
+		In:      in,
+		Out:     out,
+	}
+	if resolve == nil {
+		return t, nil
+	}
+
+	fn, err := resolve(td.PkgPath, td.Name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve transform: %v", err)
+	}
+	resolved, err := NewTransform(td.Name, td.PkgPath, fn)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.In != in || resolved.Out != out {
+		return nil, fmt.Errorf("transform %s.%s has changed signature", td.PkgPath, td.Name)
+	}
+
+	return resolved, nil
+}
+
+// resolve backs the component with its function, rebuilding the ones
+// provided by flo itself.
+func (c *Component) resolve(resolve ResolveFunc) error {
+	var v reflect.Value
+	switch {
+	case c.Kind == ComponentKindJoin, c.Kind == ComponentKindGuard, c.Kind == ComponentKindLiteral, c.Kind == ComponentKindFlo, c.IsPlaceholder():
+		return nil
+	case c.PkgPath == flocodecPkg:
+		var err error
+		if v, err = codecFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flostdPkg:
+		var err error
+		if v, err = stdFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flohttpPkg:
+		var err error
+		if v, err = httpFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flosqlPkg:
+		var err error
+		if v, err = sqlFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flocachePkg:
+		var err error
+		if v, err = cacheFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flotimePkg:
+		var err error
+		if v, err = timeFunc(c.Name); err != nil {
+			return err
+		}
+	case c.PkgPath == flosecretPkg && c.Name == "Resolve":
+		v = reflect.ValueOf(flosecret.Resolve)
+	case c.PkgPath == flobrokerPkg:
+		var err error
+		if v, err = brokerFunc(c.Name, c.TypeArgs); err != nil {
+			return err
+		}
+	case c.PkgPath == flovalidatePkg && len(c.IOs) > 0:
+		v = validateFunc(c.IOs[0].RType)
+	case resolve != nil:
+		fn, err := resolve(c.PkgPath, c.Name)
+		if err != nil {
+			return err
+		}
+		v = reflect.ValueOf(fn)
+	default:
+		return nil
+	}
+	if c.Cache != nil {
+		var err error
+		if v, err = cachedFunc(c, v); err != nil {
+			return err
+		}
+	}
+
+	return c.bindValue(v)
+}
+
+// bindValue backs the component with the function v after making sure it
+// matches the component ios.
+func (c *Component) bindValue(v reflect.Value) error {
+	if !v.IsValid() || v.Kind() != reflect.Func {
+		return fmt.Errorf("value of kind %q is not a function", v.Kind())
+	}
+
+	vt := v.Type()
+	ins, outs := c.IOs.SeparateINsOUTs()
+	if vt.NumIn() != len(ins) || vt.NumOut() != len(outs) {
+		return fmt.Errorf(
+			"function has %d arguments and %d results but component expects %d and %d",
+			vt.NumIn(), vt.NumOut(), len(ins), len(outs),
+		)
+	}
+	for i, in := range ins {
+		if vt.In(i) != in.RType {
+			return fmt.Errorf("argument %d is %s but component expects %s", i+1, vt.In(i), in.RType)
+		}
+	}
+	for i, out := range outs {
+		if vt.Out(i) != out.RType {
+			return fmt.Errorf("result %d is %s but component expects %s", i+1, vt.Out(i), out.RType)
+		}
+	}
+
+	c.Value = v
+
+	return nil
+}
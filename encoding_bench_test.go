@@ -0,0 +1,102 @@
+package flo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func benchIncFn(v int) int {
+	return v + 1
+}
+
+// newBenchFlo builds a chain of n components.
+func newBenchFlo(b *testing.B, n int) *Flo {
+	b.Helper()
+
+	f, err := NewFlo("Bench", "Bench", "Bench", "bench", "Bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	in, err := NewComponentIO("in", ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := f.AddIO(in); err != nil {
+		b.Fatal(err)
+	}
+
+	prevID, prevIO := f.ID, in
+	for i := 0; i < n; i++ {
+		c, err := NewComponent(fmt.Sprintf("Inc%d", i), "bench.io/inc", "Inc", "Inc", benchIncFn)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := f.AddComponent(c); err != nil {
+			b.Fatal(err)
+		}
+		if err := f.ConnectComponent(prevID, prevIO.ID, c.ID, c.IOs[0].ID); err != nil {
+			b.Fatal(err)
+		}
+		prevID, prevIO = c.ID, c.IOs[1]
+	}
+
+	return f
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		f := newBenchFlo(b, n)
+
+		b.Run(fmt.Sprintf("binary/%d", n), func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				buf := &bytes.Buffer{}
+				if err := f.EncodeBinary(buf, nil); err != nil {
+					b.Fatal(err)
+				}
+				size = buf.Len()
+			}
+			b.ReportMetric(float64(size), "bytes/op")
+		})
+
+		b.Run(fmt.Sprintf("json/%d", n), func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := f.data(nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				buf := &bytes.Buffer{}
+				if err := json.NewEncoder(buf).Encode(data); err != nil {
+					b.Fatal(err)
+				}
+				size = buf.Len()
+			}
+			b.ReportMetric(float64(size), "bytes/op")
+		})
+
+		b.Run(fmt.Sprintf("restore/%d", n), func(b *testing.B) {
+			data, err := f.data(nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			buf := &bytes.Buffer{}
+			if err := gob.NewEncoder(buf).Encode(data); err != nil {
+				b.Fatal(err)
+			}
+			snapshot := buf.Bytes()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeBinary(bytes.NewReader(snapshot), nil, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryEncoding(t *testing.T) {
+	f := newTestFlo(t)
+
+	want := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), want))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, f.EncodeBinary(buf, nil))
+
+	t.Run("Decode", func(t *testing.T) {
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.ID, decoded.ID)
+		require.Len(t, decoded.Components, len(f.Components))
+
+		got := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), got))
+		require.Equal(t, want.String(), got.String())
+
+		for id, c := range decoded.Components {
+			require.True(t, c.Value.IsValid())
+			require.Equal(t, f.Components[id].Value.Type(), c.Value.Type())
+		}
+	})
+
+	t.Run("Decode unbound", func(t *testing.T) {
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+
+		for _, c := range decoded.Components {
+			require.False(t, c.Value.IsValid())
+		}
+	})
+
+	t.Run("Unresolvable component", func(t *testing.T) {
+		_, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, func(pkgPath, name string) (any, error) {
+			return compDFn, nil
+		})
+		require.ErrorContains(t, err, "cannot resolve component")
+	})
+
+	t.Run("Unregistered types", func(t *testing.T) {
+		c, err := flo.NewComponent("Unregistered", "githab.com/testuf/tera", "Unregistered", "Unregistered", func(unregistered) {})
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+
+		err = f.EncodeBinary(&bytes.Buffer{}, flo.NewTypeRegistry())
+		require.ErrorContains(t, err, "is not registered")
+	})
+}
+
+func TestJSONEncoding(t *testing.T) {
+	f := newTestFlo(t)
+
+	want := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), want))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, f.EncodeJSON(buf, nil))
+	require.True(t, json.Valid(buf.Bytes()))
+
+	t.Run("Decode", func(t *testing.T) {
+		decoded, err := flo.DecodeJSON(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.ID, decoded.ID)
+		require.True(t, flo.Equal(f, decoded))
+
+		got := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), got))
+		require.Equal(t, want.String(), got.String())
+	})
+
+	t.Run("Literals", func(t *testing.T) {
+		g, err := flo.NewFlo("Repeat", "Repeat", "Repeat Description", "flo", "Test Package")
+		require.NoError(t, err)
+		s := flo.In[string]("s")
+		require.NoError(t, g.AddIO(s))
+		repeat, err := flo.NewComponent("Repeat", "strings", "Repeat", "Repeat Description", strings.Repeat)
+		require.NoError(t, err)
+		require.NoError(t, g.AddComponent(repeat))
+		require.NoError(t, g.ConnectComponent(g.ID, s.ID, repeat.ID, repeat.IOs[0].ID))
+		require.NoError(t, g.SetLiteral(repeat.ID, repeat.IOs[1].ID, 3))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, g.EncodeJSON(buf, nil))
+		require.Contains(t, buf.String(), `"Literal": 3`)
+
+		decoded, err := flo.DecodeJSON(buf, nil, func(pkgPath, name string) (any, error) {
+			return strings.Repeat, nil
+		})
+		require.NoError(t, err)
+		require.True(t, flo.Equal(g, decoded))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := flo.DecodeJSON(strings.NewReader("{"), nil, nil)
+		require.ErrorContains(t, err, "cannot decode flo")
+	})
+}
@@ -0,0 +1,214 @@
+package flo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Equal reports whether a and b describe the same flo: same metadata, same
+// IOs, and the same components wired the same way. Ids, names derived from
+// them and the order in which components and connections were added are
+// ignored. The order of the flo IOs matters as it is the order of the
+// generated function params and results.
+func Equal(a, b *Flo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a == b {
+		return true
+	}
+
+	// Never hold both locks to avoid lock ordering issues.
+	a.mu.Lock()
+	ca := a.canonical()
+	a.mu.Unlock()
+
+	b.mu.Lock()
+	cb := b.canonical()
+	b.mu.Unlock()
+
+	return ca.equal(cb)
+}
+
+// Equal reports whether c and o are the same component, ignoring their ids
+// and connections.
+func (c *Component) Equal(o *Component) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+
+	return c.key() == o.key()
+}
+
+// key describes the component without its id and connections.
+func (c *Component) key() string {
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb, "%s\x00%s\x00%s\x00%s\x00%s\x00%d\x00%s\x00%t\x00%s\x00%s",
+		c.Name, c.PkgPath, c.Label, c.Description, c.Kind, c.Branches, c.Effects, c.AllowReorder, c.Message, c.ErrorPolicy,
+	)
+	if c.Cache != nil {
+		fmt.Fprintf(&sb, "\x00%+v", *c.Cache)
+	}
+	for _, t := range c.TypeArgs {
+		fmt.Fprintf(&sb, "\x00%s", TypeName(t))
+	}
+	if c.Flag != nil {
+		fmt.Fprintf(&sb, "\x00flag %+v", *c.Flag)
+	}
+	for _, v := range c.Variants {
+		fmt.Fprintf(&sb, "\x00variant %s", v)
+	}
+	if c.Owner != "" || c.Team != "" {
+		fmt.Fprintf(&sb, "\x00owner %s %s", c.Owner, c.Team)
+	}
+	if c.Cost != (Cost{}) {
+		fmt.Fprintf(&sb, "\x00cost %+v", c.Cost)
+	}
+	if c.Sub != nil {
+		fmt.Fprintf(&sb, "\x00sub %s", c.Sub.fingerprint())
+	}
+	if r := c.Receiver; r != nil {
+		fmt.Fprintf(&sb, "\x00receiver %s %s %s", r.Name, TypeName(r.Type), r.Constructor)
+	}
+	for _, io := range c.IOs {
+		fmt.Fprintf(
+			&sb, "\x00%s %s %s %t %t %t %d",
+			io.Type, TypeName(io.RType), literalKey(io), io.Owns, io.DeferRelease, io.Multi, io.MaxConnections,
+		)
+	}
+
+	return sb.String()
+}
+
+// canonicalFlo is an id free description of a flo.
+type canonicalFlo struct {
+	meta        string
+	ios         []string
+	components  []string
+	connections []string
+}
+
+func (c canonicalFlo) equal(o canonicalFlo) bool {
+	return c.meta == o.meta &&
+		slices.Equal(c.ios, o.ios) &&
+		slices.Equal(c.components, o.components) &&
+		slices.Equal(c.connections, o.connections)
+}
+
+func (f *Flo) canonical() canonicalFlo {
+	f.syncDefinitions()
+
+	res := canonicalFlo{
+		meta: strings.Join([]string{f.Name, f.Label, f.Description, f.PkgName, f.PkgDescription, f.Owner, f.Team}, "\x00"),
+	}
+	for _, io := range f.IOs {
+		res.ios = append(res.ios, fmt.Sprintf("%s %s %s %s\x00%s\x00%s", io.Name, io.ResultName, io.Type, TypeName(io.RType), io.Label, io.Description))
+	}
+
+	// Components are labelled by their content, then labels are refined
+	// with the labels of their neighbours until they stop getting more
+	// precise, so that identical components are told apart by their wiring.
+	labels := make(map[uuid.UUID]string, len(f.Components)+1)
+	for id, c := range f.Components {
+		labels[id] = hashLabel(c.key())
+	}
+	labels[f.ID] = "flo"
+
+	distinct := countDistinct(labels)
+	for range len(f.Components) {
+		neighbours := make(map[uuid.UUID][]string, len(f.Components))
+		for _, conn := range f.connectionIndex {
+			desc := f.connectionLabel(conn, labels)
+			neighbours[conn.OutComponentID] = append(neighbours[conn.OutComponentID], "out:"+desc)
+			neighbours[conn.InComponentID] = append(neighbours[conn.InComponentID], "in:"+desc)
+		}
+
+		refined := make(map[uuid.UUID]string, len(labels))
+		for id, label := range labels {
+			if id == f.ID {
+				refined[id] = label
+				continue
+			}
+			slices.Sort(neighbours[id])
+			refined[id] = hashLabel(label + "\x00" + strings.Join(neighbours[id], "\x00"))
+		}
+
+		n := countDistinct(refined)
+		labels = refined
+		if n == distinct {
+			break
+		}
+		distinct = n
+	}
+
+	for id, label := range labels {
+		if id != f.ID {
+			res.components = append(res.components, label)
+		}
+	}
+	slices.Sort(res.components)
+
+	for _, conn := range f.connectionIndex {
+		res.connections = append(res.connections, f.connectionLabel(conn, labels))
+	}
+	slices.Sort(res.connections)
+
+	return res
+}
+
+// connectionLabel describes conn using the labels of its ends.
+func (f *Flo) connectionLabel(conn *ComponentConnection, labels map[uuid.UUID]string) string {
+	desc := fmt.Sprintf(
+		"%s %s.%d>%s.%d",
+		conn.Kind,
+		labels[conn.OutComponentID], f.ioIndex(conn.OutComponentID, conn.OutComponentIOID),
+		labels[conn.InComponentID], f.ioIndex(conn.InComponentID, conn.InComponentIOID),
+	)
+	if t := conn.Transform; t != nil {
+		desc += fmt.Sprintf(" %s.%s(%s)%v", t.PkgPath, t.Name, t.Expr, t.Imports)
+	}
+	if conn.Order != 0 {
+		desc += fmt.Sprintf(" #%d", conn.Order)
+	}
+	if conn.Fallback.IsValid() {
+		desc += " ?" + fallbackKey(conn)
+	}
+
+	return desc
+}
+
+// ioIndex is the position of the io ioID in the flo or component id.
+func (f *Flo) ioIndex(id, ioID uuid.UUID) int {
+	ios := f.IOs
+	if id != f.ID {
+		c, found := f.Components[id]
+		if !found {
+			return -1
+		}
+		ios = c.IOs
+	}
+
+	return slices.IndexFunc(ios, func(io *ComponentIO) bool {
+		return io.ID == ioID
+	})
+}
+
+func hashLabel(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+func countDistinct(labels map[uuid.UUID]string) int {
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		seen[label] = struct{}{}
+	}
+
+	return len(seen)
+}
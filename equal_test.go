@@ -0,0 +1,75 @@
+package flo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	a := newTestFlo(t)
+	b := newTestFlo(t)
+
+	require.True(t, flo.Equal(a, a))
+	require.True(t, flo.Equal(a, b))
+	require.False(t, flo.Equal(a, nil))
+
+	t.Run("Components", func(t *testing.T) {
+		for _, ca := range a.Components {
+			found := false
+			for _, cb := range b.Components {
+				found = found || ca.Equal(cb)
+			}
+			require.True(t, found, ca.Name)
+		}
+	})
+
+	t.Run("Adding order is ignored", func(t *testing.T) {
+		build := func(t *testing.T, swap bool) *flo.Flo {
+			f, err := flo.NewFlo("TestEqual", "Test Equal", "Test Equal Description", "flo", "Test Package")
+			require.NoError(t, err)
+
+			pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+			require.NoError(t, err)
+			require.NoError(t, f.AddIO(pIn))
+
+			rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+			require.NoError(t, err)
+			require.NoError(t, f.AddIO(rOut))
+
+			first, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+			require.NoError(t, err)
+			second, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+			require.NoError(t, err)
+			require.True(t, first.Equal(second))
+
+			// Adding order is incidental.
+			if swap {
+				first, second = second, first
+			}
+			require.NoError(t, f.AddComponent(first))
+			require.NoError(t, f.AddComponent(second))
+			require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, first.ID, first.IOs[0].ID))
+			require.NoError(t, f.ConnectComponent(first.ID, first.IOs[1].ID, f.ID, rOut.ID))
+
+			return f
+		}
+
+		require.True(t, flo.Equal(build(t, false), build(t, true)))
+
+		f := build(t, false)
+		f.Description = "Another Description"
+		require.False(t, flo.Equal(build(t, false), f))
+	})
+
+	t.Run("Different graphs", func(t *testing.T) {
+		for _, c := range b.Components {
+			if c.Name == "CompE" {
+				require.NoError(t, b.DeleteComponent(c.ID))
+			}
+		}
+		require.False(t, flo.Equal(a, b))
+	})
+}
@@ -0,0 +1,145 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// ErrorPolicy controls what the generated code does when a component
+// returns an error.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyAbort returns the error from the flo.
+	ErrorPolicyAbort ErrorPolicy = iota
+	// ErrorPolicyContinue ignores the error, going on with zero values.
+	ErrorPolicyContinue
+	// ErrorPolicyCollect goes on with zero values, appending the error to
+	// the []error result of the flo.
+	ErrorPolicyCollect
+)
+
+func (p ErrorPolicy) String() string {
+	switch p {
+	case ErrorPolicyAbort:
+		return "ABORT"
+	case ErrorPolicyContinue:
+		return "CONTINUE"
+	case ErrorPolicyCollect:
+		return "COLLECT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// errsVar collects the errors of the components with ErrorPolicyCollect.
+const errsVar = "errs"
+
+var errorsRType = reflect.TypeFor[[]error]()
+
+// SetErrorPolicy sets what the generated code does when the component
+// componentID returns an error. Collecting errors needs an unconnected
+// []error flo out io, returning them.
+func (f *Flo) SetErrorPolicy(componentID uuid.UUID, policy ErrorPolicy) error {
+	if componentID == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+	if policy < ErrorPolicyAbort || policy > ErrorPolicyCollect {
+		return fmt.Errorf("unknown error policy %d", policy)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, found := f.Components[componentID]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", componentID)
+	}
+	if policy == ErrorPolicyCollect && f.errorsResult() == nil {
+		return errors.New("flo has no unconnected []error out io to collect errors in")
+	}
+
+	c.ErrorPolicy = policy
+	// The errors are declared, and returned, by the flo function.
+	f.markAllDirty()
+
+	return nil
+}
+
+// errorsResult is the flo out io returning the collected errors, if any.
+func (f *Flo) errorsResult() *ComponentIO {
+	for _, io := range f.IOs {
+		if io.Type == ComponentIOTypeOUT && io.RType == errorsRType && len(io.Connections) == 0 {
+			return io
+		}
+	}
+
+	return nil
+}
+
+// collectsErrors reports whether some component collects its errors.
+func (f *Flo) collectsErrors() bool {
+	for _, c := range f.Components {
+		if c.ErrorPolicy == ErrorPolicyCollect {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returnsErrors reports whether out returns the collected errors.
+func (f *Flo) returnsErrors(out *ComponentIO) bool {
+	return out == f.errorsResult() && f.collectsErrors()
+}
+
+// onErrorCode handles the error of c: the flo returns it unless the values
+// of c fall back to defaults or its error policy says otherwise. Nil means
+// the error is ignored altogether.
+func (f *Flo) onErrorCode(c *Component, outs IOs) ([]jen.Code, error) {
+	fallbacks := make(map[uuid.UUID]reflect.Value)
+	for _, out := range outs {
+		for _, conn := range out.Connections {
+			if conn.Fallback.IsValid() {
+				fallbacks[out.ID] = conn.Fallback
+				break
+			}
+		}
+	}
+	if len(fallbacks) == 0 && c.ErrorPolicy == ErrorPolicyAbort {
+		return []jen.Code{f.errorReturn(jen.Err())}, nil
+	}
+
+	var code []jen.Code
+	if len(fallbacks) == 0 && c.ErrorPolicy == ErrorPolicyCollect {
+		if f.errorsResult() == nil {
+			return nil, fmt.Errorf("component id %q collects its error but the flo has no []error out io", c.ID)
+		}
+		code = append(code, jen.Id(errsVar).Op("=").Append(jen.Id(errsVar), jen.Err()))
+	}
+
+	for _, out := range outs {
+		if !f.usesValue(out) {
+			continue
+		}
+		if len(fallbacks) == 0 {
+			code = append(code, jen.Id(out.Name).Op("=").Add(zeroCode(out.RType)))
+			continue
+		}
+		v, found := fallbacks[out.ID]
+		if !found {
+			return nil, fmt.Errorf("component id %q falls back on error but out io id %q has no fallback", c.ID, out.ID)
+		}
+		lit, err := literalCode(v)
+		if err != nil {
+			return nil, fmt.Errorf("component id %q io id %q: %v", c.ID, out.ID, err)
+		}
+		code = append(code, jen.Id(out.Name).Op("=").Add(lit))
+	}
+
+	return code, nil
+}
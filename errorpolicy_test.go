@@ -0,0 +1,97 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetErrorPolicy(t *testing.T) {
+	f, err := flo.NewFlo("TestPolicy", "Test Policy", "Test Policy Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in := flo.In[int]("in")
+	result := flo.Out[int]("result")
+	for _, io := range []*flo.ComponentIO{in, result} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	used, err := flo.NewComponent("CompB", "githab.com/testurrf/terb", "Comp B", "Used", compBFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(used))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, used.ID, used.IOs[0].ID))
+	require.NoError(t, f.SetLiteral(used.ID, used.IOs[1].ID, true))
+	require.NoError(t, f.ConnectComponent(used.ID, used.IOs[2].ID, f.ID, result.ID))
+
+	unused, err := flo.NewComponent("CompB", "githab.com/testurrf/terb", "Comp B", "Unused", compBFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(unused))
+	require.NoError(t, f.SetLiteral(unused.ID, unused.IOs[0].ID, 1))
+	require.NoError(t, f.SetLiteral(unused.ID, unused.IOs[1].ID, false))
+
+	t.Run("Invalid policies", func(t *testing.T) {
+		require.ErrorContains(t, f.SetErrorPolicy(uuid.Nil, flo.ErrorPolicyContinue), "invalid component id")
+		require.ErrorContains(t, f.SetErrorPolicy(used.ID, flo.ErrorPolicy(42)), "unknown error policy")
+		require.ErrorContains(t, f.SetErrorPolicy(uuid.New(), flo.ErrorPolicyContinue), "no component id")
+		require.ErrorContains(t, f.SetErrorPolicy(used.ID, flo.ErrorPolicyCollect), "no unconnected []error out io")
+	})
+
+	t.Run("Continue", func(t *testing.T) {
+		require.NoError(t, f.SetErrorPolicy(used.ID, flo.ErrorPolicyContinue))
+		require.NoError(t, f.SetErrorPolicy(unused.ID, flo.ErrorPolicyContinue))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `func TestPolicy(in int) int {
+	// Used
+	iod8E895F4A10213A36E8626E91E455191C1886Cb0, err := terb.CompB(in, true)
+	if err != nil {
+		iod8E895F4A10213A36E8626E91E455191C1886Cb0 = 0
+	}
+
+	// Unused
+	terb.CompB(1, false)
+
+	return iod8E895F4A10213A36E8626E91E455191C1886Cb0
+}
+`)
+	})
+
+	errs := flo.Out[[]error]("errs")
+	require.NoError(t, f.AddIO(errs))
+
+	t.Run("Collect", func(t *testing.T) {
+		require.NoError(t, f.SetErrorPolicy(used.ID, flo.ErrorPolicyCollect))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `func TestPolicy(in int) (int, []error) {
+	var errs []error
+	// Used
+	iod8E895F4A10213A36E8626E91E455191C1886Cb0, err := terb.CompB(in, true)
+	if err != nil {
+		errs = append(errs, err)
+		iod8E895F4A10213A36E8626E91E455191C1886Cb0 = 0
+	}
+
+	// Unused
+	terb.CompB(1, false)
+
+	return iod8E895F4A10213A36E8626E91E455191C1886Cb0, errs
+}
+`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+}
@@ -0,0 +1,34 @@
+package flo
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ComponentError attributes a runtime error to the component, and the io
+// when known, that failed, so that callers can tell which node of which flo
+// failed without parsing messages:
+//
+//	var cerr *flo.ComponentError
+//	if errors.As(err, &cerr) {
+//		log.Printf("flo %s failed at %s", cerr.FloID, cerr.ComponentID)
+//	}
+type ComponentError struct {
+	FloID       uuid.UUID
+	ComponentID uuid.UUID
+	IOID        uuid.UUID // Nil when not about a specific io.
+	Err         error
+}
+
+func (e *ComponentError) Error() string {
+	if e.IOID == uuid.Nil {
+		return fmt.Sprintf("component id %q: %v", e.ComponentID, e.Err)
+	}
+
+	return fmt.Sprintf("component id %q io id %q: %v", e.ComponentID, e.IOID, e.Err)
+}
+
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
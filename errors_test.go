@@ -0,0 +1,27 @@
+package flo_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentError(t *testing.T) {
+	componentID, ioID := uuid.New(), uuid.New()
+
+	err := fmt.Errorf("run: %w", &flo.ComponentError{ComponentID: componentID, Err: io.EOF})
+	require.ErrorIs(t, err, io.EOF)
+	require.EqualError(t, err, fmt.Sprintf("run: component id %q: EOF", componentID))
+
+	var cerr *flo.ComponentError
+	require.True(t, errors.As(err, &cerr))
+	require.Equal(t, componentID, cerr.ComponentID)
+
+	err = &flo.ComponentError{ComponentID: componentID, IOID: ioID, Err: io.EOF}
+	require.EqualError(t, err, fmt.Sprintf("component id %q io id %q: EOF", componentID, ioID))
+}
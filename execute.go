@@ -0,0 +1,524 @@
+package flo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/pprof"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flobudget"
+	"github.com/samber/lo"
+)
+
+// Execute runs the flo in-process, calling the function of each component
+// directly instead of rendering and compiling Go source.
+//
+// inputs holds the value of each in io of the flo by name; a missing
+// context.Context defaults to ctx. The values of the out ios are returned by
+// result name, but for errors: the error returned by the flo is returned
+// instead. Components run in the order they are rendered in, and stop at
+// the first error returned unless their error policy or fallbacks say
+// otherwise. Errors returned by components and guards, and panics of
+// components, are wrapped in a *ComponentError. Like Render, Execute
+// refuses flos rejected by their policies. Components are called with the
+// pprof labels of WithPprofLabels.
+//
+// The execution is configured with opts, e.g. WithBudget or WithFault.
+//
+// Components must be bound. Sources, receivers, feature flags and
+// expression transforms only exist in the rendered code and are not
+// supported.
+func (f *Flo) Execute(ctx context.Context, inputs map[string]any, opts ...ExecuteOption) (map[string]any, error) {
+	o := newExecuteOptions(opts)
+	if o.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.budget)
+		defer cancel()
+	}
+
+	f.mu.Lock()
+	snapshot := f.clone(false)
+	o.metrics = f.metricsForExecute()
+	f.mu.Unlock()
+
+	if err := snapshot.checkPolicies(ctx); err != nil {
+		return nil, err
+	}
+	for id := range o.faults {
+		if _, found := snapshot.Components[id]; !found {
+			return nil, fmt.Errorf("no component id %q found in flo to inject a fault into", id)
+		}
+	}
+
+	floINs, floOUTs := snapshot.IOs.SeparateINsOUTs()
+	for name := range inputs {
+		if !lo.SomeBy(floINs, func(in *ComponentIO) bool { return in.Name == name }) {
+			return nil, fmt.Errorf("no in io %q found in flo", name)
+		}
+	}
+
+	args := make([]reflect.Value, 0, len(floINs))
+	for _, in := range floINs {
+		v, found := inputs[in.Name]
+		if !found {
+			if in.RType != contextRType {
+				return nil, fmt.Errorf("missing input %q", in.Name)
+			}
+			v = ctx
+		}
+		arg, err := assignValue(in.RType, reflect.ValueOf(v))
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %v", in.Name, err)
+		}
+		args = append(args, arg)
+	}
+
+	var trace []ExecutionStep
+	if o.debugBundle != nil {
+		o.trace = &trace
+	}
+	results, err := snapshot.execute(ctx, args, o)
+	if err == nil {
+		for i, out := range floOUTs {
+			if out.IsError && !results[i].IsNil() {
+				err = results[i].Interface().(error)
+				break
+			}
+		}
+	}
+	if err != nil {
+		if o.debugBundle != nil {
+			if berr := snapshot.writeDebugBundle(o, inputs, trace, err); berr != nil {
+				err = errors.Join(err, fmt.Errorf("cannot write debug bundle: %v", berr))
+			}
+		}
+		return nil, err
+	}
+
+	outputs := make(map[string]any, len(floOUTs))
+	for i, out := range floOUTs {
+		if !out.IsError {
+			outputs[out.ResultName] = results[i].Interface()
+		}
+	}
+
+	return outputs, nil
+}
+
+// ExecuteOption configures Execute.
+type ExecuteOption func(*executeOptions)
+
+type executeOptions struct {
+	budget  time.Duration
+	faults  map[uuid.UUID]Fault
+	metrics *executeMetrics // Where the stats of the components are recorded.
+
+	debugBundle io.Writer
+	debugTypes  *TypeRegistry
+	trace       *[]ExecutionStep // Where the component calls are recorded, if set.
+}
+
+func newExecuteOptions(opts []ExecuteOption) executeOptions {
+	var o executeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// execution holds the state of a run of the flo.
+type execution struct {
+	f           *Flo
+	ctx         reflect.Value               // Context fed to the unwired context ins.
+	values      map[uuid.UUID]reflect.Value // Values of the out ios and flo in ios.
+	executed    map[uuid.UUID]struct{}
+	errs        []error         // Errors collected by the components.
+	releases    []reflect.Value // Release methods deferred, in call order.
+	budget      *flobudget.Budget
+	budgetSteps map[uuid.UUID]int // Components given a share of the budget, by step.
+	faults      map[uuid.UUID]Fault
+	metrics     *executeMetrics
+	trace       *[]ExecutionStep
+}
+
+// execute runs f with the values of its in ios and returns the values of its
+// out ios, in order.
+func (f *Flo) execute(ctx context.Context, args []reflect.Value, o executeOptions) ([]reflect.Value, error) {
+	f.syncDefinitions()
+	defer f.orderComponents()()
+
+	floINs, floOUTs := f.IOs.SeparateINsOUTs()
+	e := &execution{
+		f:        f,
+		values:   make(map[uuid.UUID]reflect.Value),
+		executed: make(map[uuid.UUID]struct{}, len(f.Components)),
+		faults:   o.faults,
+		metrics:  o.metrics,
+		trace:    o.trace,
+	}
+	e.ctx = reflect.ValueOf(&ctx).Elem()
+	e.newBudget(ctx, o.budget)
+	for i, in := range floINs {
+		e.values[in.ID] = args[i]
+		if in == f.contextParam() {
+			e.ctx = args[i]
+		}
+	}
+	defer func() {
+		for i := len(e.releases) - 1; i >= 0; i-- {
+			e.releases[i].Call(nil)
+		}
+	}()
+
+	// Same order as rendering: what the flo ins feed first, then the rest.
+	for _, in := range floINs {
+		for _, conn := range in.Connections {
+			c, found := f.Components[conn.InComponentID]
+			if !found {
+				return nil, fmt.Errorf("misconfigured connection id %q: missing ingoing component %q", conn.ID, conn.InComponentID)
+			}
+			if err := e.run(ctx, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, c := range f.orderedComponents() {
+		if err := e.run(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]reflect.Value, 0, len(floOUTs))
+	for _, out := range floOUTs {
+		switch {
+		case f.returnsErrors(out):
+			v := reflect.ValueOf(e.errs)
+			if e.errs == nil {
+				v = reflect.Zero(errorsRType)
+			}
+			results = append(results, v)
+		case len(out.Connections) > 0 && !out.IsSignal:
+			v, err := e.inValue(out)
+			if err != nil {
+				return nil, fmt.Errorf("out io %q: %v", out.ResultName, err)
+			}
+			results = append(results, v)
+		default:
+			results = append(results, reflect.Zero(out.RType))
+		}
+	}
+
+	return results, nil
+}
+
+// run runs c once the components it depends on have run.
+func (e *execution) run(ctx context.Context, c *Component) (err error) {
+	if _, found := e.executed[c.ID]; found {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.IsPlaceholder() {
+		return fmt.Errorf("component id %q is the placeholder %q of a template flo, see Instantiate", c.ID, c.Name)
+	}
+	switch {
+	case c.Kind == ComponentKindSource:
+		return fmt.Errorf("source component id %q cannot be executed", c.ID)
+	case c.Receiver != nil:
+		return fmt.Errorf("component id %q called on a receiver cannot be executed", c.ID)
+	case c.Flag != nil:
+		return fmt.Errorf("component id %q behind a feature flag cannot be executed", c.ID)
+	}
+
+	for _, id := range e.f.predecessors(c) {
+		outC, found := e.f.Components[id]
+		if !found {
+			return fmt.Errorf("misconfigured connection: missing outgoing component %q for component %q", id, c.ID)
+		}
+		if err := e.run(ctx, outC); err != nil {
+			return err
+		}
+	}
+	e.executed[c.ID] = struct{}{}
+
+	var (
+		start   time.Time // When c is called.
+		callErr error     // Returned by c through its error outs.
+	)
+	defer func() {
+		if r := recover(); r != nil {
+			err = &ComponentError{FloID: e.f.ID, ComponentID: c.ID, Err: fmt.Errorf("panic: %v", r)}
+		}
+		if !start.IsZero() {
+			e.record(c, time.Since(start), cmp.Or(err, callErr))
+		}
+	}()
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	args := make([]reflect.Value, 0, len(ins))
+	for _, in := range ins {
+		v, err := e.inValue(in)
+		if err != nil {
+			return fmt.Errorf("component id %q io id %q: %v", c.ID, in.ID, err)
+		}
+		args = append(args, v)
+	}
+
+	switch c.Kind {
+	case ComponentKindJoin:
+		return nil
+	case ComponentKindLiteral:
+		for _, out := range outs {
+			e.values[out.ID] = out.Literal
+		}
+		return nil
+	case ComponentKindGuard:
+		if len(args) == 0 || args[0].Bool() {
+			return nil
+		}
+		err := errors.New(c.Message)
+		if len(args) > 1 {
+			err = fmt.Errorf(c.Message, valuesInterfaces(args[1:])...)
+		}
+		return &ComponentError{FloID: e.f.ID, ComponentID: c.ID, Err: err}
+	}
+
+	defer e.budgetStep(c, args)()
+	start = time.Now()
+	var results []reflect.Value
+	// Labeled as with WithPprofLabels, for CPU profiles.
+	pprof.Do(ctx, pprof.Labels("flo", e.f.Name, "flo.component", c.Label), func(ctx context.Context) {
+		var injected bool
+		if results, injected, err = e.inject(ctx, c, outs); err != nil || injected {
+			return
+		}
+		results, err = e.call(ctx, c, args)
+	})
+	if err != nil {
+		return err
+	}
+	for i, out := range outs {
+		if out.IsError && !results[i].IsNil() && callErr == nil {
+			callErr = results[i].Interface().(error)
+		}
+	}
+
+	return e.handleResults(c, outs, results)
+}
+
+// call calls the function of c, or runs its sub-flo.
+func (e *execution) call(ctx context.Context, c *Component, args []reflect.Value) ([]reflect.Value, error) {
+	if c.Sub != nil && c.Cache != nil {
+		var subErr error
+		subINs, subOUTs := c.Sub.IOs.SeparateINsOUTs()
+		fn := makeFunc(ioTypes(subINs), ioTypes(subOUTs), func(args []reflect.Value) []reflect.Value {
+			results, err := callSub(ctx, c.Sub, args)
+			if err != nil {
+				subErr = err
+				results = lo.Map(subOUTs, func(out *ComponentIO, _ int) reflect.Value { return reflect.Zero(out.RType) })
+			}
+			return results
+		})
+		results := cacheAsideFunc(c, fn).Call(args)
+		if subErr != nil {
+			return nil, subErr
+		}
+		return results, nil
+	}
+	if c.Sub != nil {
+		return callSub(ctx, c.Sub, args)
+	}
+
+	if !c.IsBound() {
+		return nil, fmt.Errorf("component id %q is not bound", c.ID)
+	}
+
+	if c.Value.Type().IsVariadic() {
+		return c.Value.CallSlice(args), nil
+	}
+
+	return c.Value.Call(args), nil
+}
+
+// callSub runs the sub-flo sub. Its error is returned as the result of its
+// error out io when it has one.
+func callSub(ctx context.Context, sub *Flo, args []reflect.Value) ([]reflect.Value, error) {
+	results, err := sub.execute(ctx, args, executeOptions{})
+	if err == nil {
+		return results, nil
+	}
+
+	_, outs := sub.IOs.SeparateINsOUTs()
+	errOut, found := lo.Find(outs, func(out *ComponentIO) bool { return out.IsError })
+	if !found {
+		return nil, fmt.Errorf("sub-flo %q: %w", sub.Name, err)
+	}
+	results = make([]reflect.Value, len(outs))
+	for i, out := range outs {
+		results[i] = reflect.Zero(out.RType)
+		if out == errOut {
+			results[i] = reflect.ValueOf(&err).Elem()
+		}
+	}
+
+	return results, nil
+}
+
+// handleResults stores the results of c, handling its error the way the
+// rendered code does.
+func (e *execution) handleResults(c *Component, outs IOs, results []reflect.Value) error {
+	var (
+		callErr error
+		errOut  *ComponentIO
+	)
+	for i, out := range outs {
+		if out.IsError && !results[i].IsNil() {
+			callErr, errOut = results[i].Interface().(error), out
+		}
+	}
+
+	if callErr != nil {
+		fallbacks := make(map[uuid.UUID]reflect.Value)
+		for _, out := range outs {
+			for _, conn := range out.Connections {
+				if conn.Fallback.IsValid() {
+					fallbacks[out.ID] = conn.Fallback
+					break
+				}
+			}
+		}
+
+		switch {
+		case len(fallbacks) > 0:
+		case c.ErrorPolicy == ErrorPolicyAbort:
+			return &ComponentError{FloID: e.f.ID, ComponentID: c.ID, IOID: errOut.ID, Err: callErr}
+		case c.ErrorPolicy == ErrorPolicyCollect:
+			if e.f.errorsResult() == nil {
+				return fmt.Errorf("component id %q collects its error but the flo has no []error out io", c.ID)
+			}
+			e.errs = append(e.errs, callErr)
+		}
+
+		for i, out := range outs {
+			if out.IsError || !e.f.usesValue(out) {
+				continue
+			}
+			if len(fallbacks) == 0 {
+				results[i] = reflect.Zero(out.RType)
+				continue
+			}
+			v, found := fallbacks[out.ID]
+			if !found {
+				return fmt.Errorf("component id %q falls back on error but out io id %q has no fallback", c.ID, out.ID)
+			}
+			results[i] = v
+		}
+	}
+
+	for i, out := range outs {
+		e.values[out.ID] = results[i]
+		if method, ok := releaseMethod(out.RType); ok && out.DeferRelease && callErr == nil && !isNil(results[i]) {
+			e.releases = append(e.releases, results[i].MethodByName(method))
+		}
+	}
+
+	return nil
+}
+
+// inValue returns the value fed to in: its literal, the values of its
+// connections or the zero value.
+func (e *execution) inValue(in *ComponentIO) (reflect.Value, error) {
+	switch {
+	case in.IsSignal:
+		return reflect.Zero(in.RType), nil
+	case in.Literal.IsValid():
+		return assignValue(in.RType, in.Literal)
+	case in.Multi:
+		values := reflect.MakeSlice(in.RType, 0, len(in.Connections))
+		for _, conn := range in.Connections {
+			v, err := e.connValue(conn)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			elem, err := assignValue(in.RType.Elem(), v)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			values = reflect.Append(values, elem)
+		}
+		return values, nil
+	case len(in.Connections) > 0:
+		v, err := e.connValue(in.Connections[0])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return assignValue(in.RType, v)
+	case isImplicitContext(in):
+		return e.ctx, nil
+	default:
+		return reflect.Zero(in.RType), nil
+	}
+}
+
+// connValue returns the value carried by conn, transformed when needed.
+func (e *execution) connValue(conn *ComponentConnection) (reflect.Value, error) {
+	v, found := e.values[conn.OutComponentIOID]
+	if !found {
+		return reflect.Value{}, fmt.Errorf("no value produced by io id %q", conn.OutComponentIOID)
+	}
+
+	t := conn.Transform
+	switch {
+	case t == nil:
+		return v, nil
+	case t.Expr != "":
+		return reflect.Value{}, fmt.Errorf("expression transform %q cannot be executed", t.Expr)
+	case !t.Value.IsValid():
+		return reflect.Value{}, fmt.Errorf("transform %s.%s is not bound", t.PkgPath, t.Name)
+	}
+
+	return t.Value.Call([]reflect.Value{v})[0], nil
+}
+
+// assignValue converts v into a value of type t, turning nil values into
+// nil interfaces as the rendered code does.
+func assignValue(t reflect.Type, v reflect.Value) (reflect.Value, error) {
+	res := reflect.New(t).Elem()
+	if !v.IsValid() || isNil(v) {
+		if v.IsValid() && v.Type().AssignableTo(t) && t.Kind() != reflect.Interface {
+			res.Set(v)
+		}
+		return res, nil
+	}
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("%s cannot be assigned to %s", v.Type(), t)
+	}
+	res.Set(v)
+
+	return res, nil
+}
+
+// isNil reports whether v holds a nil value.
+func isNil(v reflect.Value) bool {
+	if _, ok := nilableKinds[v.Kind()]; ok || v.Kind() == reflect.Interface {
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func valuesInterfaces(values []reflect.Value) []any {
+	res := make([]any, 0, len(values))
+	for _, v := range values {
+		res = append(res, v.Interface())
+	}
+
+	return res
+}
@@ -0,0 +1,169 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute(t *testing.T) {
+	f := newTestFlo(t)
+
+	t.Run("Results", func(t *testing.T) {
+		outputs, err := f.Execute(context.Background(), map[string]any{"in": 5, "unused": 0})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 21}, outputs)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := f.Execute(context.Background(), map[string]any{"in": -1, "unused": 0})
+		require.ErrorContains(t, err, "f1 is less than zero")
+
+		var cerr *flo.ComponentError
+		require.ErrorAs(t, err, &cerr)
+		compB := componentNamed(f, "CompB")
+		require.Equal(t, f.ID, cerr.FloID)
+		require.Equal(t, compB.ID, cerr.ComponentID)
+		require.Equal(t, compB.IOs[3].ID, cerr.IOID)
+	})
+
+	t.Run("Policies", func(t *testing.T) {
+		f := newTestFlo(t)
+		require.NoError(t, f.AddPolicy(flo.PolicyFunc(func(context.Context, flo.FloView) []flo.ValidationError {
+			return []flo.ValidationError{{Message: "not approved"}}
+		})))
+
+		_, err := f.Execute(context.Background(), map[string]any{"in": 5, "unused": 0})
+		require.EqualError(t, err, "flo rejected by policies: not approved")
+	})
+
+	t.Run("Invalid inputs", func(t *testing.T) {
+		_, err := f.Execute(context.Background(), map[string]any{"unused": 0})
+		require.EqualError(t, err, `missing input "in"`)
+
+		_, err = f.Execute(context.Background(), map[string]any{"in": 5, "unused": 0, "other": 1})
+		require.EqualError(t, err, `no in io "other" found in flo`)
+
+		_, err = f.Execute(context.Background(), map[string]any{"in": "5", "unused": 0})
+		require.EqualError(t, err, `input "in": string cannot be assigned to int`)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := f.Execute(ctx, map[string]any{"in": 5, "unused": 0})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Error policy", func(t *testing.T) {
+		f := newTestFlo(t)
+		compB := componentNamed(f, "CompB")
+		require.NoError(t, f.SetErrorPolicy(compB.ID, flo.ErrorPolicyContinue))
+
+		outputs, err := f.Execute(context.Background(), map[string]any{"in": -1, "unused": 0})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 9}, outputs)
+	})
+
+	t.Run("Sub-flo", func(t *testing.T) {
+		parent, err := flo.NewFlo("Parent", "Parent", "Parent Description", "flo", "")
+		require.NoError(t, err)
+
+		for _, io := range []struct {
+			name  string
+			typ   flo.ComponentIOType
+			rType reflect.Type
+		}{
+			{"ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context]()},
+			{"in", flo.ComponentIOTypeIN, reflect.TypeFor[int]()},
+			{"unused", flo.ComponentIOTypeIN, reflect.TypeFor[int]()},
+			{"result", flo.ComponentIOTypeOUT, reflect.TypeFor[int]()},
+			{"err", flo.ComponentIOTypeOUT, reflect.TypeFor[error]()},
+		} {
+			pio, err := flo.NewComponentIO(io.name, io.typ, io.rType, parent.ID)
+			require.NoError(t, err)
+			require.NoError(t, parent.AddIO(pio))
+		}
+
+		c, err := parent.AddFlo(f)
+		require.NoError(t, err)
+		require.NoError(t, parent.ConnectComponent(parent.ID, parent.IOs[0].ID, c.ID, c.IOs[0].ID))
+		require.NoError(t, parent.ConnectComponent(parent.ID, parent.IOs[1].ID, c.ID, c.IOs[1].ID))
+		require.NoError(t, parent.SetLiteral(c.ID, c.IOs[2].ID, 0))
+		require.NoError(t, parent.ConnectComponent(c.ID, c.IOs[3].ID, parent.ID, parent.IOs[3].ID))
+
+		outputs, err := parent.Execute(context.Background(), map[string]any{"in": 5, "unused": 0})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 21}, outputs)
+
+		_, err = parent.Execute(context.Background(), map[string]any{"in": -1, "unused": 0})
+		require.ErrorContains(t, err, "f1 is less than zero")
+
+		var cerr *flo.ComponentError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, parent.ID, cerr.FloID)
+		require.Equal(t, c.ID, cerr.ComponentID)
+	})
+
+	t.Run("Variadic", func(t *testing.T) {
+		f, err := flo.NewFlo("Join", "Join", "Join Description", "flo", "")
+		require.NoError(t, err)
+
+		pElems, err := flo.NewComponentIO("elems", flo.ComponentIOTypeIN, reflect.TypeFor[[]string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pElems))
+
+		rPath, err := flo.NewComponentIO("path", flo.ComponentIOTypeOUT, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rPath))
+
+		join, err := flo.NewComponent("Join", "path", "Join", "Join Description", path.Join)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(join))
+		require.NoError(t, f.ConnectComponent(f.ID, pElems.ID, join.ID, join.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(join.ID, join.IOs[1].ID, f.ID, rPath.ID))
+
+		outputs, err := f.Execute(context.Background(), map[string]any{"elems": []string{"a", "b"}})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"path": "a/b"}, outputs)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "path.Join(elems...)")
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		f, err := flo.NewFlo("Panic", "Panic", "Panic Description", "flo", "")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rOut))
+
+		boom, err := flo.NewComponent("Boom", "githab.com/testuf/tera", "Boom", "Boom Description", func(int) int {
+			panic("boom")
+		})
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(boom))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, boom.ID, boom.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(boom.ID, boom.IOs[1].ID, f.ID, rOut.ID))
+
+		_, err = f.Execute(context.Background(), map[string]any{"in": 1})
+		require.EqualError(t, err, fmt.Sprintf("component id %q: panic: boom", boom.ID))
+
+		var cerr *flo.ComponentError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, boom.ID, cerr.ComponentID)
+	})
+}
@@ -0,0 +1,87 @@
+package flo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// SetFallback makes the connection connectionID carry value instead of
+// aborting the flo when the component producing it returns an error, which
+// is then swallowed:
+//
+//	v, err := pkg.Fetch()
+//	if err != nil {
+//		v = "default"
+//	}
+//
+// Every value of the component used downstream needs a fallback for its
+// error to be swallowed. A nil value removes the fallback.
+func (f *Flo) SetFallback(connectionID uuid.UUID, value any) error {
+	if connectionID == uuid.Nil {
+		return errors.New("invalid connection id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	conn, found := f.connectionIndex[connectionID]
+	if !found {
+		return fmt.Errorf("unknown connection id %q", connectionID)
+	}
+	if conn.Kind == ComponentConnectionKindSequence {
+		return fmt.Errorf("connection id %q is a sequence", connectionID)
+	}
+	c, found := f.Components[conn.OutComponentID]
+	if !found {
+		return fmt.Errorf("connection id %q does not come from a component", connectionID)
+	}
+	if !lo.SomeBy(c.IOs, func(io *ComponentIO) bool { return io.Type == ComponentIOTypeOUT && io.IsError }) {
+		return fmt.Errorf("component id %q returns no error", c.ID)
+	}
+	out, found := c.IOs.GetByID(conn.OutComponentIOID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on component id %q", conn.OutComponentIOID, c.ID)
+	}
+
+	f.markDirty(c.ID)
+
+	if value == nil {
+		conn.Fallback = reflect.Value{}
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(out.RType) {
+		return fmt.Errorf("fallback %s cannot be assigned to component io id %q of type %s", v.Type(), out.ID, out.RType)
+	}
+	if _, err := literalCode(v); err != nil {
+		return fmt.Errorf("invalid fallback for connection id %q: %v", connectionID, err)
+	}
+	for _, other := range out.Connections {
+		if other.Fallback.IsValid() && other.ID != conn.ID && valueKey(other.Fallback) != valueKey(v) {
+			return fmt.Errorf("connection id %q already falls back to another value", other.ID)
+		}
+	}
+	conn.Fallback = v
+
+	return nil
+}
+
+// fallbackKey describes the fallback of conn, if any, for comparisons.
+func fallbackKey(conn *ComponentConnection) string {
+	return valueKey(conn.Fallback)
+}
+
+// encodeFallback serializes the fallback of conn, if any.
+func encodeFallback(conn *ComponentConnection) ([]byte, error) {
+	if !conn.Fallback.IsValid() {
+		return nil, nil
+	}
+
+	return json.Marshal(conn.Fallback.Interface())
+}
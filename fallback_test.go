@@ -0,0 +1,80 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFallback(t *testing.T) {
+	f, err := flo.NewFlo("TestFallback", "Test Fallback", "Test Fallback Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in := flo.In[int]("in")
+	result := flo.Out[int]("result")
+	for _, io := range []*flo.ComponentIO{in, result, flo.In[string]("s"), flo.Out[int]("length")} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	compB, err := flo.NewComponent("CompB", "githab.com/testurrf/terb", "Comp B", "Comp B Description", compBFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compB))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, compB.ID, compB.IOs[0].ID))
+	require.NoError(t, f.SetLiteral(compB.ID, compB.IOs[1].ID, true))
+	require.NoError(t, f.ConnectComponent(compB.ID, compB.IOs[2].ID, f.ID, result.ID))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.ConnectComponent(f.ID, f.IOs[2].ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, f.IOs[3].ID))
+
+	conn := compB.IOs[2].Connections[0].ID
+
+	t.Run("Invalid fallbacks", func(t *testing.T) {
+		require.ErrorContains(t, f.SetFallback(uuid.New(), 1), "unknown connection id")
+		require.ErrorContains(t, f.SetFallback(in.Connections[0].ID, 1), "does not come from a component")
+		require.ErrorContains(t, f.SetFallback(length.IOs[1].Connections[0].ID, 1), "returns no error")
+		require.ErrorContains(t, f.SetFallback(conn, "1"), "cannot be assigned")
+	})
+
+	require.NoError(t, f.SetFallback(conn, 42))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `
+	iod8E895F4A10213A36E8626E91E455191C1886Cb0, err := terb.CompB(in, true)
+	if err != nil {
+		iod8E895F4A10213A36E8626E91E455191C1886Cb0 = 42
+	}
+`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		fingerprint := f.Fingerprint()
+		require.NoError(t, f.SetFallback(conn, nil))
+		require.NotEqual(t, fingerprint, f.Fingerprint())
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `
+	if err != nil {
+		return 0, 0
+	}
+`)
+	})
+}
@@ -0,0 +1,65 @@
+package flo
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/flotime"
+)
+
+// Fault is a failure injected into a component by Execute, e.g. to check in
+// tests that error policies and fallbacks are wired right.
+type Fault struct {
+	Latency time.Duration // Delay before the call, cut short when the execution is done.
+	Err     error         // Returned by the component instead of calling it.
+	Panic   any           // Panicked with instead of calling the component.
+}
+
+// WithFault injects fault into the component id of the flo. Errors go
+// through the error out io of the component, as if returned by it, or fail
+// the execution with a *ComponentError when it has none.
+func WithFault(id uuid.UUID, fault Fault) ExecuteOption {
+	return func(o *executeOptions) {
+		if o.faults == nil {
+			o.faults = make(map[uuid.UUID]Fault)
+		}
+		o.faults[id] = fault
+	}
+}
+
+// inject applies the fault of c, if any. It returns the results of c when
+// they replace its call.
+func (e *execution) inject(ctx context.Context, c *Component, outs IOs) ([]reflect.Value, bool, error) {
+	fault, found := e.faults[c.ID]
+	if !found {
+		return nil, false, nil
+	}
+
+	if fault.Latency > 0 {
+		if err := flotime.Sleep(ctx, fault.Latency); err != nil {
+			return nil, false, err
+		}
+	}
+	if fault.Panic != nil {
+		panic(fault.Panic)
+	}
+	if fault.Err == nil {
+		return nil, false, nil
+	}
+
+	injected := false
+	results := make([]reflect.Value, len(outs))
+	for i, out := range outs {
+		results[i] = reflect.Zero(out.RType)
+		if out.IsError && !injected {
+			results[i], injected = errorValue(fault.Err), true
+		}
+	}
+	if !injected {
+		return nil, false, &ComponentError{FloID: e.f.ID, ComponentID: c.ID, Err: fault.Err}
+	}
+
+	return results, true, nil
+}
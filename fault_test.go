@@ -0,0 +1,64 @@
+package flo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault(t *testing.T) {
+	f := newTestFlo(t)
+	compB := componentNamed(f, "CompB")
+	inputs := map[string]any{"in": 5, "unused": 0}
+	errChaos := errors.New("chaos")
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := f.Execute(context.Background(), inputs, flo.WithFault(compB.ID, flo.Fault{Err: errChaos}))
+		require.ErrorIs(t, err, errChaos)
+
+		var cerr *flo.ComponentError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, compB.ID, cerr.ComponentID)
+		require.Equal(t, compB.IOs[3].ID, cerr.IOID)
+	})
+
+	t.Run("Error policy", func(t *testing.T) {
+		f := newTestFlo(t)
+		compB := componentNamed(f, "CompB")
+		require.NoError(t, f.SetErrorPolicy(compB.ID, flo.ErrorPolicyContinue))
+
+		// The result of CompB is zeroed.
+		outputs, err := f.Execute(context.Background(), inputs, flo.WithFault(compB.ID, flo.Fault{Err: errChaos}))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 15}, outputs)
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := f.Execute(ctx, inputs, flo.WithFault(compB.ID, flo.Fault{Latency: time.Hour}))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		outputs, err := f.Execute(context.Background(), inputs, flo.WithFault(compB.ID, flo.Fault{Latency: time.Millisecond}))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 21}, outputs)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		_, err := f.Execute(context.Background(), inputs, flo.WithFault(compB.ID, flo.Fault{Panic: "chaos"}))
+		require.EqualError(t, err, fmt.Sprintf("component id %q: panic: chaos", compB.ID))
+	})
+
+	t.Run("Unknown component", func(t *testing.T) {
+		id := uuid.New()
+		_, err := f.Execute(context.Background(), inputs, flo.WithFault(id, flo.Fault{Err: errChaos}))
+		require.EqualError(t, err, fmt.Sprintf("no component id %q found in flo to inject a fault into", id))
+	})
+}
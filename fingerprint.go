@@ -0,0 +1,122 @@
+package flo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Fingerprint is a stable hash of the flo content. It only changes when the
+// graph does: ids are replaced by the position of what they identify, so
+// that two flos built the same way share the same fingerprint.
+func (f *Flo) Fingerprint() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.fingerprint()
+}
+
+func (f *Flo) fingerprint() string {
+	f.syncDefinitions()
+
+	components := f.orderedComponents()
+
+	// Ids are volatile so refer to things by position instead.
+	refs := make(map[uuid.UUID]string, len(components)+1)
+	refs[f.ID] = "flo"
+	for i, io := range f.IOs {
+		refs[io.ID] = "flo." + strconv.Itoa(i)
+	}
+	for i, c := range components {
+		refs[c.ID] = strconv.Itoa(i)
+		for j, io := range c.IOs {
+			refs[io.ID] = strconv.Itoa(i) + "." + strconv.Itoa(j)
+		}
+	}
+
+	h := sha256.New()
+
+	write(h, f.Name, f.Label, f.Description, f.PkgName, f.PkgDescription)
+	// Only when set to keep the fingerprints of unowned flos.
+	if f.Owner != "" || f.Team != "" {
+		write(h, "owner", f.Owner, f.Team)
+	}
+	for _, io := range f.IOs {
+		writeIO(h, refs, io)
+		// Only when set to keep the fingerprints of undocumented flos.
+		if io.Label != "" || io.Description != "" {
+			write(h, io.Label, io.Description)
+		}
+	}
+
+	for _, c := range components {
+		write(h, c.Name, c.PkgPath, c.Label, c.Description, c.Kind, c.Branches, c.Effects, c.AllowReorder)
+		if c.Message != "" {
+			write(h, c.Message)
+		}
+		if c.ErrorPolicy != ErrorPolicyAbort {
+			write(h, c.ErrorPolicy)
+		}
+		if c.Cache != nil {
+			write(h, c.Cache.TTL, c.Cache.NoError)
+		}
+		for _, t := range c.TypeArgs {
+			write(h, TypeName(t))
+		}
+		if c.Flag != nil {
+			write(h, "flag", c.Flag.Name, c.Flag.Off)
+		}
+		for _, v := range c.Variants {
+			write(h, "variant", v)
+		}
+		if c.Owner != "" || c.Team != "" {
+			write(h, "owner", c.Owner, c.Team)
+		}
+		if c.Sub != nil {
+			write(h, "sub", c.Sub.fingerprint())
+		}
+		if c.Receiver != nil {
+			write(h, "receiver", c.Receiver.Name, TypeName(c.Receiver.Type), c.Receiver.Constructor)
+		}
+		if c.Cost != (Cost{}) {
+			write(h, "cost", c.Cost.Latency, c.Cost.Price)
+		}
+		for _, io := range c.IOs {
+			writeIO(h, refs, io)
+		}
+	}
+
+	for _, conn := range f.Sequences {
+		write(h, refs[conn.OutComponentID], refs[conn.InComponentID])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeIO(h hash.Hash, refs map[uuid.UUID]string, io *ComponentIO) {
+	write(h, io.Name, io.ResultName, io.Type, TypeName(io.RType), literalKey(io), io.Owns, io.DeferRelease, io.Multi, io.MaxConnections)
+	for _, conn := range io.Connections {
+		write(h, refs[conn.OutComponentIOID], refs[conn.InComponentIOID], conn.Order)
+		if t := conn.Transform; t != nil {
+			write(h, t.Name, t.PkgPath, t.Expr)
+			if len(t.Imports) > 0 {
+				write(h, "imports", t.Imports)
+			}
+		}
+		if conn.Fallback.IsValid() {
+			write(h, fallbackKey(conn))
+		}
+	}
+}
+
+// write feeds values to h, each one terminated so that they can't run
+// into each other.
+func write(h hash.Hash, values ...any) {
+	for _, v := range values {
+		fmt.Fprintf(h, "%v\x00", v)
+	}
+}
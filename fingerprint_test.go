@@ -0,0 +1,38 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := newTestFlo(t)
+	b := newTestFlo(t)
+	require.NotEqual(t, a.ID, b.ID)
+
+	fingerprint := a.Fingerprint()
+	require.Len(t, fingerprint, 64)
+	require.Equal(t, fingerprint, b.Fingerprint())
+
+	t.Run("Changes with the graph", func(t *testing.T) {
+		for _, c := range b.Components {
+			if c.Name == "CompE" {
+				require.NoError(t, b.DeleteComponent(c.ID))
+			}
+		}
+		require.NotEqual(t, fingerprint, b.Fingerprint())
+
+		a.Description = "Another Description"
+		require.NotEqual(t, fingerprint, a.Fingerprint())
+	})
+
+	t.Run("Header", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, a.Render(context.Background(), out, flo.WithFingerprint()))
+		require.Contains(t, out.String(), "// Code generated by flo. Do not edit!\n// Fingerprint: "+a.Fingerprint()+"\n")
+	})
+}
@@ -0,0 +1,193 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/mgjules/flo/floflag"
+	"github.com/samber/lo"
+)
+
+const floflagPkg = "github.com/mgjules/flo/floflag"
+
+var flagProviderRType = reflect.TypeFor[floflag.Provider]()
+
+// FlagGate runs a component only when a feature flag is enabled, or only
+// when it is disabled if Off.
+//
+// The components gated by the same flag are rendered together:
+//
+//	if flags.Enabled(ctx, "checkout-v2") {
+//		// components of the flag.
+//	} else {
+//		// Off components of the flag.
+//	}
+//
+// flags and ctx are the first floflag.Provider and context.Context flo ins,
+// floflag.Default and context.Background() otherwise. The results of gated
+// components can only be used within their branch.
+type FlagGate struct {
+	Name string // Name of the feature flag.
+	Off  bool   // Runs the component when the flag is disabled instead.
+}
+
+// SetFlag puts the component id behind the feature flag gate, or runs it
+// unconditionally again when nil.
+func (f *Flo) SetFlag(id uuid.UUID, gate *FlagGate) error {
+	if gate != nil && gate.Name == "" {
+		return errors.New("missing flag name")
+	}
+	if gate != nil {
+		g := *gate
+		gate = &g
+	}
+
+	return f.setComponent(id, func(c *Component) {
+		c.Flag = gate
+	})
+}
+
+type flagGateKey struct{}
+
+// withFlagGate marks the components rendered with ctx as part of a branch.
+func withFlagGate(ctx context.Context, gate FlagGate) context.Context {
+	return context.WithValue(ctx, flagGateKey{}, gate)
+}
+
+func flagGateFrom(ctx context.Context) (FlagGate, bool) {
+	gate, ok := ctx.Value(flagGateKey{}).(FlagGate)
+	return gate, ok
+}
+
+// flagIOs returns the flo ins the gates get their context and feature flag
+// provider from, if any, and whether the flo has gates at all.
+func (f *Flo) flagIOs() (ctx, flags *ComponentIO, gated bool) {
+	if !lo.SomeBy(lo.Values(f.Components), func(c *Component) bool { return c.Flag != nil }) {
+		return nil, nil, false
+	}
+
+	ins, _ := f.IOs.SeparateINsOUTs()
+	for _, in := range ins {
+		switch {
+		case ctx == nil && in.RType == contextRType:
+			ctx = in
+		case flags == nil && in.RType == flagProviderRType:
+			flags = in
+		}
+	}
+
+	return ctx, flags, true
+}
+
+// renderGate renders every component gated by the flag name into an
+// if/else statement.
+func (f *Flo) renderGate(ctx context.Context, g *jen.Group, name string, rendered map[uuid.UUID]struct{}) error {
+	members := lo.Filter(f.orderedComponents(), func(c *Component, _ int) bool {
+		return c.Flag != nil && c.Flag.Name == name
+	})
+	branch := make(map[uuid.UUID]bool, len(members))
+	for _, c := range members {
+		branch[c.ID] = c.Flag.Off
+	}
+
+	for _, c := range members {
+		_, outs := c.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			for _, conn := range out.Connections {
+				if off, found := branch[conn.InComponentID]; !found || off != c.Flag.Off {
+					return fmt.Errorf(
+						"component id %q gated by flag %q is used outside its branch by %q",
+						c.ID, name, conn.InComponentID,
+					)
+				}
+			}
+		}
+	}
+	for _, conn := range f.Sequences {
+		out, outFound := branch[conn.OutComponentID]
+		in, inFound := branch[conn.InComponentID]
+		if outFound && inFound && out != in {
+			return fmt.Errorf("sequence connection id %q crosses the branches of flag %q", conn.ID, name)
+		}
+	}
+
+	// What the branches depend on goes first.
+	for _, c := range members {
+		for _, id := range f.predecessors(c) {
+			if _, found := branch[id]; found {
+				continue
+			}
+			if _, found := rendered[id]; found {
+				continue
+			}
+			outC, found := f.Components[id]
+			if !found {
+				return fmt.Errorf(
+					"misconfigured connection: missing outgoing component %q for component %q",
+					id, c.ID,
+				)
+			}
+			if err := f.RenderComponent(ctx, g, outC, rendered); err != nil {
+				return err
+			}
+		}
+	}
+
+	var renderErr error
+	branchCode := func(off bool) func(*jen.Group) {
+		return func(bg *jen.Group) {
+			bctx := withFlagGate(ctx, FlagGate{Name: name, Off: off})
+			for _, c := range members {
+				if c.Flag.Off != off || renderErr != nil {
+					continue
+				}
+				renderErr = f.RenderComponent(bctx, bg, c, rendered)
+			}
+		}
+	}
+
+	cond := f.flagCode(name)
+	on := lo.SomeBy(members, func(c *Component) bool { return !c.Flag.Off })
+	off := lo.SomeBy(members, func(c *Component) bool { return c.Flag.Off })
+	switch {
+	case on && off:
+		g.If(cond).BlockFunc(branchCode(false)).Else().BlockFunc(branchCode(true))
+	case on:
+		g.If(cond).BlockFunc(branchCode(false))
+	default:
+		g.If(jen.Op("!").Add(cond)).BlockFunc(branchCode(true))
+	}
+	g.Line()
+
+	return renderErr
+}
+
+// blockEndRe matches the blank lines left at the end of nested blocks by
+// the last component rendered in them.
+var blockEndRe = regexp.MustCompile(`\n\n(\t+\})`)
+
+// trimBlockEnds removes the blank lines ending the nested blocks of src.
+func trimBlockEnds(src []byte) []byte {
+	return blockEndRe.ReplaceAll(src, []byte("\n$1"))
+}
+
+// flagCode tells whether the flag name is enabled.
+func (f *Flo) flagCode(name string) *jen.Statement {
+	ctx, flags, _ := f.flagIOs()
+
+	ctxCode := jen.Qual("context", "Background").Call()
+	if ctx != nil {
+		ctxCode = jen.Id(ctx.Name)
+	}
+	provider := jen.Qual(floflagPkg, "Default")
+	if flags != nil {
+		provider = jen.Id(flags.Name)
+	}
+
+	return provider.Dot("Enabled").Call(ctxCode, jen.Lit(name))
+}
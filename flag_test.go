@@ -0,0 +1,89 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/floflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagGates(t *testing.T) {
+	f, err := flo.NewFlo("Checkout", "Checkout", "Checkout Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, flags, in, out := flo.In[context.Context]("ctx"), flo.In[floflag.Provider]("flags"), flo.In[string]("s"), flo.Out[string]("res")
+	for _, io := range []*flo.ComponentIO{ctx, flags, in, out} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	newComponent := func(name string, fn any, gate *flo.FlagGate) *flo.Component {
+		c, err := flo.NewComponent(name, "strings", name, name+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.SetFlag(c.ID, gate))
+		return c
+	}
+	trim := newComponent("TrimSpace", strings.TrimSpace, nil)
+	upper := newComponent("ToUpper", strings.ToUpper, &flo.FlagGate{Name: "shout"})
+	title := newComponent("ToTitle", strings.ToTitle, &flo.FlagGate{Name: "shout"})
+	lower := newComponent("ToLower", strings.ToLower, &flo.FlagGate{Name: "shout", Off: true})
+
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, trim.ID, trim.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, f.ID, out.ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, upper.ID, upper.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(upper.ID, upper.IOs[1].ID, title.ID, title.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, lower.ID, lower.IOs[0].ID))
+
+	require.ErrorContains(t, f.SetFlag(upper.ID, &flo.FlagGate{}), "missing flag name")
+	require.ErrorContains(t, f.SetFlag(uuid.New(), nil), "no component id")
+
+	t.Run("Render", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), buf))
+		code := buf.String()
+		require.Contains(t, code, "func Checkout(ctx context.Context, flags floflag.Provider, s string) string {")
+		require.Contains(t, code, `if flags.Enabled(ctx, "shout") {`)
+		require.Contains(t, code, "} else {")
+		require.Less(t, strings.Index(code, "strings.TrimSpace("), strings.Index(code, "if flags.Enabled"))
+		require.Less(t, strings.Index(code, "strings.ToUpper("), strings.Index(code, "strings.ToTitle("))
+		require.Less(t, strings.Index(code, "strings.ToTitle("), strings.Index(code, "} else {"))
+		require.Greater(t, strings.Index(code, "strings.ToLower(s)"), strings.Index(code, "} else {"))
+		require.Equal(t, 1, strings.Count(code, "if flags.Enabled"))
+		require.Contains(t, code, "strings.ToLower(s)\n\t}\n")
+
+		buf.Reset()
+		require.NoError(t, f.RenderIncremental(context.Background(), buf))
+		require.NoError(t, f.RenderIncremental(context.Background(), buf))
+		require.Equal(t, 2, strings.Count(buf.String(), "if flags.Enabled"))
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		g, err := flo.NewFlo("Greet", "Greet", "Greet Description", "flo", "Test Package")
+		require.NoError(t, err)
+		s := flo.In[string]("s")
+		require.NoError(t, g.AddIO(s))
+		lower, err := flo.NewComponent("ToLower", "strings", "Lower", "Lower Description", strings.ToLower)
+		require.NoError(t, err)
+		require.NoError(t, g.AddComponent(lower))
+		require.NoError(t, g.ConnectComponent(g.ID, s.ID, lower.ID, lower.IOs[0].ID))
+		require.NoError(t, g.SetFlag(lower.ID, &flo.FlagGate{Name: "quiet", Off: true}))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, g.Render(context.Background(), buf))
+		require.Contains(t, buf.String(), `if !floflag.Default.Enabled(context.Background(), "quiet") {`)
+	})
+
+	t.Run("Used outside its branch", func(t *testing.T) {
+		require.NoError(t, f.SetFlag(title.ID, &flo.FlagGate{Name: "shout", Off: true}))
+		err := f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, `gated by flag "shout" is used outside its branch`)
+		require.NoError(t, f.SetFlag(title.ID, nil))
+		err = f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, `gated by flag "shout" is used outside its branch`)
+	})
+}
@@ -1,66 +1,225 @@
 package flo
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha1"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/dave/jennifer/jen"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
-	"github.com/yassinebenaid/godump"
 )
 
 // Flo is a fancy name for a bidirectional graph with some opionionated rules.
 // It is essentially rendered as a wrapper function declaration.
 // IOs are just the function parameters and return values.
 // Nodes are called components and represent function calls.
+//
+// A Flo is safe for concurrent use through its methods. Its exported fields,
+// and those of its components and ios, must not be accessed while other
+// goroutines use it; View returns a snapshot which can.
+//
+// Mutating the exported fields directly also bypasses the validation keeping
+// the flo consistent, e.g. its connection index. It is deprecated in favour
+// of the Set, Connect and Delete methods, and the fields will be unexported
+// in v2.
 type Flo struct {
-	mu             sync.Mutex
-	ID             uuid.UUID
-	Name           string
-	Label          string
-	Description    string
-	PkgName        string
+	mu sync.Mutex
+	ID uuid.UUID
+	// Deprecated: Use SetName.
+	Name string
+	// Deprecated: Use SetLabel.
+	Label string
+	// Deprecated: Use SetDescription.
+	Description string
+	// Deprecated: Use SetPkgName.
+	PkgName string
+	// Deprecated: Use SetPkgDescription.
 	PkgDescription string
-	Components     map[uuid.UUID]*Component
-	IOs            IOs
+	// Person owning the flo, e.g. "@jdoe".
+	//
+	// Deprecated: Use SetOwner.
+	Owner string
+	// Team owning the flo, e.g. "@acme/payments".
+	//
+	// Deprecated: Use SetOwner.
+	Team string
+	// Deprecated: Use View, AddComponent and DeleteComponent.
+	Components map[uuid.UUID]*Component
+	// Deprecated: Use View, AddIO and DeleteIO.
+	IOs IOs
+	// Ordering only connections between components.
+	//
+	// Deprecated: Use View, ConnectSequence and DeleteConnection.
+	Sequences []*ComponentConnection
 
 	// handy to quickly find a connection details.
 	connectionIndex map[uuid.UUID]*ComponentConnection
+	// keeps the rendering of unrelated components deterministic.
+	componentOrder []uuid.UUID
+	// rendered code of the components that did not change since the last
+	// incremental render.
+	fragments map[uuid.UUID]jen.Code
+	// previous writer of each writer while rendering.
+	writerOrder map[uuid.UUID]uuid.UUID
+	// consumers hinted to run before each consumer while rendering.
+	hintOrder map[uuid.UUID][]uuid.UUID
+	// names of the variables already taken, built lazily.
+	ioNames map[string]struct{}
+	// approve the flo before it is rendered.
+	policies []Policy
+	// runtime stats of the components, recorded by Execute.
+	metrics *executeMetrics
 }
 
+// A Component is a node of a flo. Like those of Flo, its exported fields
+// that have a setter are deprecated in favour of it.
 type Component struct {
-	ID          uuid.UUID
-	Name        string
-	PkgPath     string
-	Label       string
+	ID uuid.UUID
+	// Deprecated: Use SetComponentName.
+	Name string
+	// Deprecated: Use SetComponentPkgPath.
+	PkgPath string
+	// Deprecated: Use SetComponentLabel.
+	Label string
+	// Deprecated: Use SetComponentDescription.
 	Description string
+	Kind        ComponentKind
 	Value       reflect.Value // Enable use of instantiated object's methods or functions.
-	IOs         IOs
+	// Deprecated: Use View, Flo.IO and the setters of the ios.
+	IOs      IOs
+	Branches int    // Number of upstream branches a join waits for.
+	Message  string // Error message of a guard, formatted with its args.
+	// What the generated code does when the component errors.
+	//
+	// Deprecated: Use SetErrorPolicy.
+	ErrorPolicy ErrorPolicy
+	Cache       *CacheAside    // Caches the results of the component when set.
+	TypeArgs    []reflect.Type // Explicit type arguments of generic functions.
+	// Side effects, unknown unless set or inferred.
+	//
+	// Deprecated: Use SetEffects.
+	Effects Effects
+	// Lets a writer be reordered relative to the other writers.
+	//
+	// Deprecated: Use SetAllowReorder.
+	AllowReorder bool
+	// Where editors draw the component.
+	//
+	// Deprecated: Use SetPosition.
+	Position Position
+	// Variants the component belongs to, all when empty.
+	//
+	// Deprecated: Use SetVariants.
+	Variants []string
+	// Feature flag the component runs behind when set.
+	//
+	// Deprecated: Use SetFlag.
+	Flag *FlagGate
+	// Person owning the component, e.g. "@jdoe".
+	//
+	// Deprecated: Use SetComponentOwner.
+	Owner string
+	// Team owning the component, e.g. "@acme/search".
+	//
+	// Deprecated: Use SetComponentOwner.
+	Team string
+	// What a call costs, unknown when zero.
+	//
+	// Deprecated: Use SetCost.
+	Cost Cost
+	// Instance whose method is called, a function is called when nil.
+	//
+	// Deprecated: Use SetReceiver.
+	Receiver *Receiver
+	Sub      *Flo // Flo run by a component added with AddFlo.
+
+	def       *ComponentDefinition // Set when instantiated from a definition.
+	overrides definitionFields     // Metadata set on the instance, not pulled from def.
 }
 
+// A ComponentIO is a param or result of a component or of a flo. Like those
+// of Flo, its exported fields that have a setter are deprecated in favour of
+// it.
 type ComponentIO struct {
-	ID          uuid.UUID
-	Name        string // autogenerated short id used as variable name.
-	Type        ComponentIOType
-	RType       reflect.Type
-	IsError     bool
-	ParentID    uuid.UUID              // Used for back reference.
-	Connections []*ComponentConnection // Many outgoing but one incoming.
+	ID uuid.UUID
+	// Autogenerated short id used as variable name.
+	//
+	// Deprecated: Use SetIOName.
+	Name     string
+	Type     ComponentIOType
+	RType    reflect.Type
+	IsError  bool
+	IsSignal bool
+	// Constant fed to an unconnected in io.
+	//
+	// Deprecated: Use SetLiteral.
+	Literal reflect.Value
+	// In io taking ownership of the resource it receives.
+	//
+	// Deprecated: Use SetOwns.
+	Owns bool
+	// Out io resource released by a defer once produced.
+	//
+	// Deprecated: Use SetDeferRelease.
+	DeferRelease bool
+	// In io of slice type accepting a connection per element.
+	//
+	// Deprecated: Use SetMulti.
+	Multi bool
+	// Limits the fan-out of an out io, unlimited when 0.
+	//
+	// Deprecated: Use SetMaxConnections.
+	MaxConnections int
+	// Where editors draw the io, for flo ios only.
+	//
+	// Deprecated: Use SetPosition.
+	Position Position
+	// Documents flo ios in the generated code.
+	//
+	// Deprecated: Use SetIOLabel.
+	Label string
+	// Documents flo ios in the generated code.
+	//
+	// Deprecated: Use SetIODescription.
+	Description string
+	// Declared name of a flo out io, kept when connecting renames it.
+	//
+	// Deprecated: Use SetIOName.
+	ResultName string
+	ParentID   uuid.UUID // Used for back reference.
+	// Many outgoing but one incoming.
+	//
+	// Deprecated: Use View, ConnectComponent and DeleteConnection.
+	Connections []*ComponentConnection
 }
 
 type ComponentConnection struct {
 	ID               uuid.UUID
+	Kind             ComponentConnectionKind
 	OutComponentID   uuid.UUID
 	OutComponentIOID uuid.UUID
 	InComponentID    uuid.UUID
 	InComponentIOID  uuid.UUID
+	// Optionally massages the value on its way.
+	//
+	// Deprecated: Use SetConnectionTransform.
+	Transform *Transform
+	// Orders the consumers of the same out io, lowest first.
+	//
+	// Deprecated: Use SetConnectionOrder.
+	Order int
+	// Carried instead of failing when the out component errors.
+	//
+	// Deprecated: Use SetFallback.
+	Fallback reflect.Value
 }
 
 type IOs []*ComponentIO
@@ -73,6 +232,23 @@ const (
 	ComponentIOTypeOUT
 )
 
+type ComponentKind int
+
+const (
+	// ComponentKindFunc is a plain function call.
+	ComponentKindFunc ComponentKind = iota
+	// ComponentKindSource has no data inputs and produces a stream.
+	ComponentKindSource
+	// ComponentKindJoin waits for upstream branches to complete.
+	ComponentKindJoin
+	// ComponentKindGuard returns early from the flo unless its condition holds.
+	ComponentKindGuard
+	// ComponentKindLiteral carries a constant value.
+	ComponentKindLiteral
+	// ComponentKindFlo runs a sub-flo.
+	ComponentKindFlo
+)
+
 // NewFlo needs fn to make IOs creation much more pleasant.
 func NewFlo(
 	name, label, description string,
@@ -102,18 +278,18 @@ func NewFlo(
 	}, nil
 }
 
-func (f *Flo) PrettyDump(w io.Writer) error {
-	var d godump.Dumper
-	return d.Fprint(w, f)
-}
-
 func (f *Flo) AddIO(io *ComponentIO) error {
 	if io == nil {
 		return errors.New("missing io")
 	}
+	// Flo ios are part of the generated signature.
+	if err := checkWritable(io.RType); err != nil {
+		return fmt.Errorf("io %q cannot be rendered: %v", io.Name, err)
+	}
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
 
 	if _, found := lo.Find(f.IOs, func(fio *ComponentIO) bool {
 		return fio.Name == io.Name && fio.Type == io.Type
@@ -127,8 +303,13 @@ func (f *Flo) AddIO(io *ComponentIO) error {
 
 	// Ensure we have the correct parent id.
 	io.ParentID = f.ID
+	if io.Type == ComponentIOTypeOUT && io.ResultName == "" {
+		io.ResultName = io.Name
+	}
 
 	f.IOs = append(f.IOs, io)
+	// Error handling of every component returns the flo results.
+	f.markAllDirty()
 
 	return nil
 }
@@ -140,6 +321,7 @@ func (f *Flo) DeleteIO(id uuid.UUID) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
 
 	io, found := lo.Find(f.IOs, func(io *ComponentIO) bool {
 		return io.ID == id
@@ -155,6 +337,41 @@ func (f *Flo) DeleteIO(id uuid.UUID) error {
 	f.IOs = lo.Reject(f.IOs, func(io *ComponentIO, _ int) bool {
 		return io.ID == id
 	})
+	f.markAllDirty()
+
+	return nil
+}
+
+// ReorderIOs sets the order of the ios of the flo, hence of the params and
+// results of the generated function. ids must hold every flo io id once;
+// ins and outs are ordered among themselves.
+func (f *Flo) ReorderIOs(ids []uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	if len(ids) != len(f.IOs) {
+		return fmt.Errorf("expected %d io ids but got %d", len(f.IOs), len(ids))
+	}
+
+	ios := make(IOs, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, found := seen[id]; found {
+			return fmt.Errorf("flo io id %q given more than once", id)
+		}
+		seen[id] = struct{}{}
+
+		io, found := f.IOs.GetByID(id)
+		if !found {
+			return fmt.Errorf("flo io id %q not found", id)
+		}
+		ios = append(ios, io)
+	}
+
+	f.IOs = ios
+	// Error handling of every component returns the flo results.
+	f.markAllDirty()
 
 	return nil
 }
@@ -166,12 +383,15 @@ func (f *Flo) AddComponent(c *Component) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
 
 	if _, found := f.Components[c.ID]; found {
 		// don't override!
 		return fmt.Errorf("component id %q already exists", c.ID)
 	}
+	f.uniqueIONames(c)
 	f.Components[c.ID] = c
+	f.componentOrder = append(f.componentOrder, c.ID)
 
 	return nil
 }
@@ -183,13 +403,22 @@ func (f *Flo) DeleteComponent(id uuid.UUID) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
 
 	if c, found := f.Components[id]; found && c.IOs.HasConnections() {
 		// don't override!
 		return fmt.Errorf("component id %q has connections", c.ID)
 	}
 
+	if _, found := lo.Find(f.Sequences, func(conn *ComponentConnection) bool {
+		return conn.OutComponentID == id || conn.InComponentID == id
+	}); found {
+		return fmt.Errorf("component id %q has sequence connections", id)
+	}
+
 	delete(f.Components, id)
+	f.componentOrder = lo.Without(f.componentOrder, id)
+	f.markDirty(id)
 
 	return nil
 }
@@ -218,14 +447,28 @@ func (f *Flo) ConnectComponent(
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
 
+	return f.connectComponent(
+		outComponentID, outComponentIOID,
+		inComponentID, inComponentIOID,
+		nil,
+	)
+}
+
+// connectionEnds returns the ios of a connection after checking that
+// anything but their types allows it.
+func (f *Flo) connectionEnds(
+	outComponentID, outComponentIOID uuid.UUID,
+	inComponentID, inComponentIOID uuid.UUID,
+) (*ComponentIO, *ComponentIO, error) {
 	var outIOs IOs
 
 	isFloOutgoing := outComponentID == f.ID
 	if !isFloOutgoing {
 		outComponent, found := f.Components[outComponentID]
 		if !found {
-			return fmt.Errorf("no out component id %q found in flo", outComponentID)
+			return nil, nil, fmt.Errorf("no out component id %q found in flo", outComponentID)
 		}
 		outIOs = outComponent.IOs
 	} else {
@@ -233,7 +476,7 @@ func (f *Flo) ConnectComponent(
 	}
 	outComponentIO, found := outIOs.GetByID(outComponentIOID)
 	if !found {
-		return fmt.Errorf("no component io id %q found on out component id %q", outComponentIOID, outComponentID)
+		return nil, nil, fmt.Errorf("no component io id %q found on out component id %q", outComponentIOID, outComponentID)
 	}
 
 	var inIOs IOs
@@ -242,7 +485,7 @@ func (f *Flo) ConnectComponent(
 	if !isFloIngoing {
 		inComponent, found := f.Components[inComponentID]
 		if !found {
-			return fmt.Errorf("no in component id %q found in flo", outComponentID)
+			return nil, nil, fmt.Errorf("no in component id %q found in flo", outComponentID)
 		}
 		inIOs = inComponent.IOs
 	} else {
@@ -250,32 +493,48 @@ func (f *Flo) ConnectComponent(
 	}
 	inComponentIO, found := inIOs.GetByID(inComponentIOID)
 	if !found {
-		return fmt.Errorf("no component io id %q found on in component id %q", inComponentIOID, inComponentID)
+		return nil, nil, fmt.Errorf("no component io id %q found on in component id %q", inComponentIOID, inComponentID)
 	}
 
 	// We can't handle cyclic right now.
 	if outComponentID == inComponentID {
-		return fmt.Errorf("component id %q cannot connect to itself", outComponentID)
+		return nil, nil, fmt.Errorf("component id %q cannot connect to itself", outComponentID)
+	}
+
+	if !isFloOutgoing && !isFloIngoing && f.dependsOn(outComponentID, inComponentID) {
+		return nil, nil, fmt.Errorf(
+			"component id %q already depends on component id %q",
+			outComponentID,
+			inComponentID,
+		)
 	}
 
 	// Remember that if the component is a flo we inverse the flow check ;) (no pun intended).
 	if !isFloOutgoing && outComponentIO.Type != ComponentIOTypeOUT {
-		return fmt.Errorf("out component io id %q is not of type out", outComponentIOID)
+		return nil, nil, fmt.Errorf("out component io id %q is not of type out", outComponentIOID)
 	} else if isFloOutgoing && outComponentIO.Type != ComponentIOTypeIN {
-		return fmt.Errorf("out flo io id %q is not of type in", outComponentIOID)
+		return nil, nil, fmt.Errorf("out flo io id %q is not of type in", outComponentIOID)
 	}
 	if !isFloIngoing && inComponentIO.Type != ComponentIOTypeIN {
-		return fmt.Errorf("out component io id %q is not of type in", inComponentIOID)
+		return nil, nil, fmt.Errorf("out component io id %q is not of type in", inComponentIOID)
 	} else if isFloIngoing && inComponentIO.Type != ComponentIOTypeOUT {
-		return fmt.Errorf("out flo io id %q is not of type out", inComponentIOID)
+		return nil, nil, fmt.Errorf("out flo io id %q is not of type out", inComponentIOID)
 	}
 
-	if len(inComponentIO.Connections) > 0 {
-		return fmt.Errorf("in component io id %q already has a connection", inComponentIOID)
+	if len(inComponentIO.Connections) > 0 && !inComponentIO.Multi {
+		return nil, nil, fmt.Errorf("in component io id %q already has a connection", inComponentIOID)
+	}
+	if inComponentIO.Literal.IsValid() {
+		return nil, nil, fmt.Errorf("in component io id %q has a literal", inComponentIOID)
+	}
+	if max := outComponentIO.MaxConnections; max > 0 && len(outComponentIO.Connections) >= max {
+		return nil, nil, fmt.Errorf("out component io id %q cannot have more than %d connections", outComponentIOID, max)
 	}
 
 	_, found = lo.Find(outIOs, func(io *ComponentIO) bool {
 		if io == nil ||
+			// Multi ios can be fed by several ios of the same component.
+			(inComponentIO.Multi && io != outComponentIO) ||
 			(!isFloOutgoing && io.Type != ComponentIOTypeOUT) ||
 			(isFloOutgoing && io.Type != ComponentIOTypeIN) {
 			return false
@@ -292,7 +551,7 @@ func (f *Flo) ConnectComponent(
 		return found
 	})
 	if found {
-		return fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"in component id %q already has a connection with out component id %q through io id %q",
 			inComponentID,
 			outComponentID,
@@ -301,11 +560,45 @@ func (f *Flo) ConnectComponent(
 	}
 
 	// TODO: this might need more work than it look.
-	if !outComponentIO.RType.AssignableTo(inComponentIO.RType) {
+	if outComponentIO.IsSignal && !inComponentIO.IsSignal {
+		return nil, nil, fmt.Errorf(
+			"out component io id %q is a signal and can only connect to a signal",
+			outComponentIOID,
+		)
+	}
+
+	return outComponentIO, inComponentIO, nil
+}
+
+func (f *Flo) connectComponent(
+	outComponentID, outComponentIOID uuid.UUID,
+	inComponentID, inComponentIOID uuid.UUID,
+	transform *Transform,
+) error {
+	outComponentIO, inComponentIO, err := f.connectionEnds(
+		outComponentID, outComponentIOID,
+		inComponentID, inComponentIOID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if transform != nil {
+		if err := transform.check(outComponentIO, inComponentIO); err != nil {
+			return fmt.Errorf(
+				"out component io id %q cannot be transformed to component io id %q: %v",
+				outComponentIOID,
+				inComponentIOID,
+				err,
+			)
+		}
+	} else if !inComponentIO.IsSignal && !outComponentIO.RType.AssignableTo(inComponentIO.acceptedType()) {
+		// Anything can trigger a signal.
 		return fmt.Errorf(
-			"out component io id %q cannot be assigned to component io id %q",
+			"out component io id %q cannot be assigned to component io id %q: %v",
 			outComponentIOID,
 			inComponentIOID,
+			assignError(outComponentIO.RType, inComponentIO.acceptedType()),
 		)
 	}
 
@@ -319,6 +612,7 @@ func (f *Flo) ConnectComponent(
 			err,
 		)
 	}
+	conn.Transform = transform
 
 	if outComponentIO.Connections == nil {
 		outComponentIO.Connections = make([]*ComponentConnection, 0)
@@ -330,12 +624,35 @@ func (f *Flo) ConnectComponent(
 	outComponentIO.Connections = append(outComponentIO.Connections, conn)
 	inComponentIO.Connections = append(inComponentIO.Connections, conn)
 	f.connectionIndex[conn.ID] = conn
+	f.markDirty(conn.OutComponentID, conn.InComponentID)
 
-	inComponentIO.Name = outComponentIO.Name
+	// Multi ios refer to each of their connections instead.
+	if !inComponentIO.Multi {
+		inComponentIO.Name = outComponentIO.Name
+	}
 
 	return nil
 }
 
+// Connections returns the ids of the connections, sequences included, from
+// or to the component id, or the flo itself.
+func (f *Flo) Connections(id uuid.UUID) []uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ids []uuid.UUID
+	for _, conn := range f.connectionIndex {
+		if conn.OutComponentID == id || conn.InComponentID == id {
+			ids = append(ids, conn.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	return ids
+}
+
 func (f *Flo) DeleteConnection(connectionID uuid.UUID) error {
 	if connectionID == uuid.Nil {
 		return errors.New("invalid connnection id")
@@ -343,19 +660,29 @@ func (f *Flo) DeleteConnection(connectionID uuid.UUID) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	return f.deleteConnection(connectionID)
+}
 
+func (f *Flo) deleteConnection(connectionID uuid.UUID) error {
 	conn, found := f.connectionIndex[connectionID]
 	if !found {
 		return fmt.Errorf("unknown connection id %q", connectionID)
 	}
 
 	defer delete(f.connectionIndex, connectionID)
+	f.markDirty(conn.OutComponentID, conn.InComponentID)
 
-	outComponent, found := f.Components[conn.OutComponentID]
-	if !found {
-		return fmt.Errorf("no out component id %q found in flo", conn.OutComponentID)
+	if conn.Kind == ComponentConnectionKindSequence {
+		f.Sequences = lo.Reject(f.Sequences, func(conn *ComponentConnection, _ int) bool {
+			return conn.ID == connectionID
+		})
+
+		return nil
 	}
-	outComponentIO, found := outComponent.IOs.GetByID(conn.OutComponentIOID)
+
+	outComponentIO, found := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
 	if !found {
 		return fmt.Errorf("no component io id %q found on out component id %q", conn.OutComponentIOID, conn.OutComponentID)
 	}
@@ -364,15 +691,19 @@ func (f *Flo) DeleteConnection(connectionID uuid.UUID) error {
 		return conn.ID == connectionID
 	})
 
-	inComponent, found := f.Components[conn.InComponentID]
-	if !found {
-		return fmt.Errorf("no in component id %q found in flo", conn.OutComponentID)
-	}
-	inComponentIO, found := inComponent.IOs.GetByID(conn.InComponentIOID)
+	inComponentIO, found := f.componentIO(conn.InComponentID, conn.InComponentIOID)
 	if !found {
 		return fmt.Errorf("no component io id %q found on in component id %q", conn.InComponentIOID, conn.InComponentID)
 	}
 
+	if inComponentIO.Multi {
+		inComponentIO.Connections = lo.Reject(inComponentIO.Connections, func(conn *ComponentConnection, _ int) bool {
+			return conn.ID == connectionID
+		})
+
+		return nil
+	}
+
 	inComponentIO.Name = ""
 	inComponentIO.Connections = make([]*ComponentConnection, 0)
 
@@ -382,30 +713,118 @@ func (f *Flo) DeleteConnection(connectionID uuid.UUID) error {
 func (f *Flo) Render(
 	ctx context.Context,
 	w io.Writer,
+	opts ...RenderOption,
 ) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	return f.render(ctx, w, newRenderOptions(opts), false)
+}
+
+// render renders the flo. When incremental, the cached code of clean
+// components is reused.
+func (f *Flo) render(
+	ctx context.Context,
+	w io.Writer,
+	o renderOptions,
+	incremental bool,
+) error {
+	if o.variant != "" {
+		p, err := f.project(o.variant)
+		if err != nil {
+			return err
+		}
+		o.variant = ""
+		return p.render(ctx, w, o, false)
+	}
+
+	if err := f.checkPolicies(ctx); err != nil {
+		return err
+	}
+
+	if err := f.resolvePkgPath(&o); err != nil {
+		return err
+	}
+	ctx = withRenderOptions(ctx, o)
+	f.syncDefinitions()
+	defer f.orderComponents()()
+
+	if err := f.checkImports(o.pkgPath); err != nil {
+		return err
+	}
+	if err := f.checkOwnership(); err != nil {
+		return err
+	}
+
+	code := jen.NewFile(f.PkgName)
+	if o.pkgPath != "" {
+		code = jen.NewFilePathName(o.pkgPath, f.PkgName)
+	}
+	code.HeaderComment("Code generated by flo. Do not edit!")
+	if o.buildConstraint != "" {
+		line, err := buildConstraintCode(o.buildConstraint)
+		if err != nil {
+			return err
+		}
+		code.HeaderComment(line)
+	}
+	if o.fingerprint {
+		code.HeaderComment("Fingerprint: " + f.fingerprint())
+	}
+	code.PackageComment(f.PkgDescription)
+	if err := f.renderFunc(ctx, code, f.Name, incremental); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := code.Render(buf); err != nil {
+		return err
+	}
+
+	src := trimBlockEnds(buf.Bytes())
+	if o.skeleton != nil {
+		var err error
+		if src, err = f.applySkeleton(o.skeleton, src); err != nil {
+			return err
+		}
+	}
+
+	return o.postProcess(w, f.Name+".go", src)
+}
+
+// renderFunc adds the function of the flo, called name, to code.
+func (f *Flo) renderFunc(ctx context.Context, code *jen.File, name string, incremental bool) error {
+	f.funcDoc(code)
+
+	return f.renderFuncCode(ctx, code.Func().Id(name), incremental)
+}
+
+// renderFuncCode adds the params, results and body of the function of the
+// flo to s, e.g. a named function or a function literal.
+func (f *Flo) renderFuncCode(ctx context.Context, s *jen.Statement, incremental bool) error {
+	ctx = f.withFoldedCalls(ctx)
+	o := renderOptionsFrom(ctx)
 	rendered := make(map[uuid.UUID]struct{}, len(f.Components))
 
 	floINs, floOUTs := f.IOs.SeparateINsOUTs()
+	flagCtx, flagProvider, _ := f.flagIOs()
 
 	// Generate the wrapper(flo) function.
 	var blockG *jen.Group
-	code := jen.NewFile(f.PkgName)
-	code.HeaderComment("Code generated by flo. Do not edit!")
-	code.PackageComment(f.PkgDescription)
-	code.Func().Id(f.Name).
+	s.
 		ParamsFunc(
 			func(g *jen.Group) {
 				for _, in := range floINs {
 					g.Do(func(s *jen.Statement) {
-						if len(in.Connections) > 0 {
+						if f.usesContextParam(in) || len(in.Connections) > 0 || in == flagCtx || in == flagProvider {
 							s.Id(in.Name)
 							return
 						}
 						s.Id("_")
-					}).Qual(in.RType.PkgPath(), in.RType.Name())
+					}).Add(typeCode(in.RType))
+				}
+				for _, r := range f.receiverParams() {
+					g.Id(r.Name).Add(typeCode(r.Type))
 				}
 			}).
 		Do(
@@ -413,12 +832,22 @@ func (f *Flo) Render(
 				if len(floOUTs) == 0 {
 					return
 				}
+				if o.namedResults {
+					names := resultNames(floINs, floOUTs)
+					s.ParamsFunc(func(g *jen.Group) {
+						for i, out := range floOUTs {
+							g.Id(names[i]).Add(typeCode(out.RType))
+						}
+					})
+					return
+				}
 				if len(floOUTs) == 1 {
-					s.Qual(floOUTs[0].RType.PkgPath(), floOUTs[0].RType.Name())
+					s.Add(typeCode(floOUTs[0].RType))
+					return
 				}
 				s.Parens(jen.ListFunc(func(g *jen.Group) {
 					for _, out := range floOUTs {
-						g.Qual(out.RType.PkgPath(), out.RType.Name())
+						g.Add(typeCode(out.RType))
 					}
 				}))
 			}).
@@ -428,6 +857,26 @@ func (f *Flo) Render(
 			},
 		)
 
+	f.constructReceivers(blockG)
+	ctx = f.renderBudget(ctx, blockG)
+	if o.pprofLabels && len(f.Components) > 0 {
+		blockG.Add(f.pprofRestore())
+	}
+	if f.collectsErrors() {
+		blockG.Var().Id(errsVar).Index().Error()
+	}
+
+	if incremental && !o.parallel {
+		if err := f.renderFragments(ctx, blockG, rendered); err != nil {
+			return err
+		}
+	}
+	if o.parallel {
+		if err := f.renderParallel(ctx, blockG, rendered); err != nil {
+			return fmt.Errorf("failed to render component: %v", err)
+		}
+	}
+
 	// starts at the ingoing of a flo.
 	for _, in := range floINs {
 		for _, conn := range in.Connections {
@@ -454,7 +903,7 @@ func (f *Flo) Render(
 	}
 
 	// handle orphaned components.
-	for _, c := range f.Components {
+	for _, c := range f.orderedComponents() {
 		if _, found := rendered[c.ID]; found {
 			continue
 		}
@@ -471,28 +920,38 @@ func (f *Flo) Render(
 		}
 	}
 
+	ifaces := f.interfaceResults(blockG, floINs, floOUTs, o.namedResults)
+
 	// Generate the return statement.
 	blockG.
 		ReturnFunc(
 			func(g *jen.Group) {
 				for _, out := range floOUTs {
+					if f.returnsErrors(out) {
+						g.Id(errsVar)
+						continue
+					}
+					if name, found := ifaces[out.ID]; found {
+						g.Id(name)
+						continue
+					}
+					if out.IsSignal {
+						g.Add(signalValue())
+						continue
+					}
 					if len(out.Connections) > 0 {
-						g.Id(out.Name)
+						g.Add(inValue(out))
 						continue
 					}
 					if out.IsError {
 						g.Nil()
 						continue
 					}
-					g.Id(fmt.Sprintf("%v", reflect.Zero(out.RType).Interface()))
+					g.Add(zeroCode(out.RType))
 				}
 			},
 		)
 
-	if err := code.Render(w); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -513,90 +972,295 @@ func (f *Flo) RenderComponent(
 		// Skip as we already rendered that component.
 		return nil
 	}
+	if c.IsPlaceholder() {
+		return fmt.Errorf("component id %q is the placeholder %q of a template flo, see Instantiate", c.ID, c.Name)
+	}
+	gate, inGate := flagGateFrom(ctx)
+	if c.Flag != nil && (!inGate || gate.Name != c.Flag.Name) {
+		return f.renderGate(ctx, g, c.Flag.Name, rendered)
+	}
+
+	if c.Kind == ComponentKindJoin {
+		if n := len(f.predecessors(c)); n != c.Branches {
+			return fmt.Errorf(
+				"join component id %q expects %d branches but got %d",
+				c.ID, c.Branches, n,
+			)
+		}
+	}
 
 	ins, outs := c.IOs.SeparateINsOUTs()
-	for _, in := range ins {
-		for _, conn := range in.Connections {
-			if f.ID == conn.OutComponentID {
-				// Outgoing was flo so considered to have been rendered already.
-				continue
-			}
+	// Components we depend on, whether through data or sequence, go first.
+	for _, id := range f.predecessors(c) {
+		if _, found := rendered[id]; found {
+			continue
+		}
 
-			if _, found := rendered[conn.OutComponentID]; found {
-				continue
-			}
+		outC, found := f.Components[id]
+		if !found {
+			// Again! Ghost component!
+			return fmt.Errorf(
+				"misconfigured connection: missing outgoing component %q for component %q",
+				id, c.ID,
+			)
+		}
+		if inGate && outC.Flag != nil && outC.Flag.Name == gate.Name && outC.Flag.Off != gate.Off {
+			// The other branch never runs along with this one.
+			continue
+		}
 
-			outC, found := f.Components[conn.OutComponentID]
-			if !found {
-				// Again! Ghost component!
-				return fmt.Errorf(
-					"misconfigured connection id %q: missing outgoing component %q",
-					conn.ID, conn.OutComponentID,
-				)
-			}
+		if err := f.RenderComponent(
+			ctx,
+			g,
+			outC,
+			rendered,
+		); err != nil {
+			return err
+		}
+	}
 
-			if err := f.RenderComponent(
-				ctx,
-				g,
-				outC,
-				rendered,
-			); err != nil {
-				return err
-			}
+	o := renderOptionsFrom(ctx)
+	if o.pprofLabels {
+		g.Add(f.pprofLabel(c))
+	}
+	if err := runComponentHooks(ctx, o.beforeComponent, c, g); err != nil {
+		return err
+	}
+
+	cmt, err := comment(c, o)
+	if err != nil {
+		return err
+	}
+
+	if c.Kind == ComponentKindJoin {
+		// Branches are rendered one after the other so there is nothing to
+		// wait for. Those running concurrently are waited for by
+		// renderGroup.
+		g.Add(cmt)
+		rendered[c.ID] = struct{}{}
+
+		return runComponentHooks(ctx, o.afterComponent, c, g)
+	}
+
+	if c.Kind == ComponentKindLiteral {
+		lit, err := f.literalComponentCode(c, outs)
+		if err != nil {
+			return err
+		}
+		if lit != nil {
+			g.Add(cmt).Add(lit).Line()
+		}
+		rendered[c.ID] = struct{}{}
+
+		return runComponentHooks(ctx, o.afterComponent, c, g)
+	}
+
+	// Sources get their own cancelable context so that they stop producing
+	// as soon as the flo returns.
+	var sourceCtx string
+	if c.Kind == ComponentKindSource {
+		if len(ins) == 0 || ins[0].Name == "" && !isImplicitContext(ins[0]) {
+			return fmt.Errorf("source component id %q has no connected context", c.ID)
+		}
+		parent := f.contextValue()
+		if ins[0].Name != "" {
+			parent = jen.Id(ins[0].Name)
 		}
+
+		// Named after the out io, unique in the flo, so that several sources
+		// of the same function don't collide.
+		data := sha1.Sum([]byte(fmt.Sprintf("%s-%s-%s", c.PkgPath, c.Name, outs[0].Name)))
+		sourceCtx = lo.CamelCase(fmt.Sprintf("ctx%x", data))
+		cancel := lo.CamelCase(fmt.Sprintf("cancel%x", data))
+		g.
+			Add(cmt).
+			List(jen.Id(sourceCtx), jen.Id(cancel)).
+			Op(":=").
+			Qual("context", "WithCancel").Call(parent).
+			Line().
+			Defer().Id(cancel).Call()
+	}
+
+	literals, err := literalArgs(c, ins)
+	if err != nil {
+		return err
+	}
+
+	if c.Kind == ComponentKindGuard {
+		guard, err := f.guardCode(c, ins, literals)
+		if err != nil {
+			return err
+		}
+		g.Add(cmt).Add(guard).Line()
+		rendered[c.ID] = struct{}{}
+
+		return runComponentHooks(ctx, o.afterComponent, c, g)
+	}
+
+	if folded, ok := o.folded[c.ID]; ok {
+		g.Add(cmt).Add(folded).Line()
+		rendered[c.ID] = struct{}{}
+
+		return runComponentHooks(ctx, o.afterComponent, c, g)
+	}
+
+	onError, err := f.onErrorCode(c, outs)
+	if err != nil {
+		return err
+	}
+
+	args := spreadVariadic(c, f.callArgs(ins, literals, sourceCtx))
+	if step := budgetStepCode(c, o, args); step != nil {
+		g.Add(cmt).Add(step)
+		cmt = jen.Null()
+	}
+	fn, err := f.calleeCode(ctx, c)
+	if err != nil {
+		return err
+	}
+	var call *jen.Statement
+	inline, inlined := timeCode(c, o, ins, args)
+	switch {
+	case c.Cache != nil:
+		call = cacheAsideCall(c, fn, args)
+	case inlined:
+		call = jen.Add(inline)
+	default:
+		call = fn.Call(args...)
 	}
 
 	// Generate Go code.
 	var hasErrorReturn bool
+	// Results are simply discarded when none of them are used.
+	hasAssignment := lo.SomeBy(outs, func(out *ComponentIO) bool {
+		return f.usesValue(out) || (out.IsError && onError != nil) || out.DeferRelease
+	})
 	g.
-		Comment(c.Description).
-		Line().
-		ListFunc(func(g *jen.Group) {
-			for _, out := range outs {
-				if len(out.Connections) > 0 {
-					g.Id(out.Name)
-					continue
-				}
-				if out.IsError {
-					hasErrorReturn = true
-					g.Err()
-					continue
-				}
-				g.Id("_")
-			}
-		}).
 		Do(func(s *jen.Statement) {
-			if len(outs) > 0 {
-				s.Op(":=")
+			if sourceCtx == "" {
+				s.Add(cmt)
 			}
 		}).
-		Qual(c.PkgPath, c.Name).
-		CallFunc(func(g *jen.Group) {
-			for _, in := range ins {
-				g.Id(in.Name)
+		Do(func(s *jen.Statement) {
+			if !hasAssignment {
+				if inlined && len(outs) == 1 {
+					// Inline expressions are not all valid statements.
+					s.Id("_").Op("=")
+				}
+				return
 			}
+			s.ListFunc(func(g *jen.Group) {
+				for _, out := range outs {
+					if f.usesValue(out) || out.DeferRelease {
+						g.Id(out.Name)
+						continue
+					}
+					if out.IsError && onError != nil {
+						hasErrorReturn = true
+						g.Err()
+						continue
+					}
+					g.Id("_")
+				}
+			}).Op(":=")
 		}).
+		Add(call).
 		Line().
 		Do(func(s *jen.Statement) {
 			if hasErrorReturn {
-				s.If(jen.Err().Op("!=").Nil()).Block(
-					jen.ReturnFunc(func(g *jen.Group) {
-						_, outs := f.IOs.SeparateINsOUTs()
-						for _, out := range outs {
-							if out.IsError {
-								g.Err()
-								continue
-							}
-							g.Id(fmt.Sprintf("%v", reflect.Zero(out.RType).Interface()))
-						}
-					}),
-				).Line()
+				s.If(jen.Err().Op("!=").Nil()).Block(onError...).Line()
+			}
+			for _, out := range outs {
+				if method, ok := releaseMethod(out.RType); ok && out.DeferRelease {
+					s.Defer().Id(out.Name).Dot(method).Call().Line()
+				}
 			}
 		}).Line()
 
 	rendered[c.ID] = struct{}{}
 
-	return nil
+	return runComponentHooks(ctx, o.afterComponent, c, g)
+}
+
+// literalArgs returns the literals fed to the in ios of c.
+func literalArgs(c *Component, ins IOs) (map[uuid.UUID]jen.Code, error) {
+	literals := make(map[uuid.UUID]jen.Code)
+	for _, in := range ins {
+		if !in.Literal.IsValid() {
+			continue
+		}
+		lit, err := literalCode(in.Literal)
+		if err != nil {
+			return nil, fmt.Errorf("component id %q io id %q: %v", c.ID, in.ID, err)
+		}
+		literals[in.ID] = lit
+	}
+
+	return literals, nil
+}
+
+// callArgs returns the arguments of a component call, the first one being
+// the context sourceCtx when set.
+func (f *Flo) callArgs(ins IOs, literals map[uuid.UUID]jen.Code, sourceCtx string) []jen.Code {
+	args := make([]jen.Code, 0, len(ins))
+	for i, in := range ins {
+		switch lit, found := literals[in.ID]; {
+		case i == 0 && sourceCtx != "":
+			args = append(args, jen.Id(sourceCtx))
+		case in.IsSignal:
+			// Signals carry no value.
+			args = append(args, signalValue())
+		case found:
+			args = append(args, lit)
+		case in.Multi:
+			args = append(args, f.multiValue(in))
+		case isImplicitContext(in):
+			args = append(args, f.contextValue())
+		default:
+			args = append(args, inValue(in))
+		}
+	}
+
+	return args
+}
+
+// spreadVariadic passes the last of args, a slice, as the variadic param
+// of c when its function is variadic.
+func spreadVariadic(c *Component, args []jen.Code) []jen.Code {
+	if c.Cache != nil || !c.IsBound() || c.Value.Kind() != reflect.Func || !c.Value.Type().IsVariadic() {
+		return args
+	}
+	args[len(args)-1] = jen.Add(args[len(args)-1]).Op("...")
+
+	return args
+}
+
+// calleeCode returns what c calls, the body of its sub-flo when inlined.
+func (f *Flo) calleeCode(ctx context.Context, c *Component) (*jen.Statement, error) {
+	o := renderOptionsFrom(ctx)
+	if _, stubbed := o.stubs[c.ID]; c.Sub != nil && o.inlineFlos && !stubbed {
+		return c.Sub.inlineCode(ctx)
+	}
+
+	return callee(c, o), nil
+}
+
+// errorReturn returns err from the flo, along with zero values.
+func (f *Flo) errorReturn(err jen.Code) jen.Code {
+	return jen.ReturnFunc(func(g *jen.Group) {
+		_, outs := f.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			if f.returnsErrors(out) {
+				g.Id(errsVar)
+				continue
+			}
+			if out.IsError {
+				g.Add(err)
+				continue
+			}
+			g.Add(zeroCode(out.RType))
+		}
+	})
 }
 
 func (f *Flo) Symbols() map[string]map[string]reflect.Value {
@@ -606,7 +1270,7 @@ func (f *Flo) Symbols() map[string]map[string]reflect.Value {
 	symbols := map[string]map[string]reflect.Value{}
 
 	for _, c := range f.Components {
-		if c.Name == "" || c.PkgPath == "" {
+		if c.Name == "" || c.PkgPath == "" || !c.Value.IsValid() {
 			continue
 		}
 
@@ -670,14 +1334,33 @@ func NewComponentIO(
 		return nil, errors.New("invalid parent ID")
 	}
 
+	io := newComponentIO(name, typ, rType)
+	io.ParentID = parentID
+
+	return io, nil
+}
+
+func newComponentIO(name string, typ ComponentIOType, rType reflect.Type) *ComponentIO {
 	return &ComponentIO{
 		ID:       uuid.New(),
 		Name:     name,
 		Type:     typ,
 		RType:    rType,
 		IsError:  rType.Implements(reflect.TypeFor[error]()),
-		ParentID: parentID,
-	}, nil
+		IsSignal: rType == signalRType,
+	}
+}
+
+// In creates an IN io of type T for Flo.AddIO, which sets its parent:
+//
+//	f.AddIO(flo.In[context.Context]("ctx"))
+func In[T any](name string) *ComponentIO {
+	return newComponentIO(lo.CamelCase(name), ComponentIOTypeIN, reflect.TypeFor[T]())
+}
+
+// Out creates an OUT io of type T for Flo.AddIO, which sets its parent.
+func Out[T any](name string) *ComponentIO {
+	return newComponentIO(lo.CamelCase(name), ComponentIOTypeOUT, reflect.TypeFor[T]())
 }
 
 func NewComponentIOsFromComponent(c *Component) error {
@@ -688,7 +1371,10 @@ func NewComponentIOsFromComponent(c *Component) error {
 		return fmt.Errorf("value of kind %q is not a function", c.Value.Kind())
 	}
 
-	vt := c.Value.Type()
+	return newComponentIOsFromType(c, c.Value.Type())
+}
+
+func newComponentIOsFromType(c *Component, vt reflect.Type) error {
 	c.IOs = make(IOs, 0, vt.NumIn()+vt.NumOut())
 	for i := 0; i < vt.NumIn(); i++ {
 		p := vt.In(i)
@@ -800,3 +1486,22 @@ func (t ComponentIOType) String() string {
 		return "UNKNOWN"
 	}
 }
+
+func (k ComponentKind) String() string {
+	switch k {
+	case ComponentKindFunc:
+		return "FUNC"
+	case ComponentKindSource:
+		return "SOURCE"
+	case ComponentKindJoin:
+		return "JOIN"
+	case ComponentKindGuard:
+		return "GUARD"
+	case ComponentKindLiteral:
+		return "LITERAL"
+	case ComponentKindFlo:
+		return "FLO"
+	default:
+		return "UNKNOWN"
+	}
+}
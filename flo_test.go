@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/mgjules/flo"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/yaegi/interp"
@@ -300,3 +301,83 @@ func TestSync(ctx context.Context, in int, _ int) (int, error) {
 
 	// f.PrettyDump(os.Stdout)
 }
+
+func TestConnections(t *testing.T) {
+	f := newTestFlo(t)
+
+	require.Len(t, f.Connections(f.ID), 5)
+	require.Empty(t, f.Connections(uuid.New()))
+
+	for _, c := range f.Components {
+		if c.Name != "CompC" {
+			continue
+		}
+		ids := f.Connections(c.ID)
+		require.Len(t, ids, 4)
+		require.NoError(t, f.DeleteConnection(ids[0]))
+		require.Len(t, f.Connections(c.ID), 3)
+	}
+}
+
+func TestTypedIOs(t *testing.T) {
+	f, err := flo.NewFlo("TestTyped", "Test Typed", "Test Typed Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx := flo.In[context.Context]("ctx")
+	require.Equal(t, flo.ComponentIOTypeIN, ctx.Type)
+	require.Equal(t, reflect.TypeFor[context.Context](), ctx.RType)
+	require.Equal(t, uuid.Nil, ctx.ParentID)
+
+	s := flo.In[string]("in")
+	out := flo.Out[int]("out")
+	errOut := flo.Out[error]("err")
+	require.True(t, errOut.IsError)
+	require.False(t, out.IsError)
+	require.True(t, flo.In[flo.Signal]("done").IsSignal)
+
+	for _, io := range []*flo.ComponentIO{ctx, s, out, errOut} {
+		require.NoError(t, f.AddIO(io))
+		require.Equal(t, f.ID, io.ParentID)
+	}
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.ConnectComponent(f.ID, s.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, out.ID))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), buf))
+	require.Contains(t, buf.String(), "func TestTyped(_ context.Context, in string) (int, error) {")
+}
+
+func TestReorderIOs(t *testing.T) {
+	f, err := flo.NewFlo("TestReorder", "Test Reorder", "Test Reorder Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, ctx, out, errOut := flo.In[string]("in"), flo.In[context.Context]("ctx"), flo.Out[int]("out"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{in, out, ctx, errOut} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, out.ID))
+
+	render := func() string {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), buf))
+		return buf.String()
+	}
+	require.Contains(t, render(), "func TestReorder(in string, _ context.Context) (int, error) {")
+
+	require.ErrorContains(t, f.ReorderIOs([]uuid.UUID{ctx.ID}), "expected 4 io ids but got 1")
+	require.ErrorContains(t, f.ReorderIOs([]uuid.UUID{ctx.ID, ctx.ID, errOut.ID, out.ID}), "given more than once")
+	require.ErrorContains(t, f.ReorderIOs([]uuid.UUID{ctx.ID, in.ID, errOut.ID, length.IOs[0].ID}), "not found")
+
+	require.NoError(t, f.ReorderIOs([]uuid.UUID{errOut.ID, ctx.ID, in.ID, out.ID}))
+	require.Contains(t, render(), "func TestReorder(_ context.Context, in string) (error, int) {")
+	require.Contains(t, render(), "return nil, io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd")
+}
@@ -0,0 +1,113 @@
+// Package flobroker publishes and consumes messages for the broker
+// components of flo.
+//
+// Adapters for actual brokers, e.g. NATS or Kafka, implement Broker in
+// their own packages.
+package flobroker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Broker publishes and consumes messages by topic.
+type Broker interface {
+	// Publish publishes msg to topic.
+	Publish(ctx context.Context, topic string, msg []byte) error
+	// Consume blocks until a message of topic is received or ctx is done.
+	Consume(ctx context.Context, topic string) ([]byte, error)
+}
+
+// Default is the broker used by flo when none is injected, e.g. when
+// running flos directly.
+var Default Broker = NewMemory()
+
+// Memory is an in-memory Broker queuing messages by topic.
+type Memory struct {
+	mu     sync.Mutex
+	topics map[string]chan []byte
+	size   int
+}
+
+// NewMemory creates an in-memory broker buffering up to 64 messages per
+// topic.
+func NewMemory() *Memory {
+	return &Memory{
+		topics: make(map[string]chan []byte),
+		size:   64,
+	}
+}
+
+func (m *Memory) topic(name string) chan []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch, found := m.topics[name]
+	if !found {
+		ch = make(chan []byte, m.size)
+		m.topics[name] = ch
+	}
+
+	return ch
+}
+
+// Publish implements Broker.
+func (m *Memory) Publish(ctx context.Context, topic string, msg []byte) error {
+	select {
+	case m.topic(topic) <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Consume implements Broker.
+func (m *Memory) Consume(ctx context.Context, topic string) ([]byte, error) {
+	select {
+	case msg := <-m.topic(topic):
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Publish publishes v encoded as JSON to topic.
+func Publish[T any](ctx context.Context, b Broker, topic string, v T) error {
+	return PublishValue(ctx, b, topic, v)
+}
+
+// Consume consumes the next message of topic decoded from JSON.
+func Consume[T any](ctx context.Context, b Broker, topic string) (T, error) {
+	var v T
+	if err := ConsumeTo(ctx, b, topic, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// PublishValue is Publish for any v.
+func PublishValue(ctx context.Context, b Broker, topic string, v any) error {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode message for %q: %v", topic, err)
+	}
+
+	return b.Publish(ctx, topic, msg)
+}
+
+// ConsumeTo is Consume decoding into dst, a pointer.
+func ConsumeTo(ctx context.Context, b Broker, topic string, dst any) error {
+	msg, err := b.Consume(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(msg, dst); err != nil {
+		return fmt.Errorf("cannot decode message of %q: %v", topic, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package flobroker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo/flobroker"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	ID   int
+	Name string
+}
+
+func TestPublishConsume(t *testing.T) {
+	ctx := context.Background()
+	b := flobroker.NewMemory()
+
+	require.NoError(t, flobroker.Publish(ctx, b, "events", event{ID: 1, Name: "created"}))
+	require.NoError(t, flobroker.Publish(ctx, b, "events", event{ID: 2, Name: "updated"}))
+
+	for _, want := range []event{{ID: 1, Name: "created"}, {ID: 2, Name: "updated"}} {
+		got, err := flobroker.Consume[event](ctx, b, "events")
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	require.NoError(t, b.Publish(ctx, "other", []byte("not json")))
+	_, err := flobroker.Consume[event](ctx, b, "other")
+	require.ErrorContains(t, err, `cannot decode message of "other"`)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = flobroker.Consume[event](canceled, b, "events")
+	require.ErrorIs(t, err, context.Canceled)
+}
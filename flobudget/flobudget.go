@@ -0,0 +1,96 @@
+// Package flobudget divides a time budget between the steps of a flo, used
+// by Execute and the code rendered with WithDeadlineBudget.
+package flobudget
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Budget divides the time left before a deadline between steps, in
+// proportion to their weights, e.g. their expected latency.
+type Budget struct {
+	mu       sync.Mutex
+	deadline time.Time
+	weights  []time.Duration
+	started  []bool
+}
+
+// New budgets total, or the time left before the deadline of ctx when
+// sooner, for the steps of the given weights. Steps without weight weigh
+// the mean of the others, or all weigh the same when none has a weight.
+func New(ctx context.Context, total time.Duration, weights ...time.Duration) *Budget {
+	deadline := time.Now().Add(total)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	var (
+		sum   time.Duration
+		known int
+	)
+	for _, w := range weights {
+		if w > 0 {
+			sum += w
+			known++
+		}
+	}
+	mean := time.Duration(1)
+	if known > 0 {
+		mean = sum / time.Duration(known)
+	}
+
+	b := &Budget{
+		deadline: deadline,
+		weights:  make([]time.Duration, len(weights)),
+		started:  make([]bool, len(weights)),
+	}
+	for i, w := range weights {
+		if w <= 0 {
+			w = mean
+		}
+		b.weights[i] = w
+	}
+
+	return b
+}
+
+// Deadline is the deadline of the whole budget.
+func (b *Budget) Deadline() time.Time {
+	return b.deadline
+}
+
+// Step derives from ctx the context of the step i, whose deadline is the
+// share of the step of the time left, the steps already started aside.
+func (b *Budget) Step(ctx context.Context, i int) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, b.stepDeadline(i))
+}
+
+func (b *Budget) stepDeadline(i int) time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if i < 0 || i >= len(b.weights) {
+		return b.deadline
+	}
+
+	var left time.Duration
+	for j, w := range b.weights {
+		if !b.started[j] {
+			left += w
+		}
+	}
+	if b.started[i] {
+		left += b.weights[i]
+	}
+	b.started[i] = true
+
+	remaining := time.Until(b.deadline)
+	if remaining <= 0 {
+		return b.deadline
+	}
+	share := time.Duration(float64(remaining) * float64(b.weights[i]) / float64(left))
+
+	return time.Now().Add(share)
+}
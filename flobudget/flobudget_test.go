@@ -0,0 +1,54 @@
+package flobudget_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo/flobudget"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	t.Run("Weights", func(t *testing.T) {
+		b := flobudget.New(context.Background(), time.Second, time.Millisecond, 3*time.Millisecond)
+
+		ctx, cancel := b.Step(context.Background(), 0)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.InDelta(t, 250*time.Millisecond, time.Until(deadline), float64(20*time.Millisecond))
+
+		// The last step gets the whole time left.
+		ctx, cancel = b.Step(context.Background(), 1)
+		defer cancel()
+		deadline, _ = ctx.Deadline()
+		require.InDelta(t, time.Second, time.Until(deadline), float64(20*time.Millisecond))
+	})
+
+	t.Run("Unknown weights", func(t *testing.T) {
+		b := flobudget.New(context.Background(), time.Second, 0, 0)
+
+		ctx, cancel := b.Step(context.Background(), 0)
+		defer cancel()
+		deadline, _ := ctx.Deadline()
+		require.InDelta(t, 500*time.Millisecond, time.Until(deadline), float64(20*time.Millisecond))
+	})
+
+	t.Run("Context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		b := flobudget.New(ctx, time.Hour, time.Millisecond)
+		require.WithinDuration(t, time.Now().Add(100*time.Millisecond), b.Deadline(), 20*time.Millisecond)
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		b := flobudget.New(context.Background(), 0, time.Millisecond)
+
+		ctx, cancel := b.Step(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
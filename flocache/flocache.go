@@ -0,0 +1,242 @@
+// Package flocache caches values for the cache components of flo.
+package flocache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Cache stores encoded values by key, e.g. in Redis.
+type Cache interface {
+	// Get returns the value of key, if found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of key for ttl, forever when 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Default is the cache used by flo when none is injected, e.g. when
+// running flos directly.
+var Default Cache = NewMemory()
+
+// Memory is an in-memory Cache.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+type entry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemory creates an empty in-memory cache.
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]entry),
+		now:     time.Now,
+	}
+}
+
+// Get implements Cache.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, found := m.entries[key]
+	if !found {
+		return nil, false, nil
+	}
+	if !e.expires.IsZero() && !m.now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+// Set implements Cache.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expires = m.now().Add(ttl)
+	}
+	m.entries[key] = e
+
+	return nil
+}
+
+// Get returns the value of key decoded from JSON, if found.
+func Get[T any](ctx context.Context, c Cache, key string) (T, bool, error) {
+	var v T
+	found, err := GetTo(ctx, c, key, &v)
+	if err != nil || !found {
+		var zero T
+		return zero, false, err
+	}
+
+	return v, true, nil
+}
+
+// Set stores v encoded as JSON under key for ttl, forever when 0.
+func Set[T any](ctx context.Context, c Cache, key string, v T, ttl time.Duration) error {
+	return SetValue(ctx, c, key, v, ttl)
+}
+
+// Aside returns the cached value of key, computing and caching it for ttl
+// when missing. Errors of compute are not cached.
+func Aside[T any](ctx context.Context, c Cache, key string, ttl time.Duration, compute func() (T, error)) (T, error) {
+	var zero T
+	if v, found, err := Get[T](ctx, c, key); err != nil || found {
+		return v, err
+	}
+
+	v, err := compute()
+	if err != nil {
+		return zero, err
+	}
+	if err := Set(ctx, c, key, v, ttl); err != nil {
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// GetTo is Get decoding into dst, a pointer.
+func GetTo(ctx context.Context, c Cache, key string, dst any) (bool, error) {
+	data, found, err := c.Get(ctx, key)
+	if err != nil || !found {
+		return false, err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("cannot decode cached %q: %v", key, err)
+	}
+
+	return true, nil
+}
+
+// SetValue is Set for any v.
+func SetValue(ctx context.Context, c Cache, key string, v any, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode %q: %v", key, err)
+	}
+
+	return c.Set(ctx, key, data, ttl)
+}
+
+// AsideTo is Aside setting the value to dst, a pointer.
+func AsideTo(ctx context.Context, c Cache, key string, ttl time.Duration, dst any, compute func() (any, error)) error {
+	if found, err := GetTo(ctx, c, key, dst); err != nil || found {
+		return err
+	}
+
+	v, err := compute()
+	if err != nil {
+		return err
+	}
+	if err := SetValue(ctx, c, key, v, ttl); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(dst).Elem()
+	if v == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	rv.Set(reflect.ValueOf(v))
+
+	return nil
+}
+
+// Key derives the cache key of the call of the function prefix with args,
+// from their types and JSON encodings. The types of args must pass
+// CheckKey.
+func Key(prefix string, args ...any) string {
+	h := sha256.New()
+	for _, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			// e.g. NaN floats, told apart by their printed value.
+			data = []byte(fmt.Sprint(arg))
+		}
+		fmt.Fprintf(h, "%T\x00%s\x00", arg, data)
+	}
+
+	return prefix + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// CheckKey checks that the values of t can be part of a key, i.e. that
+// their JSON encoding tells them apart. Interfaces, functions, channels
+// and structs with unexported or ignored fields cannot.
+func CheckKey(t reflect.Type) error {
+	return checkKey(t, make(map[reflect.Type]struct{}))
+}
+
+func checkKey(t reflect.Type, seen map[reflect.Type]struct{}) error {
+	if _, found := seen[t]; found {
+		return nil
+	}
+	seen[t] = struct{}{}
+
+	if isMarshaler(t) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return checkKey(t.Elem(), seen)
+	case reflect.Map:
+		switch k := t.Key(); {
+		case k.Kind() == reflect.String, isMarshaler(k):
+		case k.Kind() >= reflect.Int && k.Kind() <= reflect.Uintptr:
+		default:
+			return fmt.Errorf("%s cannot be part of a key: unsupported map key %s", t, k)
+		}
+		return checkKey(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				return fmt.Errorf("%s cannot be part of a key: field %s is not encoded", t, field.Name)
+			}
+			if err := checkKey(field.Type, seen); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s cannot be part of a key", t)
+	}
+}
+
+// isMarshaler reports whether t encodes itself.
+func isMarshaler(t reflect.Type) bool {
+	for _, i := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(i) || reflect.PointerTo(t).Implements(i) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package flocache_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo/flocache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	c := flocache.NewMemory()
+
+	_, found, err := flocache.Get[int](ctx, c, "a")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, flocache.Set(ctx, c, "a", 42, 0))
+	v, found, err := flocache.Get[int](ctx, c, "a")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 42, v)
+
+	require.NoError(t, flocache.Set(ctx, c, "b", 1, time.Millisecond))
+	time.Sleep(2 * time.Millisecond)
+	_, found, err = flocache.Get[int](ctx, c, "b")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	_, _, err = flocache.Get[string](ctx, c, "a")
+	require.ErrorContains(t, err, `cannot decode cached "a"`)
+}
+
+func TestAside(t *testing.T) {
+	ctx := context.Background()
+	c := flocache.NewMemory()
+
+	calls := 0
+	compute := func() (string, error) {
+		calls++
+		return "computed", nil
+	}
+	for range 2 {
+		v, err := flocache.Aside(ctx, c, "k", 0, compute)
+		require.NoError(t, err)
+		require.Equal(t, "computed", v)
+	}
+	require.Equal(t, 1, calls)
+
+	failure := errors.New("failure")
+	_, err := flocache.Aside(ctx, c, "failing", 0, func() (string, error) { return "", failure })
+	require.ErrorIs(t, err, failure)
+	_, found, err := c.Get(ctx, "failing")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, flocache.Key("pkg.Fn", 1, "a"), flocache.Key("pkg.Fn", 1, "a"))
+	require.NotEqual(t, flocache.Key("pkg.Fn", 1, "a"), flocache.Key("pkg.Fn", 1, "b"))
+	require.NotEqual(t, flocache.Key("pkg.Fn", 1), flocache.Key("pkg.Other", 1))
+	require.NotEqual(t, flocache.Key("pkg.Fn", 1), flocache.Key("pkg.Fn", int64(1)))
+	require.NotEqual(t, flocache.Key("pkg.Fn", []string{"a", "b"}), flocache.Key("pkg.Fn", "a", "b"))
+}
+
+type keyable struct {
+	Name  string
+	Since time.Time
+	Tags  map[string][]int
+	Next  *keyable
+}
+
+type hidden struct {
+	Name   string
+	secret string
+}
+
+type ignored struct {
+	Name   string
+	Secret string `json:"-"`
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[int](),
+		reflect.TypeFor[[]string](),
+		reflect.TypeFor[map[int]float64](),
+		reflect.TypeFor[*keyable](),
+	} {
+		require.NoError(t, flocache.CheckKey(typ), typ)
+	}
+
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[any](),
+		reflect.TypeFor[context.Context](),
+		reflect.TypeFor[func()](),
+		reflect.TypeFor[chan int](),
+		reflect.TypeFor[complex128](),
+		reflect.TypeFor[hidden](),
+		reflect.TypeFor[[]ignored](),
+		reflect.TypeFor[map[[2]int]string](),
+	} {
+		require.Error(t, flocache.CheckKey(typ), typ)
+	}
+}
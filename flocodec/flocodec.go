@@ -0,0 +1,21 @@
+// Package flocodec provides serialization helpers used by the codec
+// components of flo.
+package flocodec
+
+import "encoding/json"
+
+// EncodeJSON marshals v to JSON.
+func EncodeJSON[T any](v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeJSON unmarshals data into a new T.
+func DecodeJSON[T any](data []byte) (T, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,39 @@
+// Package floflag evaluates the feature flags gating the components of flo.
+package floflag
+
+import "context"
+
+// Provider tells whether feature flags are enabled, e.g. by querying a
+// feature flag service.
+type Provider interface {
+	Enabled(ctx context.Context, name string) bool
+}
+
+// Static enables the flags set to true, e.g. in tests.
+type Static map[string]bool
+
+// Enabled implements Provider.
+func (s Static) Enabled(_ context.Context, name string) bool {
+	return s[name]
+}
+
+// ProviderFunc adapts a function to Provider.
+type ProviderFunc func(ctx context.Context, name string) bool
+
+// Enabled implements Provider.
+func (fn ProviderFunc) Enabled(ctx context.Context, name string) bool {
+	return fn(ctx, name)
+}
+
+// Default is the provider used by flo when none is injected. It enables
+// no flag.
+var Default Provider = Static{}
+
+// Enabled tells whether the flag name is enabled by p, Default when nil.
+func Enabled(ctx context.Context, p Provider, name string) bool {
+	if p == nil {
+		p = Default
+	}
+
+	return p.Enabled(ctx, name)
+}
@@ -0,0 +1,27 @@
+package floflag_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo/floflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	flags := floflag.Static{"checkout-v2": true, "dark-mode": false}
+	require.True(t, floflag.Enabled(ctx, flags, "checkout-v2"))
+	require.False(t, floflag.Enabled(ctx, flags, "dark-mode"))
+	require.False(t, floflag.Enabled(ctx, flags, "unknown"))
+
+	require.False(t, floflag.Enabled(ctx, nil, "checkout-v2"))
+
+	type userKey struct{}
+	beta := floflag.ProviderFunc(func(ctx context.Context, name string) bool {
+		return name == "checkout-v2" && ctx.Value(userKey{}) == "beta"
+	})
+	require.True(t, floflag.Enabled(context.WithValue(ctx, userKey{}, "beta"), beta, "checkout-v2"))
+	require.False(t, floflag.Enabled(ctx, beta, "checkout-v2"))
+}
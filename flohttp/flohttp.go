@@ -0,0 +1,149 @@
+// Package flohttp sends the JSON requests of the HTTP components of flo.
+package flohttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Doer sends HTTP requests, e.g. an *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client sends the requests.
+var Client Doer = http.DefaultClient
+
+// StatusError is returned for responses with a non 2xx status.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Fetch sends a request without body to the URL expanded from urlTemplate
+// and params, decoding the JSON response into a Res.
+func Fetch[Res any](ctx context.Context, method, urlTemplate string, params []string) (Res, error) {
+	var res Res
+	if err := Do(ctx, method, urlTemplate, params, nil, &res); err != nil {
+		var zero Res
+		return zero, err
+	}
+
+	return res, nil
+}
+
+// Send is like Fetch but sends body encoded as JSON.
+func Send[Req, Res any](ctx context.Context, method, urlTemplate string, params []string, body Req) (Res, error) {
+	var res Res
+	if err := Do(ctx, method, urlTemplate, params, body, &res); err != nil {
+		var zero Res
+		return zero, err
+	}
+
+	return res, nil
+}
+
+// Do sends a request to the URL expanded from urlTemplate and params, with
+// body encoded as JSON unless nil, decoding the JSON response, if any, into
+// res.
+func Do(ctx context.Context, method, urlTemplate string, params []string, body, res any) error {
+	u, err := Expand(urlTemplate, params)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("cannot encode body: %v", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Body: data}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("cannot decode response: %v", err)
+	}
+
+	return nil
+}
+
+// Expand fills the {name} placeholders of urlTemplate with params, in
+// order, escaping them for the part of the URL they are in.
+func Expand(urlTemplate string, params []string) (string, error) {
+	var (
+		sb    strings.Builder
+		n     int
+		query bool
+	)
+	for rest := urlTemplate; rest != ""; {
+		start := strings.IndexAny(rest, "{?")
+		if start < 0 {
+			sb.WriteString(rest)
+			break
+		}
+		sb.WriteString(rest[:start])
+		if rest[start] == '?' {
+			query = true
+			sb.WriteByte('?')
+			rest = rest[start+1:]
+			continue
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed placeholder in %q", urlTemplate)
+		}
+		if n == len(params) {
+			return "", fmt.Errorf("missing param %s in %q", rest[start:start+end+1], urlTemplate)
+		}
+		if query {
+			sb.WriteString(url.QueryEscape(params[n]))
+		} else {
+			sb.WriteString(url.PathEscape(params[n]))
+		}
+		n++
+		rest = rest[start+end+1:]
+	}
+	if n < len(params) {
+		return "", errors.New("too many params")
+	}
+
+	return sb.String(), nil
+}
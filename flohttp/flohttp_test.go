@@ -0,0 +1,65 @@
+package flohttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mgjules/flo/flohttp"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func TestExpand(t *testing.T) {
+	u, err := flohttp.Expand("https://api.test/users/{id}/posts?q={q}", []string{"a b", "c&d"})
+	require.NoError(t, err)
+	require.Equal(t, "https://api.test/users/a%20b/posts?q=c%26d", u)
+
+	_, err = flohttp.Expand("https://api.test/users/{id}", nil)
+	require.ErrorContains(t, err, "missing param {id}")
+
+	_, err = flohttp.Expand("https://api.test/users", []string{"a"})
+	require.ErrorContains(t, err, "too many params")
+
+	_, err = flohttp.Expand("https://api.test/users/{id", []string{"a"})
+	require.ErrorContains(t, err, "unclosed placeholder")
+}
+
+func TestRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			_, _ = io.WriteString(w, `{"name":"Ada"}`)
+		case "/users":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var u user
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&u))
+			_, _ = io.WriteString(w, `{"name":"`+u.Name+`!"}`)
+		default:
+			http.Error(w, "nope", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	u, err := flohttp.Fetch[user](ctx, http.MethodGet, srv.URL+"/users/{id}", []string{"1"})
+	require.NoError(t, err)
+	require.Equal(t, user{Name: "Ada"}, u)
+
+	u, err = flohttp.Send[user, user](ctx, http.MethodPost, srv.URL+"/users", nil, user{Name: "Charles"})
+	require.NoError(t, err)
+	require.Equal(t, user{Name: "Charles!"}, u)
+
+	_, err = flohttp.Fetch[user](ctx, http.MethodGet, srv.URL+"/users/{id}", []string{"2"})
+	var serr *flohttp.StatusError
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, http.StatusNotFound, serr.StatusCode)
+}
@@ -0,0 +1,97 @@
+// Package flosecret resolves secrets for the secret components of flo.
+//
+// Secrets are redacted whenever they are printed, logged or encoded so
+// that they do not leak through dumps, traces or error messages; only
+// Reveal gives their value away.
+package flosecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Redacted stands for the value of secrets.
+const Redacted = "[REDACTED]"
+
+// Secret is a sensitive value, e.g. a password or an API key.
+type Secret struct {
+	value string
+}
+
+// New wraps value into a secret.
+func New(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the value of the secret.
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+// String implements fmt.Stringer.
+func (Secret) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (Secret) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter so that no verb reveals the secret.
+func (Secret) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprint(f, Redacted)
+}
+
+// LogValue implements slog.LogValuer.
+func (Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler, hence JSON too.
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// Provider looks up secrets by name, e.g. in a vault.
+type Provider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// Env looks up secrets in the environment variables.
+type Env struct{}
+
+// ErrNotFound is returned by providers when a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Secret implements Provider.
+func (Env) Secret(_ context.Context, name string) (string, error) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+// Default is the provider used by flo when none is injected, e.g. when
+// running flos directly.
+var Default Provider = Env{}
+
+// Resolve looks up the secret name with p, Default when nil.
+func Resolve(ctx context.Context, p Provider, name string) (Secret, error) {
+	if p == nil {
+		p = Default
+	}
+
+	value, err := p.Secret(ctx, name)
+	if err != nil {
+		// The error of the provider is trusted not to hold the value.
+		return Secret{}, fmt.Errorf("cannot resolve secret %q: %w", name, err)
+	}
+
+	return New(value), nil
+}
@@ -0,0 +1,44 @@
+package flosecret_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/mgjules/flo/flosecret"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedaction(t *testing.T) {
+	s := flosecret.New("hunter2")
+	require.Equal(t, "hunter2", s.Reveal())
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		require.Equal(t, flosecret.Redacted, fmt.Sprintf(format, s), format)
+	}
+	require.Equal(t, "{"+flosecret.Redacted+"}", fmt.Sprintf("%v", struct{ S flosecret.Secret }{s}))
+
+	data, err := json.Marshal(map[string]flosecret.Secret{"password": s})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"password":"[REDACTED]"}`, string(data))
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(buf, nil)).Info("connecting", "password", s)
+	require.Contains(t, buf.String(), "password="+flosecret.Redacted)
+	require.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("FLOSECRET_TEST", "hunter2")
+
+	s, err := flosecret.Resolve(context.Background(), nil, "FLOSECRET_TEST")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", s.Reveal())
+
+	_, err = flosecret.Resolve(context.Background(), flosecret.Env{}, "FLOSECRET_MISSING")
+	require.ErrorIs(t, err, flosecret.ErrNotFound)
+	require.ErrorContains(t, err, `cannot resolve secret "FLOSECRET_MISSING"`)
+}
@@ -0,0 +1,111 @@
+// Package flosql runs the SQL queries of the database components of flo.
+package flosql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// QueryRow runs query with args, scanning the single row it returns into
+// a T. sql.ErrNoRows is returned when there is none.
+//
+// Structs, unless scanners themselves, are scanned field by field, in
+// order.
+func QueryRow[T any](ctx context.Context, db *sql.DB, query string, args []any) (T, error) {
+	var v T
+	if err := QueryRowTo(ctx, db, query, args, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// Query runs query with args, scanning each row it returns into a T like
+// QueryRow.
+func Query[T any](ctx context.Context, db *sql.DB, query string, args []any) ([]T, error) {
+	var v []T
+	if err := QueryTo(ctx, db, query, args, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Exec runs the statement query with args, returning the number of rows
+// affected.
+func Exec(ctx context.Context, db *sql.DB, query string, args []any) (int64, error) {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// QueryRowTo is QueryRow scanning into dst, a pointer.
+func QueryRowTo(ctx context.Context, db *sql.DB, query string, args []any, dst any) error {
+	return db.QueryRowContext(ctx, query, args...).Scan(targets(reflect.ValueOf(dst).Elem())...)
+}
+
+// QueryTo is Query appending to dst, a pointer to a slice.
+func QueryTo(ctx context.Context, db *sql.DB, query string, args []any, dst any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	s := reflect.ValueOf(dst).Elem()
+	for rows.Next() {
+		v := reflect.New(s.Type().Elem()).Elem()
+		if err := rows.Scan(targets(v)...); err != nil {
+			return err
+		}
+		s.Set(reflect.Append(s, v))
+	}
+
+	return rows.Err()
+}
+
+var (
+	scannerRType = reflect.TypeFor[sql.Scanner]()
+	timeRType    = reflect.TypeFor[time.Time]()
+)
+
+// targets returns the scan targets of v, an addressable value: its fields
+// for structs, unless scanners themselves, or v itself.
+func targets(v reflect.Value) []any {
+	if !scanned(v.Type()) {
+		return []any{v.Addr().Interface()}
+	}
+
+	res := make([]any, v.NumField())
+	for i := range res {
+		res[i] = v.Field(i).Addr().Interface()
+	}
+
+	return res
+}
+
+// scanned reports whether t is scanned field by field.
+func scanned(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeRType && !reflect.PointerTo(t).Implements(scannerRType)
+}
+
+// Check tells why t cannot be scanned, if it can't.
+func Check(t reflect.Type) error {
+	if !scanned(t) {
+		return nil
+	}
+	for i := range t.NumField() {
+		if f := t.Field(i); !f.IsExported() {
+			return fmt.Errorf("field %s of %s is not exported", f.Name, t)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package flosql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo/flosql"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver answers "users" with two users and "echo" with its arg.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+type fakeStmt struct {
+	query string
+}
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	switch s.query {
+	case "users":
+		return &fakeRows{
+			columns: []string{"name", "age"},
+			values:  [][]driver.Value{{"Ada", int64(36)}, {"Charles", int64(79)}},
+		}, nil
+	case "echo":
+		return &fakeRows{columns: []string{"v"}, values: [][]driver.Value{{args[0]}}}, nil
+	default:
+		return &fakeRows{columns: []string{"v"}}, nil
+	}
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("flosqltest", fakeDriver{})
+}
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestQueries(t *testing.T) {
+	db, err := sql.Open("flosqltest", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	u, err := flosql.QueryRow[user](ctx, db, "users", nil)
+	require.NoError(t, err)
+	require.Equal(t, user{Name: "Ada", Age: 36}, u)
+
+	users, err := flosql.Query[user](ctx, db, "users", nil)
+	require.NoError(t, err)
+	require.Equal(t, []user{{"Ada", 36}, {"Charles", 79}}, users)
+
+	v, err := flosql.QueryRow[int](ctx, db, "echo", []any{42})
+	require.NoError(t, err)
+	require.Equal(t, 42, v)
+
+	_, err = flosql.QueryRow[int](ctx, db, "nothing", nil)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	n, err := flosql.Exec(ctx, db, "delete", []any{1, 2})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, flosql.Check(reflect.TypeFor[user]()))
+	require.NoError(t, flosql.Check(reflect.TypeFor[sql.NullString]()))
+	require.ErrorContains(t, flosql.Check(reflect.TypeFor[struct{ name string }]()), "not exported")
+}
@@ -0,0 +1,134 @@
+// Package flosrc provides ready-made stream sources to be used with
+// flo.NewSourceComponent.
+//
+// Every source stops producing and closes its channel once the given context
+// is canceled.
+package flosrc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+)
+
+// Ticker emits the current time every d.
+func Ticker(ctx context.Context, d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time)
+
+	go func() {
+		defer close(ch)
+
+		t := time.NewTicker(d)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.C:
+				select {
+				case ch <- now:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Range emits the integers in [start, end).
+func Range(ctx context.Context, start, end int) <-chan int {
+	ch := make(chan int)
+
+	go func() {
+		defer close(ch)
+
+		for i := start; i < end; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Lines emits every line read from r.
+// The returned error channel receives at most one error and is closed
+// alongside the lines channel.
+func Lines(ctx context.Context, r io.Reader) (<-chan string, <-chan error) {
+	ch := make(chan string)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(ch)
+
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			select {
+			case ch <- s.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := s.Err(); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return ch, errCh
+}
+
+// PageFunc fetches a single page of items.
+// It reports whether there are more pages to fetch.
+type PageFunc[T any] func(ctx context.Context, page int) ([]T, bool, error)
+
+// Paginate emits every item of every page returned by fetch, starting at
+// page 0 until fetch reports that there are no more pages.
+// The returned error channel receives at most one error, the one of fetch or
+// of ctx when canceled before the last item, and is closed alongside the
+// items channel.
+func Paginate[T any](ctx context.Context, fetch PageFunc[T]) (<-chan T, <-chan error) {
+	ch := make(chan T)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(ch)
+
+		for page := 0; ; page++ {
+			if err := ctx.Err(); err != nil {
+				errCh <- err
+				return
+			}
+
+			items, more, err := fetch(ctx, page)
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			for _, item := range items {
+				select {
+				case ch <- item:
+				case <-ctx.Done():
+					errCh <- ctx.Err()
+					return
+				}
+			}
+
+			if !more {
+				return
+			}
+		}
+	}()
+
+	return ch, errCh
+}
@@ -0,0 +1,175 @@
+package flosrc_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/mgjules/flo/flosrc"
+	"github.com/stretchr/testify/require"
+)
+
+// requireNoLeak fails the test when the goroutines started since it was
+// called are still running once the test ends.
+func requireNoLeak(t *testing.T) {
+	t.Helper()
+
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		// Not require.Eventually as it runs goroutines of its own.
+		for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%d goroutines leaked", runtime.NumGoroutine()-before)
+			}
+		}
+	})
+}
+
+// requireClosed fails the test when ch is not closed, after being drained,
+// within a second.
+func requireClosed[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("channel not closed")
+		}
+	}
+}
+
+func TestTicker(t *testing.T) {
+	requireNoLeak(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := flosrc.Ticker(ctx, time.Millisecond)
+	first, second := <-ch, <-ch
+	require.True(t, second.After(first))
+
+	cancel()
+	requireClosed(t, ch)
+}
+
+func TestRange(t *testing.T) {
+	requireNoLeak(t)
+
+	var got []int
+	for i := range flosrc.Range(context.Background(), 2, 5) {
+		got = append(got, i)
+	}
+	require.Equal(t, []int{2, 3, 4}, got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := flosrc.Range(ctx, 0, 1000)
+	require.Equal(t, 0, <-ch)
+	cancel()
+	requireClosed(t, ch)
+}
+
+func TestLines(t *testing.T) {
+	t.Run("Lines", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ch, errCh := flosrc.Lines(context.Background(), strings.NewReader("a\nb\n\nc"))
+		var got []string
+		for line := range ch {
+			got = append(got, line)
+		}
+		require.Equal(t, []string{"a", "b", "", "c"}, got)
+		require.NoError(t, <-errCh)
+		requireClosed(t, errCh)
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ch, errCh := flosrc.Lines(context.Background(), iotest.TimeoutReader(strings.NewReader("a\n")))
+		requireClosed(t, ch)
+		require.ErrorIs(t, <-errCh, iotest.ErrTimeout)
+		requireClosed(t, errCh)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, errCh := flosrc.Lines(ctx, strings.NewReader("a\nb\nc\n"))
+		require.Equal(t, "a", <-ch)
+		cancel()
+		requireClosed(t, ch)
+		requireClosed(t, errCh)
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	pages := [][]int{{1, 2}, {3}, {4, 5}}
+	fetch := func(_ context.Context, page int) ([]int, bool, error) {
+		return pages[page], page < len(pages)-1, nil
+	}
+
+	t.Run("Pages", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ch, errCh := flosrc.Paginate(context.Background(), fetch)
+		var got []int
+		for item := range ch {
+			got = append(got, item)
+		}
+		require.Equal(t, []int{1, 2, 3, 4, 5}, got)
+		require.NoError(t, <-errCh)
+	})
+
+	t.Run("Fetch error", func(t *testing.T) {
+		requireNoLeak(t)
+
+		boom := errors.New("boom")
+		ch, errCh := flosrc.Paginate(context.Background(), func(ctx context.Context, page int) ([]int, bool, error) {
+			if page == 1 {
+				return nil, false, boom
+			}
+			return fetch(ctx, page)
+		})
+		var got []int
+		for item := range ch {
+			got = append(got, item)
+		}
+		require.Equal(t, []int{1, 2}, got)
+		require.ErrorIs(t, <-errCh, boom)
+		requireClosed(t, errCh)
+	})
+
+	t.Run("Canceled while emitting", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, errCh := flosrc.Paginate(ctx, fetch)
+		require.Equal(t, 1, <-ch)
+		cancel()
+		requireClosed(t, ch)
+		require.ErrorIs(t, <-errCh, context.Canceled)
+		requireClosed(t, errCh)
+	})
+
+	t.Run("Canceled between pages", func(t *testing.T) {
+		requireNoLeak(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, errCh := flosrc.Paginate(ctx, func(ctx context.Context, page int) ([]int, bool, error) {
+			// Cancel once the last item of the page is taken.
+			defer cancel()
+			return []int{page}, true, nil
+		})
+		require.Equal(t, 0, <-ch)
+		requireClosed(t, ch)
+		require.ErrorIs(t, <-errCh, context.Canceled)
+	})
+}
@@ -0,0 +1,92 @@
+// Package flostd provides the generic building blocks of the standard
+// components of flo, e.g. flo.NewStdComponent("Map", ...).
+package flostd
+
+import "errors"
+
+// ErrChunkSize is returned by Chunk for sizes lower than 1.
+var ErrChunkSize = errors.New("chunk size must be positive")
+
+// Map applies fn to each element of s.
+func Map[T, U any](s []T, fn func(T) U) []U {
+	res := make([]U, len(s))
+	for i, v := range s {
+		res[i] = fn(v)
+	}
+
+	return res
+}
+
+// Filter keeps the elements of s for which keep returns true.
+func Filter[T any](s []T, keep func(T) bool) []T {
+	var res []T
+	for _, v := range s {
+		if keep(v) {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Reduce folds the elements of s into an accumulator starting at init.
+func Reduce[T, A any](s []T, init A, fn func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = fn(acc, v)
+	}
+
+	return acc
+}
+
+// First returns the first element of s, if any.
+func First[T any](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return s[0], true
+}
+
+// Chunk splits s into slices of size elements, the last one holding the
+// remaining elements.
+func Chunk[T any](s []T, size int) ([][]T, error) {
+	if size < 1 {
+		return nil, ErrChunkSize
+	}
+
+	var res [][]T
+	for len(s) > size {
+		res = append(res, s[:size:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		res = append(res, s[:len(s):len(s)])
+	}
+
+	return res, nil
+}
+
+// Merge concatenates a and b into a new slice.
+func Merge[T any](a, b []T) []T {
+	res := make([]T, 0, len(a)+len(b))
+	res = append(res, a...)
+	return append(res, b...)
+}
+
+// Zip pairs the elements of a and b, up to the shortest of both.
+func Zip[T, U any](a []T, b []U) []struct {
+	First  T
+	Second U
+} {
+	res := make([]struct {
+		First  T
+		Second U
+	}, min(len(a), len(b)))
+	for i := range res {
+		res[i].First, res[i].Second = a[i], b[i]
+	}
+
+	return res
+}
@@ -0,0 +1,36 @@
+package flostd_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mgjules/flo/flostd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlostd(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, flostd.Map(s, strconv.Itoa))
+	require.Equal(t, []int{2, 4}, flostd.Filter(s, func(v int) bool { return v%2 == 0 }))
+	require.Equal(t, 15, flostd.Reduce(s, 0, func(acc, v int) int { return acc + v }))
+
+	first, ok := flostd.First(s)
+	require.True(t, ok)
+	require.Equal(t, 1, first)
+	_, ok = flostd.First([]int{})
+	require.False(t, ok)
+
+	chunks, err := flostd.Chunk(s, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+	_, err = flostd.Chunk(s, 0)
+	require.ErrorIs(t, err, flostd.ErrChunkSize)
+
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6}, flostd.Merge(s, []int{6}))
+
+	pairs := flostd.Zip(s, []string{"a", "b"})
+	require.Len(t, pairs, 2)
+	require.Equal(t, 2, pairs[1].First)
+	require.Equal(t, "b", pairs[1].Second)
+}
@@ -0,0 +1,228 @@
+// Package flotest helps testing code embedding flos.
+package flotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+)
+
+// StressOption configures Stress.
+type StressOption func(*stressOptions)
+
+type stressOptions struct {
+	workers    int
+	iterations int
+	seed       uint64
+}
+
+// WithWorkers sets the number of goroutines hammering the flo. Defaults to 8.
+func WithWorkers(n int) StressOption {
+	return func(o *stressOptions) {
+		o.workers = n
+	}
+}
+
+// WithIterations sets the number of operations done by each worker.
+// Defaults to 200.
+func WithIterations(n int) StressOption {
+	return func(o *stressOptions) {
+		o.iterations = n
+	}
+}
+
+// WithSeed makes the operations picked by the workers reproducible.
+func WithSeed(seed uint64) StressOption {
+	return func(o *stressOptions) {
+		o.seed = seed
+	}
+}
+
+// stressInc backs the components added by Stress.
+func stressInc(v int) int {
+	return v + 1
+}
+
+// Stress hammers f with concurrent mutations and reads, i.e. adding,
+// connecting, sequencing and deleting components while rendering,
+// fingerprinting, validating and encoding it. Run it under -race.
+//
+// Mutations are expected to conflict and fail, only panics and broken
+// invariants are reported. The components added by Stress are removed
+// before it returns.
+func Stress(f *flo.Flo, opts ...StressOption) error {
+	o := stressOptions{
+		workers:    8,
+		iterations: 200,
+		seed:       rand.Uint64(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &stress{f: f}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewPCG(o.seed, uint64(w)))
+			for i := 0; i < o.iterations; i++ {
+				if err := s.step(r); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("worker %d, iteration %d: %w", w, i, err))
+					mu.Unlock()
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if err := s.cleanup(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+type stress struct {
+	f *flo.Flo
+
+	mu         sync.Mutex
+	components []*flo.Component
+}
+
+// pick returns one of the components added so far.
+func (s *stress) pick(r *rand.Rand) (*flo.Component, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.components) == 0 {
+		return nil, false
+	}
+
+	return s.components[r.IntN(len(s.components))], true
+}
+
+func (s *stress) step(r *rand.Rand) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	ctx := context.Background()
+
+	switch r.IntN(12) {
+	case 0, 1:
+		c, err := flo.NewComponent("Inc", "github.com/mgjules/flo/flotest", "Inc", "Inc", stressInc)
+		if err != nil {
+			return err
+		}
+		if err := s.f.AddComponent(c); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.components = append(s.components, c)
+		s.mu.Unlock()
+	case 2, 3:
+		out, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		in, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		// The ios of a component never change, only their connections.
+		_ = s.f.ConnectComponent(out.ID, out.IOs[1].ID, in.ID, in.IOs[0].ID)
+	case 4:
+		c, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		if ids := s.f.Connections(c.ID); len(ids) > 0 {
+			_ = s.f.DeleteConnection(ids[r.IntN(len(ids))])
+			return nil
+		}
+		_ = s.f.SetLiteral(c.ID, c.IOs[0].ID, r.IntN(10))
+	case 5:
+		before, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		after, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		_ = s.f.ConnectSequence(before.ID, after.ID)
+	case 6:
+		c, ok := s.pick(r)
+		if !ok {
+			return nil
+		}
+		if err := s.f.DeleteComponent(c.ID); err == nil {
+			s.mu.Lock()
+			for i := range s.components {
+				if s.components[i] == c {
+					s.components = append(s.components[:i], s.components[i+1:]...)
+					break
+				}
+			}
+			s.mu.Unlock()
+		}
+	case 7:
+		_ = s.f.Render(ctx, io.Discard)
+	case 8:
+		_ = s.f.RenderIncremental(ctx, io.Discard)
+	case 9:
+		_ = s.f.Fingerprint()
+		_ = s.f.DirtyComponents()
+		_ = s.f.Symbols()
+	case 10:
+		_ = s.f.Validate(ctx)
+		_ = s.f.EncodeBinary(io.Discard, nil)
+	case 11:
+		return s.f.Invariants()
+	}
+
+	return nil
+}
+
+// cleanup removes the components added by Stress and their connections.
+func (s *stress) cleanup() error {
+	// Connections between two of the components are listed twice.
+	deleted := make(map[uuid.UUID]struct{})
+	for _, c := range s.components {
+		for _, id := range s.f.Connections(c.ID) {
+			if _, found := deleted[id]; found {
+				continue
+			}
+			if err := s.f.DeleteConnection(id); err != nil {
+				return fmt.Errorf("cannot delete connection id %q: %w", id, err)
+			}
+			deleted[id] = struct{}{}
+		}
+	}
+	for _, c := range s.components {
+		if err := s.f.DeleteComponent(c.ID); err != nil {
+			return fmt.Errorf("cannot delete component id %q: %w", c.ID, err)
+		}
+	}
+	s.components = nil
+
+	return s.f.Invariants()
+}
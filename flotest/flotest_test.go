@@ -0,0 +1,28 @@
+package flotest_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flotest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStress(t *testing.T) {
+	f, err := flo.NewFlo("TestStress", "Test Stress", "Test Stress Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	before := f.Fingerprint()
+
+	require.NoError(t, flotest.Stress(f, flotest.WithWorkers(4), flotest.WithIterations(100), flotest.WithSeed(42)))
+	require.Empty(t, f.Components)
+	require.Equal(t, before, f.Fingerprint())
+	require.NoError(t, f.Render(context.Background(), io.Discard))
+}
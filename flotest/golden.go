@@ -0,0 +1,125 @@
+package flotest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+)
+
+// Golden compares the code rendered from f with opts to the golden file at
+// path, or writes the golden file when update is set, typically by an
+// -update flag:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func TestFlo(t *testing.T) {
+//		flotest.Golden(t, newFlo(t), "testdata/flo.go.golden", *update)
+//	}
+func Golden(t testing.TB, f *flo.Flo, path string, update bool, opts ...flo.RenderOption) {
+	t.Helper()
+
+	got := &bytes.Buffer{}
+	if err := f.Render(context.Background(), got, opts...); err != nil {
+		t.Fatalf("cannot render flo: %v", err)
+		return
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create golden file directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("cannot write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read golden file, run with -update to create it: %v", err)
+		return
+	}
+	if diff := lineDiff(string(bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n"))), got.String()); diff != "" {
+		t.Errorf("rendered code differs from %s, run with -update to accept it:\n%s", path, diff)
+	}
+}
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 2
+
+// lineDiff returns the lines removed from want, prefixed with "-", and
+// added to got, prefixed with "+", around some context. It is empty when
+// want and got are equal.
+func lineDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Only the changes and the lines around them are shown.
+	show := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(k-diffContext, 0); c <= min(k+diffContext, len(lines)-1); c++ {
+			show[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	for k, l := range lines {
+		if !show[k] {
+			if k > 0 && show[k-1] {
+				sb.WriteString("...\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&sb, "%c%s\n", l.op, l.text)
+	}
+
+	return sb.String()
+}
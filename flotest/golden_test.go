@@ -0,0 +1,70 @@
+package flotest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flotest"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the failures of Golden.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestGolden(t *testing.T) {
+	f, err := flo.NewFlo("TestGolden", "Test Golden", "Test Golden Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	n, s := flo.In[int]("n"), flo.Out[string]("s")
+	require.NoError(t, f.AddIO(n))
+	require.NoError(t, f.AddIO(s))
+	itoa, err := flo.NewComponent("Itoa", "strconv", "Itoa", "Format n", strconv.Itoa)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(itoa))
+	require.NoError(t, f.ConnectComponent(f.ID, n.ID, itoa.ID, itoa.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(itoa.ID, itoa.IOs[1].ID, f.ID, s.ID))
+
+	path := filepath.Join(t.TempDir(), "testdata", "golden.go.golden")
+
+	r := &recorder{}
+	flotest.Golden(r, f, path, false)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "run with -update to create it")
+
+	flotest.Golden(t, f, path, true)
+	flotest.Golden(t, f, path, false)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "func TestGolden(n int) string {")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), "strconv.Itoa(n)", "strconv.Itoa(n + 1)", 1)), 0o644))
+
+	r = &recorder{}
+	flotest.Golden(r, f, path, false)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "rendered code differs from "+path)
+	require.Contains(t, r.errors[0], `
+ 	// Format n
+-	ioffa16Acdd887621A448177A20D92D9Fb68219293 := strconv.Itoa(n + 1)
++	ioffa16Acdd887621A448177A20D92D9Fb68219293 := strconv.Itoa(n)
+ 
+`)
+}
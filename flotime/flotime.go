@@ -0,0 +1,67 @@
+// Package flotime backs the time components of flo, which are rendered
+// inline rather than as calls to this package.
+package flotime
+
+import (
+	"context"
+	"time"
+)
+
+// Now returns the current time.
+func Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t.
+func Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Until returns the duration until t.
+func Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// Sleep pauses for d or until ctx is done, in which case it returns the
+// error of ctx.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deadline returns the deadline of ctx, if any.
+func Deadline(ctx context.Context) (time.Time, bool) {
+	return ctx.Deadline()
+}
+
+// Expired reports whether deadline has passed.
+func Expired(deadline time.Time) bool {
+	return !time.Now().Before(deadline)
+}
+
+// Add returns t+d.
+func Add(t time.Time, d time.Duration) time.Time {
+	return t.Add(d)
+}
+
+// Sub returns the duration t-u.
+func Sub(t, u time.Time) time.Duration {
+	return t.Sub(u)
+}
+
+// AddDuration returns d+e.
+func AddDuration(d, e time.Duration) time.Duration {
+	return d + e
+}
+
+// ScaleDuration returns d*n.
+func ScaleDuration(d time.Duration, n int) time.Duration {
+	return d * time.Duration(n)
+}
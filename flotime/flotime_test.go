@@ -0,0 +1,41 @@
+package flotime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo/flotime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleep(t *testing.T) {
+	require.NoError(t, flotime.Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, flotime.Sleep(ctx, time.Hour), context.Canceled)
+}
+
+func TestDeadline(t *testing.T) {
+	_, ok := flotime.Deadline(context.Background())
+	require.False(t, ok)
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	got, ok := flotime.Deadline(ctx)
+	require.True(t, ok)
+	require.Equal(t, deadline, got)
+
+	require.False(t, flotime.Expired(deadline))
+	require.True(t, flotime.Expired(time.Now().Add(-time.Second)))
+}
+
+func TestArithmetic(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := flotime.Add(t0, time.Hour)
+	require.Equal(t, time.Hour, flotime.Sub(t1, t0))
+	require.Equal(t, 90*time.Minute, flotime.AddDuration(time.Hour, 30*time.Minute))
+	require.Equal(t, 3*time.Second, flotime.ScaleDuration(time.Second, 3))
+}
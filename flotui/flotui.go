@@ -0,0 +1,204 @@
+// Package flotui is a terminal viewer of flos, e.g. to debug them over SSH
+// where no browser is available.
+//
+// The viewer lists the components of the flo layer by layer, as placed by
+// Flo.Layout, shows the ios and connections of the selected one, and renders
+// or validates the flo on demand. The graph is a snapshot of the flo taken
+// when the viewer is created.
+package flotui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+)
+
+// Run shows f until the user quits.
+func Run(f *flo.Flo) error {
+	_, err := tea.NewProgram(New(f), tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeGraph mode = iota
+	modeSource
+	modeValidation
+)
+
+// Model is the bubbletea model of the viewer.
+type Model struct {
+	f           *flo.Flo
+	view        flo.FloView
+	components  []flo.ComponentView
+	connections map[uuid.UUID]flo.ConnectionView
+	cursor      int
+	mode        mode
+	output      []string // Lines of the rendered source or validation errors.
+	offset      int      // First visible line of output.
+	height      int
+}
+
+// New creates the viewer of f.
+func New(f *flo.Flo) Model {
+	view := f.View()
+	components := slices.Clone(view.Components)
+	// Positions from Layout give the execution order, labels break ties.
+	sort.SliceStable(components, func(i, j int) bool {
+		a, b := components[i], components[j]
+		if a.Position.X != b.Position.X {
+			return a.Position.X < b.Position.X
+		}
+		if a.Position.Y != b.Position.Y {
+			return a.Position.Y < b.Position.Y
+		}
+		return a.Label < b.Label
+	})
+
+	connections := make(map[uuid.UUID]flo.ConnectionView, len(view.Connections))
+	for _, conn := range view.Connections {
+		connections[conn.ID] = conn
+	}
+
+	return Model{
+		f:           f,
+		view:        view,
+		components:  components,
+		connections: connections,
+		height:      24,
+	}
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			m.mode, m.output, m.offset = modeGraph, nil, 0
+		case "up", "k":
+			if m.mode != modeGraph {
+				m.offset = max(m.offset-1, 0)
+				break
+			}
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			if m.mode != modeGraph {
+				m.offset = min(m.offset+1, max(len(m.output)-1, 0))
+				break
+			}
+			m.cursor = min(m.cursor+1, max(len(m.components)-1, 0))
+		case "r":
+			m.mode, m.offset = modeSource, 0
+			out := &bytes.Buffer{}
+			if err := m.f.Render(context.Background(), out); err != nil {
+				m.output = []string{"render failed: " + err.Error()}
+				break
+			}
+			m.output = strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+		case "v":
+			m.mode, m.offset = modeValidation, 0
+			m.output = []string{"no problems found"}
+			if errs := m.f.Validate(context.Background()); len(errs) > 0 {
+				m.output = m.output[:0]
+				for _, err := range errs {
+					m.output = append(m.output, err.Error())
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s — %s\n\n", m.view.Name, m.view.Label)
+
+	switch m.mode {
+	case modeSource, modeValidation:
+		end := min(m.offset+max(m.height-4, 1), len(m.output))
+		for _, line := range m.output[m.offset:end] {
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n↑/↓ scroll • esc back • q quit\n")
+		return sb.String()
+	}
+
+	if len(m.components) == 0 {
+		sb.WriteString("no components\n")
+	}
+	for i, c := range m.components {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		fmt.Fprintf(&sb, "%s%s (%s.%s)\n", cursor, c.Label, c.PkgPath, c.Name)
+	}
+
+	if m.cursor < len(m.components) {
+		c := m.components[m.cursor]
+		fmt.Fprintf(&sb, "\n%s\n", c.Description)
+		for _, io := range c.IOs {
+			fmt.Fprintf(&sb, "  %-3s %s %s%s\n", io.Type, io.RType, io.Name, m.ends(io))
+		}
+	}
+
+	sb.WriteString("\n↑/↓ select • r render • v validate • q quit\n")
+
+	return sb.String()
+}
+
+// ends describes what io is connected to.
+func (m Model) ends(io flo.IOView) string {
+	var ends []string
+	for _, connID := range io.Connections {
+		conn := m.connections[connID]
+		id := conn.InComponentID
+		if io.Type == flo.ComponentIOTypeIN {
+			id = conn.OutComponentID
+		}
+		ends = append(ends, m.label(id))
+	}
+	if len(ends) == 0 {
+		return ""
+	}
+
+	arrow := " → "
+	if io.Type == flo.ComponentIOTypeIN {
+		arrow = " ← "
+	}
+
+	return arrow + strings.Join(ends, ", ")
+}
+
+func (m Model) label(id uuid.UUID) string {
+	if id == m.view.ID {
+		return m.view.Name
+	}
+	for _, c := range m.view.Components {
+		if c.ID == id {
+			return c.Label
+		}
+	}
+
+	return id.String()
+}
@@ -0,0 +1,83 @@
+package flotui_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flotui"
+	"github.com/stretchr/testify/require"
+)
+
+func lenFn(s string) int {
+	return len(s)
+}
+
+func TestViewer(t *testing.T) {
+	f, err := flo.NewFlo("TestTUI", "Test TUI", "Test TUI Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	first, err := flo.NewComponent("Len", "githab.com/testuf/tera", "First Len", "First Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(first))
+
+	second, err := flo.NewComponent("Count", "githab.com/testuf/tera", "Second Len", "Second Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(second))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, first.ID, first.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, second.ID, second.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(first.ID, first.IOs[1].ID, f.ID, rOut.ID))
+	f.Layout(flo.LayoutOptions{})
+
+	var m tea.Model = flotui.New(f)
+	press := func(key string) {
+		t.Helper()
+
+		var cmd tea.Cmd
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		require.Nil(t, cmd)
+	}
+
+	view := m.View()
+	require.Contains(t, view, "> First Len (githab.com/testuf/tera.Len)")
+	require.Contains(t, view, "IN  string")
+	require.Contains(t, view, "← TestTUI")
+	require.Contains(t, view, "→ TestTUI")
+
+	press("j")
+	require.Contains(t, m.View(), "> Second Len")
+	require.Contains(t, m.View(), "Second Description")
+
+	t.Run("Render", func(t *testing.T) {
+		press("r")
+		require.Contains(t, m.View(), "func TestTUI(in string) int {")
+
+		press("j")
+		require.False(t, strings.Contains(m.View(), "// Code generated by flo"))
+
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		require.Nil(t, cmd)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		press("v")
+		require.Contains(t, m.View(), "no problems found")
+	})
+
+	t.Run("Quit", func(t *testing.T) {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		require.NotNil(t, cmd)
+		require.IsType(t, tea.QuitMsg{}, cmd())
+	})
+}
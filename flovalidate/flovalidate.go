@@ -0,0 +1,140 @@
+// Package flovalidate validates structs inside flos.
+//
+// The validation logic is pluggable through Default: any value with a
+// Struct(any) error method, e.g. a *validator.Validate from
+// github.com/go-playground/validator, can be used instead of the built-in
+// TagValidator.
+package flovalidate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validator validates a struct.
+type Validator interface {
+	Struct(v any) error
+}
+
+// Default is the validator used by Struct.
+var Default Validator = TagValidator{}
+
+// Struct validates v with the Default validator and returns it as is.
+func Struct[T any](v T) (T, error) {
+	if err := Default.Struct(v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// TagValidator validates structs according to their `validate` tags.
+//
+// Supported rules are:
+//   - required: the field must not be its zero value.
+//   - min=N: numbers must be >= N, strings, slices and maps must have a length >= N.
+//   - max=N: numbers must be <= N, strings, slices and maps must have a length <= N.
+//
+// Nested structs are validated too.
+type TagValidator struct{}
+
+func (tv TagValidator) Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("nil struct")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("value of kind %q is not a struct", rv.Kind())
+	}
+
+	return validateStruct(rv, rv.Type().Name())
+}
+
+func validateStruct(rv reflect.Value, path string) error {
+	var errs []error
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		fieldPath := path + "." + field.Name
+
+		if tag, found := field.Tag.Lookup("validate"); found {
+			for _, rule := range strings.Split(tag, ",") {
+				if err := validateRule(fv, strings.TrimSpace(rule)); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", fieldPath, err))
+				}
+			}
+		}
+
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := validateStruct(fv, fieldPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateRule(v reflect.Value, rule string) error {
+	name, param, _ := strings.Cut(rule, "=")
+	switch name {
+	case "":
+		return nil
+	case "required":
+		if v.IsZero() {
+			return errors.New("is required")
+		}
+		return nil
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s rule %q", name, rule)
+		}
+
+		n, err := measure(v)
+		if err != nil {
+			return err
+		}
+
+		if name == "min" && n < limit {
+			return fmt.Errorf("must be at least %s", param)
+		}
+		if name == "max" && n > limit {
+			return fmt.Errorf("must be at most %s", param)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown rule %q", name)
+	}
+}
+
+func measure(v reflect.Value) (float64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return float64(v.Len()), nil
+	default:
+		return 0, fmt.Errorf("cannot measure value of kind %q", v.Kind())
+	}
+}
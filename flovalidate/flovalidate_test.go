@@ -0,0 +1,41 @@
+package flovalidate_test
+
+import (
+	"testing"
+
+	"github.com/mgjules/flo/flovalidate"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type user struct {
+	Name    string `validate:"required,max=5"`
+	Age     int    `validate:"min=18"`
+	Tags    []string
+	Address *address
+}
+
+func TestStruct(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		u := user{Name: "Ada", Age: 36, Address: &address{City: "London"}}
+		v, err := flovalidate.Struct(u)
+		require.NoError(t, err)
+		require.Equal(t, u, v)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v, err := flovalidate.Struct(&user{Name: "Charles", Age: 12, Address: &address{}})
+		require.Nil(t, v)
+		require.ErrorContains(t, err, "user.Name: must be at most 5")
+		require.ErrorContains(t, err, "user.Age: must be at least 18")
+		require.ErrorContains(t, err, "user.Address.City: is required")
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		_, err := flovalidate.Struct(42)
+		require.ErrorContains(t, err, "not a struct")
+	})
+}
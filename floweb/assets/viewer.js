@@ -0,0 +1,133 @@
+// Draws the flo served at graph.json as SVG, positioned by Flo.Layout.
+(() => {
+	"use strict";
+
+	const NS = "http://www.w3.org/2000/svg";
+	const WIDTH = 140, HEIGHT = 40;
+
+	const canvas = document.getElementById("canvas");
+	const viewport = document.getElementById("viewport");
+	const details = document.getElementById("details");
+	const view = { x: 40, y: 40, scale: 1 };
+	let graph = null, selected = null;
+
+	const el = (name, attrs, parent) => {
+		const e = document.createElementNS(NS, name);
+		for (const [k, v] of Object.entries(attrs)) e.setAttribute(k, v);
+		if (parent) parent.appendChild(e);
+		return e;
+	};
+	const text = (s) => String(s).replace(/[&<>"]/g, (c) => `&#${c.charCodeAt(0)};`);
+
+	const transform = () =>
+		viewport.setAttribute("transform", `translate(${view.x} ${view.y}) scale(${view.scale})`);
+
+	function draw() {
+		viewport.replaceChildren();
+		const defs = el("defs", {}, viewport);
+		const marker = el("marker", { id: "arrow", viewBox: "0 0 10 10", refX: 10, refY: 5, markerWidth: 8, markerHeight: 8, orient: "auto" }, defs);
+		el("path", { d: "M0,0 L10,5 L0,10 z", fill: "#888" }, marker);
+
+		const nodes = new Map(graph.nodes.map((n) => [n.id, n]));
+		const invalid = new Set(graph.problems.map((p) => p.node));
+		for (const e of graph.edges) {
+			const from = nodes.get(e.from), to = nodes.get(e.to);
+			if (!from || !to) continue;
+			const x1 = from.x + WIDTH / 2, y1 = from.y, x2 = to.x - WIDTH / 2, y2 = to.y;
+			const mx = (x1 + x2) / 2;
+			const cls = ["edge", e.sequence ? "sequence" : "", selected && (e.from === selected || e.to === selected) ? "selected" : ""];
+			el("path", { class: cls.join(" "), d: `M${x1},${y1} C${mx},${y1} ${mx},${y2} ${x2},${y2}` }, viewport);
+			if (e.label) el("text", { class: "edge-label", x: mx, y: (y1 + y2) / 2 - 4 }, viewport).textContent = e.label;
+		}
+		for (const n of graph.nodes) {
+			const cls = ["node", n.kind, invalid.has(n.id) ? "invalid" : "", n.id === selected ? "selected" : ""];
+			const g = el("g", { class: cls.join(" "), transform: `translate(${n.x - WIDTH / 2} ${n.y - HEIGHT / 2})` }, viewport);
+			el("rect", { width: WIDTH, height: HEIGHT }, g);
+			el("text", { x: WIDTH / 2, y: HEIGHT / 2 }, g).textContent = n.label;
+			el("title", {}, g).textContent = n.package || n.kind;
+			g.addEventListener("click", (ev) => {
+				ev.stopPropagation();
+				select(n.id);
+			});
+		}
+		transform();
+	}
+
+	function select(id) {
+		selected = id;
+		draw();
+
+		const n = graph.nodes.find((n) => n.id === id);
+		if (!n) {
+			details.innerHTML = `<p class="hint">Select a node to inspect it. Drag to pan, scroll to zoom.</p>`;
+			return;
+		}
+		const label = (nid) => (graph.nodes.find((n) => n.id === nid) || { label: nid }).label;
+		const rows = (n.ios || []).map((io) => `<tr><td>${io.type}</td><td>${text(io.name)}</td><td>${text(io.go)}</td></tr>`);
+		const edges = graph.edges
+			.filter((e) => e.from === id || e.to === id)
+			.map((e) => `<li>${text(label(e.from))} → ${text(label(e.to))}${e.sequence ? " (sequence)" : e.label ? ` <small>${text(e.label)}</small>` : ""}</li>`);
+		const problems = graph.problems.filter((p) => p.node === id).map((p) => `<li>${text(p.message)}</li>`);
+		details.innerHTML = `
+			<h2>${text(n.label)}</h2>
+			${n.package ? `<p><code>${text(n.package)}</code></p>` : ""}
+			${n.description ? `<p>${text(n.description)}</p>` : ""}
+			<h3>IOs</h3><table>${rows.join("")}</table>
+			<h3>Connections</h3><ul>${edges.join("") || "<li class=hint>none</li>"}</ul>
+			${problems.length ? `<h3>Problems</h3><ul>${problems.join("")}</ul>` : ""}`;
+	}
+
+	async function load() {
+		const res = await fetch("graph.json");
+		graph = await res.json();
+		document.title = `flo — ${graph.name}`;
+		document.getElementById("title").textContent = `${graph.name} — ${graph.label}`;
+		document.getElementById("problems").innerHTML = graph.problems.map((p) => `<li>${text(p.message)}</li>`).join("");
+		select(selected);
+
+		const src = await fetch("source");
+		document.getElementById("code").textContent = await src.text();
+	}
+
+	let pan = null, dragged = false;
+	canvas.addEventListener("mousedown", (ev) => {
+		pan = { x: ev.clientX - view.x, y: ev.clientY - view.y, moved: false };
+		canvas.classList.add("panning");
+	});
+	window.addEventListener("mousemove", (ev) => {
+		if (!pan) return;
+		view.x = ev.clientX - pan.x;
+		view.y = ev.clientY - pan.y;
+		pan.moved = true;
+		transform();
+	});
+	window.addEventListener("mouseup", () => {
+		canvas.classList.remove("panning");
+		dragged = pan !== null && pan.moved;
+		pan = null;
+	});
+	canvas.addEventListener("click", () => {
+		if (!dragged) select(null);
+	});
+	canvas.addEventListener("wheel", (ev) => {
+		ev.preventDefault();
+		const factor = ev.deltaY < 0 ? 1.1 : 1 / 1.1;
+		const r = canvas.getBoundingClientRect();
+		const px = ev.clientX - r.left, py = ev.clientY - r.top;
+		view.x = px - (px - view.x) * factor;
+		view.y = py - (py - view.y) * factor;
+		view.scale *= factor;
+		transform();
+	}, { passive: false });
+
+	for (const b of document.querySelectorAll("nav [data-tab]")) {
+		b.addEventListener("click", () => {
+			for (const t of document.querySelectorAll("[data-tab], .tab")) t.classList.remove("active");
+			b.classList.add("active");
+			document.getElementById(b.dataset.tab).classList.add("active");
+		});
+	}
+	document.getElementById("reload").addEventListener("click", load);
+
+	load();
+})();
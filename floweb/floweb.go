@@ -0,0 +1,207 @@
+// Package floweb serves a read-only view of a flo in the browser, e.g. to
+// inspect it during development:
+//
+//	go floweb.Serve(f, "localhost:8080")
+//
+// The page draws the graph as laid out by Flo.Layout, shows the ios and
+// connections of the selected node, the validation problems and the
+// rendered source. Its assets are embedded so no network is required.
+package floweb
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Serve lays f out and serves its view on addr until it fails.
+//
+// The flo must not be mutated while served, except through Flo methods.
+func Serve(f *flo.Flo, addr string) error {
+	f.Layout(flo.LayoutOptions{})
+
+	return http.ListenAndServe(addr, Handler(f))
+}
+
+// Handler serves the view of f. Unlike Serve, it keeps the positions of the
+// components as they are.
+//
+//	GET /             the viewer
+//	GET /graph.json   the nodes, edges and validation problems of the flo
+//	GET /source       the rendered source of the flo
+func Handler(f *flo.Flo) http.Handler {
+	static, _ := fs.Sub(assets, "assets")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /graph.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newGraph(r.Context(), f))
+	})
+	mux.HandleFunc("GET /source", func(w http.ResponseWriter, r *http.Request) {
+		out := &bytes.Buffer{}
+		if err := f.Render(r.Context(), out); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = out.WriteTo(w)
+	})
+
+	return mux
+}
+
+type graph struct {
+	Name     string    `json:"name"`
+	Label    string    `json:"label"`
+	Nodes    []node    `json:"nodes"`
+	Edges    []edge    `json:"edges"`
+	Problems []problem `json:"problems"`
+}
+
+// node is a component or an io of the flo.
+type node struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        string    `json:"kind"` // "component", "IN" or "OUT".
+	Label       string    `json:"label"`
+	Description string    `json:"description,omitempty"`
+	Package     string    `json:"package,omitempty"`
+	X           float64   `json:"x"`
+	Y           float64   `json:"y"`
+	IOs         []port    `json:"ios,omitempty"`
+}
+
+type port struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Type string    `json:"type"`
+	Go   string    `json:"go"`
+}
+
+type edge struct {
+	ID       uuid.UUID `json:"id"`
+	From     uuid.UUID `json:"from"`
+	To       uuid.UUID `json:"to"`
+	Label    string    `json:"label,omitempty"`
+	Sequence bool      `json:"sequence,omitempty"`
+}
+
+type problem struct {
+	Node    uuid.UUID `json:"node"`
+	Message string    `json:"message"`
+}
+
+func newGraph(ctx context.Context, f *flo.Flo) graph {
+	v := f.View()
+	g := graph{
+		Name:     v.Name,
+		Label:    v.Label,
+		Nodes:    []node{},
+		Edges:    []edge{},
+		Problems: []problem{},
+	}
+
+	// nodeID is the node holding the io of the component.
+	nodeID := func(componentID, ioID uuid.UUID) uuid.UUID {
+		if componentID == v.ID {
+			return ioID
+		}
+		return componentID
+	}
+	ioNames := make(map[uuid.UUID]string)
+	connections := make(map[uuid.UUID]flo.ConnectionView, len(v.Connections))
+	for _, conn := range v.Connections {
+		connections[conn.ID] = conn
+	}
+	dataEdge := func(connID uuid.UUID) edge {
+		conn := connections[connID]
+		return edge{
+			ID:   conn.ID,
+			From: nodeID(conn.OutComponentID, conn.OutComponentIOID),
+			To:   nodeID(conn.InComponentID, conn.InComponentIOID),
+		}
+	}
+
+	for _, i := range v.IOs {
+		ioNames[i.ID] = i.Name
+		g.Nodes = append(g.Nodes, node{
+			ID:    i.ID,
+			Kind:  i.Type.String(),
+			Label: i.Name,
+			X:     i.Position.X,
+			Y:     i.Position.Y,
+			IOs:   []port{{ID: i.ID, Name: i.Name, Type: i.Type.String(), Go: i.RType.String()}},
+		})
+	}
+	components := slices.Clone(v.Components)
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].ID.String() < components[j].ID.String()
+	})
+	for _, c := range components {
+		for _, i := range c.IOs {
+			ioNames[i.ID] = i.Name
+		}
+	}
+	for _, c := range components {
+		n := node{
+			ID:          c.ID,
+			Kind:        "component",
+			Label:       c.Label,
+			Description: c.Description,
+			Package:     c.PkgPath + "." + c.Name,
+			X:           c.Position.X,
+			Y:           c.Position.Y,
+		}
+		for _, i := range c.IOs {
+			n.IOs = append(n.IOs, port{ID: i.ID, Name: i.Name, Type: i.Type.String(), Go: i.RType.String()})
+			if i.Type != flo.ComponentIOTypeIN {
+				continue
+			}
+			for _, connID := range i.Connections {
+				e := dataEdge(connID)
+				e.Label = ioNames[connections[connID].OutComponentIOID] + " → " + i.Name
+				g.Edges = append(g.Edges, e)
+			}
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	// Connections to the results of the flo are only held by their ios.
+	for _, i := range v.IOs {
+		if i.Type != flo.ComponentIOTypeOUT {
+			continue
+		}
+		for _, connID := range i.Connections {
+			e := dataEdge(connID)
+			e.Label = ioNames[connections[connID].OutComponentIOID]
+			g.Edges = append(g.Edges, e)
+		}
+	}
+	for _, conn := range v.Connections {
+		if conn.Kind != flo.ComponentConnectionKindSequence {
+			continue
+		}
+		g.Edges = append(g.Edges, edge{
+			ID:       conn.ID,
+			From:     conn.OutComponentID,
+			To:       conn.InComponentID,
+			Sequence: true,
+		})
+	}
+
+	for _, err := range f.Validate(ctx) {
+		g.Problems = append(g.Problems, problem{Node: err.ComponentID, Message: err.Message})
+	}
+
+	return g
+}
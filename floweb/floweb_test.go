@@ -0,0 +1,108 @@
+package floweb_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/floweb"
+	"github.com/stretchr/testify/require"
+)
+
+func lenFn(s string) int {
+	return len(s)
+}
+
+func TestHandler(t *testing.T) {
+	f, err := flo.NewFlo("TestWeb", "Test Web", "Test Web Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rOut.ID))
+	f.Layout(flo.LayoutOptions{})
+
+	srv := httptest.NewServer(floweb.Handler(f))
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) (*http.Response, string) {
+		t.Helper()
+
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return res, string(body)
+	}
+
+	t.Run("Assets", func(t *testing.T) {
+		res, body := get(t, "/")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, body, `<script src="viewer.js"></script>`)
+
+		res, _ = get(t, "/viewer.js")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		res, body := get(t, "/graph.json")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+		var g struct {
+			Name  string
+			Nodes []struct {
+				ID    string
+				Kind  string
+				Label string
+				X     float64
+			}
+			Edges []struct {
+				From  string
+				To    string
+				Label string
+			}
+			Problems []struct{}
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &g))
+		require.Equal(t, "TestWeb", g.Name)
+		require.Len(t, g.Nodes, 3)
+		require.Equal(t, "IN", g.Nodes[0].Kind)
+		require.Equal(t, "OUT", g.Nodes[1].Kind)
+		require.Equal(t, "component", g.Nodes[2].Kind)
+		require.Less(t, g.Nodes[0].X, g.Nodes[2].X)
+		require.Less(t, g.Nodes[2].X, g.Nodes[1].X)
+
+		require.Len(t, g.Edges, 2)
+		require.Equal(t, pIn.ID.String(), g.Edges[0].From)
+		require.Equal(t, length.ID.String(), g.Edges[0].To)
+		require.Equal(t, "in → "+length.IOs[0].Name, g.Edges[0].Label)
+		require.Equal(t, length.ID.String(), g.Edges[1].From)
+		require.Equal(t, rOut.ID.String(), g.Edges[1].To)
+		require.Empty(t, g.Problems)
+	})
+
+	t.Run("Source", func(t *testing.T) {
+		res, body := get(t, "/source")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, body, "func TestWeb(in string) int {")
+	})
+}
@@ -0,0 +1,107 @@
+package flo
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// WithConstantFolding calls, at render time, the components whose inputs
+// are all literals and writes their results as constants instead of the
+// calls.
+//
+// Only components known to be pure are called, see SetEffects and
+// InferEffects. Components with unknown or side effects, without inputs,
+// failing, or returning values that can't be written as literals are left
+// as is.
+func WithConstantFolding() RenderOption {
+	return func(o *renderOptions) {
+		o.foldConstants = true
+	}
+}
+
+// withFoldedCalls computes the folded calls of the flo once per render, so
+// that their components are called only once.
+func (f *Flo) withFoldedCalls(ctx context.Context) context.Context {
+	o := renderOptionsFrom(ctx)
+	if !o.foldConstants {
+		return ctx
+	}
+
+	o.folded = make(map[uuid.UUID]*jen.Statement)
+	for _, c := range f.Components {
+		if code, ok := f.foldedCall(c); ok {
+			o.folded[c.ID] = code
+		}
+	}
+
+	return withRenderOptions(ctx, o)
+}
+
+// foldedCall computes the results of c when all its inputs are literals and
+// returns the code assigning them.
+func (f *Flo) foldedCall(c *Component) (code *jen.Statement, ok bool) {
+	if c.Kind != ComponentKindFunc || !c.IsBound() || !c.Effects.IsPure() {
+		return nil, false
+	}
+	// The bound value is not the receiver the generated code calls.
+	if c.Receiver != nil {
+		return nil, false
+	}
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	if len(ins) == 0 {
+		return nil, false
+	}
+
+	args := make([]reflect.Value, 0, len(ins))
+	for _, in := range ins {
+		if !in.Literal.IsValid() {
+			return nil, false
+		}
+		args = append(args, in.Literal)
+	}
+
+	defer func() {
+		// A panicking component is left to fail at runtime.
+		if recover() != nil {
+			code, ok = nil, false
+		}
+	}()
+	var results []reflect.Value
+	if c.Value.Type().IsVariadic() {
+		results = c.Value.CallSlice(args)
+	} else {
+		results = c.Value.Call(args)
+	}
+
+	var (
+		names  []jen.Code
+		values []jen.Code
+	)
+	for i, out := range outs {
+		if out.IsError {
+			if !results[i].IsNil() {
+				return nil, false
+			}
+			continue
+		}
+		if !f.usesValue(out) {
+			continue
+		}
+		lit, err := literalCode(results[i])
+		if err != nil {
+			return nil, false
+		}
+		names = append(names, jen.Id(out.Name))
+		values = append(values, lit)
+	}
+
+	if len(names) == 0 {
+		return jen.Null(), true
+	}
+
+	return jen.List(names...).Op(":=").List(values...), true
+}
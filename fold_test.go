@@ -0,0 +1,146 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func checkFn(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("empty")
+	}
+
+	return strings.ToUpper(s), nil
+}
+
+func TestConstantFolding(t *testing.T) {
+	f, err := flo.NewFlo("TestFold", "Test Fold", "Test Fold Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.SetLiteral(length.ID, length.IOs[0].ID, "hello"))
+	require.NoError(t, f.SetEffects(length.ID, flo.EffectPure))
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Add Description", addFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, add.ID, add.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, add.ID, add.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[2].ID, f.ID, rOut.ID))
+
+	// Fails with its literal so it must stay a runtime call.
+	check, err := flo.NewComponent("Check", "githab.com/testuf/tera", "Check", "Check Description", checkFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(check))
+	require.NoError(t, f.SetLiteral(check.ID, check.IOs[0].ID, ""))
+	require.NoError(t, f.SetEffects(check.ID, flo.EffectPure))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithConstantFolding()))
+	require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import tera "githab.com/testuf/tera"
+
+func TestFold(in int) (int, error) {
+	// Len Description
+	io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd := 5
+
+	// Add Description
+	iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3 := tera.Add(io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd, in)
+
+	// Check Description
+	_, err := tera.Check("")
+	if err != nil {
+		return 0, err
+	}
+
+	return iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3, nil
+}
+`, out.String())
+}
+
+func TestConstantFoldingSkipsReceivers(t *testing.T) {
+	f, err := flo.NewFlo("Count", "Count", "Count Description", "flo", "Count Package")
+	require.NoError(t, err)
+
+	rTotal, err := flo.NewComponentIO("total", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rTotal))
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Adds v.", (&Counter{}).Add)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+	require.NoError(t, f.SetLiteral(add.ID, add.IOs[0].ID, 1))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[1].ID, f.ID, rTotal.ID))
+	require.NoError(t, f.SetReceiver(add.ID, &flo.Receiver{Name: "counter", Type: reflect.TypeFor[*Counter]()}))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithConstantFolding()))
+	require.Contains(t, out.String(), "counter.Add(1)")
+}
+
+func TestConstantFoldingCallsPureComponentsOnce(t *testing.T) {
+	f, err := flo.NewFlo("TestFold", "Test Fold", "Test Fold Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	var pureCalls, unknownCalls int
+	pure, err := flo.NewComponent("Pure", "githab.com/testuf/tera", "Pure", "Pure Description", func(v int) int {
+		pureCalls++
+		return v
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(pure))
+	require.NoError(t, f.SetLiteral(pure.ID, pure.IOs[0].ID, 1))
+	require.NoError(t, f.SetEffects(pure.ID, flo.EffectPure))
+
+	// Effects are unknown, e.g. it could read the environment.
+	unknown, err := flo.NewComponent("Unknown", "githab.com/testuf/tera", "Unknown", "Unknown Description", func(v int) int {
+		unknownCalls++
+		return v
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(unknown))
+	require.NoError(t, f.SetLiteral(unknown.ID, unknown.IOs[0].ID, 2))
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Add Description", addFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+	require.NoError(t, f.ConnectComponent(pure.ID, pure.IOs[1].ID, add.ID, add.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(unknown.ID, unknown.IOs[1].ID, add.ID, add.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[2].ID, f.ID, rOut.ID))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithConstantFolding(), flo.WithParallel()))
+	require.Contains(t, out.String(), "tera.Unknown(2)")
+	require.NotContains(t, out.String(), "tera.Pure(")
+	require.Equal(t, 1, pureCalls)
+	require.Zero(t, unknownCalls)
+}
@@ -0,0 +1,183 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func fuzzLenFn(s string) int {
+	return len(s)
+}
+
+func fuzzItoaFn(v int) (string, error) {
+	return fmt.Sprint(v), nil
+}
+
+// fuzzFuncs backs the components added by FuzzMutations.
+var fuzzFuncs = []any{benchIncFn, benchAddFn, fuzzLenFn, fuzzItoaFn}
+
+// fuzzOp is a graph mutation driven by the fuzzer bytes.
+type fuzzOp func(f *Flo, next func() int) error
+
+var fuzzOps = []fuzzOp{
+	// AddComponent.
+	func(f *Flo, next func() int) error {
+		fn := fuzzFuncs[next()%len(fuzzFuncs)]
+		c, err := NewComponent(fmt.Sprintf("Fn%d", len(f.componentOrder)), "fuzz.io/fn", "Fn", "Fn", fn)
+		if err != nil {
+			return err
+		}
+		return f.AddComponent(c)
+	},
+	// DeleteComponent.
+	func(f *Flo, next func() int) error {
+		return f.DeleteComponent(pickComponent(f, next()))
+	},
+	// AddIO.
+	func(f *Flo, next func() int) error {
+		typ := ComponentIOType(next()%2 + 1)
+		rType := []reflect.Type{reflect.TypeFor[int](), reflect.TypeFor[string](), reflect.TypeFor[error]()}[next()%3]
+		fio, err := NewComponentIO(fmt.Sprintf("io%d", len(f.IOs)), typ, rType, f.ID)
+		if err != nil {
+			return err
+		}
+		return f.AddIO(fio)
+	},
+	// DeleteIO.
+	func(f *Flo, next func() int) error {
+		if len(f.IOs) == 0 {
+			return errors.New("no flo io")
+		}
+		return f.DeleteIO(f.IOs[next()%len(f.IOs)].ID)
+	},
+	// ConnectComponent, including the flo ios.
+	func(f *Flo, next func() int) error {
+		outID, outIO := pickIO(f, next(), next())
+		inID, inIO := pickIO(f, next(), next())
+		return f.ConnectComponent(outID, outIO, inID, inIO)
+	},
+	// DeleteConnection.
+	func(f *Flo, next func() int) error {
+		return f.DeleteConnection(pickConnection(f, next()))
+	},
+	// ConnectSequence.
+	func(f *Flo, next func() int) error {
+		return f.ConnectSequence(pickComponent(f, next()), pickComponent(f, next()))
+	},
+	// SetLiteral.
+	func(f *Flo, next func() int) error {
+		id, ioID := pickIO(f, next(), next())
+		return f.SetLiteral(id, ioID, []any{nil, 42, "fuzz"}[next()%3])
+	},
+}
+
+func pickComponent(f *Flo, i int) uuid.UUID {
+	if len(f.componentOrder) == 0 {
+		return uuid.Nil
+	}
+
+	return f.componentOrder[i%len(f.componentOrder)]
+}
+
+// pickIO picks an io of either the flo or one of its components.
+func pickIO(f *Flo, i, j int) (uuid.UUID, uuid.UUID) {
+	id, ios := f.ID, f.IOs
+	if n := len(f.componentOrder); n > 0 && i%(n+1) < n {
+		id = f.componentOrder[i%(n+1)]
+		ios = f.Components[id].IOs
+	}
+	if len(ios) == 0 {
+		return id, uuid.Nil
+	}
+
+	return id, ios[j%len(ios)].ID
+}
+
+func pickConnection(f *Flo, i int) uuid.UUID {
+	var ids []uuid.UUID
+	for _, c := range f.orderedComponents() {
+		for _, cio := range c.IOs {
+			for _, conn := range cio.Connections {
+				ids = append(ids, conn.ID)
+			}
+		}
+	}
+	for _, conn := range f.Sequences {
+		ids = append(ids, conn.ID)
+	}
+	if len(ids) == 0 {
+		return uuid.Nil
+	}
+
+	return ids[i%len(ids)]
+}
+
+// checkFuzzInvariants also checks what only holds when the flo is mutated
+// through its methods.
+func checkFuzzInvariants(f *Flo) error {
+	if err := f.Invariants(); err != nil {
+		return err
+	}
+	if len(f.componentOrder) != len(f.Components) {
+		return fmt.Errorf("%d components are ordered out of %d", len(f.componentOrder), len(f.Components))
+	}
+
+	return nil
+}
+
+func FuzzMutations(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 1, 2, 0, 1, 4, 1, 0, 0, 1, 5, 0})
+	f.Add([]byte{2, 0, 0, 2, 1, 1, 0, 1, 0, 3, 4, 2, 0, 0, 0, 4, 0, 1, 1, 1, 6, 0, 1, 1, 0})
+	f.Add([]byte{0, 3, 0, 2, 7, 0, 0, 1, 1, 4, 0, 0, 1, 0, 3, 0, 1, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Keeps every run fast, bugs show up with a few dozen ops anyway.
+		if len(data) > 1024 {
+			t.Skip()
+		}
+
+		fl, err := NewFlo("Fuzz", "Fuzz", "Fuzz", "fuzz", "Fuzz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pos := 0
+		next := func() int {
+			if pos >= len(data) {
+				return 0
+			}
+			pos++
+			return int(data[pos-1])
+		}
+
+		for pos < len(data) {
+			op := next() % len(fuzzOps)
+			// Failing mutations must leave the flo untouched as much as
+			// successful ones must leave it consistent.
+			_ = fuzzOps[op](fl, next)
+			if err := checkFuzzInvariants(fl); err != nil {
+				t.Fatalf("op %d at byte %d: %v", op, pos, err)
+			}
+		}
+
+		out := &bytes.Buffer{}
+		if err := fl.Render(context.Background(), out); err != nil {
+			return
+		}
+		fl.MarkDirty()
+		incremental := &bytes.Buffer{}
+		if err := fl.RenderIncremental(context.Background(), incremental); err != nil {
+			t.Fatalf("incremental render failed: %v", err)
+		}
+		if err := fl.Render(context.Background(), io.Discard); err != nil {
+			t.Fatalf("render failed after an incremental render: %v", err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// NewComponentFromTypesFunc creates an unbound component from a go/types
+// function, without having to import its package into the running binary.
+// The types used by the signature are resolved through registry; a nil
+// registry uses DefaultTypeRegistry.
+//
+// Such components can be rendered, validated and visualized. They are bound
+// later on with Bind or a ComponentRegistry when needed.
+func NewComponentFromTypesFunc(
+	fn *types.Func,
+	label, description string,
+	registry *TypeRegistry,
+) (*Component, error) {
+	if fn == nil {
+		return nil, errors.New("missing function")
+	}
+	if fn.Pkg() == nil {
+		return nil, fmt.Errorf("function %q has no package", fn.Name())
+	}
+	if registry == nil {
+		registry = DefaultTypeRegistry
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a function", fn.Name())
+	}
+	if sig.Recv() != nil {
+		return nil, fmt.Errorf("%q is a method", fn.Name())
+	}
+
+	vt, err := goTypesType(sig, registry)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve signature of %q: %v", fn.Name(), err)
+	}
+
+	c := Component{
+		ID:          uuid.New(),
+		Name:        fn.Name(),
+		PkgPath:     fn.Pkg().Path(),
+		Label:       label,
+		Description: description,
+	}
+
+	if err := newComponentIOsFromType(&c, vt); err != nil {
+		return nil, fmt.Errorf("cannot generate component ios: %v", err)
+	}
+
+	return &c, nil
+}
+
+// goTypesType converts a go/types type into its reflect.Type. Named types
+// must be known to registry.
+func goTypesType(t types.Type, registry *TypeRegistry) (reflect.Type, error) {
+	switch t := types.Unalias(t).(type) {
+	case *types.Basic:
+		if t.Info()&types.IsUntyped != 0 || t.Kind() == types.UnsafePointer {
+			return nil, fmt.Errorf("unsupported basic type %s", t)
+		}
+		return registry.Resolve(t.Name())
+	case *types.Named:
+		if t.TypeArgs().Len() > 0 {
+			return nil, fmt.Errorf("unsupported generic type %s", t)
+		}
+		obj := t.Obj()
+		if obj.Pkg() == nil {
+			return registry.Resolve(obj.Name())
+		}
+		return registry.Resolve(obj.Pkg().Path() + "." + obj.Name())
+	case *types.Pointer:
+		elem, err := goTypesType(t.Elem(), registry)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+	case *types.Slice:
+		elem, err := goTypesType(t.Elem(), registry)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case *types.Array:
+		elem, err := goTypesType(t.Elem(), registry)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ArrayOf(int(t.Len()), elem), nil
+	case *types.Map:
+		key, err := goTypesType(t.Key(), registry)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := goTypesType(t.Elem(), registry)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, elem), nil
+	case *types.Chan:
+		elem, err := goTypesType(t.Elem(), registry)
+		if err != nil {
+			return nil, err
+		}
+		dir := reflect.BothDir
+		switch t.Dir() {
+		case types.RecvOnly:
+			dir = reflect.RecvDir
+		case types.SendOnly:
+			dir = reflect.SendDir
+		}
+		return reflect.ChanOf(dir, elem), nil
+	case *types.Interface:
+		if t.NumMethods() > 0 || !t.IsMethodSet() {
+			return nil, fmt.Errorf("unsupported unnamed interface %s", t)
+		}
+		return reflect.TypeFor[any](), nil
+	case *types.Struct:
+		fields := make([]reflect.StructField, 0, t.NumFields())
+		for i := range t.NumFields() {
+			field := t.Field(i)
+			ft, err := goTypesType(field.Type(), registry)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, reflect.StructField{
+				Name:      field.Name(),
+				Type:      ft,
+				Tag:       reflect.StructTag(t.Tag(i)),
+				Anonymous: field.Embedded(),
+			})
+		}
+		return structOf(fields)
+	case *types.Signature:
+		if t.TypeParams().Len() > 0 {
+			return nil, errors.New("type parameters are not supported")
+		}
+		tuple := func(tup *types.Tuple) ([]reflect.Type, error) {
+			res := make([]reflect.Type, 0, tup.Len())
+			for i := range tup.Len() {
+				rt, err := goTypesType(tup.At(i).Type(), registry)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, rt)
+			}
+			return res, nil
+		}
+		in, err := tuple(t.Params())
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+		out, err := tuple(t.Results())
+		if err != nil {
+			return nil, fmt.Errorf("invalid results: %v", err)
+		}
+		return reflect.FuncOf(in, out, t.Variadic()), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
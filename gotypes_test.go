@@ -0,0 +1,63 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+const gotypesSrc = `package tera
+
+import (
+	"context"
+	"time"
+)
+
+type Secret struct{}
+
+func Wait(ctx context.Context, d time.Duration, names ...string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func Reveal(s Secret) string {
+	return ""
+}
+`
+
+func TestNewComponentFromTypesFunc(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "tera.go", gotypesSrc, 0)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("githab.com/testuf/tera", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	t.Run("Unknown named type", func(t *testing.T) {
+		_, err := flo.NewComponentFromTypesFunc(pkg.Scope().Lookup("Reveal").(*types.Func), "Reveal", "", nil)
+		require.ErrorContains(t, err, `unknown type "githab.com/testuf/tera.Secret"`)
+	})
+
+	wait, err := flo.NewComponentFromTypesFunc(pkg.Scope().Lookup("Wait").(*types.Func), "Wait", "Wait Description", nil)
+	require.NoError(t, err)
+	require.False(t, wait.IsBound())
+	require.Equal(t, "Wait", wait.Name)
+	require.Equal(t, "githab.com/testuf/tera", wait.PkgPath)
+	require.Len(t, wait.IOs, 5)
+
+	f, err := flo.NewFlo("TestTypes", "Test Types", "Test Types Description", "flo", "Test Package")
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(wait))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.RenderStubs(context.Background(), out, "githab.com/testuf/tera"))
+	require.Contains(t, out.String(), "func Wait(context.Context, time.Duration, []string) (map[string][]byte, error)")
+}
@@ -0,0 +1,98 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// NewGuardComponent creates a precondition returning early from the flo,
+// with an error made of message, when its condition is false:
+//
+//	if !ok {
+//		return 0, fmt.Errorf("age %d is too young", age)
+//	}
+//
+// Its first in io is the bool condition, followed by an in io for each of
+// args, the operands of message. Components running only once the
+// precondition holds are attached with ConnectSequence.
+func NewGuardComponent(
+	name string,
+	label, description string,
+	message string,
+	args ...reflect.Type,
+) (*Component, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+	if message == "" {
+		return nil, errors.New("missing message")
+	}
+
+	c := &Component{
+		ID:          uuid.New(),
+		Name:        name,
+		Label:       label,
+		Description: description,
+		Kind:        ComponentKindGuard,
+		Message:     message,
+	}
+	c.IOs = make(IOs, 0, len(args)+1)
+	for i, t := range append([]reflect.Type{boolRType}, args...) {
+		io, err := NewComponentIO("", ComponentIOTypeIN, t, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error for argument %d: %w", i, err)
+		}
+		c.IOs = append(c.IOs, io)
+	}
+
+	return c, nil
+}
+
+var boolRType = reflect.TypeFor[bool]()
+
+// guardCode returns early from the flo when the condition of c is false.
+func (f *Flo) guardCode(c *Component, ins IOs, literals map[uuid.UUID]jen.Code) (jen.Code, error) {
+	_, floOUTs := f.IOs.SeparateINsOUTs()
+	if !lo.SomeBy(floOUTs, func(out *ComponentIO) bool { return out.IsError }) {
+		return nil, fmt.Errorf("guard component id %q needs the flo to return an error", c.ID)
+	}
+	if len(ins) == 0 || len(ins[0].Connections) == 0 {
+		return nil, fmt.Errorf("guard component id %q has no connected condition", c.ID)
+	}
+
+	args := []jen.Code{jen.Lit(c.Message)}
+	for _, in := range ins[1:] {
+		if isSecret(in.RType) {
+			return nil, fmt.Errorf("guard component id %q cannot format secret io id %q", c.ID, in.ID)
+		}
+		if lit, found := literals[in.ID]; found {
+			args = append(args, lit)
+			continue
+		}
+		if len(in.Connections) == 0 {
+			return nil, fmt.Errorf("guard component id %q io id %q is not connected", c.ID, in.ID)
+		}
+		args = append(args, inValue(in))
+	}
+
+	err := jen.Qual("errors", "New").Call(args...)
+	if len(args) > 1 {
+		err = jen.Qual("fmt", "Errorf").Call(args...)
+	}
+
+	return jen.If(not(ins[0])).Block(f.errorReturn(err)), nil
+}
+
+// not negates the bool value of in.
+func not(in *ComponentIO) jen.Code {
+	if hasExprTransform(in) {
+		return jen.Op("!").Parens(inValue(in))
+	}
+
+	return jen.Op("!").Add(inValue(in))
+}
@@ -0,0 +1,108 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func isPositiveFn(n int) bool {
+	return n > 0
+}
+
+func doubleFn(n int) int {
+	return n * 2
+}
+
+func TestGuardComponent(t *testing.T) {
+	t.Run("Invalid guards", func(t *testing.T) {
+		_, err := flo.NewGuardComponent("", "Guard", "Guard", "invalid")
+		require.ErrorContains(t, err, "missing name")
+
+		_, err = flo.NewGuardComponent("Guard", "Guard", "Guard", "")
+		require.ErrorContains(t, err, "missing message")
+	})
+
+	f, err := flo.NewFlo("TestGuard", "Test Guard", "Test Guard Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	n := flo.In[int]("n")
+	result := flo.Out[int]("result")
+	for _, io := range []*flo.ComponentIO{n, result} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	positive, err := flo.NewComponent("IsPositive", "githab.com/testuf/tera", "Is Positive", "Check n", isPositiveFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(positive))
+
+	guard, err := flo.NewGuardComponent("Guard", "Guard", "Reject non positive n", "%d is not positive", reflect.TypeFor[int]())
+	require.NoError(t, err)
+	require.Equal(t, flo.ComponentKindGuard, guard.Kind)
+	require.Len(t, guard.IOs, 2)
+	require.NoError(t, f.AddComponent(guard))
+
+	double, err := flo.NewComponent("Double", "githab.com/testuf/tera", "Double", "Double n", doubleFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(double))
+
+	require.NoError(t, f.ConnectComponent(f.ID, n.ID, positive.ID, positive.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(positive.ID, positive.IOs[1].ID, guard.ID, guard.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, n.ID, guard.ID, guard.IOs[1].ID))
+	require.NoError(t, f.ConnectSequence(guard.ID, double.ID))
+	require.NoError(t, f.ConnectComponent(f.ID, n.ID, double.ID, double.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(double.ID, double.IOs[1].ID, f.ID, result.ID))
+
+	t.Run("Flo must return an error", func(t *testing.T) {
+		err := f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, "needs the flo to return an error")
+	})
+
+	require.NoError(t, f.AddIO(flo.Out[error]("err")))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `func TestGuard(n int) (int, error) {
+	// Check n
+	iocde82608B4467F31F754Fbde3Fbc63D8E8C2Cbe7 := tera.IsPositive(n)
+
+	// Reject non positive n
+	if !iocde82608B4467F31F754Fbde3Fbc63D8E8C2Cbe7 {
+		return 0, fmt.Errorf("%d is not positive", n)
+	}
+
+	// Double n
+	iob5E220575E7D8Bb062F99F9C256866Ad92D645B0 := tera.Double(n)
+
+	return iob5E220575E7D8Bb062F99F9C256866Ad92D645B0, nil
+}
+`)
+	})
+
+	t.Run("Execute", func(t *testing.T) {
+		outputs, err := f.Execute(context.Background(), map[string]any{"n": 2})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"result": 4}, outputs)
+
+		_, err = f.Execute(context.Background(), map[string]any{"n": -2})
+		require.ErrorContains(t, err, "-2 is not positive")
+
+		var cerr *flo.ComponentError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, guard.ID, cerr.ComponentID)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+}
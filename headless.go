@@ -0,0 +1,371 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// NewHeadlessComponent creates a component from a function signature such
+// as "func(context.Context, int) (int, error)" without needing the function
+// itself. The types used by the signature are resolved through types; a nil
+// types uses DefaultTypeRegistry.
+//
+// Headless components can be rendered and validated right away while their
+// function is bound later on with Bind or a ComponentRegistry.
+func NewHeadlessComponent(
+	name, pkgPath string,
+	label, description string,
+	signature string,
+	types *TypeRegistry,
+) (*Component, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+	if pkgPath == "" {
+		return nil, errors.New("missing pkg path")
+	}
+	vt, err := ParseSignature(signature, types)
+	if err != nil {
+		return nil, err
+	}
+
+	c := Component{
+		ID:          uuid.New(),
+		Name:        name,
+		PkgPath:     pkgPath,
+		Label:       label,
+		Description: description,
+	}
+
+	if err := newComponentIOsFromType(&c, vt); err != nil {
+		return nil, fmt.Errorf("cannot generate component ios: %v", err)
+	}
+
+	return &c, nil
+}
+
+// ParseSignature parses a function signature into its reflect.Type.
+// A nil types uses DefaultTypeRegistry.
+func ParseSignature(signature string, types *TypeRegistry) (reflect.Type, error) {
+	if types == nil {
+		types = DefaultTypeRegistry
+	}
+
+	expr, err := parser.ParseExpr(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %v", signature, err)
+	}
+
+	ft, ok := expr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("signature %q is not a function type", signature)
+	}
+
+	return astFuncType(ft, types)
+}
+
+// Bind backs an unbound component, e.g. a headless one, with fn.
+// fn must match the component ios, or the cached function for cache-aside
+// components.
+func (c *Component) Bind(fn any) error {
+	v := reflect.ValueOf(fn)
+	if c.Cache != nil {
+		var err error
+		if v, err = cachedFunc(c, v); err != nil {
+			return err
+		}
+	}
+
+	return c.bindValue(v)
+}
+
+// IsBound reports whether the component is backed by a function.
+func (c *Component) IsBound() bool {
+	return c.Value.IsValid()
+}
+
+func astType(expr ast.Expr, types *TypeRegistry) (reflect.Type, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return types.Resolve(e.Name)
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type expression %T", e.X)
+		}
+		return types.resolveQualified(pkg.Name, e.Sel.Name)
+	case *ast.ParenExpr:
+		return astType(e.X, types)
+	case *ast.StarExpr:
+		elem, err := astType(e.X, types)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+	case *ast.ArrayType:
+		elem, err := astType(e.Elt, types)
+		if err != nil {
+			return nil, err
+		}
+		if e.Len == nil {
+			return reflect.SliceOf(elem), nil
+		}
+		lit, ok := e.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, errors.New("array length must be an integer literal")
+		}
+		n, err := strconv.Atoi(lit.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array length: %v", err)
+		}
+		return reflect.ArrayOf(n, elem), nil
+	case *ast.MapType:
+		key, err := astType(e.Key, types)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := astType(e.Value, types)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, elem), nil
+	case *ast.ChanType:
+		elem, err := astType(e.Value, types)
+		if err != nil {
+			return nil, err
+		}
+		dir := reflect.BothDir
+		switch e.Dir {
+		case ast.RECV:
+			dir = reflect.RecvDir
+		case ast.SEND:
+			dir = reflect.SendDir
+		}
+		return reflect.ChanOf(dir, elem), nil
+	case *ast.InterfaceType:
+		if e.Methods != nil && len(e.Methods.List) > 0 {
+			return nil, errors.New("only empty interfaces are supported inline")
+		}
+		return reflect.TypeFor[any](), nil
+	case *ast.FuncType:
+		return astFuncType(e, types)
+	case *ast.StructType:
+		var fields []reflect.StructField
+		for _, field := range e.Fields.List {
+			t, err := astType(field.Type, types)
+			if err != nil {
+				return nil, err
+			}
+			var tag reflect.StructTag
+			if field.Tag != nil {
+				raw, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid tag %s: %v", field.Tag.Value, err)
+				}
+				tag = reflect.StructTag(raw)
+			}
+			if len(field.Names) == 0 {
+				fields = append(fields, reflect.StructField{Name: embeddedName(t), Type: t, Tag: tag, Anonymous: true})
+				continue
+			}
+			for _, name := range field.Names {
+				fields = append(fields, reflect.StructField{Name: name.Name, Type: t, Tag: tag})
+			}
+		}
+		return structOf(fields)
+	default:
+		return nil, fmt.Errorf("unsupported type expression %T", expr)
+	}
+}
+
+// embeddedName is the name of a field embedding t.
+func embeddedName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// structOf is reflect.StructOf returning an error instead of panicking,
+// e.g. on unexported fields.
+func structOf(fields []reflect.StructField) (t reflect.Type, err error) {
+	for _, field := range fields {
+		if !token.IsExported(field.Name) {
+			return nil, fmt.Errorf("field %q is not exported", field.Name)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid struct: %v", r)
+		}
+	}()
+
+	return reflect.StructOf(fields), nil
+}
+
+func astFuncType(ft *ast.FuncType, types *TypeRegistry) (reflect.Type, error) {
+	if ft.TypeParams != nil {
+		return nil, errors.New("type parameters are not supported")
+	}
+
+	var variadic bool
+	fields := func(list *ast.FieldList, params bool) ([]reflect.Type, error) {
+		var res []reflect.Type
+		if list == nil {
+			return res, nil
+		}
+		for i, field := range list.List {
+			expr := field.Type
+			if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+				if !params || i != len(list.List)-1 || len(field.Names) > 1 {
+					return nil, errors.New("only the last argument can be variadic")
+				}
+				variadic = true
+				expr = &ast.ArrayType{Elt: ellipsis.Elt}
+			}
+
+			t, err := astType(expr, types)
+			if err != nil {
+				return nil, err
+			}
+
+			n := max(len(field.Names), 1)
+			for range n {
+				res = append(res, t)
+			}
+		}
+		return res, nil
+	}
+
+	in, err := fields(ft.Params, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	out, err := fields(ft.Results, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid results: %v", err)
+	}
+
+	return reflect.FuncOf(in, out, variadic), nil
+}
+
+// resolveQualified resolves a type written as pkgName.Name, pkgName being
+// the last element of the package path.
+func (r *TypeRegistry) resolveQualified(pkgName, name string) (reflect.Type, error) {
+	if t, found := r.Lookup(pkgName + "." + name); found {
+		return t, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var match reflect.Type
+	for key, t := range r.types {
+		// Types are known by their own name or, for aliases, by the name
+		// they are registered under.
+		dot := strings.LastIndex(key, ".")
+		aliased := dot > 0 && key[dot+1:] == name && packageName(key[:dot]) == pkgName
+		if !aliased && (t.Name() != name || packageName(t.PkgPath()) != pkgName) {
+			continue
+		}
+		if match != nil && match != t {
+			return nil, fmt.Errorf("ambiguous type %s.%s", pkgName, name)
+		}
+		match = t
+	}
+	if match == nil {
+		return nil, fmt.Errorf("unknown type %s.%s", pkgName, name)
+	}
+
+	return match, nil
+}
+
+// packageName guesses the name of a package from its path.
+func packageName(pkgPath string) string {
+	for i := len(pkgPath) - 1; i >= 0; i-- {
+		if pkgPath[i] == '/' {
+			return pkgPath[i+1:]
+		}
+	}
+
+	return pkgPath
+}
+
+// ComponentRegistry holds the functions components can be bound to,
+// identified by their package path and name.
+type ComponentRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]any
+}
+
+func NewComponentRegistry() *ComponentRegistry {
+	return &ComponentRegistry{
+		funcs: make(map[string]any),
+	}
+}
+
+// Register makes fn available as pkgPath.name.
+func (r *ComponentRegistry) Register(pkgPath, name string, fn any) error {
+	if pkgPath == "" {
+		return errors.New("missing pkg path")
+	}
+	if name == "" {
+		return errors.New("missing name")
+	}
+	if v := reflect.ValueOf(fn); !v.IsValid() || v.Kind() != reflect.Func {
+		return fmt.Errorf("value of kind %q is not a function", v.Kind())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := pkgPath + "." + name
+	if _, found := r.funcs[key]; found {
+		return fmt.Errorf("function %q already registered", key)
+	}
+	r.funcs[key] = fn
+
+	return nil
+}
+
+// Resolve returns the function registered as pkgPath.name.
+// It can be used as a ResolveFunc.
+func (r *ComponentRegistry) Resolve(pkgPath, name string) (any, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fn, found := r.funcs[pkgPath+"."+name]
+	if !found {
+		return nil, fmt.Errorf("function %s.%s is not registered", pkgPath, name)
+	}
+
+	return fn, nil
+}
+
+// BindComponents binds every unbound component of f to its registered
+// function.
+func (r *ComponentRegistry) BindComponents(f *Flo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.orderedComponents() {
+		if c.IsBound() {
+			continue
+		}
+		if err := c.resolve(r.Resolve); err != nil {
+			return fmt.Errorf("cannot bind component id %q: %v", c.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadlessComponent(t *testing.T) {
+	t.Run("Invalid signatures", func(t *testing.T) {
+		_, err := flo.NewHeadlessComponent("Nope", "githab.com/testuf/tera", "Nope", "Nope", "int", nil)
+		require.ErrorContains(t, err, "not a function type")
+
+		_, err = flo.NewHeadlessComponent("Nope", "githab.com/testuf/tera", "Nope", "Nope", "func(foo.Bar)", nil)
+		require.ErrorContains(t, err, "unknown type foo.Bar")
+
+		_, err = flo.NewHeadlessComponent("Nope", "githab.com/testuf/tera", "Nope", "Nope", "func(", nil)
+		require.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("Parse signature", func(t *testing.T) {
+		typ, err := flo.ParseSignature(
+			"func(ctx context.Context, d time.Duration, b []byte, opts ...string) (map[string]*url.URL, <-chan int, error)",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Equal(t, reflect.TypeOf(func(context.Context, time.Duration, []byte, ...string) (map[string]*url.URL, <-chan int, error) {
+			return nil, nil, nil
+		}), typ)
+	})
+
+	f, err := flo.NewFlo("TestHeadless", "Test Headless", "Test Headless Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("a", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("b", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	double, err := flo.NewHeadlessComponent("Double", "githab.com/testuf/tera", "Double", "Double Description", "func(a int) int", nil)
+	require.NoError(t, err)
+	require.False(t, double.IsBound())
+	require.NoError(t, f.AddComponent(double))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, double.ID, double.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(double.ID, double.IOs[1].ID, f.ID, rOut.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "tera.Double(a)")
+		require.Empty(t, f.Symbols())
+	})
+
+	t.Run("Bind", func(t *testing.T) {
+		require.ErrorContains(t, double.Bind(time.Sleep), "function has 1 arguments and 0 results")
+
+		registry := flo.NewComponentRegistry()
+		require.NoError(t, registry.Register("githab.com/testuf/tera", "Double", func(a int) int { return a * 2 }))
+		require.ErrorContains(t, registry.Register("githab.com/testuf/tera", "Double", time.Sleep), "already registered")
+
+		require.NoError(t, registry.BindComponents(f))
+		require.True(t, double.IsBound())
+		require.NotEmpty(t, f.Symbols())
+	})
+}
+
+func TestRenderStubs(t *testing.T) {
+	f, err := flo.NewFlo("TestStubs", "Test Stubs", "Test Stubs Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	fetch, err := flo.NewHeadlessComponent(
+		"Fetch", "githab.com/testuf/tera", "Fetch", "Fetch fetches a page.",
+		"func(context.Context, *url.URL) ([]byte, error)", nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(fetch))
+
+	count, err := flo.NewHeadlessComponent(
+		"Count", "githab.com/testuf/tera", "Count", "",
+		"func([]byte) (map[string]int, time.Duration)", nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(count))
+
+	bound, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(bound))
+
+	t.Run("Unknown package", func(t *testing.T) {
+		err := f.RenderStubs(context.Background(), &bytes.Buffer{}, "githab.com/testuf/nope")
+		require.ErrorContains(t, err, "no unbound components")
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.RenderStubs(context.Background(), out, "githab.com/testuf/tera"))
+	require.Equal(t, `// Code generated by flo. Fill in the implementations and remove this comment.
+
+package tera
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Fetch fetches a page.
+func Fetch(context.Context, *url.URL) ([]byte, error) {
+	panic("not implemented")
+}
+
+func Count([]byte) (map[string]int, time.Duration) {
+	panic("not implemented")
+}
+`, out.String())
+}
@@ -0,0 +1,90 @@
+package flo_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+// testFuncs backs the components of newTestFlo.
+var testFuncs = map[string]any{
+	"githab.com/testuf/tera.CompA":   (compA{val: 10}).AddVal,
+	"githab.com/testurrf/terb.CompB": compBFn,
+	"githab.com/testuf/tera.CompC":   compCFn,
+	"githab.com/testam/taaar.CompD":  compDFn,
+	"gitlub.com/testing/teag.CompE":  compEFn,
+}
+
+func resolveTestFunc(pkgPath, name string) (any, error) {
+	fn, found := testFuncs[pkgPath+"."+name]
+	if !found {
+		return nil, fmt.Errorf("unknown function %s.%s", pkgPath, name)
+	}
+
+	return fn, nil
+}
+
+// newTestFlo builds the same flo as TestFlo.
+func newTestFlo(t testing.TB) *flo.Flo {
+	t.Helper()
+
+	f, err := flo.NewFlo(
+		"TestSync",
+		"Test Flo Label",
+		"Test Flo Description",
+		"flo",
+		"Test Package Flo Description",
+	)
+	require.NoError(t, err)
+
+	for _, io := range []struct {
+		name  string
+		typ   flo.ComponentIOType
+		rType reflect.Type
+	}{
+		{"ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context]()},
+		{"in", flo.ComponentIOTypeIN, reflect.TypeFor[int]()},
+		{"unused", flo.ComponentIOTypeIN, reflect.TypeFor[int]()},
+		{"result", flo.ComponentIOTypeOUT, reflect.TypeFor[int]()},
+		{"err", flo.ComponentIOTypeOUT, reflect.TypeFor[error]()},
+	} {
+		fio, err := flo.NewComponentIO(io.name, io.typ, io.rType, f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(fio))
+	}
+
+	components := make(map[string]*flo.Component)
+	for _, c := range []struct {
+		name, pkgPath, label string
+	}{
+		{"CompA", "githab.com/testuf/tera", "Test Comp A"},
+		{"CompB", "githab.com/testurrf/terb", "Test Comp B"},
+		{"CompC", "githab.com/testuf/tera", "Test Comp C"},
+		{"CompD", "githab.com/testam/taaar", "Test Comp D"},
+		{"CompE", "gitlub.com/testing/teag", "Test Comp E"},
+	} {
+		fn, err := resolveTestFunc(c.pkgPath, c.name)
+		require.NoError(t, err)
+
+		comp, err := flo.NewComponent(c.name, c.pkgPath, c.label+" Label", c.label+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(comp))
+		components[c.name] = comp
+	}
+
+	compA, compB, compC, compD := components["CompA"], components["CompB"], components["CompC"], components["CompD"]
+	require.NoError(t, f.ConnectComponent(f.ID, f.IOs[0].ID, compC.ID, compC.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, f.IOs[0].ID, compA.ID, compA.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, f.IOs[1].ID, compA.ID, compA.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, f.IOs[1].ID, compB.ID, compB.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(compD.ID, compD.IOs[0].ID, compB.ID, compB.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(compA.ID, compA.IOs[2].ID, compC.ID, compC.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(compB.ID, compB.IOs[2].ID, compC.ID, compC.IOs[2].ID))
+	require.NoError(t, f.ConnectComponent(compC.ID, compC.IOs[3].ID, f.ID, f.IOs[3].ID))
+
+	return f
+}
@@ -0,0 +1,103 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mgjules/flo/flohttp"
+)
+
+// flohttpPkg holds the functions the HTTP components render calls to.
+const flohttpPkg = "github.com/mgjules/flo/flohttp"
+
+var (
+	stringRType  = reflect.TypeFor[string]()
+	stringsRType = reflect.TypeFor[[]string]()
+)
+
+// NewHTTPComponent creates a component sending a method request to
+// urlTemplate and decoding the JSON response into a res, e.g.
+//
+//	func(ctx context.Context, method, url string, params []string, body req) (res, error)
+//
+// The {name} placeholders of urlTemplate are filled, in order, by the
+// strings connected to the params io, which accepts a connection per
+// placeholder. A nil req sends no body. The requests are sent by
+// flohttp.Client.
+func NewHTTPComponent(
+	method, urlTemplate string,
+	req, res reflect.Type,
+	label, description string,
+) (*Component, error) {
+	if method == "" {
+		return nil, errors.New("missing method")
+	}
+	if urlTemplate == "" {
+		return nil, errors.New("missing url template")
+	}
+	if _, err := flohttp.Expand(urlTemplate, make([]string, strings.Count(urlTemplate, "{"))); err != nil {
+		return nil, fmt.Errorf("invalid url template: %v", err)
+	}
+	if res == nil {
+		return nil, errors.New("missing response type")
+	}
+
+	name, typeArgs := "Fetch", []reflect.Type{res}
+	if req != nil {
+		name, typeArgs = "Send", []reflect.Type{req, res}
+	}
+
+	fn, err := httpFunc(name, typeArgs)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewComponent(name, flohttpPkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = typeArgs
+	c.IOs[1].Literal = reflect.ValueOf(strings.ToUpper(method))
+	c.IOs[2].Literal = reflect.ValueOf(urlTemplate)
+	c.IOs[3].Multi = true
+
+	return c, nil
+}
+
+// httpFunc rebuilds the function backing an HTTP component.
+func httpFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	in := []reflect.Type{contextRType, stringRType, stringRType, stringsRType}
+	switch {
+	case name == "Fetch" && len(typeArgs) == 1:
+	case name == "Send" && len(typeArgs) == 2:
+		in = append(in, typeArgs[0])
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown http component %q with %d type arguments", name, len(typeArgs))
+	}
+	res := typeArgs[len(typeArgs)-1]
+
+	return makeFunc(in, []reflect.Type{res, errorRType},
+		func(args []reflect.Value) []reflect.Value {
+			var body any
+			if len(args) > 4 {
+				body = args[4].Interface()
+			}
+			ctx, ok := args[0].Interface().(context.Context)
+			if !ok {
+				ctx = context.Background()
+			}
+			v := reflect.New(res)
+			err := flohttp.Do(
+				ctx,
+				args[1].String(), args[2].String(),
+				args[3].Interface().([]string),
+				body, v.Interface(),
+			)
+			if err != nil {
+				return []reflect.Value{reflect.Zero(res), errorValue(err)}
+			}
+			return []reflect.Value{v.Elem(), errorValue(nil)}
+		}), nil
+}
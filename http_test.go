@@ -0,0 +1,77 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPComponent(t *testing.T) {
+	resT := reflect.TypeFor[map[string]string]()
+
+	t.Run("Invalid components", func(t *testing.T) {
+		_, err := flo.NewHTTPComponent("", "https://api.test", nil, resT, "Get", "Get")
+		require.ErrorContains(t, err, "missing method")
+
+		_, err = flo.NewHTTPComponent("GET", "https://api.test/{id", nil, resT, "Get", "Get")
+		require.ErrorContains(t, err, "invalid url template")
+
+		_, err = flo.NewHTTPComponent("GET", "https://api.test", nil, nil, "Get", "Get")
+		require.ErrorContains(t, err, "missing response type")
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`"}`)
+	}))
+	defer srv.Close()
+
+	get, err := flo.NewHTTPComponent("get", srv.URL+"/users/{id}", nil, resT, "Get User", "Get the user")
+	require.NoError(t, err)
+	require.Equal(t, flo.EffectNetwork, flo.InferEffects(get)&flo.EffectNetwork)
+
+	t.Run("Call", func(t *testing.T) {
+		res := get.Value.Call([]reflect.Value{
+			reflect.ValueOf(context.Background()),
+			get.IOs[1].Literal,
+			get.IOs[2].Literal,
+			reflect.ValueOf([]string{"42"}),
+		})
+		require.True(t, res[1].IsNil())
+		require.Equal(t, map[string]string{"path": "/users/42"}, res[0].Interface())
+	})
+
+	f, err := flo.NewFlo("TestHTTP", "Test HTTP", "Test HTTP Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, id := flo.In[context.Context]("ctx"), flo.In[string]("id")
+	user, rErr := flo.Out[map[string]string]("user"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, id, user, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+	require.NoError(t, f.AddComponent(get))
+	require.NoError(t, f.ConnectComponent(f.ID, ctx.ID, get.ID, get.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, id.ID, get.ID, get.IOs[3].ID))
+	require.NoError(t, f.ConnectComponent(get.ID, get.IOs[4].ID, f.ID, user.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `:= flohttp.Fetch[map[string]string](ctx, "GET", "`+srv.URL+`/users/{id}", []string{id})`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.True(t, decoded.Components[get.ID].IsBound())
+	})
+}
@@ -0,0 +1,406 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// ImportFunc bootstraps a flo from the straight-line function called name in
+// the Go source src. Every call statement becomes a component, bound later on
+// with Bind or a ComponentRegistry, and the data flowing between the calls
+// becomes connections.
+//
+// The function may only assign the results of package-qualified calls, check
+// the errors they return and return. Arguments are parameters, results of
+// previous calls or constants. Imports are resolved through imp; a nil imp
+// type-checks the imported packages from source. The types used are resolved
+// through registry; a nil registry uses DefaultTypeRegistry.
+func ImportFunc(src []byte, name string, imp types.Importer, registry *TypeRegistry) (*Flo, error) {
+	if registry == nil {
+		registry = DefaultTypeRegistry
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %v", err)
+	}
+	if imp == nil {
+		imp = importer.ForCompiler(fset, "source", nil)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: imp}
+	if _, err := conf.Check(file.Name.Name, fset, []*ast.File{file}, info); err != nil {
+		return nil, fmt.Errorf("invalid source: %v", err)
+	}
+
+	fd, found := findFuncDecl(file, name)
+	if !found {
+		return nil, fmt.Errorf("no function %q found in source", name)
+	}
+	if fd.Recv != nil || fd.Type.TypeParams != nil {
+		return nil, fmt.Errorf("%q is a method or a generic function", name)
+	}
+
+	description := strings.TrimSpace(fd.Doc.Text())
+	if description == "" {
+		description = fmt.Sprintf("Imported from %s.", name)
+	}
+	var pkgDescription string
+	if file.Doc != nil {
+		pkgDescription = strings.TrimSpace(file.Doc.Text())
+	}
+
+	f, err := NewFlo(name, name, description, file.Name.Name, pkgDescription)
+	if err != nil {
+		return nil, err
+	}
+
+	im := funcImporter{
+		f:        f,
+		fset:     fset,
+		info:     info,
+		registry: registry,
+		vars:     make(map[types.Object]ioRef),
+	}
+	if err := im.params(fd); err != nil {
+		return nil, err
+	}
+
+	for i, stmt := range fd.Body.List {
+		if err := im.stmt(stmt, i == len(fd.Body.List)-1); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func findFuncDecl(file *ast.File, name string) (*ast.FuncDecl, bool) {
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Name.Name == name {
+			return fd, true
+		}
+	}
+
+	return nil, false
+}
+
+// ioRef points at the io holding the value of a variable.
+type ioRef struct {
+	componentID uuid.UUID
+	ioID        uuid.UUID
+}
+
+type funcImporter struct {
+	f        *Flo
+	fset     *token.FileSet
+	info     *types.Info
+	registry *TypeRegistry
+	vars     map[types.Object]ioRef
+}
+
+// params adds the parameters of fd as in ios of the flo.
+func (im *funcImporter) params(fd *ast.FuncDecl) error {
+	for _, field := range fd.Type.Params.List {
+		if len(field.Names) == 0 {
+			return fmt.Errorf("%s: unnamed parameter", im.fset.Position(field.Pos()))
+		}
+		for _, ident := range field.Names {
+			obj := im.info.Defs[ident]
+			rt, err := goTypesType(obj.Type(), im.registry)
+			if err != nil {
+				return fmt.Errorf("%s: cannot resolve parameter %q: %v", im.fset.Position(ident.Pos()), ident.Name, err)
+			}
+			io, err := NewComponentIO(ident.Name, ComponentIOTypeIN, rt, im.f.ID)
+			if err != nil {
+				return err
+			}
+			if err := im.f.AddIO(io); err != nil {
+				return err
+			}
+			im.vars[obj] = ioRef{componentID: im.f.ID, ioID: io.ID}
+		}
+	}
+
+	return nil
+}
+
+func (im *funcImporter) stmt(stmt ast.Stmt, last bool) error {
+	pos := im.fset.Position(stmt.Pos())
+
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if len(s.Rhs) != 1 {
+			return fmt.Errorf("%s: only single calls can be assigned", pos)
+		}
+		call, ok := s.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return fmt.Errorf("%s: only calls can be assigned", pos)
+		}
+		return im.call(call, s.Lhs)
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return fmt.Errorf("%s: not a call", pos)
+		}
+		return im.call(call, nil)
+	case *ast.IfStmt:
+		// Components already return their errors.
+		if im.isErrCheck(s) {
+			return nil
+		}
+		return fmt.Errorf("%s: only error checks returning zero values and the checked error are supported, the function must be straight-line", pos)
+	case *ast.ReturnStmt:
+		if !last {
+			return fmt.Errorf("%s: early return", pos)
+		}
+		return im.results(s)
+	default:
+		return fmt.Errorf("%s: unsupported statement, the function must be straight-line", pos)
+	}
+}
+
+// call adds a component for the function called, connects its arguments and
+// names its results after lhs.
+func (im *funcImporter) call(call *ast.CallExpr, lhs []ast.Expr) error {
+	pos := im.fset.Position(call.Pos())
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return fmt.Errorf("%s: only package-qualified functions can be called", pos)
+	}
+	fn, ok := im.info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return fmt.Errorf("%s: only package-qualified functions can be called", pos)
+	}
+	if call.Ellipsis.IsValid() {
+		return fmt.Errorf("%s: variadic calls are not supported", pos)
+	}
+
+	c, err := NewComponentFromTypesFunc(fn, fn.Name(), fmt.Sprintf("Calls %s.%s.", fn.Pkg().Name(), fn.Name()), im.registry)
+	if err != nil {
+		return fmt.Errorf("%s: %v", pos, err)
+	}
+	ins, outs := c.IOs.SeparateINsOUTs()
+	if len(call.Args) != len(ins) {
+		return fmt.Errorf("%s: variadic calls are not supported", pos)
+	}
+	if len(lhs) > 0 && len(lhs) != len(outs) {
+		return fmt.Errorf("%s: every result must be assigned", pos)
+	}
+
+	// Results take the name of their variable.
+	results := make(map[types.Object]ioRef, len(lhs))
+	for i, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("%s: results can only be assigned to variables", pos)
+		}
+		if ident.Name == "_" {
+			continue
+		}
+		if !outs[i].IsError {
+			outs[i].Name = ident.Name
+		}
+		obj := im.info.Defs[ident]
+		if obj == nil {
+			obj = im.info.Uses[ident]
+		}
+		results[obj] = ioRef{componentID: c.ID, ioID: outs[i].ID}
+	}
+
+	if err := im.f.AddComponent(c); err != nil {
+		return err
+	}
+
+	for i, arg := range call.Args {
+		if err := im.arg(c, ins[i], arg); err != nil {
+			return fmt.Errorf("%s: argument %d: %v", im.fset.Position(arg.Pos()), i+1, err)
+		}
+	}
+
+	for obj, ref := range results {
+		im.vars[obj] = ref
+	}
+
+	return nil
+}
+
+// arg feeds in with arg, either a constant or a known variable.
+func (im *funcImporter) arg(c *Component, in *ComponentIO, arg ast.Expr) error {
+	if tv := im.info.Types[arg]; tv.Value != nil {
+		v, err := constantValue(tv.Value, in.RType)
+		if err != nil {
+			return err
+		}
+		return im.f.SetLiteral(c.ID, in.ID, v.Interface())
+	}
+
+	ref, err := im.ref(arg)
+	if err != nil {
+		return err
+	}
+
+	return im.f.ConnectComponent(ref.componentID, ref.ioID, c.ID, in.ID)
+}
+
+func (im *funcImporter) ref(expr ast.Expr) (ioRef, error) {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return ioRef{}, errors.New("only parameters, results and constants are supported")
+	}
+	ref, found := im.vars[im.info.Uses[ident]]
+	if !found {
+		return ioRef{}, fmt.Errorf("unknown variable %q", ident.Name)
+	}
+
+	return ref, nil
+}
+
+// results adds the results of the function as out ios of the flo, fed by the
+// values returned. Errors are returned by the components already.
+func (im *funcImporter) results(ret *ast.ReturnStmt) error {
+	for i, expr := range ret.Results {
+		pos := im.fset.Position(expr.Pos())
+
+		tv := im.info.Types[expr]
+		if tv.IsNil() || types.Identical(tv.Type, types.Universe.Lookup("error").Type()) {
+			io, err := NewComponentIO("err", ComponentIOTypeOUT, errorRType, im.f.ID)
+			if err != nil {
+				return err
+			}
+			if err := im.f.AddIO(io); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rt, err := goTypesType(tv.Type, im.registry)
+		if err != nil {
+			return fmt.Errorf("%s: cannot resolve result %d: %v", pos, i+1, err)
+		}
+
+		ref, err := im.ref(expr)
+		if err != nil {
+			return fmt.Errorf("%s: result %d: %v", pos, i+1, err)
+		}
+		if ref.componentID == im.f.ID {
+			return fmt.Errorf("%s: result %d: parameters cannot be returned as is", pos, i+1)
+		}
+
+		io, err := NewComponentIO(ast.Unparen(expr).(*ast.Ident).Name, ComponentIOTypeOUT, rt, im.f.ID)
+		if err != nil {
+			return err
+		}
+		if err := im.f.AddIO(io); err != nil {
+			return err
+		}
+		if err := im.f.ConnectComponent(ref.componentID, ref.ioID, im.f.ID, io.ID); err != nil {
+			return fmt.Errorf("%s: result %d: %v", pos, i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// isErrCheck reports whether s is a bare "if err != nil { return ..., err }"
+// returning zero values along with the checked error, as the components
+// of the flo do.
+func (im *funcImporter) isErrCheck(s *ast.IfStmt) bool {
+	if s.Init != nil || s.Else != nil || len(s.Body.List) != 1 {
+		return false
+	}
+	ret, ok := s.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 {
+		return false
+	}
+	cond, ok := s.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ || !im.info.Types[cond.Y].IsNil() {
+		return false
+	}
+	checked, ok := ast.Unparen(cond.X).(*ast.Ident)
+	if !ok || !types.Identical(im.info.TypeOf(checked), types.Universe.Lookup("error").Type()) {
+		return false
+	}
+
+	last := len(ret.Results) - 1
+	returned, ok := ast.Unparen(ret.Results[last]).(*ast.Ident)
+	if !ok || im.info.Uses[returned] == nil || im.info.Uses[returned] != im.info.Uses[checked] {
+		return false
+	}
+
+	return lo.EveryBy(ret.Results[:last], im.isZero)
+}
+
+// isZero reports whether expr is the zero value of its type, e.g. 0, "",
+// nil or T{}.
+func (im *funcImporter) isZero(expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		return len(lit.Elts) == 0
+	}
+
+	tv := im.info.Types[expr]
+	switch {
+	case tv.IsNil():
+		return true
+	case tv.Value == nil:
+		return false
+	}
+	switch tv.Value.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(tv.Value)
+	case constant.String:
+		return constant.StringVal(tv.Value) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(tv.Value) == 0
+	default:
+		return false
+	}
+}
+
+// constantValue converts the constant v into a value of type t.
+func constantValue(v constant.Value, t reflect.Type) (reflect.Value, error) {
+	var x any
+	switch v.Kind() {
+	case constant.Bool:
+		x = constant.BoolVal(v)
+	case constant.String:
+		x = constant.StringVal(v)
+	case constant.Int:
+		i, exact := constant.Int64Val(v)
+		if !exact {
+			return reflect.Value{}, fmt.Errorf("constant %s overflows int64", v)
+		}
+		x = i
+	case constant.Float:
+		fl, _ := constant.Float64Val(v)
+		x = fl
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported constant %s", v)
+	}
+
+	rv := reflect.ValueOf(x)
+	if (rv.Kind() == reflect.String) != (t.Kind() == reflect.String) || !rv.CanConvert(t) {
+		return reflect.Value{}, fmt.Errorf("constant %s cannot be converted to %s", v, t)
+	}
+
+	return rv.Convert(t), nil
+}
@@ -0,0 +1,100 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+const importSrc = `// Package conv converts things.
+package conv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Triple repeats the number in s three times.
+func Triple(s string) (string, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return "", err
+	}
+	text := strconv.Itoa(n)
+	tripled := strings.Repeat(text, 3)
+	return tripled, nil
+}
+
+func Loop(s string) string {
+	for range 3 {
+		s = strings.ToUpper(s)
+	}
+	return s
+}
+
+func Nested(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+func Wrapped(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("atoi: %w", err)
+	}
+	return n, nil
+}
+
+func Defaulted(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 1, err
+	}
+	return n, nil
+}
+`
+
+func TestImportFunc(t *testing.T) {
+	t.Run("Unknown function", func(t *testing.T) {
+		_, err := flo.ImportFunc([]byte(importSrc), "Double", nil, nil)
+		require.ErrorContains(t, err, `no function "Double" found`)
+	})
+
+	t.Run("Not straight-line", func(t *testing.T) {
+		_, err := flo.ImportFunc([]byte(importSrc), "Loop", nil, nil)
+		require.ErrorContains(t, err, "must be straight-line")
+	})
+
+	t.Run("Error checks", func(t *testing.T) {
+		for _, name := range []string{"Wrapped", "Defaulted"} {
+			_, err := flo.ImportFunc([]byte(importSrc), name, nil, nil)
+			require.ErrorContains(t, err, "only error checks returning zero values and the checked error are supported", name)
+		}
+	})
+
+	t.Run("Nested calls", func(t *testing.T) {
+		_, err := flo.ImportFunc([]byte(importSrc), "Nested", nil, nil)
+		require.ErrorContains(t, err, "only parameters, results and constants are supported")
+	})
+
+	t.Run("Straight-line", func(t *testing.T) {
+		f, err := flo.ImportFunc([]byte(importSrc), "Triple", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "conv", f.PkgName)
+		require.Equal(t, "Triple repeats the number in s three times.", f.Description)
+		require.Len(t, f.Components, 3)
+		require.Len(t, f.IOs, 3)
+		require.Empty(t, f.Validate(context.Background()))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "func Triple(s string) (string, error) {")
+		require.Contains(t, out.String(), "n, err := strconv.Atoi(s)")
+		require.Contains(t, out.String(), "text := strconv.Itoa(n)")
+		require.Contains(t, out.String(), "tripled := strings.Repeat(text, 3)")
+		require.Contains(t, out.String(), "return tripled, nil")
+	})
+}
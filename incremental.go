@@ -0,0 +1,112 @@
+package flo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// RenderIncremental is like Render but reuses the code generated by the
+// previous call for the components that did not change since then, which
+// makes re-rendering large flos on every edit cheap.
+//
+// Changes made through the Flo methods are tracked. Components modified
+// directly must be flagged with MarkDirty. The same options should be used
+// between incremental renders, or the cache reset with MarkDirty first.
+func (f *Flo) RenderIncremental(
+	ctx context.Context,
+	w io.Writer,
+	opts ...RenderOption,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.render(ctx, w, newRenderOptions(opts), true)
+}
+
+// MarkDirty flags components as changed so that RenderIncremental generates
+// their code again. Without ids, every component is flagged.
+func (f *Flo) MarkDirty(ids ...uuid.UUID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(ids) == 0 {
+		f.markAllDirty()
+		return
+	}
+
+	f.markDirty(ids...)
+}
+
+// DirtyComponents returns the ids of the components RenderIncremental will
+// generate the code of again.
+func (f *Flo) DirtyComponents() []uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ids []uuid.UUID
+	for _, c := range f.orderedComponents() {
+		if _, found := f.fragments[c.ID]; !found {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	return ids
+}
+
+func (f *Flo) markDirty(ids ...uuid.UUID) {
+	for _, id := range ids {
+		delete(f.fragments, id)
+	}
+}
+
+func (f *Flo) markAllDirty() {
+	f.fragments = nil
+}
+
+// renderFragments renders every component in execution order into g, from
+// the cache when clean.
+func (f *Flo) renderFragments(
+	ctx context.Context,
+	g *jen.Group,
+	rendered map[uuid.UUID]struct{},
+) error {
+	order, err := f.executionOrder()
+	if err != nil {
+		return fmt.Errorf("failed to render component: %v", err)
+	}
+
+	if f.fragments == nil {
+		f.fragments = make(map[uuid.UUID]jen.Code, len(f.Components))
+	}
+
+	for _, c := range order {
+		fragment, found := f.fragments[c.ID]
+		if found && c.Flag == nil {
+			g.Add(fragment)
+			rendered[c.ID] = struct{}{}
+			continue
+		}
+
+		// The components c depends on are already rendered, so only c ends
+		// up in the fragment.
+		var renderErr error
+		fragment = jen.CustomFunc(jen.Options{Separator: "\n"}, func(fg *jen.Group) {
+			renderErr = f.RenderComponent(ctx, fg, c, rendered)
+		})
+		if renderErr != nil {
+			return fmt.Errorf("failed to render component: %v", renderErr)
+		}
+
+		// Gated components are rendered along with their whole gate.
+		if c.Flag == nil {
+			f.fragments[c.ID] = fragment
+		}
+		g.Add(fragment)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderIncremental(t *testing.T) {
+	f := newTestFlo(t)
+	require.Len(t, f.DirtyComponents(), len(f.Components))
+
+	render := func(t *testing.T) (string, string) {
+		t.Helper()
+
+		full := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), full))
+
+		incremental := &bytes.Buffer{}
+		require.NoError(t, f.RenderIncremental(context.Background(), incremental))
+
+		return full.String(), incremental.String()
+	}
+
+	full, incremental := render(t)
+	require.Equal(t, full, incremental)
+	require.Empty(t, f.DirtyComponents())
+
+	var compE *flo.Component
+	for _, c := range f.Components {
+		if c.Name == "CompE" {
+			compE = c
+		}
+	}
+	require.NotNil(t, compE)
+
+	t.Run("Clean components are cached", func(t *testing.T) {
+		compE.Description = "Changed Description"
+
+		_, incremental := render(t)
+		require.NotContains(t, incremental, "Changed Description")
+
+		f.MarkDirty(compE.ID)
+		require.Len(t, f.DirtyComponents(), 1)
+
+		full, incremental := render(t)
+		require.Equal(t, full, incremental)
+		require.Contains(t, incremental, "Changed Description")
+	})
+
+	t.Run("Changes are tracked", func(t *testing.T) {
+		require.NoError(t, f.DeleteComponent(compE.ID))
+		require.Empty(t, f.DirtyComponents())
+
+		full, incremental := render(t)
+		require.Equal(t, full, incremental)
+		require.NotContains(t, incremental, "CompE")
+
+		var conn *flo.ComponentConnection
+		for _, c := range f.Components {
+			for _, io := range c.IOs {
+				for _, cc := range io.Connections {
+					if cc.OutComponentID != f.ID && cc.InComponentID != f.ID {
+						conn = cc
+					}
+				}
+			}
+		}
+		require.NotNil(t, conn)
+		require.NoError(t, f.DeleteConnection(conn.ID))
+		require.ElementsMatch(t, []uuid.UUID{conn.OutComponentID, conn.InComponentID}, f.DirtyComponents())
+	})
+}
@@ -0,0 +1,145 @@
+package flo
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strconv"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// assignError explains why values of type from cannot be assigned to to,
+// naming the method missing for interfaces.
+func assignError(from, to reflect.Type) error {
+	if to.Kind() != reflect.Interface {
+		return fmt.Errorf("%s cannot be assigned to %s", from, to)
+	}
+
+	for i := range to.NumMethod() {
+		want := to.Method(i)
+		got, found := from.MethodByName(want.Name)
+		switch {
+		case !found && from.Kind() != reflect.Pointer && reflect.PointerTo(from).Implements(to):
+			return fmt.Errorf("%s does not implement %s (method %s has pointer receiver)", from, to, want.Name)
+		case !found:
+			return fmt.Errorf("%s does not implement %s (missing method %s)", from, to, want.Name)
+		case from.Kind() != reflect.Interface && got.Type.NumIn() > 0 && !signatureMatches(got.Type, want.Type):
+			return fmt.Errorf("%s does not implement %s (wrong type for method %s)", from, to, want.Name)
+		}
+	}
+
+	return fmt.Errorf("%s does not implement %s", from, to)
+}
+
+// signatureMatches reports whether the method m, receiver included, has the
+// signature of the interface method want.
+func signatureMatches(m, want reflect.Type) bool {
+	if m.NumIn()-1 != want.NumIn() || m.NumOut() != want.NumOut() || m.IsVariadic() != want.IsVariadic() {
+		return false
+	}
+	for i := range want.NumIn() {
+		if m.In(i+1) != want.In(i) {
+			return false
+		}
+	}
+	for i := range want.NumOut() {
+		if m.Out(i) != want.Out(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nilableKinds are the kinds whose nil values turn into non-nil interfaces
+// once returned as such.
+var nilableKinds = map[reflect.Kind]struct{}{
+	reflect.Pointer: {},
+	reflect.Map:     {},
+	reflect.Slice:   {},
+	reflect.Chan:    {},
+	reflect.Func:    {},
+}
+
+// interfaceResults declares, in g, the interface values returned for the
+// flo outs of interface type fed by concrete values which may be nil, and
+// returns their variables by out io id. A nil pointer is then returned as a
+// nil interface rather than as an interface holding a nil pointer:
+//
+//	var r io.Reader
+//	if buf != nil {
+//		r = buf
+//	}
+//
+// Named results are assigned directly.
+func (f *Flo) interfaceResults(g *jen.Group, ins, outs IOs, named bool) map[uuid.UUID]string {
+	taken := map[string]struct{}{"err": {}, errsVar: {}}
+	for _, in := range ins {
+		taken[in.Name] = struct{}{}
+	}
+	for _, c := range f.Components {
+		for _, io := range c.IOs {
+			taken[io.Name] = struct{}{}
+		}
+	}
+	results := resultNames(ins, outs)
+
+	vars := make(map[uuid.UUID]string)
+	for i, out := range outs {
+		if out.RType.Kind() != reflect.Interface || len(out.Connections) == 0 {
+			continue
+		}
+		conn := out.Connections[0]
+		from := conn.Transform.outType()
+		if from == nil {
+			src, found := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
+			if !found {
+				continue
+			}
+			from = src.RType
+		}
+		if _, found := nilableKinds[from.Kind()]; !found {
+			continue
+		}
+
+		name := results[i]
+		if !named {
+			name = uniqueName(name, taken)
+			g.Var().Id(name).Add(typeCode(out.RType))
+		}
+		vars[out.ID] = name
+
+		g.If(jen.Add(inValue(out)).Op("!=").Nil()).Block(
+			jen.Id(name).Op("=").Add(inValue(out)),
+		).Line()
+	}
+
+	return vars
+}
+
+// uniqueName suffixes base with a number until it is neither taken nor a
+// keyword, and takes it.
+func uniqueName(base string, taken map[string]struct{}) string {
+	name := base
+	for i := 2; ; i++ {
+		_, found := taken[name]
+		if !found && !token.IsKeyword(name) {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	taken[name] = struct{}{}
+
+	return name
+}
+
+// outType returns the type t produces, if any.
+func (t *Transform) outType() reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	return t.Out
+}
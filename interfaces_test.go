@@ -0,0 +1,99 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func newBufferFn(s string) (*bytes.Buffer, error) {
+	return bytes.NewBufferString(s), nil
+}
+
+func bufferFn(s string) bytes.Buffer {
+	return *bytes.NewBufferString(s)
+}
+
+func TestInterfaceOutputs(t *testing.T) {
+	f, err := flo.NewFlo("Open", "Open", "Open Description", "flo", "Open Package")
+	require.NoError(t, err)
+
+	pS, err := flo.NewComponentIO("s", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pS))
+
+	rReader, err := flo.NewComponentIO("r", flo.ComponentIOTypeOUT, reflect.TypeFor[io.Reader](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rReader))
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	newBuffer, err := flo.NewComponent("NewBuffer", "githab.com/testuf/tera", "New Buffer", "New Buffer Description", newBufferFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(newBuffer))
+
+	buffer, err := flo.NewComponent("Buffer", "githab.com/testuf/tera", "Buffer", "Buffer Description", bufferFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(buffer))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pS.ID, newBuffer.ID, newBuffer.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pS.ID, buffer.ID, buffer.IOs[0].ID))
+
+	t.Run("Not implemented", func(t *testing.T) {
+		err := f.ConnectComponent(buffer.ID, buffer.IOs[1].ID, f.ID, rReader.ID)
+		require.ErrorContains(t, err, "bytes.Buffer does not implement io.Reader (method Read has pointer receiver)")
+
+	})
+
+	require.NoError(t, f.ConnectComponent(newBuffer.ID, newBuffer.IOs[1].ID, f.ID, rReader.ID))
+	require.Empty(t, f.Validate(context.Background()))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Open Package
+package flo
+
+import (
+	tera "githab.com/testuf/tera"
+	"io"
+)
+
+func Open(s string) (io.Reader, error) {
+	// New Buffer Description
+	iodcffe4F48B75138219155377Eb936719Dd623Cbb, err := tera.NewBuffer(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buffer Description
+	tera.Buffer(s)
+
+	var r io.Reader
+	if iodcffe4F48B75138219155377Eb936719Dd623Cbb != nil {
+		r = iodcffe4F48B75138219155377Eb936719Dd623Cbb
+	}
+
+	return r, nil
+}
+`, out.String())
+	})
+
+	t.Run("Render with named results", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithNamedResults()))
+		require.Contains(t, out.String(), "func Open(s string) (r io.Reader, err2 error) {")
+		require.Contains(t, out.String(), "\t\tr = iodcffe4F48B75138219155377Eb936719Dd623Cbb\n")
+		require.NotContains(t, out.String(), "var r io.Reader")
+		require.Contains(t, out.String(), "return r, nil")
+	})
+}
@@ -0,0 +1,115 @@
+package flo
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// Invariants verifies the internal consistency of the flo: every indexed
+// connection is referenced by the ios at both of its ends and the other way
+// around, ios point back to their parent and there are no nil entries.
+//
+// It is meant for embedders mutating Components or IOs directly, e.g. after
+// bulk imports.
+func (f *Flo) Invariants() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.invariants()
+}
+
+func (f *Flo) invariants() error {
+	conns := make(map[uuid.UUID]struct{}, len(f.connectionIndex))
+	checkIOs := func(id uuid.UUID, ios IOs) error {
+		for _, io := range ios {
+			if io == nil {
+				return fmt.Errorf("component id %q has a nil io", id)
+			}
+			if io.ParentID != id {
+				return fmt.Errorf("io id %q has parent id %q instead of %q", io.ID, io.ParentID, id)
+			}
+			for _, conn := range io.Connections {
+				if conn == nil {
+					return fmt.Errorf("io id %q has a nil connection", io.ID)
+				}
+				if f.connectionIndex[conn.ID] != conn {
+					return fmt.Errorf("connection id %q of io id %q is not indexed", conn.ID, io.ID)
+				}
+				conns[conn.ID] = struct{}{}
+			}
+			if io.Type == ComponentIOTypeIN && id != f.ID && !io.Multi && len(io.Connections) > 1 {
+				return fmt.Errorf("in io id %q has %d connections", io.ID, len(io.Connections))
+			}
+		}
+
+		return nil
+	}
+
+	if err := checkIOs(f.ID, f.IOs); err != nil {
+		return err
+	}
+	for id, c := range f.Components {
+		if c == nil {
+			return fmt.Errorf("component id %q is nil", id)
+		}
+		if c.ID != id {
+			return fmt.Errorf("component id %q is indexed as %q", c.ID, id)
+		}
+		if err := checkIOs(id, c.IOs); err != nil {
+			return err
+		}
+	}
+	for _, conn := range f.Sequences {
+		if conn == nil {
+			return fmt.Errorf("flo id %q has a nil sequence", f.ID)
+		}
+		if f.connectionIndex[conn.ID] != conn {
+			return fmt.Errorf("sequence id %q is not indexed", conn.ID)
+		}
+		for _, id := range []uuid.UUID{conn.OutComponentID, conn.InComponentID} {
+			if _, found := f.Components[id]; !found {
+				return fmt.Errorf("sequence id %q references missing component id %q", conn.ID, id)
+			}
+		}
+		conns[conn.ID] = struct{}{}
+	}
+
+	for id, conn := range f.connectionIndex {
+		if conn == nil {
+			return fmt.Errorf("connection id %q is nil", id)
+		}
+		if _, found := conns[id]; !found {
+			return fmt.Errorf("connection id %q is dangling", id)
+		}
+		if conn.Kind == ComponentConnectionKindSequence {
+			continue
+		}
+		for _, end := range [][2]uuid.UUID{
+			{conn.OutComponentID, conn.OutComponentIOID},
+			{conn.InComponentID, conn.InComponentIOID},
+		} {
+			io, found := f.componentIO(end[0], end[1])
+			if !found {
+				return fmt.Errorf("connection id %q references missing io id %q", id, end[1])
+			}
+			if !lo.Contains(io.Connections, conn) {
+				return fmt.Errorf("connection id %q is missing from io id %q", id, end[1])
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkInvariants panics when the flo is inconsistent in debug builds, i.e.
+// built with the flodebug tag. It must be called with the lock held.
+func (f *Flo) checkInvariants() {
+	if !debug {
+		return
+	}
+	if err := f.invariants(); err != nil {
+		panic(fmt.Sprintf("flo id %q: %v", f.ID, err))
+	}
+}
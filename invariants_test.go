@@ -0,0 +1,42 @@
+package flo_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvariants(t *testing.T) {
+	t.Run("Consistent", func(t *testing.T) {
+		f := newTestFlo(t)
+		require.NoError(t, f.Invariants())
+	})
+
+	t.Run("Unindexed connection", func(t *testing.T) {
+		f := newTestFlo(t)
+		conn, err := flo.NewComponentConnect(f.ID, f.IOs[2].ID, f.ID, f.IOs[4].ID)
+		require.NoError(t, err)
+		f.IOs[2].Connections = append(f.IOs[2].Connections, conn)
+		require.ErrorContains(t, f.Invariants(), "is not indexed")
+	})
+
+	t.Run("Dangling connection", func(t *testing.T) {
+		f := newTestFlo(t)
+		f.IOs[3].Connections = nil
+		require.ErrorContains(t, f.Invariants(), "is missing from io id")
+	})
+
+	t.Run("Wrong parent", func(t *testing.T) {
+		f := newTestFlo(t)
+		f.IOs[0].ParentID = uuid.New()
+		require.ErrorContains(t, f.Invariants(), "has parent id")
+	})
+
+	t.Run("Nil component", func(t *testing.T) {
+		f := newTestFlo(t)
+		f.Components[uuid.New()] = nil
+		require.ErrorContains(t, f.Invariants(), "is nil")
+	})
+}
@@ -0,0 +1,103 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// SetIOLabel sets the label of the flo io ioID, documenting it in the
+// generated code.
+func (f *Flo) SetIOLabel(ioID uuid.UUID, label string) error {
+	return f.setIODoc(ioID, func(io *ComponentIO) {
+		io.Label = label
+	})
+}
+
+// SetIODescription sets the description of the flo io ioID, documenting it
+// in the generated code.
+func (f *Flo) SetIODescription(ioID uuid.UUID, description string) error {
+	return f.setIODoc(ioID, func(io *ComponentIO) {
+		io.Description = description
+	})
+}
+
+func (f *Flo) setIODoc(ioID uuid.UUID, set func(io *ComponentIO)) error {
+	if ioID == uuid.Nil {
+		return errors.New("invalid io id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	io, found := f.IOs.GetByID(ioID)
+	if !found {
+		return fmt.Errorf("no io id %q found in flo", ioID)
+	}
+	set(io)
+
+	return nil
+}
+
+// ioDoc describes io in a single line, e.g. "in (Input): The string to
+// measure.". Results are described by their type as their names are the
+// ones of the values returned.
+func ioDoc(io *ComponentIO) string {
+	if io.Type == ComponentIOTypeOUT {
+		return TypeName(io.RType) + ioDocSuffix(io)
+	}
+
+	return io.Name + ioDocSuffix(io)
+}
+
+// ioDocSuffix is the label and description part of ioDoc.
+func ioDocSuffix(io *ComponentIO) string {
+	var doc string
+	if io.Label != "" {
+		doc += " (" + io.Label + ")"
+	}
+	if io.Description != "" {
+		doc += ": " + io.Description
+	}
+
+	return doc
+}
+
+// funcDoc writes the doc comment of the flo function, listing its params
+// and results, when any of its ios is documented.
+func (f *Flo) funcDoc(code *jen.File) {
+	documented := false
+	for _, io := range f.IOs {
+		documented = documented || io.Label != "" || io.Description != ""
+	}
+	if !documented {
+		return
+	}
+
+	if f.Description != "" {
+		code.Comment(f.Description)
+		code.Comment("")
+	}
+
+	ins, outs := f.IOs.SeparateINsOUTs()
+	for i, section := range []struct {
+		title string
+		ios   IOs
+	}{
+		{"Params", ins},
+		{"Results", outs},
+	} {
+		if len(section.ios) == 0 {
+			continue
+		}
+		if i > 0 && len(ins) > 0 {
+			code.Comment("")
+		}
+		code.Comment(section.title + ":")
+		for _, io := range section.ios {
+			code.Comment("  - " + ioDoc(io))
+		}
+	}
+}
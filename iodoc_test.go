@@ -0,0 +1,78 @@
+package flo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIODocs(t *testing.T) {
+	f, err := flo.NewFlo("TestDoc", "Test Doc", "TestDoc measures strings.", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, out := flo.In[string]("in"), flo.Out[int]("out")
+	require.NoError(t, f.AddIO(in))
+	require.NoError(t, f.AddIO(out))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, out.ID))
+
+	undocumented := f.Fingerprint()
+
+	require.ErrorContains(t, f.SetIOLabel(length.IOs[0].ID, "Nope"), "no io id")
+	require.ErrorContains(t, f.SetIODescription(uuid.Nil, "Nope"), "invalid io id")
+
+	require.NoError(t, f.SetIOLabel(in.ID, "Input"))
+	require.NoError(t, f.SetIODescription(in.ID, "The string to measure."))
+	require.NoError(t, f.SetIODescription(out.ID, "Its length in bytes."))
+	require.NotEqual(t, undocumented, f.Fingerprint())
+
+	t.Run("Render", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), buf))
+		require.Contains(t, buf.String(), `
+// TestDoc measures strings.
+//
+// Params:
+//   - in (Input): The string to measure.
+//
+// Results:
+//   - int: Its length in bytes.
+func TestDoc(in string) int {
+`)
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+
+		decoded, err := flo.DecodeBinary(buf, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "Input", decoded.IOs[0].Label)
+		require.Equal(t, "The string to measure.", decoded.IOs[0].Description)
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+
+	t.Run("Bundle docs", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.ExportBundle(buf, nil))
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		r, err := zr.Open("README.md")
+		require.NoError(t, err)
+		docs, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Contains(t, string(docs), "- `in` `string` (Input): The string to measure.\n")
+		require.Contains(t, string(docs), "- `int`: Its length in bytes.\n")
+	})
+}
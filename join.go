@@ -0,0 +1,37 @@
+package flo
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// NewJoinComponent creates a synchronization point waiting for the given
+// number of upstream branches to complete before letting the downstream
+// components run.
+//
+// Branches and downstream components are attached with ConnectSequence.
+// With WithParallel, the branches run concurrently and the join waits for
+// them.
+func NewJoinComponent(
+	name string,
+	label, description string,
+	branches int,
+) (*Component, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+	if branches < 1 {
+		return nil, errors.New("join needs at least one branch")
+	}
+
+	return &Component{
+		ID:          uuid.New(),
+		Name:        name,
+		Label:       label,
+		Description: description,
+		Kind:        ComponentKindJoin,
+		IOs:         make(IOs, 0),
+		Branches:    branches,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinComponent(t *testing.T) {
+	t.Run("Invalid joins", func(t *testing.T) {
+		_, err := flo.NewJoinComponent("", "Join", "Join", 2)
+		require.ErrorContains(t, err, "missing name")
+
+		_, err = flo.NewJoinComponent("Join", "Join", "Join", 0)
+		require.ErrorContains(t, err, "at least one branch")
+	})
+
+	f, err := flo.NewFlo("TestJoin", "Test Join", "Test Join Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	compD, err := flo.NewComponent("CompD", "githab.com/testam/taaar", "Comp D", "Comp D Description", compDFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compD))
+
+	compE, err := flo.NewComponent("CompE", "gitlub.com/testing/teag", "Comp E", "Comp E Description", compEFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compE))
+
+	compF, err := flo.NewComponent("CompF", "gitlub.com/testing/teag", "Comp F", "Comp F Description", compEFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compF))
+
+	join, err := flo.NewJoinComponent("Join", "Join", "Wait for D and E", 2)
+	require.NoError(t, err)
+	require.Equal(t, flo.ComponentKindJoin, join.Kind)
+	require.NoError(t, f.AddComponent(join))
+
+	require.NoError(t, f.ConnectSequence(join.ID, compF.ID))
+	require.NoError(t, f.ConnectSequence(compD.ID, join.ID))
+
+	t.Run("Join must have all its branches", func(t *testing.T) {
+		err := f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, "expects 2 branches but got 1")
+	})
+
+	require.NoError(t, f.ConnectSequence(compE.ID, join.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	taaar "githab.com/testam/taaar"
+	teag "gitlub.com/testing/teag"
+)
+
+func TestJoin() {
+	// Comp D Description
+	taaar.CompD()
+
+	// Comp E Description
+	teag.CompE()
+
+	// Wait for D and E
+
+	// Comp F Description
+	teag.CompF()
+
+	return
+}
+`, out.String())
+	})
+	t.Run("Render with parallel", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithParallel()))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	taaar "githab.com/testam/taaar"
+	teag "gitlub.com/testing/teag"
+	errgroup "golang.org/x/sync/errgroup"
+)
+
+func TestJoin() {
+	var (
+		eg errgroup.Group
+	)
+	eg.Go(func() error {
+		// Comp D Description
+		taaar.CompD()
+		return nil
+	})
+	eg.Go(func() error {
+		// Comp E Description
+		teag.CompE()
+		return nil
+	})
+	// Wait for D and E
+	if err := eg.Wait(); err != nil {
+		return
+	}
+
+	// Comp F Description
+	teag.CompF()
+
+	return
+}
+`, out.String())
+	})
+}
@@ -0,0 +1,157 @@
+package flo
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Position of a component or flo io in editors. It does not affect the
+// rendered code, fingerprints or equality.
+type Position struct {
+	X float64
+	Y float64
+}
+
+// LayoutOptions configures Layout. Zero values take the defaults.
+type LayoutOptions struct {
+	LayerSpacing float64 // Horizontal distance between layers, 200 by default.
+	NodeSpacing  float64 // Vertical distance within a layer, 100 by default.
+	Sweeps       int     // Crossing reduction passes, 4 by default.
+}
+
+// Layout computes layered positions for all the components and ios of the
+// flo, Sugiyama-style: the params of the flo come first, each component is
+// one layer after its furthest predecessor, the results come last, and
+// nodes are ordered within layers to reduce crossings.
+func (f *Flo) Layout(opts LayoutOptions) {
+	if opts.LayerSpacing == 0 {
+		opts.LayerSpacing = 200
+	}
+	if opts.NodeSpacing == 0 {
+		opts.NodeSpacing = 100
+	}
+	if opts.Sweeps == 0 {
+		opts.Sweeps = 4
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	floINs, floOUTs := f.IOs.SeparateINsOUTs()
+
+	// Nodes are the components and the ios of the flo, linked to the nodes
+	// feeding them.
+	preds := make(map[uuid.UUID][]uuid.UUID)
+	for _, c := range f.orderedComponents() {
+		ins, _ := c.IOs.SeparateINsOUTs()
+		for _, in := range ins {
+			for _, conn := range in.Connections {
+				if conn.OutComponentID == f.ID {
+					preds[c.ID] = append(preds[c.ID], conn.OutComponentIOID)
+				}
+			}
+		}
+		for _, id := range f.predecessors(c) {
+			if _, found := f.Components[id]; found {
+				preds[c.ID] = append(preds[c.ID], id)
+			}
+		}
+	}
+	for _, out := range floOUTs {
+		for _, conn := range out.Connections {
+			if conn.OutComponentID == f.ID {
+				preds[out.ID] = append(preds[out.ID], conn.OutComponentIOID)
+				continue
+			}
+			preds[out.ID] = append(preds[out.ID], conn.OutComponentID)
+		}
+	}
+
+	// Layers by longest path.
+	layerOf := make(map[uuid.UUID]int)
+	for _, in := range floINs {
+		layerOf[in.ID] = 0
+	}
+	var assign func(id uuid.UUID) int
+	assign = func(id uuid.UUID) int {
+		if layer, found := layerOf[id]; found {
+			return layer
+		}
+		layer := 1
+		for _, pred := range preds[id] {
+			layer = max(layer, assign(pred)+1)
+		}
+		layerOf[id] = layer
+
+		return layer
+	}
+	last := 1
+	for _, c := range f.orderedComponents() {
+		last = max(last, assign(c.ID)+1)
+	}
+	for _, out := range floOUTs {
+		layerOf[out.ID] = last
+	}
+
+	layers := make([][]uuid.UUID, last+1)
+	for _, in := range floINs {
+		layers[0] = append(layers[0], in.ID)
+	}
+	for _, c := range f.orderedComponents() {
+		layers[layerOf[c.ID]] = append(layers[layerOf[c.ID]], c.ID)
+	}
+	for _, out := range floOUTs {
+		layers[last] = append(layers[last], out.ID)
+	}
+
+	// Crossing reduction by barycenter of the predecessors, keeping the
+	// current order for ties and nodes without predecessors.
+	index := make(map[uuid.UUID]float64)
+	reindex := func(layer []uuid.UUID) {
+		for i, id := range layer {
+			index[id] = float64(i)
+		}
+	}
+	for _, layer := range layers {
+		reindex(layer)
+	}
+	for sweep := 0; sweep < opts.Sweeps; sweep++ {
+		for _, layer := range layers[1:] {
+			barycenter := make(map[uuid.UUID]float64, len(layer))
+			for _, id := range layer {
+				barycenter[id] = index[id]
+				if len(preds[id]) == 0 {
+					continue
+				}
+				var sum float64
+				for _, pred := range preds[id] {
+					sum += index[pred]
+				}
+				barycenter[id] = sum / float64(len(preds[id]))
+			}
+			slices.SortStableFunc(layer, func(a, b uuid.UUID) int {
+				return cmp.Compare(barycenter[a], barycenter[b])
+			})
+			reindex(layer)
+		}
+	}
+
+	positions := make(map[uuid.UUID]Position)
+	for l, layer := range layers {
+		for i, id := range layer {
+			positions[id] = Position{
+				X: float64(l) * opts.LayerSpacing,
+				Y: (float64(i) - float64(len(layer)-1)/2) * opts.NodeSpacing,
+			}
+		}
+	}
+
+	for _, io := range f.IOs {
+		io.Position = positions[io.ID]
+	}
+	for _, c := range f.Components {
+		c.Position = positions[c.ID]
+	}
+}
@@ -0,0 +1,56 @@
+package flo_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayout(t *testing.T) {
+	f := newTestFlo(t)
+	fingerprint := f.Fingerprint()
+
+	f.Layout(flo.LayoutOptions{})
+
+	components := make(map[string]*flo.Component)
+	for _, c := range f.Components {
+		components[c.Name] = c
+	}
+
+	// Params first, then components after their predecessors, results last.
+	require.Equal(t, 0.0, f.IOs[0].Position.X)
+	require.Equal(t, 200.0, components["CompA"].Position.X)
+	require.Equal(t, 200.0, components["CompD"].Position.X)
+	require.Equal(t, 400.0, components["CompB"].Position.X)
+	require.Equal(t, 600.0, components["CompC"].Position.X)
+	require.Equal(t, 800.0, f.IOs[3].Position.X)
+
+	// Layers are centered.
+	require.Equal(t, -100.0, f.IOs[0].Position.Y)
+	require.Equal(t, 0.0, f.IOs[1].Position.Y)
+	require.Equal(t, 100.0, f.IOs[2].Position.Y)
+
+	t.Run("Options", func(t *testing.T) {
+		f.Layout(flo.LayoutOptions{LayerSpacing: 10, NodeSpacing: 5})
+		require.Equal(t, 30.0, components["CompC"].Position.X)
+		require.Equal(t, -5.0, f.IOs[0].Position.Y)
+	})
+
+	t.Run("Metadata only", func(t *testing.T) {
+		require.Equal(t, fingerprint, f.Fingerprint())
+		require.True(t, flo.Equal(f, newTestFlo(t)))
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(buf, nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.IOs[0].Position, decoded.IOs[0].Position)
+		for id, c := range f.Components {
+			require.Equal(t, c.Position, decoded.Components[id].Position)
+		}
+	})
+}
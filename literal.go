@@ -0,0 +1,216 @@
+package flo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// SetLiteral feeds the unconnected in io ioID of the component componentID
+// with a constant value. A nil value removes the literal.
+func (f *Flo) SetLiteral(componentID, ioID uuid.UUID, value any) error {
+	if componentID == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+	if ioID == uuid.Nil {
+		return errors.New("invalid io id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, found := f.Components[componentID]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", componentID)
+	}
+	in, found := c.IOs.GetByID(ioID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on component id %q", ioID, componentID)
+	}
+	if in.Type != ComponentIOTypeIN {
+		return fmt.Errorf("component io id %q is not of type in", ioID)
+	}
+	if len(in.Connections) > 0 {
+		return fmt.Errorf("component io id %q already has a connection", ioID)
+	}
+
+	f.markDirty(componentID)
+
+	if value == nil {
+		in.Literal = reflect.Value{}
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(in.RType) {
+		return fmt.Errorf("literal %s cannot be assigned to component io id %q of type %s", v.Type(), ioID, in.RType)
+	}
+	if _, err := literalCode(v); err != nil {
+		return fmt.Errorf("invalid literal for component io id %q: %v", ioID, err)
+	}
+	in.Literal = v
+
+	return nil
+}
+
+// literalCode writes v as a Go literal. Only values made of basic types,
+// slices, maps and structs with exported fields are supported.
+func literalCode(v reflect.Value) (*jen.Statement, error) {
+	t := v.Type()
+	if t == secretRType {
+		// Secrets would end up in the generated code.
+		return nil, errors.New("secrets cannot be literals")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		if t.PkgPath() == "" {
+			return jen.Lit(v.Interface()), nil
+		}
+		// Named basic types, e.g. time.Duration, are converted from an
+		// untyped constant.
+		return typeCode(t).Call(untypedLit(v)), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return jen.Nil(), nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && t.Elem().PkgPath() == "" {
+			return typeCode(t).Call(jen.Lit(string(v.Bytes()))), nil
+		}
+		items := make([]jen.Code, 0, v.Len())
+		for i := range v.Len() {
+			item, err := literalCode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return typeCode(t).Values(items...), nil
+	case reflect.Map:
+		if v.IsNil() {
+			return jen.Nil(), nil
+		}
+		keys := v.MapKeys()
+		// Keeps the output stable.
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		items := make(jen.Dict, len(keys))
+		for _, k := range keys {
+			key, err := literalCode(k)
+			if err != nil {
+				return nil, err
+			}
+			value, err := literalCode(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			items[key] = value
+		}
+		return typeCode(t).Values(items), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return jen.Nil(), nil
+		}
+		return literalCode(v.Elem())
+	case reflect.Struct:
+		// Zero fields are left out.
+		fields := make(jen.Dict)
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if v.Field(i).IsZero() {
+				continue
+			}
+			if !field.IsExported() {
+				return nil, fmt.Errorf("unexported field %s of %s cannot be set", field.Name, t)
+			}
+			value, err := literalCode(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			fields[jen.Id(field.Name)] = value
+		}
+		return typeCode(t).Values(fields), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return jen.Nil(), nil
+		}
+		if t.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unsupported literal of type %s", t)
+		}
+		value, err := literalCode(v.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op("&").Add(value), nil
+	default:
+		return nil, fmt.Errorf("unsupported literal of type %s", t)
+	}
+}
+
+// untypedLit writes the basic value v as an untyped constant.
+func untypedLit(v reflect.Value) *jen.Statement {
+	switch v.Kind() {
+	case reflect.Bool:
+		return jen.Lit(v.Bool())
+	case reflect.String:
+		return jen.Lit(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return jen.Lit(int(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() <= math.MaxInt {
+			return jen.Lit(int(v.Uint()))
+		}
+		return jen.Lit(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return jen.Lit(v.Float())
+	default:
+		return jen.Lit(v.Complex())
+	}
+}
+
+// literalKey describes the literal of in, if any, for comparisons.
+func literalKey(in *ComponentIO) string {
+	return valueKey(in.Literal)
+}
+
+// valueKey describes v, if valid, for comparisons.
+func valueKey(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("%s(%#v)", v.Type(), v.Interface())
+}
+
+// encodeLiteral serializes the literal of in, if any.
+func encodeLiteral(in *ComponentIO) ([]byte, error) {
+	if !in.Literal.IsValid() {
+		return nil, nil
+	}
+
+	return json.Marshal(in.Literal.Interface())
+}
+
+// decodeLiteral deserializes a literal of type t.
+func decodeLiteral(data []byte, t reflect.Type) (reflect.Value, error) {
+	if len(data) == 0 {
+		return reflect.Value{}, nil
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return v.Elem(), nil
+}
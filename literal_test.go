@@ -0,0 +1,76 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func literalsFn(time.Duration, []string, map[string]int, []byte, float64, bool) {}
+
+func TestLiterals(t *testing.T) {
+	f, err := flo.NewFlo("TestLiterals", "Test Literals", "Test Literals Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	c, err := flo.NewComponent("Literals", "githab.com/testuf/tera", "Literals", "Literals Description", literalsFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(c))
+
+	for i, v := range []any{
+		2 * time.Second,
+		[]string{"a", "b"},
+		map[string]int{"b": 2, "a": 1},
+		[]byte("raw"),
+		1.5,
+		true,
+	} {
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[i].ID, v))
+	}
+
+	want := `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	tera "githab.com/testuf/tera"
+	"time"
+)
+
+func TestLiterals() {
+	// Literals Description
+	tera.Literals(time.Duration(2000000000), []string{"a", "b"}, map[string]int{
+		"a": 1,
+		"b": 2,
+	}, []byte("raw"), 1.5, true)
+
+	return
+}
+`
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Equal(t, want, out.String())
+
+	t.Run("Snapshot", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+
+		decoded, err := flo.DecodeBinary(buf, nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), out))
+		require.Equal(t, want, out.String())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[5].ID, nil))
+		require.False(t, c.IOs[5].Literal.IsValid())
+	})
+}
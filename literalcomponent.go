@@ -0,0 +1,88 @@
+package flo
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// NewLiteralComponent creates a component whose single out io carries value,
+// saving a wrapper function just to feed a constant into other components.
+// It is rendered as a constant when value is of a basic type, or as a
+// variable otherwise:
+//
+//	const env = "prod"
+//	cfg := config.Config{Retries: 3}
+func NewLiteralComponent(value any) (*Component, error) {
+	if value == nil {
+		return nil, errors.New("missing value")
+	}
+
+	v := reflect.ValueOf(value)
+	if err := checkWritable(v.Type()); err != nil {
+		return nil, fmt.Errorf("literal cannot be rendered: %v", err)
+	}
+	if _, err := literalCode(v); err != nil {
+		return nil, fmt.Errorf("invalid literal: %v", err)
+	}
+
+	c := &Component{
+		ID:          uuid.New(),
+		Name:        "Literal",
+		Label:       "Literal",
+		Description: fmt.Sprintf("Literal of type %s.", v.Type()),
+		Kind:        ComponentKindLiteral,
+	}
+	out, err := NewComponentIO(
+		fmt.Sprintf("lit%x", sha1.Sum([]byte(valueKey(v)))),
+		ComponentIOTypeOUT,
+		v.Type(),
+		c.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error for value: %w", err)
+	}
+	out.Literal = v
+	c.IOs = IOs{out}
+
+	return c, nil
+}
+
+// literalComponentCode declares the value of the literal component c, if
+// used at all.
+func (f *Flo) literalComponentCode(c *Component, outs IOs) (jen.Code, error) {
+	if len(outs) != 1 || !outs[0].Literal.IsValid() {
+		return nil, fmt.Errorf("literal component id %q has no value", c.ID)
+	}
+	out := outs[0]
+	if !f.usesValue(out) {
+		return nil, nil
+	}
+
+	lit, err := literalCode(out.Literal)
+	if err != nil {
+		return nil, fmt.Errorf("literal component id %q: %v", c.ID, err)
+	}
+	if isConstKind(out.RType.Kind()) {
+		return jen.Const().Id(out.Name).Op("=").Add(lit), nil
+	}
+
+	return jen.Id(out.Name).Op(":=").Add(lit), nil
+}
+
+// isConstKind reports whether values of kind k can be constants.
+func isConstKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type LiteralConfig struct {
+	Retries int
+	Backoff time.Duration
+	Tags    []string
+	secret  string
+}
+
+func TestNewLiteralComponent(t *testing.T) {
+	_, err := flo.NewLiteralComponent(nil)
+	require.ErrorContains(t, err, "missing value")
+
+	_, err = flo.NewLiteralComponent(LiteralConfig{secret: "x"})
+	require.ErrorContains(t, err, "unexported field secret")
+
+	_, err = flo.NewLiteralComponent(func() {})
+	require.ErrorContains(t, err, "unsupported literal")
+
+	f, err := flo.NewFlo("Literals", "Literals", "Literals Description", "flo", "Literals Package")
+	require.NoError(t, err)
+
+	env, err := flo.NewLiteralComponent("prod")
+	require.NoError(t, err)
+	require.Equal(t, flo.ComponentKindLiteral, env.Kind)
+	require.NoError(t, f.AddComponent(env))
+
+	cfg, err := flo.NewLiteralComponent(&LiteralConfig{Retries: 3, Backoff: time.Second, Tags: []string{"a"}})
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(cfg))
+
+	unused, err := flo.NewLiteralComponent(42)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(unused))
+
+	toUpper, err := flo.NewComponent("ToUpper", "strings", "To Upper", "Upper cases the environment.", strings.ToUpper)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(toUpper))
+
+	rEnv, err := flo.NewComponentIO("env", flo.ComponentIOTypeOUT, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rEnv))
+
+	rCfg, err := flo.NewComponentIO("cfg", flo.ComponentIOTypeOUT, reflect.TypeFor[*LiteralConfig](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rCfg))
+
+	require.NoError(t, f.ConnectComponent(env.ID, env.IOs[0].ID, toUpper.ID, toUpper.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(toUpper.ID, toUpper.IOs[1].ID, f.ID, rEnv.ID))
+	require.NoError(t, f.ConnectComponent(cfg.ID, cfg.IOs[0].ID, f.ID, rCfg.ID))
+	require.Empty(t, f.Validate(context.Background()))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Literals Package
+package flo
+
+import (
+	flotest "github.com/mgjules/flo_test"
+	"strings"
+	"time"
+)
+
+func Literals() (string, *flotest.LiteralConfig) {
+	// Literal of type string.
+	const lit5A5187E1D5Be979Ad52Ec56Bdc25398136F9E58E = "prod"
+
+	// Literal of type *flo_test.LiteralConfig.
+	lit581B98F62E1C3Defdc747F61Df50215E61E00064 := &flotest.LiteralConfig{
+		Backoff: time.Duration(1000000000),
+		Retries: 3,
+		Tags:    []string{"a"},
+	}
+
+	// Upper cases the environment.
+	io78A9413719Dbd81Eeeb3C9D820Ac7Ed3744C85Ba := strings.ToUpper(lit5A5187E1D5Be979Ad52Ec56Bdc25398136F9E58E)
+
+	return io78A9413719Dbd81Eeeb3C9D820Ac7Ed3744C85Ba, lit581B98F62E1C3Defdc747F61Df50215E61E00064
+}
+`, out.String())
+	t.Run("Encoding", func(t *testing.T) {
+		types := flo.NewTypeRegistry()
+		require.NoError(t, types.Register(flo.TypeName(reflect.TypeFor[LiteralConfig]()), reflect.TypeFor[LiteralConfig]()))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeJSON(buf, types))
+
+		decoded, err := flo.DecodeJSON(buf, types, func(string, string) (any, error) {
+			return strings.ToUpper, nil
+		})
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+
+		got := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), got))
+		require.Equal(t, out.String(), got.String())
+	})
+}
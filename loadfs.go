@@ -0,0 +1,123 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extensions of the files LoadFS picks up.
+const (
+	SnapshotExt = ".flo"     // Written by Flo.EncodeBinary.
+	BundleExt   = ".flo.zip" // Written by Flo.ExportBundle.
+)
+
+// FloRegistry holds loaded flos by name.
+type FloRegistry struct {
+	mu   sync.RWMutex
+	flos map[string]*Flo
+}
+
+func NewFloRegistry() *FloRegistry {
+	return &FloRegistry{
+		flos: make(map[string]*Flo),
+	}
+}
+
+// Register makes f available under its name.
+func (r *FloRegistry) Register(f *Flo) error {
+	if f == nil {
+		return errors.New("missing flo")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.flos[f.Name]; found {
+		return fmt.Errorf("flo %q already registered", f.Name)
+	}
+	r.flos[f.Name] = f
+
+	return nil
+}
+
+// Get returns the flo registered as name.
+func (r *FloRegistry) Get(name string) (*Flo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, found := r.flos[name]
+	return f, found
+}
+
+// Names returns the sorted names of the registered flos.
+func (r *FloRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.flos))
+	for name := range r.flos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadFS loads every snapshot and bundle found in fsys, typically an
+// embed.FS, binding their components to the functions of components.
+//
+//	//go:embed flos
+//	var flos embed.FS
+//
+//	registry, err := flo.LoadFS(flos, components)
+func LoadFS(fsys fs.FS, components *ComponentRegistry) (*FloRegistry, error) {
+	if components == nil {
+		return nil, errors.New("missing component registry")
+	}
+
+	r := NewFloRegistry()
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		var load func(file fs.File) (*Flo, error)
+		switch {
+		case strings.HasSuffix(path, BundleExt):
+			load = func(file fs.File) (*Flo, error) {
+				return ImportBundle(file, nil, components.Resolve)
+			}
+		case strings.HasSuffix(path, SnapshotExt):
+			load = func(file fs.File) (*Flo, error) {
+				return DecodeBinary(file, nil, components.Resolve)
+			}
+		default:
+			return nil
+		}
+
+		file, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		f, err := load(file)
+		if err != nil {
+			return fmt.Errorf("cannot load %s: %v", path, err)
+		}
+		if err := r.Register(f); err != nil {
+			return fmt.Errorf("cannot load %s: %v", path, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,63 @@
+package flo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFS(t *testing.T) {
+	components := flo.NewComponentRegistry()
+	for key, fn := range testFuncs {
+		dot := strings.LastIndex(key, ".")
+		require.NoError(t, components.Register(key[:dot], key[dot+1:], fn))
+	}
+
+	f := newTestFlo(t)
+
+	snapshot := &bytes.Buffer{}
+	require.NoError(t, f.EncodeBinary(snapshot, nil))
+
+	other, err := flo.NewFlo("TestOther", "Test Other", "Test Other Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	bundle := &bytes.Buffer{}
+	require.NoError(t, other.ExportBundle(bundle, nil))
+
+	fsys := fstest.MapFS{
+		"flos/sync.flo":             {Data: snapshot.Bytes()},
+		"flos/nested/other.flo.zip": {Data: bundle.Bytes()},
+		"flos/README.md":            {Data: []byte("ignored")},
+	}
+
+	registry, err := flo.LoadFS(fsys, components)
+	require.NoError(t, err)
+	require.Equal(t, []string{"TestOther", "TestSync"}, registry.Names())
+
+	loaded, found := registry.Get("TestSync")
+	require.True(t, found)
+	require.Equal(t, f.Fingerprint(), loaded.Fingerprint())
+	for _, c := range loaded.Components {
+		require.True(t, c.IsBound())
+	}
+
+	_, found = registry.Get("Nope")
+	require.False(t, found)
+
+	t.Run("Duplicate names", func(t *testing.T) {
+		fsys["flos/copy.flo"] = &fstest.MapFile{Data: snapshot.Bytes()}
+		defer delete(fsys, "flos/copy.flo")
+
+		_, err := flo.LoadFS(fsys, components)
+		require.ErrorContains(t, err, `flo "TestSync" already registered`)
+	})
+
+	t.Run("Unknown components", func(t *testing.T) {
+		_, err := flo.LoadFS(fsys, flo.NewComponentRegistry())
+		require.ErrorContains(t, err, "cannot load flos/")
+	})
+}
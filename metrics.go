@@ -0,0 +1,132 @@
+package flo
+
+import (
+	"expvar"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// metricsWindow is the number of the last calls of a component its
+// durations are computed over.
+const metricsWindow = 1024
+
+// ComponentMetrics are the runtime stats of a component called by Execute.
+type ComponentMetrics struct {
+	Count  int           // Calls since the first execution.
+	Errors int           // Calls that failed: returned an error or panicked.
+	P50    time.Duration // Median duration over the last calls.
+	P95    time.Duration // 95th percentile duration over the last calls.
+}
+
+// ErrorRate is the share of the calls that failed.
+func (m ComponentMetrics) ErrorRate() float64 {
+	if m.Count == 0 {
+		return 0
+	}
+
+	return float64(m.Errors) / float64(m.Count)
+}
+
+// Metrics returns the runtime stats of the components called by Execute,
+// by component id. Components of sub-flos are accounted to the component
+// running the sub-flo.
+func (f *Flo) Metrics() map[uuid.UUID]ComponentMetrics {
+	f.mu.Lock()
+	m := f.metrics
+	f.mu.Unlock()
+
+	return m.snapshot()
+}
+
+// MetricsVar exposes Metrics as an expvar.Var, e.g.:
+//
+//	expvar.Publish("flo."+f.Name, f.MetricsVar())
+func (f *Flo) MetricsVar() expvar.Var {
+	return expvar.Func(func() any {
+		return f.Metrics()
+	})
+}
+
+// executeMetrics aggregates the stats of the components of a flo, across
+// executions.
+type executeMetrics struct {
+	mu         sync.Mutex
+	components map[uuid.UUID]*componentStats
+}
+
+type componentStats struct {
+	count     int
+	errors    int
+	durations []time.Duration // Ring of the last metricsWindow durations.
+	next      int
+}
+
+// metricsForExecute returns the metrics Execute records into, created on
+// first use.
+func (f *Flo) metricsForExecute() *executeMetrics {
+	if f.metrics == nil {
+		f.metrics = &executeMetrics{components: make(map[uuid.UUID]*componentStats)}
+	}
+
+	return f.metrics
+}
+
+func (m *executeMetrics) record(id uuid.UUID, d time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, found := m.components[id]
+	if !found {
+		s = &componentStats{}
+		m.components[id] = s
+	}
+	s.count++
+	if failed {
+		s.errors++
+	}
+	if len(s.durations) < metricsWindow {
+		s.durations = append(s.durations, d)
+		return
+	}
+	s.durations[s.next] = d
+	s.next = (s.next + 1) % metricsWindow
+}
+
+func (m *executeMetrics) snapshot() map[uuid.UUID]ComponentMetrics {
+	res := make(map[uuid.UUID]ComponentMetrics)
+	if m == nil {
+		return res
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, s := range m.components {
+		durations := slices.Clone(s.durations)
+		slices.Sort(durations)
+		res[id] = ComponentMetrics{
+			Count:  s.count,
+			Errors: s.errors,
+			P50:    percentile(durations, 50),
+			P95:    percentile(durations, 95),
+		}
+	}
+
+	return res
+}
+
+// percentile returns the p-th percentile of sorted, by nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[(len(sorted)*p+99)/100-1]
+}
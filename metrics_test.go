@@ -0,0 +1,33 @@
+package flo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	f := newTestFlo(t)
+	require.Empty(t, f.Metrics())
+
+	for _, in := range []int{5, 5, 5, -1} {
+		_, _ = f.Execute(context.Background(), map[string]any{"in": in, "unused": 0})
+	}
+
+	metrics := f.Metrics()
+	compB := componentNamed(f, "CompB")
+	require.Equal(t, 4, metrics[compB.ID].Count)
+	require.Equal(t, 1, metrics[compB.ID].Errors)
+	require.InDelta(t, 0.25, metrics[compB.ID].ErrorRate(), 0.001)
+	require.GreaterOrEqual(t, metrics[compB.ID].P95, metrics[compB.ID].P50)
+
+	// Not called once CompB fails.
+	compC := componentNamed(f, "CompC")
+	require.Equal(t, 3, metrics[compC.ID].Count)
+	require.Zero(t, metrics[compC.ID].ErrorRate())
+
+	require.Contains(t, f.MetricsVar().String(), compB.ID.String())
+	require.Equal(t, flo.ComponentMetrics{}.ErrorRate(), 0.0)
+}
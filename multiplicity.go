@@ -0,0 +1,134 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// SetMulti makes the in io of a component, which must be a slice, accept
+// any number of connections, each one feeding an element of the slice.
+func (f *Flo) SetMulti(componentID, ioID uuid.UUID, multi bool) error {
+	if componentID == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+	if ioID == uuid.Nil {
+		return errors.New("invalid io id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	c, found := f.Components[componentID]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", componentID)
+	}
+	in, found := c.IOs.GetByID(ioID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on component id %q", ioID, componentID)
+	}
+	if in.Type != ComponentIOTypeIN {
+		return fmt.Errorf("component io id %q is not of type in", ioID)
+	}
+	if in.RType.Kind() != reflect.Slice {
+		return fmt.Errorf("component io id %q of type %s is not a slice", ioID, in.RType)
+	}
+	if len(in.Connections) > 0 {
+		return fmt.Errorf("component io id %q already has a connection", ioID)
+	}
+
+	f.markDirty(componentID)
+	in.Multi = multi
+
+	return nil
+}
+
+// SetMaxConnections limits the number of connections of an out io of a
+// component, or of an in io of the flo. A max of 0 lifts the limit.
+func (f *Flo) SetMaxConnections(componentID, ioID uuid.UUID, max int) error {
+	if componentID == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+	if ioID == uuid.Nil {
+		return errors.New("invalid io id")
+	}
+	if max < 0 {
+		return errors.New("max connections cannot be negative")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	io, found := f.componentIO(componentID, ioID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on component id %q", ioID, componentID)
+	}
+	if typ := io.Type; (componentID == f.ID) != (typ == ComponentIOTypeIN) {
+		return fmt.Errorf("component io id %q has no outgoing connections", ioID)
+	}
+	if max > 0 && len(io.Connections) > max {
+		return fmt.Errorf("component io id %q already has %d connections", ioID, len(io.Connections))
+	}
+
+	io.MaxConnections = max
+
+	return nil
+}
+
+// acceptedType is the type the values connected to the in io must be
+// assignable to.
+func (io *ComponentIO) acceptedType() reflect.Type {
+	if io.Multi {
+		return io.RType.Elem()
+	}
+
+	return io.RType
+}
+
+// multiValue gathers the values connected to the multi in io into a slice.
+func (f *Flo) multiValue(in *ComponentIO) jen.Code {
+	return typeCode(in.RType).ValuesFunc(func(g *jen.Group) {
+		for _, conn := range in.Connections {
+			out, found := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
+			if !found {
+				continue
+			}
+			if conn.Transform != nil {
+				g.Add(conn.Transform.render(out.Name))
+				continue
+			}
+			g.Id(out.Name)
+		}
+	})
+}
+
+// validateMultiplicity checks the limits set on the ios, e.g. for flos
+// decoded or mutated directly.
+func (f *Flo) validateMultiplicity() []ValidationError {
+	var errs []ValidationError
+
+	for _, c := range f.orderedComponents() {
+		for _, io := range c.IOs {
+			if io.Multi && (io.Type != ComponentIOTypeIN || io.RType.Kind() != reflect.Slice) {
+				errs = append(errs, ValidationError{
+					ComponentID: c.ID,
+					IOID:        io.ID,
+					Message:     fmt.Sprintf("only in ios of slice type can be multi, not %s", io.RType),
+				})
+			}
+			if io.MaxConnections > 0 && len(io.Connections) > io.MaxConnections {
+				errs = append(errs, ValidationError{
+					ComponentID: c.ID,
+					IOID:        io.ID,
+					Message:     fmt.Sprintf("has %d connections but at most %d are allowed", len(io.Connections), io.MaxConnections),
+				})
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,105 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func sumAllFn(vs []int) int {
+	var sum int
+	for _, v := range vs {
+		sum += v
+	}
+
+	return sum
+}
+
+func TestMultiplicity(t *testing.T) {
+	f, err := flo.NewFlo("TestMulti", "Test Multi", "Test Multi Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pA, err := flo.NewComponentIO("a", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pA))
+
+	pB, err := flo.NewComponentIO("b", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pB))
+
+	rSum, err := flo.NewComponentIO("sum", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rSum))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+
+	sum, err := flo.NewComponent("Sum", "githab.com/testuf/tera", "Sum", "Sum Description", sumAllFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(sum))
+
+	t.Run("Multi needs a slice", func(t *testing.T) {
+		err := f.SetMulti(length.ID, length.IOs[0].ID, true)
+		require.ErrorContains(t, err, "is not a slice")
+	})
+
+	t.Run("Single by default", func(t *testing.T) {
+		err := f.ConnectComponent(f.ID, pB.ID, sum.ID, sum.IOs[0].ID)
+		require.ErrorContains(t, err, "cannot be assigned")
+	})
+
+	require.NoError(t, f.SetMulti(sum.ID, sum.IOs[0].ID, true))
+	require.NoError(t, f.ConnectComponent(f.ID, pA.ID, length.ID, length.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, sum.ID, sum.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pB.ID, sum.ID, sum.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(sum.ID, sum.IOs[1].ID, f.ID, rSum.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "tera.Sum([]int{"+length.IOs[1].Name+", b})\n")
+	})
+
+	t.Run("Max connections", func(t *testing.T) {
+		err := f.SetMaxConnections(f.ID, pB.ID, 0)
+		require.NoError(t, err)
+
+		err = f.SetMaxConnections(sum.ID, sum.IOs[0].ID, 1)
+		require.ErrorContains(t, err, "has no outgoing connections")
+
+		require.NoError(t, f.SetMaxConnections(length.ID, length.IOs[1].ID, 1))
+		err = f.ConnectComponent(length.ID, length.IOs[1].ID, sum.ID, sum.IOs[0].ID)
+		require.ErrorContains(t, err, "cannot have more than 1 connections")
+
+		// Exceeding the limit is caught by Validate when bypassing the checks.
+		rLen, err := flo.NewComponentIO("len", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rLen))
+		length.IOs[1].MaxConnections = 0
+		require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rLen.ID))
+		length.IOs[1].MaxConnections = 1
+		errs := f.Validate(context.Background())
+		require.Len(t, errs, 1)
+		require.ErrorContains(t, errs[0], "has 2 connections but at most 1 are allowed")
+
+		require.NoError(t, f.DeleteConnection(rLen.Connections[0].ID))
+		require.NoError(t, f.DeleteIO(rLen.ID))
+		require.Empty(t, f.Validate(context.Background()))
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(buf, nil, func(_, name string) (any, error) {
+			return map[string]any{"Len": lenFn, "Sum": sumAllFn}[name], nil
+		})
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+}
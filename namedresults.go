@@ -0,0 +1,35 @@
+package flo
+
+// WithNamedResults names the results of the generated function after the
+// flo out ios, e.g. (length int, err error), documenting what each of them
+// is.
+//
+// Names clashing with a param, another result, a keyword or the err and
+// errs variables of the function body are suffixed with a number.
+func WithNamedResults() RenderOption {
+	return func(o *renderOptions) {
+		o.namedResults = true
+	}
+}
+
+// resultNames names the results outs of a flo taking the params ins.
+func resultNames(ins, outs IOs) []string {
+	taken := map[string]struct{}{"err": {}, errsVar: {}}
+	for _, in := range ins {
+		if len(in.Connections) > 0 {
+			taken[in.Name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(outs))
+	for _, out := range outs {
+		base := out.ResultName
+		if base == "" || base == "_" {
+			base = "result"
+		}
+
+		names = append(names, uniqueName(base, taken))
+	}
+
+	return names
+}
@@ -0,0 +1,55 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWithNamedResults(t *testing.T) {
+	f := newTestFlo(t)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithNamedResults()))
+	// err is the variable of the component calls.
+	require.Contains(t, out.String(), `func TestSync(ctx context.Context, in int, _ int) (result int, err2 error) {`)
+	require.Contains(t, out.String(), `	if err != nil {
+		return 0, err
+	}`)
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+
+		decodedOut := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), decodedOut, flo.WithNamedResults()))
+		require.Equal(t, out.String(), decodedOut.String())
+	})
+
+	t.Run("Clashing names", func(t *testing.T) {
+		f, err := flo.NewFlo("TestNamed", "Test Named", "Test Named Description", "flo", "Test Package")
+		require.NoError(t, err)
+		for _, io := range []*flo.ComponentIO{
+			flo.In[string]("value"),
+			flo.Out[int]("value"),
+			flo.Out[int]("type"),
+			flo.Out[int](""),
+		} {
+			require.NoError(t, f.AddIO(io))
+		}
+		length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(length))
+		require.NoError(t, f.ConnectComponent(f.ID, f.IOs[0].ID, length.ID, length.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, f.IOs[1].ID))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithNamedResults()))
+		require.Contains(t, out.String(), `func TestNamed(value string) (value2 int, type2 int, result int) {`)
+	})
+}
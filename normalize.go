@@ -0,0 +1,98 @@
+package flo
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Normalize puts the flo into a canonical state so that its serialized form
+// is stable and diffs are minimal:
+//   - connections are sorted by the position of the components and ios they
+//     link;
+//   - connections referring to missing components or ios are dropped;
+//   - duplicated sequence connections are collapsed;
+//   - internal indexes are rebuilt from the graph.
+//
+// The order of the ios and components is kept as it drives the generated
+// code.
+func (f *Flo) Normalize() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	components := f.orderedComponents()
+	f.componentOrder = make([]uuid.UUID, 0, len(components))
+	for _, c := range components {
+		f.componentOrder = append(f.componentOrder, c.ID)
+	}
+
+	position := make(map[uuid.UUID]int, len(components)+1)
+	position[f.ID] = -1
+	for i, c := range components {
+		position[c.ID] = i
+	}
+
+	// exists reports whether both ends of conn are still around.
+	exists := func(conn *ComponentConnection) bool {
+		if _, found := position[conn.OutComponentID]; !found {
+			return false
+		}
+		if _, found := position[conn.InComponentID]; !found {
+			return false
+		}
+		if conn.Kind == ComponentConnectionKindSequence {
+			return true
+		}
+		return f.ioIndex(conn.OutComponentID, conn.OutComponentIOID) >= 0 &&
+			f.ioIndex(conn.InComponentID, conn.InComponentIOID) >= 0
+	}
+
+	compare := func(a, b *ComponentConnection) int {
+		return cmp.Or(
+			cmp.Compare(position[a.OutComponentID], position[b.OutComponentID]),
+			cmp.Compare(f.ioIndex(a.OutComponentID, a.OutComponentIOID), f.ioIndex(b.OutComponentID, b.OutComponentIOID)),
+			cmp.Compare(position[a.InComponentID], position[b.InComponentID]),
+			cmp.Compare(f.ioIndex(a.InComponentID, a.InComponentIOID), f.ioIndex(b.InComponentID, b.InComponentIOID)),
+		)
+	}
+
+	f.connectionIndex = make(map[uuid.UUID]*ComponentConnection, len(f.connectionIndex))
+	normalizeIOs := func(ios IOs, parentID uuid.UUID) {
+		for _, io := range ios {
+			io.ParentID = parentID
+			io.Connections = slices.DeleteFunc(io.Connections, func(conn *ComponentConnection) bool {
+				return !exists(conn)
+			})
+			// The order of the connections of multi ios is meaningful.
+			if !io.Multi {
+				slices.SortStableFunc(io.Connections, compare)
+			}
+			for _, conn := range io.Connections {
+				f.connectionIndex[conn.ID] = conn
+			}
+		}
+	}
+
+	normalizeIOs(f.IOs, f.ID)
+	for _, c := range components {
+		normalizeIOs(c.IOs, c.ID)
+	}
+
+	seen := make(map[[2]uuid.UUID]struct{}, len(f.Sequences))
+	f.Sequences = slices.DeleteFunc(f.Sequences, func(conn *ComponentConnection) bool {
+		key := [2]uuid.UUID{conn.OutComponentID, conn.InComponentID}
+		if _, found := seen[key]; found || !exists(conn) {
+			return true
+		}
+		seen[key] = struct{}{}
+		return false
+	})
+	slices.SortStableFunc(f.Sequences, compare)
+	for _, conn := range f.Sequences {
+		f.connectionIndex[conn.ID] = conn
+	}
+
+	f.markAllDirty()
+}
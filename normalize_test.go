@@ -0,0 +1,74 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	f, err := flo.NewFlo("TestNormalize", "Test Normalize", "Test Normalize Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	var lens []*flo.Component
+	for range 3 {
+		c, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		lens = append(lens, c)
+	}
+
+	// Fan out in reverse order.
+	for i := len(lens) - 1; i >= 0; i-- {
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, lens[i].ID, lens[i].IOs[0].ID))
+	}
+	require.NoError(t, f.ConnectSequence(lens[0].ID, lens[1].ID))
+
+	before := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), before))
+
+	// Corrupt the flo with a dangling connection and a duplicated sequence.
+	ghost := &flo.ComponentConnection{
+		ID:               uuid.New(),
+		OutComponentID:   f.ID,
+		OutComponentIOID: pIn.ID,
+		InComponentID:    uuid.New(),
+		InComponentIOID:  uuid.New(),
+	}
+	pIn.Connections = append(pIn.Connections, ghost)
+	f.Sequences = append(f.Sequences, f.Sequences[0])
+	require.Error(t, f.Render(context.Background(), &bytes.Buffer{}))
+
+	f.Normalize()
+
+	require.Len(t, pIn.Connections, 3)
+	for i, conn := range pIn.Connections {
+		require.Equal(t, lens[i].ID, conn.InComponentID)
+	}
+	require.Len(t, f.Sequences, 1)
+
+	after := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), after))
+	require.Equal(t, before.String(), after.String())
+
+	t.Run("Stable", func(t *testing.T) {
+		snapshot := func() []byte {
+			out := &bytes.Buffer{}
+			require.NoError(t, f.EncodeBinary(out, nil))
+			return out.Bytes()
+		}
+
+		first := snapshot()
+		f.Normalize()
+		require.Equal(t, first, snapshot())
+	})
+}
@@ -0,0 +1,278 @@
+package flo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// MergeDuplicates merges the components calling the same function with the
+// same inputs into a single call whose results are shared. It returns the
+// number of components removed.
+//
+// It is opt-in as it assumes the merged functions are pure: calling them
+// once or more must not make a difference. Components known to have side
+// effects are never merged.
+func (f *Flo) MergeDuplicates() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	merges, err := f.mergeDuplicates()
+
+	return len(merges), err
+}
+
+// EliminateCommonSubexpressions merges identical chains of calls anywhere in
+// the flo, i.e. the same functions fed with the same inputs or literals, so
+// that they are computed once. It merges duplicates over and over as
+// merging components can make the ones they feed identical too.
+//
+// Like MergeDuplicates, it assumes the merged functions are pure.
+func (f *Flo) EliminateCommonSubexpressions() (*MergeReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	var report MergeReport
+	for {
+		merges, err := f.mergeDuplicates()
+		report.Merges = append(report.Merges, merges...)
+		if err != nil {
+			return &report, err
+		}
+		if len(merges) == 0 {
+			return &report, nil
+		}
+		report.Rounds++
+	}
+}
+
+// Merge records a component merged into another one.
+type Merge struct {
+	Kept    uuid.UUID
+	Removed uuid.UUID
+	Call    string // e.g. "strings.TrimSpace".
+}
+
+// MergeReport tells what an optimization merged.
+type MergeReport struct {
+	Rounds int
+	Merges []Merge
+}
+
+func (r *MergeReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d merges in %d rounds", len(r.Merges), r.Rounds)
+	for _, m := range r.Merges {
+		fmt.Fprintf(&sb, "\n%s: %s merged into %s", m.Call, m.Removed, m.Kept)
+	}
+
+	return sb.String()
+}
+
+func (f *Flo) mergeDuplicates() ([]Merge, error) {
+	var merges []Merge
+	kept := make(map[string]*Component)
+	for _, c := range f.orderedComponents() {
+		key, ok := f.callKey(c)
+		if !ok {
+			continue
+		}
+
+		keep, found := kept[key]
+		if !found {
+			kept[key] = c
+			continue
+		}
+
+		ok, err := f.mergeComponent(keep, c)
+		if err != nil {
+			return merges, err
+		}
+		if ok {
+			merges = append(merges, Merge{
+				Kept:    keep.ID,
+				Removed: c.ID,
+				Call:    c.PkgPath + "." + c.Name,
+			})
+		}
+	}
+
+	return merges, nil
+}
+
+// callKey identifies the call made by c: its function and its inputs.
+// Only function components with all their inputs connected have one.
+func (f *Flo) callKey(c *Component) (string, bool) {
+	if c.Kind != ComponentKindFunc || !c.Effects.mayAssumePure() {
+		return "", false
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s.%s", c.PkgPath, c.Name)
+	for _, t := range c.TypeArgs {
+		fmt.Fprintf(&sb, "\x00%s", TypeName(t))
+	}
+	if c.ErrorPolicy != ErrorPolicyAbort {
+		fmt.Fprintf(&sb, "\x00policy %s", c.ErrorPolicy)
+	}
+	if c.Flag != nil {
+		fmt.Fprintf(&sb, "\x00flag %s %t", c.Flag.Name, c.Flag.Off)
+	}
+	if len(c.Variants) > 0 {
+		fmt.Fprintf(&sb, "\x00variants %s", strings.Join(c.Variants, ","))
+	}
+	if r := c.Receiver; r != nil {
+		fmt.Fprintf(&sb, "\x00receiver %s %s %s", r.Name, TypeName(r.Type), r.Constructor)
+	}
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	for _, out := range outs {
+		fmt.Fprintf(&sb, "\x00%s", TypeName(out.RType))
+		// Merged calls must swallow their errors the same way.
+		if conn, found := lo.Find(out.Connections, func(conn *ComponentConnection) bool {
+			return conn.Fallback.IsValid()
+		}); found {
+			fmt.Fprintf(&sb, " fallback %s", fallbackKey(conn))
+		}
+	}
+	for _, in := range ins {
+		if in.Literal.IsValid() {
+			fmt.Fprintf(&sb, "\x00%s", literalKey(in))
+			continue
+		}
+		if len(in.Connections) != 1 {
+			return "", false
+		}
+		conn := in.Connections[0]
+		fmt.Fprintf(&sb, "\x00%s %s %s", TypeName(in.RType), conn.OutComponentID, conn.OutComponentIOID)
+		if t := conn.Transform; t != nil {
+			fmt.Fprintf(&sb, " %s.%s(%s)%v", t.PkgPath, t.Name, t.Expr, t.Imports)
+		}
+	}
+
+	return sb.String(), true
+}
+
+// mergeComponent moves everything depending on dup over to keep, then
+// deletes dup. Both must share the same call key. It reports false, leaving
+// the flo untouched, when the merge would introduce a cycle or exceed the
+// fan-out of keep.
+func (f *Flo) mergeComponent(keep, dup *Component) (bool, error) {
+	if f.dependsOn(keep.ID, dup.ID) || f.dependsOn(dup.ID, keep.ID) {
+		return false, nil
+	}
+	for _, id := range f.successors(dup) {
+		if f.dependsOn(keep.ID, id) {
+			return false, nil
+		}
+	}
+
+	dupINs, dupOUTs := dup.IOs.SeparateINsOUTs()
+	_, keepOUTs := keep.IOs.SeparateINsOUTs()
+
+	// A merge failing halfway would leave the flo changed.
+	for i, out := range dupOUTs {
+		keepOUT := keepOUTs[i]
+		if max := keepOUT.MaxConnections; max > 0 && len(keepOUT.Connections)+len(out.Connections) > max {
+			return false, nil
+		}
+		for _, conn := range out.Connections {
+			if lo.SomeBy(keepOUT.Connections, func(kc *ComponentConnection) bool {
+				return kc.InComponentIOID == conn.InComponentIOID
+			}) {
+				return false, nil
+			}
+		}
+	}
+
+	for i, out := range dupOUTs {
+		for _, conn := range append([]*ComponentConnection(nil), out.Connections...) {
+			if err := f.deleteConnection(conn.ID); err != nil {
+				return false, fmt.Errorf("cannot merge component id %q: %v", dup.ID, err)
+			}
+			if err := f.connectComponent(
+				keep.ID, keepOUTs[i].ID,
+				conn.InComponentID, conn.InComponentIOID,
+				conn.Transform,
+			); err != nil {
+				return false, fmt.Errorf("cannot merge component id %q: %v", dup.ID, err)
+			}
+			moved := keepOUTs[i].Connections[len(keepOUTs[i].Connections)-1]
+			moved.Fallback, moved.Order = conn.Fallback, conn.Order
+		}
+	}
+
+	for _, in := range dupINs {
+		for _, conn := range append([]*ComponentConnection(nil), in.Connections...) {
+			if err := f.deleteConnection(conn.ID); err != nil {
+				return false, fmt.Errorf("cannot merge component id %q: %v", dup.ID, err)
+			}
+		}
+	}
+
+	for _, conn := range append([]*ComponentConnection(nil), f.Sequences...) {
+		if conn.OutComponentID != dup.ID && conn.InComponentID != dup.ID {
+			continue
+		}
+		if err := f.deleteConnection(conn.ID); err != nil {
+			return false, fmt.Errorf("cannot merge component id %q: %v", dup.ID, err)
+		}
+
+		before, after := conn.OutComponentID, conn.InComponentID
+		if before == dup.ID {
+			before = keep.ID
+		}
+		if after == dup.ID {
+			after = keep.ID
+		}
+		if before == after || f.isSequenced(before, after) {
+			continue
+		}
+		if err := f.connectSequence(before, after); err != nil {
+			return false, fmt.Errorf("cannot merge component id %q: %v", dup.ID, err)
+		}
+	}
+
+	delete(f.Components, dup.ID)
+	f.componentOrder = lo.Without(f.componentOrder, dup.ID)
+	f.markDirty(dup.ID)
+
+	return true, nil
+}
+
+// successors returns the ids of the components depending directly on c.
+func (f *Flo) successors(c *Component) []uuid.UUID {
+	var ids []uuid.UUID
+
+	_, outs := c.IOs.SeparateINsOUTs()
+	for _, out := range outs {
+		for _, conn := range out.Connections {
+			if conn.InComponentID != f.ID {
+				ids = append(ids, conn.InComponentID)
+			}
+		}
+	}
+
+	for _, conn := range f.Sequences {
+		if conn.OutComponentID == c.ID {
+			ids = append(ids, conn.InComponentID)
+		}
+	}
+
+	return ids
+}
+
+// isSequenced reports whether before is already sequenced before after.
+func (f *Flo) isSequenced(before, after uuid.UUID) bool {
+	for _, conn := range f.Sequences {
+		if conn.OutComponentID == before && conn.InComponentID == after {
+			return true
+		}
+	}
+
+	return false
+}
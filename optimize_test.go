@@ -0,0 +1,334 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func addFn(a, b int) int {
+	return a + b
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	f, err := flo.NewFlo("TestMerge", "Test Merge", "Test Merge Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	var lens []*flo.Component
+	for _, label := range []string{"Len", "Len again"} {
+		c, err := flo.NewComponent("Len", "githab.com/testuf/tera", label, label+" Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, c.ID, c.IOs[0].ID))
+		lens = append(lens, c)
+	}
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Add Description", addFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+	require.NoError(t, f.ConnectComponent(lens[0].ID, lens[0].IOs[1].ID, add.ID, add.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(lens[1].ID, lens[1].IOs[1].ID, add.ID, add.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[2].ID, f.ID, rOut.ID))
+
+	merged, err := f.MergeDuplicates()
+	require.NoError(t, err)
+	require.Equal(t, 1, merged)
+	require.Len(t, f.Components, 2)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import tera "githab.com/testuf/tera"
+
+func TestMerge(in string) int {
+	// Len Description
+	io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd := tera.Len(in)
+
+	// Add Description
+	iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3 := tera.Add(io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd, io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd)
+
+	return iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3
+}
+`, out.String())
+
+	merged, err = f.MergeDuplicates()
+	require.NoError(t, err)
+	require.Zero(t, merged)
+}
+
+func TestEliminateCommonSubexpressions(t *testing.T) {
+	f, err := flo.NewFlo("TestCSE", "Test CSE", "Test CSE Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	// Two identical chains: Len("hello") + 1.
+	var incs []*flo.Component
+	for range 2 {
+		length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(length))
+		require.NoError(t, f.SetLiteral(length.ID, length.IOs[0].ID, "hello"))
+
+		inc, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Add Description", addFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(inc))
+		require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, inc.ID, inc.IOs[0].ID))
+		require.NoError(t, f.SetLiteral(inc.ID, inc.IOs[1].ID, 1))
+		incs = append(incs, inc)
+	}
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Add Description", addFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+	require.NoError(t, f.ConnectComponent(incs[0].ID, incs[0].IOs[2].ID, add.ID, add.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(incs[1].ID, incs[1].IOs[2].ID, add.ID, add.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[2].ID, f.ID, rOut.ID))
+
+	t.Run("Invalid literals", func(t *testing.T) {
+		require.ErrorContains(t, f.SetLiteral(add.ID, add.IOs[0].ID, 1), "already has a connection")
+		require.ErrorContains(t, f.SetLiteral(incs[0].ID, incs[0].IOs[1].ID, "1"), "cannot be assigned")
+	})
+
+	report, err := f.EliminateCommonSubexpressions()
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Rounds)
+	require.Len(t, report.Merges, 2)
+	require.Equal(t, "githab.com/testuf/tera.Len", report.Merges[0].Call)
+	require.Equal(t, "githab.com/testuf/tera.Add", report.Merges[1].Call)
+	require.Len(t, f.Components, 3)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Equal(t, 1, strings.Count(out.String(), `tera.Len("hello")`))
+	require.Equal(t, 2, strings.Count(out.String(), "tera.Add("))
+	require.True(t, strings.HasPrefix(report.String(), "2 merges in 1 rounds\n"))
+}
+
+// newAtoiFlo returns a flo parsing its in twice, into its outs a and b.
+func newAtoiFlo(t *testing.T) (*flo.Flo, []*flo.Component) {
+	t.Helper()
+
+	f, err := flo.NewFlo("TestAtoi", "Test Atoi", "Test Atoi Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	var atois []*flo.Component
+	for _, name := range []string{"a", "b"} {
+		rOut, err := flo.NewComponentIO(name, flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rOut))
+
+		c, err := flo.NewComponent("Atoi", "strconv", "Atoi", "Atoi Description", strconv.Atoi)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, c.ID, c.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(c.ID, c.IOs[1].ID, f.ID, rOut.ID))
+		atois = append(atois, c)
+	}
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	return f, atois
+}
+
+func TestMergeDuplicatesKeepsBehaviour(t *testing.T) {
+	t.Run("Fallbacks", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetFallback(atois[0].IOs[1].Connections[0].ID, 7))
+		require.NoError(t, f.SetFallback(atois[1].IOs[1].Connections[0].ID, 9))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+
+		outputs, err := f.Execute(context.Background(), map[string]any{"in": "x"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"a": 7, "b": 9}, outputs)
+
+		require.NoError(t, f.SetFallback(atois[1].IOs[1].Connections[0].ID, 7))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+
+		outputs, err = f.Execute(context.Background(), map[string]any{"in": "x"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"a": 7, "b": 7}, outputs)
+	})
+
+	t.Run("Error policies", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetErrorPolicy(atois[0].ID, flo.ErrorPolicyContinue))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+
+		_, err = f.Execute(context.Background(), map[string]any{"in": "x"})
+		require.ErrorContains(t, err, "invalid syntax")
+
+		require.NoError(t, f.SetErrorPolicy(atois[1].ID, flo.ErrorPolicyContinue))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+
+		outputs, err := f.Execute(context.Background(), map[string]any{"in": "x"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"a": 0, "b": 0}, outputs)
+	})
+
+	t.Run("Max connections", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetMaxConnections(atois[0].ID, atois[0].IOs[1].ID, 1))
+		before := f.View()
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+		require.Equal(t, before, f.View())
+	})
+
+	t.Run("Multi ins", func(t *testing.T) {
+		f, err := flo.NewFlo("TestMulti", "Test Multi", "Test Multi Description", "flo", "Test Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		sum, err := flo.NewComponent("Sum", "githab.com/testuf/tera", "Sum", "Sum Description", sumAllFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(sum))
+		require.NoError(t, f.SetMulti(sum.ID, sum.IOs[0].ID, true))
+
+		for _, label := range []string{"Len", "Len again"} {
+			c, err := flo.NewComponent("Len", "githab.com/testuf/tera", label, label+" Description", lenFn)
+			require.NoError(t, err)
+			require.NoError(t, f.AddComponent(c))
+			require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, c.ID, c.IOs[0].ID))
+			require.NoError(t, f.ConnectComponent(c.ID, c.IOs[1].ID, sum.ID, sum.IOs[0].ID))
+		}
+		before := f.View()
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+		require.Equal(t, before, f.View())
+	})
+
+	t.Run("Connection order", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetConnectionOrder(atois[1].IOs[1].Connections[0].ID, 2))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+
+		var orders []int
+		for _, conn := range f.View().Connections {
+			if conn.OutComponentID == atois[0].ID {
+				orders = append(orders, conn.Order)
+			}
+		}
+		require.ElementsMatch(t, []int{0, 2}, orders)
+	})
+
+	t.Run("Flags", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetFlag(atois[1].ID, &flo.FlagGate{Name: "beta"}))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+
+		require.NoError(t, f.SetFlag(atois[0].ID, &flo.FlagGate{Name: "beta", Off: true}))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+
+		require.NoError(t, f.SetFlag(atois[0].ID, &flo.FlagGate{Name: "beta"}))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+	})
+
+	t.Run("Variants", func(t *testing.T) {
+		f, atois := newAtoiFlo(t)
+		require.NoError(t, f.SetVariants(atois[0].ID, "cloud"))
+		require.NoError(t, f.SetVariants(atois[1].ID, "onprem"))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithVariant("onprem")))
+		require.Contains(t, out.String(), "strconv.Atoi")
+
+		require.NoError(t, f.SetVariants(atois[0].ID, "onprem", "cloud"))
+		require.NoError(t, f.SetVariants(atois[1].ID, "cloud", "onprem"))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+	})
+
+	t.Run("Receivers", func(t *testing.T) {
+		f, err := flo.NewFlo("Count", "Count", "Count Description", "flo", "Count Package")
+		require.NoError(t, err)
+
+		pV, err := flo.NewComponentIO("v", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pV))
+
+		var adds []*flo.Component
+		for _, name := range []string{"a", "b"} {
+			rOut, err := flo.NewComponentIO(name, flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+			require.NoError(t, err)
+			require.NoError(t, f.AddIO(rOut))
+
+			add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Adds v.", (&Counter{}).Add)
+			require.NoError(t, err)
+			require.NoError(t, f.AddComponent(add))
+			require.NoError(t, f.ConnectComponent(f.ID, pV.ID, add.ID, add.IOs[0].ID))
+			require.NoError(t, f.ConnectComponent(add.ID, add.IOs[1].ID, f.ID, rOut.ID))
+			adds = append(adds, add)
+		}
+
+		counterType := reflect.TypeFor[*Counter]()
+		require.NoError(t, f.SetReceiver(adds[0].ID, &flo.Receiver{Name: "x", Type: counterType}))
+		require.NoError(t, f.SetReceiver(adds[1].ID, &flo.Receiver{Name: "y", Type: counterType}))
+
+		merged, err := f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Zero(t, merged)
+
+		require.NoError(t, f.SetReceiver(adds[1].ID, &flo.Receiver{Name: "x", Type: counterType}))
+		merged, err = f.MergeDuplicates()
+		require.NoError(t, err)
+		require.Equal(t, 1, merged)
+	})
+}
@@ -0,0 +1,142 @@
+package flo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// isWriter reports whether c must keep its place relative to the other
+// writers.
+func (c *Component) isWriter() bool {
+	return c.Effects.Has(EffectWritesState) && !c.AllowReorder
+}
+
+// orderComponents applies the implicit ordering constraints, i.e. the order
+// of the writers then the order hints of the connections.
+//
+// It returns a func undoing the ordering, to be called once done.
+func (f *Flo) orderComponents() func() {
+	if f.writerOrder != nil {
+		// Already ordered by a caller.
+		return func() {}
+	}
+
+	undoWriters := f.orderWriters()
+	undoHints := f.orderHints()
+
+	return func() {
+		undoHints()
+		undoWriters()
+	}
+}
+
+// orderWriters chains the writers in the order they were added to the flo,
+// so that they are never reordered relative to each other. A writer that
+// has to run first because of the data it needs keeps doing so.
+//
+// It returns a func undoing the ordering, to be called once done.
+func (f *Flo) orderWriters() func() {
+	if f.writerOrder != nil {
+		// Already ordered by a caller.
+		return func() {}
+	}
+
+	f.writerOrder = make(map[uuid.UUID]uuid.UUID)
+
+	var prev *Component
+	for _, c := range f.orderedComponents() {
+		if !c.isWriter() {
+			continue
+		}
+		if prev != nil && !f.dependsOn(prev.ID, c.ID) {
+			f.writerOrder[c.ID] = prev.ID
+		}
+		prev = c
+	}
+
+	return func() {
+		f.writerOrder = nil
+	}
+}
+
+// SetConnectionOrder sets the order of a connection among the connections
+// of the same out io: independent consumers are rendered by increasing order.
+func (f *Flo) SetConnectionOrder(connectionID uuid.UUID, order int) error {
+	if connectionID == uuid.Nil {
+		return errors.New("invalid connection id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	conn, found := f.connectionIndex[connectionID]
+	if !found {
+		return fmt.Errorf("unknown connection id %q", connectionID)
+	}
+	if conn.Kind == ComponentConnectionKindSequence {
+		return fmt.Errorf("connection id %q is a sequence", connectionID)
+	}
+
+	conn.Order = order
+	f.markDirty(conn.InComponentID)
+
+	return nil
+}
+
+// orderHints makes the consumers of the same out io run by increasing order
+// of their connections. Consumers with the same order, or depending on each
+// other the other way around, are left alone.
+//
+// It returns a func undoing the ordering, to be called once done.
+func (f *Flo) orderHints() func() {
+	f.hintOrder = make(map[uuid.UUID][]uuid.UUID)
+
+	for _, c := range f.orderedComponents() {
+		_, outs := c.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			f.orderConsumers(out.Connections)
+		}
+	}
+	ins, _ := f.IOs.SeparateINsOUTs()
+	for _, in := range ins {
+		f.orderConsumers(in.Connections)
+	}
+
+	return func() {
+		f.hintOrder = nil
+	}
+}
+
+func (f *Flo) orderConsumers(conns []*ComponentConnection) {
+	if !slices.ContainsFunc(conns, func(conn *ComponentConnection) bool {
+		return conn.Order != conns[0].Order
+	}) {
+		return
+	}
+
+	conns = slices.DeleteFunc(slices.Clone(conns), func(conn *ComponentConnection) bool {
+		_, found := f.Components[conn.InComponentID]
+		return !found
+	})
+	slices.SortStableFunc(conns, func(a, b *ComponentConnection) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+
+	// Each consumer waits for those of the previous order.
+	var prev, current []uuid.UUID
+	for i, conn := range conns {
+		if i > 0 && conn.Order != conns[i-1].Order {
+			prev, current = current, nil
+		}
+		current = append(current, conn.InComponentID)
+		for _, id := range prev {
+			if id != conn.InComponentID && !f.dependsOn(id, conn.InComponentID) {
+				f.hintOrder[conn.InComponentID] = append(f.hintOrder[conn.InComponentID], id)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFn(string) {}
+
+func TestWritersOrdering(t *testing.T) {
+	f, err := flo.NewFlo("TestOrdering", "Test Ordering", "Test Ordering Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	// The first writer is fed by a literal while the second one is fed by
+	// the flo, which used to render it first.
+	first, err := flo.NewComponent("Write", "githab.com/testuf/tera", "First", "First Description", writeFn)
+	require.NoError(t, err)
+	first.Effects = flo.EffectWritesState
+	require.NoError(t, f.AddComponent(first))
+	require.NoError(t, f.SetLiteral(first.ID, first.IOs[0].ID, "first"))
+
+	second, err := flo.NewComponent("Save", "githab.com/testuf/tera", "Second", "Second Description", writeFn)
+	require.NoError(t, err)
+	second.Effects = flo.EffectWritesState
+	require.NoError(t, f.AddComponent(second))
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, second.ID, second.IOs[0].ID))
+
+	render := func(t *testing.T) string {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		return out.String()
+	}
+
+	out := render(t)
+	require.Less(t, strings.Index(out, "First Description"), strings.Index(out, "Second Description"))
+
+	t.Run("Incremental", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.RenderIncremental(context.Background(), out))
+		require.Equal(t, render(t), out.String())
+	})
+
+	t.Run("Allow reorder", func(t *testing.T) {
+		first.AllowReorder = true
+
+		out := render(t)
+		require.Greater(t, strings.Index(out, "First Description"), strings.Index(out, "Second Description"))
+	})
+}
+
+func TestConnectionOrder(t *testing.T) {
+	f, err := flo.NewFlo("TestOrder", "Test Order", "Test Order Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	consumers := make(map[string]*flo.Component)
+	for _, name := range []string{"A", "B", "C"} {
+		c, err := flo.NewComponent("Write", "githab.com/testuf/tera", name, name+" Description", writeFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, c.ID, c.IOs[0].ID))
+		consumers[name] = c
+	}
+
+	order := func(t *testing.T) []string {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+
+		names := []string{"A", "B", "C"}
+		sort.Slice(names, func(i, j int) bool {
+			return strings.Index(out.String(), names[i]+" Description") < strings.Index(out.String(), names[j]+" Description")
+		})
+		return names
+	}
+
+	require.Equal(t, []string{"A", "B", "C"}, order(t))
+
+	err = f.SetConnectionOrder(uuid.New(), 1)
+	require.ErrorContains(t, err, "unknown connection id")
+
+	require.NoError(t, f.SetConnectionOrder(consumers["A"].IOs[0].Connections[0].ID, 2))
+	require.NoError(t, f.SetConnectionOrder(consumers["C"].IOs[0].Connections[0].ID, 1))
+	require.Equal(t, []string{"B", "C", "A"}, order(t))
+
+	t.Run("Dependencies win", func(t *testing.T) {
+		require.NoError(t, f.ConnectSequence(consumers["A"].ID, consumers["B"].ID))
+		require.Equal(t, []string{"A", "B", "C"}, order(t))
+	})
+}
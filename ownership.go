@@ -0,0 +1,150 @@
+package flo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// SetOwner sets the person and the team owning the flo, either may be
+// empty.
+func (f *Flo) SetOwner(owner, team string) error {
+	if err := checkOwners(owner, team); err != nil {
+		return err
+	}
+
+	return f.setFlo(func() { f.Owner, f.Team = owner, team })
+}
+
+// SetComponentOwner sets the person and the team owning the component id,
+// either may be empty.
+func (f *Flo) SetComponentOwner(id uuid.UUID, owner, team string) error {
+	if err := checkOwners(owner, team); err != nil {
+		return err
+	}
+
+	return f.setComponent(id, func(c *Component) {
+		c.Owner, c.Team = owner, team
+	})
+}
+
+// checkOwners makes sure owners fit on a CODEOWNERS line.
+func checkOwners(owners ...string) error {
+	for _, owner := range owners {
+		if strings.ContainsAny(owner, " \t\r\n#") {
+			return fmt.Errorf("invalid owner %q", owner)
+		}
+	}
+
+	return nil
+}
+
+// OwnershipReport attributes the file generated from a flo and its
+// components.
+type OwnershipReport struct {
+	Flo        string
+	File       string
+	Owner      string               `json:",omitempty"`
+	Team       string               `json:",omitempty"`
+	Components []ComponentOwnership `json:",omitempty"` // Only the owned ones.
+}
+
+// ComponentOwnership attributes a component.
+type ComponentOwnership struct {
+	ID    uuid.UUID
+	Name  string
+	Label string
+	Owner string `json:",omitempty"`
+	Team  string `json:",omitempty"`
+}
+
+// Ownership reports who owns the flo, generated in file, and its
+// components.
+func (f *Flo) Ownership(file string) OwnershipReport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r := OwnershipReport{
+		Flo:   f.Name,
+		File:  file,
+		Owner: f.Owner,
+		Team:  f.Team,
+	}
+	for _, c := range f.orderedComponents() {
+		if c.Owner == "" && c.Team == "" {
+			continue
+		}
+		r.Components = append(r.Components, ComponentOwnership{
+			ID:    c.ID,
+			Name:  c.PkgPath + "." + c.Name,
+			Label: c.Label,
+			Owner: c.Owner,
+			Team:  c.Team,
+		})
+	}
+
+	return r
+}
+
+// Owners returns the owners of the file: the team and the owner of the
+// flo, then those of its components, without duplicates.
+func (r OwnershipReport) Owners() []string {
+	owners := []string{r.Team, r.Owner}
+	for _, c := range r.Components {
+		owners = append(owners, c.Team, c.Owner)
+	}
+
+	return lo.Uniq(lo.Compact(owners))
+}
+
+// CodeownersRenderer renders the CODEOWNERS fragment attributing the file
+// generated by RenderFS in Dir, relative to the root of the repository, to
+// the owners of the flo and its components.
+type CodeownersRenderer struct {
+	Dir string
+}
+
+var _ Renderer = CodeownersRenderer{}
+
+// Render implements Renderer.
+func (r CodeownersRenderer) Render(_ context.Context, f *Flo, w io.Writer) error {
+	report := f.Ownership(generatedFile(r.Dir, f))
+	owners := report.Owners()
+	if len(owners) == 0 {
+		return fmt.Errorf("flo %q has no owner", report.Flo)
+	}
+
+	_, err := fmt.Fprintf(w, "# Generated by flo from %s.\n/%s %s\n", report.Flo, report.File, strings.Join(owners, " "))
+	return err
+}
+
+// OwnershipJSONRenderer renders the OwnershipReport of the file generated
+// by RenderFS in Dir as JSON.
+type OwnershipJSONRenderer struct {
+	Dir string
+}
+
+var _ Renderer = OwnershipJSONRenderer{}
+
+// Render implements Renderer.
+func (r OwnershipJSONRenderer) Render(_ context.Context, f *Flo, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	return enc.Encode(f.Ownership(generatedFile(r.Dir, f)))
+}
+
+// generatedFile is the path of the file RenderFS generates for f in dir.
+func generatedFile(dir string, f *Flo) string {
+	f.mu.Lock()
+	name := lo.SnakeCase(f.Name) + ".go"
+	f.mu.Unlock()
+
+	return strings.TrimPrefix(path.Join(dir, name), "/")
+}
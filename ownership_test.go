@@ -0,0 +1,62 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnership(t *testing.T) {
+	f := newTestFlo(t)
+
+	var a, b *flo.Component
+	for _, c := range f.Components {
+		switch c.Name {
+		case "CompA":
+			a = c
+		case "CompB":
+			b = c
+		}
+	}
+
+	fsys := memFS{}
+	opts := []flo.RenderOption{
+		flo.WithFile("CODEOWNERS", flo.CodeownersRenderer{Dir: "/internal/flows"}),
+		flo.WithFile("ownership.json", flo.OwnershipJSONRenderer{Dir: "internal/flows"}),
+	}
+	require.ErrorContains(t, f.RenderFS(context.Background(), fsys, opts...), `flo "TestSync" has no owner`)
+
+	require.ErrorContains(t, f.SetOwner("@j doe", ""), `invalid owner "@j doe"`)
+	require.NoError(t, f.SetOwner("@jdoe", "@acme/payments"))
+	require.NoError(t, f.SetComponentOwner(a.ID, "", "@acme/search"))
+	require.NoError(t, f.SetComponentOwner(b.ID, "@jdoe", "@acme/payments"))
+
+	t.Run("Report", func(t *testing.T) {
+		r := f.Ownership("flows/test_sync.go")
+		require.Equal(t, "TestSync", r.Flo)
+		require.Len(t, r.Components, 2)
+		require.Equal(t, []string{"@acme/payments", "@jdoe", "@acme/search"}, r.Owners())
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		require.NoError(t, f.RenderFS(context.Background(), fsys, opts...))
+		require.Equal(t, "# Generated by flo from TestSync.\n/internal/flows/test_sync.go @acme/payments @jdoe @acme/search\n", fsys["CODEOWNERS"])
+
+		var r flo.OwnershipReport
+		require.NoError(t, json.Unmarshal([]byte(fsys["ownership.json"]), &r))
+		require.Equal(t, f.Ownership("internal/flows/test_sync.go"), r)
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.Equal(t, f.Ownership(""), decoded.Ownership(""))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+}
@@ -0,0 +1,335 @@
+package flo
+
+import (
+	"context"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const errgroupPkg = "golang.org/x/sync/errgroup"
+
+// WithParallel runs the components not depending on each other
+// concurrently, in goroutines of an errgroup.Group, waiting for them before
+// the components depending on them run. The first error returned is
+// returned by the flo once they are all done. It cancels the context of
+// the group, given to the components instead of the context of the flo, so
+// that their siblings stop early.
+//
+// The branches of a join ready to run together run in a group of their
+// own, whose Wait the join is rendered as.
+//
+// Only plain calls aborting the flo on error run concurrently. Sources,
+// joins, guards, literals, flagged and cached components, and those with an
+// error policy, fallbacks or deferred releases run one after the other.
+func WithParallel() RenderOption {
+	return func(o *renderOptions) {
+		o.parallel = true
+	}
+}
+
+// renderParallel renders the components in waves: those whose dependencies
+// are all rendered, concurrently when at least two of them can be.
+func (f *Flo) renderParallel(ctx context.Context, g *jen.Group, rendered map[uuid.UUID]struct{}) error {
+	o := renderOptionsFrom(ctx)
+	taken := f.varNames()
+
+	for {
+		var sequential, concurrent []*Component
+		for _, c := range f.orderedComponents() {
+			if _, found := rendered[c.ID]; found {
+				continue
+			}
+			if !lo.EveryBy(f.predecessors(c), func(id uuid.UUID) bool {
+				_, found := rendered[id]
+				return found
+			}) {
+				continue
+			}
+			if f.runsConcurrently(c, o) {
+				concurrent = append(concurrent, c)
+				continue
+			}
+			sequential = append(sequential, c)
+		}
+		concurrent, joins := f.joinGroups(concurrent, rendered)
+		if len(concurrent) < 2 {
+			sequential = append(sequential, concurrent...)
+			concurrent = nil
+		}
+		if len(sequential) == 0 && len(concurrent) == 0 && len(joins) == 0 {
+			// Done, or left to the sequential rendering to report.
+			return nil
+		}
+
+		for _, c := range sequential {
+			if err := f.RenderComponent(ctx, g, c, rendered); err != nil {
+				return err
+			}
+		}
+		if len(concurrent) > 0 {
+			if err := f.renderGroup(ctx, g, concurrent, taken, rendered, nil); err != nil {
+				return err
+			}
+		}
+		for _, join := range joins {
+			if err := f.renderGroup(ctx, g, join.branches, taken, rendered, join.c); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// joinGroup is a join along with its branches running concurrently.
+type joinGroup struct {
+	c        *Component
+	branches []*Component
+}
+
+// joinGroups takes the branches of the joins out of concurrent, for the
+// joins whose branches left to render are all in concurrent.
+func (f *Flo) joinGroups(concurrent []*Component, rendered map[uuid.UUID]struct{}) ([]*Component, []joinGroup) {
+	var joins []joinGroup
+	for _, c := range f.orderedComponents() {
+		if _, found := rendered[c.ID]; found || c.Kind != ComponentKindJoin {
+			continue
+		}
+
+		var branches []*Component
+		ready := lo.EveryBy(f.predecessors(c), func(id uuid.UUID) bool {
+			if _, found := rendered[id]; found {
+				return true
+			}
+			branch, found := lo.Find(concurrent, func(cc *Component) bool { return cc.ID == id })
+			if found {
+				branches = append(branches, branch)
+			}
+			return found
+		})
+		if !ready || len(branches) == 0 {
+			continue
+		}
+
+		branches = lo.Uniq(branches)
+		concurrent = lo.Without(concurrent, branches...)
+		joins = append(joins, joinGroup{c: c, branches: branches})
+	}
+
+	return concurrent, joins
+}
+
+// runsConcurrently reports whether c may run in a goroutine.
+func (f *Flo) runsConcurrently(c *Component, o renderOptions) bool {
+	if c.Kind != ComponentKindFunc && c.Kind != ComponentKindFlo {
+		return false
+	}
+	if c.Flag != nil || c.Cache != nil || c.ErrorPolicy != ErrorPolicyAbort || c.IsPlaceholder() {
+		return false
+	}
+	if _, stubbed := o.stubs[c.ID]; !stubbed && c.PkgPath == flotimePkg {
+		return false
+	}
+	if _, ok := o.folded[c.ID]; ok {
+		return false
+	}
+
+	_, outs := c.IOs.SeparateINsOUTs()
+	if lo.CountBy(outs, func(out *ComponentIO) bool { return out.IsError }) > 1 {
+		return false
+	}
+
+	return lo.EveryBy(outs, func(out *ComponentIO) bool {
+		if out.DeferRelease || lo.SomeBy(out.Connections, func(conn *ComponentConnection) bool {
+			return conn.Fallback.IsValid()
+		}) {
+			return false
+		}
+		// Results are declared before the goroutines.
+		return !f.usesValue(out) || checkWritable(out.RType) == nil
+	})
+}
+
+// renderGroup renders cs running concurrently in an errgroup.Group, named
+// apart from taken. The Wait of the group is rendered as join, if not nil.
+func (f *Flo) renderGroup(
+	ctx context.Context,
+	g *jen.Group,
+	cs []*Component,
+	taken map[string]struct{},
+	rendered map[uuid.UUID]struct{},
+	join *Component,
+) error {
+	o := renderOptionsFrom(ctx)
+
+	eg, egCtx := uniqueName("eg", taken), ""
+	if lo.SomeBy(cs, func(c *Component) bool { return len(f.groupContextIns(c)) > 0 }) {
+		egCtx = uniqueName("egCtx", taken)
+	}
+
+	var outs []*ComponentIO
+	for _, c := range cs {
+		_, cOUTs := c.IOs.SeparateINsOUTs()
+		outs = append(outs, lo.Filter(cOUTs, func(out *ComponentIO, _ int) bool { return f.usesValue(out) })...)
+	}
+	if len(outs) > 0 || egCtx == "" {
+		g.Var().DefsFunc(func(g *jen.Group) {
+			for _, out := range outs {
+				g.Id(out.Name).Add(typeCode(out.RType))
+			}
+			if egCtx == "" {
+				g.Id(eg).Qual(errgroupPkg, "Group")
+			}
+		})
+	}
+	if egCtx != "" {
+		g.List(jen.Id(eg), jen.Id(egCtx)).Op(":=").Qual(errgroupPkg, "WithContext").Call(f.contextValue())
+	}
+
+	for _, c := range cs {
+		var err error
+		g.Id(eg).Dot("Go").Call(jen.Func().Params().Error().BlockFunc(func(g *jen.Group) {
+			err = f.renderGoroutine(ctx, g, c, o, egCtx)
+		}))
+		if err != nil {
+			return err
+		}
+		rendered[c.ID] = struct{}{}
+	}
+
+	wait := jen.If(jen.Err().Op(":=").Id(eg).Dot("Wait").Call(), jen.Err().Op("!=").Nil()).
+		Block(f.errorReturn(jen.Err()))
+	if join == nil {
+		g.Add(wait).Line()
+		return nil
+	}
+
+	if o.pprofLabels {
+		g.Add(f.pprofLabel(join))
+	}
+	if err := runComponentHooks(ctx, o.beforeComponent, join, g); err != nil {
+		return err
+	}
+	cmt, err := comment(join, o)
+	if err != nil {
+		return err
+	}
+	g.Add(cmt).Add(wait).Line()
+	rendered[join.ID] = struct{}{}
+
+	return runComponentHooks(ctx, o.afterComponent, join, g)
+}
+
+// renderGoroutine renders the body of the goroutine calling c, returning its
+// error. c is given egCtx, the context of the group, instead of the context
+// of the flo.
+func (f *Flo) renderGoroutine(ctx context.Context, g *jen.Group, c *Component, o renderOptions, egCtx string) error {
+	if o.pprofLabels {
+		g.Add(f.pprofLabel(c))
+	}
+	if err := runComponentHooks(ctx, o.beforeComponent, c, g); err != nil {
+		return err
+	}
+
+	cmt, err := comment(c, o)
+	if err != nil {
+		return err
+	}
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	literals, err := literalArgs(c, ins)
+	if err != nil {
+		return err
+	}
+	fn, err := f.calleeCode(ctx, c)
+	if err != nil {
+		return err
+	}
+	args := spreadVariadic(c, f.callArgs(ins, literals, ""))
+	for _, i := range f.groupContextIns(c) {
+		args[i] = jen.Id(egCtx)
+	}
+	if step := budgetStepCode(c, o, args); step != nil {
+		g.Add(cmt).Add(step)
+		cmt = jen.Null()
+	}
+	call := fn.Call(args...)
+
+	hasError := lo.SomeBy(outs, func(out *ComponentIO) bool { return out.IsError })
+	hasAssignment := hasError || lo.SomeBy(outs, f.usesValue)
+	switch {
+	case len(outs) == 1 && hasError && len(o.afterComponent) == 0:
+		g.Add(cmt).Return(call)
+		return nil
+	case !hasAssignment:
+		g.Add(cmt).Add(call)
+	default:
+		if hasError {
+			g.Add(cmt).Var().Err().Error()
+			cmt = jen.Null()
+		}
+		g.Add(cmt).ListFunc(func(g *jen.Group) {
+			for _, out := range outs {
+				switch {
+				case f.usesValue(out):
+					g.Id(out.Name)
+				case out.IsError:
+					g.Err()
+				default:
+					g.Id("_")
+				}
+			}
+		}).Op("=").Add(call)
+	}
+
+	if err := runComponentHooks(ctx, o.afterComponent, c, g); err != nil {
+		return err
+	}
+	if hasError {
+		g.Return(jen.Err())
+	} else {
+		g.Return(jen.Nil())
+	}
+
+	return nil
+}
+
+// groupContextIns returns the indexes, among the in ios of c, of those fed
+// the context of the flo, given the context of their group instead.
+func (f *Flo) groupContextIns(c *Component) []int {
+	param := f.contextParam()
+	ins, _ := c.IOs.SeparateINsOUTs()
+
+	var res []int
+	for i, in := range ins {
+		if in.RType != contextRType || in.IsSignal || in.Multi {
+			continue
+		}
+		if isImplicitContext(in) || len(in.Connections) > 0 && param != nil &&
+			lo.EveryBy(in.Connections, func(conn *ComponentConnection) bool {
+				return conn.OutComponentIOID == param.ID && conn.Transform == nil
+			}) {
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
+
+// varNames returns the names of the variables of the flo function.
+func (f *Flo) varNames() map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, io := range f.IOs {
+		names[io.Name] = struct{}{}
+	}
+	for _, c := range f.Components {
+		for _, io := range c.IOs {
+			names[io.Name] = struct{}{}
+		}
+	}
+	for _, r := range f.receivers() {
+		names[r.Name] = struct{}{}
+	}
+
+	return names
+}
@@ -0,0 +1,200 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderParallel(t *testing.T) {
+	t.Run("Branches", func(t *testing.T) {
+		f := newTestFlo(t)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithParallel()))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package Flo Description
+package flo
+
+import (
+	"context"
+	taaar "githab.com/testam/taaar"
+	tera "githab.com/testuf/tera"
+	terb "githab.com/testurrf/terb"
+	teag "gitlub.com/testing/teag"
+	errgroup "golang.org/x/sync/errgroup"
+)
+
+func TestSync(ctx context.Context, in int, _ int) (int, error) {
+	var (
+		ioff39613112342A272B0Edf2D60F8Cedd6Da8A1A0 int
+		ioa94Cdb2B64820B08Fbac3Df6700F0418263458Cc bool
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		// Test Comp A Description
+		ioff39613112342A272B0Edf2D60F8Cedd6Da8A1A0 = tera.CompA(egCtx, in)
+		return nil
+	})
+	eg.Go(func() error {
+		// Test Comp D Description
+		ioa94Cdb2B64820B08Fbac3Df6700F0418263458Cc = taaar.CompD()
+		return nil
+	})
+	eg.Go(func() error {
+		// Test Comp E Description
+		teag.CompE()
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+
+	// Test Comp B Description
+	iod8E895F4A10213A36E8626E91E455191C1886Cb0, err := terb.CompB(in, ioa94Cdb2B64820B08Fbac3Df6700F0418263458Cc)
+	if err != nil {
+		return 0, err
+	}
+
+	// Test Comp C Description
+	ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412, err := tera.CompC(ctx, ioff39613112342A272B0Edf2D60F8Cedd6Da8A1A0, iod8E895F4A10213A36E8626E91E455191C1886Cb0)
+	if err != nil {
+		return 0, err
+	}
+
+	return ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412, nil
+}
+`, out.String())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		f, err := flo.NewFlo("Check", "Check", "Check Description", "flo", "")
+		require.NoError(t, err)
+
+		ctxIO, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(ctxIO))
+		in, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(in))
+		result, err := flo.NewComponentIO("result", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(result))
+		errIO, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(errIO))
+
+		compB, err := flo.NewComponent("CompB", "githab.com/testurrf/terb", "Comp B", "Comp B Description", compBFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(compB))
+		compC, err := flo.NewComponent("CompC", "githab.com/testuf/tera", "Comp C", "Comp C Description", compCFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(compC))
+
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, compB.ID, compB.IOs[0].ID))
+		require.NoError(t, f.SetLiteral(compB.ID, compB.IOs[1].ID, true))
+		require.NoError(t, f.ConnectComponent(f.ID, ctxIO.ID, compC.ID, compC.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, compC.ID, compC.IOs[1].ID))
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, compC.ID, compC.IOs[2].ID))
+		require.NoError(t, f.ConnectComponent(compC.ID, compC.IOs[3].ID, f.ID, result.ID))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithParallel()))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+package flo
+
+import (
+	"context"
+	tera "githab.com/testuf/tera"
+	terb "githab.com/testurrf/terb"
+	errgroup "golang.org/x/sync/errgroup"
+)
+
+func Check(ctx context.Context, in int) (int, error) {
+	var (
+		ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412 int
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		// Comp B Description
+		var err error
+		_, err = terb.CompB(in, true)
+		return err
+	})
+	eg.Go(func() error {
+		// Comp C Description
+		var err error
+		ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412, err = tera.CompC(egCtx, in, in)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+
+	return ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412, nil
+}
+`, out.String())
+	})
+	t.Run("Without context", func(t *testing.T) {
+		f, err := flo.NewFlo("Check", "Check", "Check Description", "flo", "")
+		require.NoError(t, err)
+
+		in, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(in))
+		errIO, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(errIO))
+
+		compB, err := flo.NewComponent("CompB", "githab.com/testurrf/terb", "Comp B", "Comp B Description", compBFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(compB))
+		compE, err := flo.NewComponent("CompE", "gitlub.com/testing/teag", "Comp E", "Comp E Description", compEFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(compE))
+
+		require.NoError(t, f.ConnectComponent(f.ID, in.ID, compB.ID, compB.IOs[0].ID))
+		require.NoError(t, f.SetLiteral(compB.ID, compB.IOs[1].ID, true))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithParallel()))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+package flo
+
+import (
+	terb "githab.com/testurrf/terb"
+	teag "gitlub.com/testing/teag"
+	errgroup "golang.org/x/sync/errgroup"
+)
+
+func Check(in int) error {
+	var (
+		eg errgroup.Group
+	)
+	eg.Go(func() error {
+		// Comp B Description
+		var err error
+		_, err = terb.CompB(in, true)
+		return err
+	})
+	eg.Go(func() error {
+		// Comp E Description
+		teag.CompE()
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+`, out.String())
+	})
+}
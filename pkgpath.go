@@ -0,0 +1,94 @@
+package flo
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// mainPkgPath is the package path reflect reports for the main package.
+const mainPkgPath = "main"
+
+// qual writes the name of pkgPath. Names of the main package, which cannot
+// be imported, are left unqualified.
+func qual(pkgPath, name string) *jen.Statement {
+	if pkgPath == "" || pkgPath == mainPkgPath {
+		return jen.Id(name)
+	}
+
+	return jen.Qual(pkgPath, name)
+}
+
+// checkImports checks that the packages of the components can be imported
+// by the package pkgPath, the import path the flo is rendered in when known.
+func (f *Flo) checkImports(pkgPath string) error {
+	for _, c := range f.orderedComponents() {
+		if c.PkgPath == "" || c.PkgPath == pkgPath {
+			continue
+		}
+		if isUnexported(c) {
+			return fmt.Errorf("component id %q is unexported and can only be rendered in package %q, see WithSamePackage", c.ID, c.PkgPath)
+		}
+		if c.PkgPath == mainPkgPath && f.PkgName != mainPkgPath {
+			return fmt.Errorf("component id %q of package main cannot be imported by package %s", c.ID, f.PkgName)
+		}
+		if pkgPath != "" && !importable(c.PkgPath, pkgPath) {
+			return fmt.Errorf("component id %q of internal package %q cannot be imported by %q", c.ID, c.PkgPath, pkgPath)
+		}
+	}
+
+	return nil
+}
+
+// importable reports whether the package pkgPath can be imported by the
+// package from, following the rules of internal packages.
+func importable(pkgPath, from string) bool {
+	var parent string
+	switch {
+	case strings.HasPrefix(pkgPath, "internal/") || pkgPath == "internal":
+		// Internal to the standard library.
+		return isStd(from)
+	case strings.Contains(pkgPath, "/internal/"):
+		parent = pkgPath[:strings.LastIndex(pkgPath, "/internal/")]
+	case strings.HasSuffix(pkgPath, "/internal"):
+		parent = strings.TrimSuffix(pkgPath, "/internal")
+	default:
+		return true
+	}
+
+	return from == parent || strings.HasPrefix(from, parent+"/")
+}
+
+// isStd reports whether pkgPath is a package of the standard library, the
+// first element of their paths having no dot.
+func isStd(pkgPath string) bool {
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// isUnexported reports whether c calls an unexported function.
+func isUnexported(c *Component) bool {
+	return c.Kind == ComponentKindFunc && c.Receiver == nil && c.Sub == nil && !c.IsPlaceholder() && !token.IsExported(c.Name)
+}
+
+// resolvePkgPath sets the import path the flo is rendered in to the package
+// of its unexported components when rendering in the same package.
+func (f *Flo) resolvePkgPath(o *renderOptions) error {
+	if !o.samePackage || o.pkgPath != "" {
+		return nil
+	}
+
+	for _, c := range f.orderedComponents() {
+		if c.PkgPath == "" || !isUnexported(c) {
+			continue
+		}
+		if o.pkgPath != "" && o.pkgPath != c.PkgPath {
+			return fmt.Errorf("unexported components belong to both package %q and %q", o.pkgPath, c.PkgPath)
+		}
+		o.pkgPath = c.PkgPath
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func newPkgPathFlo(t *testing.T, pkgName, pkgPath, name string) *flo.Flo {
+	t.Helper()
+
+	f, err := flo.NewFlo("Run", "Run", "Run Description", pkgName, "")
+	require.NoError(t, err)
+
+	in, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(in))
+
+	c, err := flo.NewComponent(name, pkgPath, "Comp B", "Comp B Description", compBFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(c))
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, c.ID, c.IOs[0].ID))
+	require.NoError(t, f.SetLiteral(c.ID, c.IOs[1].ID, true))
+
+	return f
+}
+
+func TestRenderPkgPath(t *testing.T) {
+	t.Run("Main", func(t *testing.T) {
+		f := newPkgPathFlo(t, "main", "main", "CompB")
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), ":= CompB(in, true)\n")
+		require.NotContains(t, out.String(), "import")
+
+		f = newPkgPathFlo(t, "flows", "main", "CompB")
+		err := f.Render(context.Background(), out)
+		require.ErrorContains(t, err, "of package main cannot be imported by package flows")
+	})
+
+	t.Run("Same package", func(t *testing.T) {
+		f := newPkgPathFlo(t, "flows", "github.com/acme/app/flows", "CompB")
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithPkgPath("github.com/acme/app/flows")))
+		require.Contains(t, out.String(), ":= CompB(in, true)\n")
+		require.NotContains(t, out.String(), "import")
+	})
+
+	t.Run("Internal", func(t *testing.T) {
+		f := newPkgPathFlo(t, "flows", "github.com/acme/app/internal/store", "CompB")
+
+		for _, pkgPath := range []string{"", "github.com/acme/app", "github.com/acme/app/internal/flows", "github.com/acme/app/cmd/flows"} {
+			out := &bytes.Buffer{}
+			require.NoError(t, f.Render(context.Background(), out, flo.WithPkgPath(pkgPath)), pkgPath)
+			require.Contains(t, out.String(), `import store "github.com/acme/app/internal/store"`, pkgPath)
+			require.Contains(t, out.String(), ":= store.CompB(in, true)\n", pkgPath)
+		}
+
+		for _, pkgPath := range []string{"github.com/acme/other", "github.com/acme/application"} {
+			err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithPkgPath(pkgPath))
+			require.ErrorContains(t, err, `of internal package "github.com/acme/app/internal/store" cannot be imported by "`+pkgPath+`"`)
+		}
+
+		f = newPkgPathFlo(t, "flows", "internal/poll", "CompB")
+		require.NoError(t, f.Render(context.Background(), &bytes.Buffer{}, flo.WithPkgPath("os")))
+		err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithPkgPath("github.com/acme/app"))
+		require.ErrorContains(t, err, `of internal package "internal/poll" cannot be imported by "github.com/acme/app"`)
+	})
+	t.Run("Unexported", func(t *testing.T) {
+		f := newPkgPathFlo(t, "flows", "github.com/acme/app/flows", "compB")
+
+		err := f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, `is unexported and can only be rendered in package "github.com/acme/app/flows", see WithSamePackage`)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithSamePackage()))
+		require.Contains(t, out.String(), ":= compB(in, true)\n")
+		require.NotContains(t, out.String(), "import")
+
+		other, err := flo.NewComponent("compD", "github.com/acme/app/steps", "Comp D", "Comp D Description", compDFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(other))
+
+		err = f.Render(context.Background(), &bytes.Buffer{}, flo.WithSamePackage())
+		require.ErrorContains(t, err, "unexported components belong to both package")
+
+		err = f.Render(context.Background(), &bytes.Buffer{}, flo.WithSamePackage(), flo.WithPkgPath("github.com/acme/app/steps"))
+		require.ErrorContains(t, err, `can only be rendered in package "github.com/acme/app/flows"`)
+	})
+}
@@ -0,0 +1,193 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// Policy approves flos, e.g. so that compliance teams can let non-engineers
+// edit them. Policies added to a flo are evaluated by Validate, and Render
+// refuses to render flos they reject.
+type Policy interface {
+	// Evaluate returns the violations of the policy by the flo.
+	Evaluate(ctx context.Context, f FloView) []ValidationError
+}
+
+// PolicyFunc adapts a function to Policy.
+type PolicyFunc func(ctx context.Context, f FloView) []ValidationError
+
+var _ Policy = PolicyFunc(nil)
+
+// Evaluate implements Policy.
+func (fn PolicyFunc) Evaluate(ctx context.Context, f FloView) []ValidationError {
+	return fn(ctx, f)
+}
+
+// AddPolicy makes the flo subject to p. Policies are not encoded.
+func (f *Flo) AddPolicy(p Policy) error {
+	if p == nil {
+		return errors.New("missing policy")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.policies = append(f.policies, p)
+
+	return nil
+}
+
+// validatePolicies evaluates the policies of the flo.
+func (f *Flo) validatePolicies(ctx context.Context) []ValidationError {
+	if len(f.policies) == 0 {
+		return nil
+	}
+
+	v := f.view()
+	var errs []ValidationError
+	for _, p := range f.policies {
+		errs = append(errs, p.Evaluate(ctx, v)...)
+	}
+
+	return errs
+}
+
+// checkPolicies returns an error when the policies of the flo reject it.
+func (f *Flo) checkPolicies(ctx context.Context) error {
+	errs := f.validatePolicies(ctx)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("flo rejected by policies: %w", errors.Join(lo.Map(errs, func(err ValidationError, _ int) error {
+		return err
+	})...))
+}
+
+// Match selects components by their metadata. Its string fields are
+// path.Match patterns, e.g. "example.com/payments/*", and only its set
+// fields have to match.
+type Match struct {
+	PkgPath string
+	Name    string
+	Owner   string
+	Team    string
+	Effects Effects // Effects set on the component, all of them.
+}
+
+// Matches reports whether c matches m.
+func (m Match) Matches(c ComponentView) bool {
+	for _, field := range []struct{ pattern, value string }{
+		{m.PkgPath, c.PkgPath},
+		{m.Name, c.Name},
+		{m.Owner, c.Owner},
+		{m.Team, c.Team},
+	} {
+		if field.pattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(field.pattern, field.value); !ok {
+			return false
+		}
+	}
+
+	return c.Effects&m.Effects == m.Effects
+}
+
+// Rule is a built-in policy over the metadata of components. When a flo
+// has a component matching When, or always when When is zero, it must have
+// a component matching Require, if set, and none matching Forbid, if set,
+// e.g. flos touching payments must log an audit:
+//
+//	flo.Rule{
+//		Name:    "audit payments",
+//		When:    flo.Match{PkgPath: "example.com/payments"},
+//		Require: flo.Match{PkgPath: "example.com/audit", Name: "Log"},
+//	}
+//
+// The components of sub-flos are those of the flo too. Violations in a
+// sub-flo are reported on the component running it.
+type Rule struct {
+	Name    string
+	When    Match
+	Require Match
+	Forbid  Match
+}
+
+var _ Policy = Rule{}
+
+// ruleComponent is a component of a flo or of one of its sub-flos.
+type ruleComponent struct {
+	ComponentView
+	reportID uuid.UUID // Component of the flo the violations are reported on.
+	where    string    // Sub-flos the component is in, e.g. `sub-flo "Pay": `.
+}
+
+// ruleComponents returns the components of f and of its sub-flos, depth
+// first.
+func ruleComponents(f FloView) []ruleComponent {
+	var res []ruleComponent
+	for _, c := range f.Components {
+		res = append(res, ruleComponent{ComponentView: c, reportID: c.ID})
+		if c.Sub == nil {
+			continue
+		}
+		for _, sc := range ruleComponents(*c.Sub) {
+			sc.reportID = c.ID
+			sc.where = fmt.Sprintf("sub-flo %q: %s", c.Sub.Name, sc.where)
+			res = append(res, sc)
+		}
+	}
+
+	return res
+}
+
+// Evaluate implements Policy.
+func (r Rule) Evaluate(_ context.Context, f FloView) []ValidationError {
+	components := ruleComponents(f)
+
+	var trigger *ruleComponent
+	if r.When != (Match{}) {
+		for i, c := range components {
+			if r.When.Matches(c.ComponentView) {
+				trigger = &components[i]
+				break
+			}
+		}
+		if trigger == nil {
+			return nil
+		}
+	}
+
+	var errs []ValidationError
+	if r.Require != (Match{}) {
+		required := lo.SomeBy(components, func(c ruleComponent) bool {
+			return r.Require.Matches(c.ComponentView)
+		})
+		if !required {
+			err := ValidationError{Message: fmt.Sprintf("policy %q: flo has no required component", r.Name)}
+			if trigger != nil {
+				err.ComponentID = trigger.reportID
+				err.Message = fmt.Sprintf("policy %q: %scomponent requires another one the flo does not have", r.Name, trigger.where)
+			}
+			errs = append(errs, err)
+		}
+	}
+	if r.Forbid != (Match{}) {
+		for _, c := range components {
+			if r.Forbid.Matches(c.ComponentView) {
+				errs = append(errs, ValidationError{
+					ComponentID: c.reportID,
+					Message:     fmt.Sprintf("policy %q: %scomponent is forbidden", r.Name, c.where),
+				})
+			}
+		}
+	}
+
+	return errs
+}
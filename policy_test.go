@@ -0,0 +1,100 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies(t *testing.T) {
+	f := newTestFlo(t)
+	require.Empty(t, f.Validate(context.Background()))
+	require.ErrorContains(t, f.AddPolicy(nil), "missing policy")
+
+	var a *flo.Component
+	for _, c := range f.Components {
+		if c.Name == "CompA" {
+			a = c
+		}
+	}
+
+	audit := flo.Rule{
+		Name:    "audit tera",
+		When:    flo.Match{PkgPath: "githab.com/*/tera", Name: "CompA"},
+		Require: flo.Match{PkgPath: "example.com/audit", Name: "Log"},
+	}
+	require.NoError(t, f.AddPolicy(audit))
+	require.NoError(t, f.AddPolicy(flo.Rule{
+		Name:   "no network",
+		Forbid: flo.Match{Effects: flo.EffectNetwork},
+	}))
+
+	t.Run("Validate", func(t *testing.T) {
+		errs := f.Validate(context.Background())
+		require.Len(t, errs, 1)
+		require.Equal(t, a.ID, errs[0].ComponentID)
+		require.Contains(t, errs[0].Error(), `policy "audit tera": component requires another one`)
+
+		require.NoError(t, f.SetEffects(a.ID, flo.EffectNetwork|flo.EffectReadsState))
+		errs = f.Validate(context.Background())
+		require.Len(t, errs, 2)
+		require.Contains(t, errs[1].Error(), `policy "no network": component is forbidden`)
+		require.NoError(t, f.SetEffects(a.ID, flo.EffectReadsState))
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		err := f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, "flo rejected by policies")
+		require.ErrorContains(t, err, `policy "audit tera"`)
+	})
+
+	t.Run("Approved", func(t *testing.T) {
+		g := newTestFlo(t)
+		require.NoError(t, g.AddPolicy(flo.PolicyFunc(func(_ context.Context, v flo.FloView) []flo.ValidationError {
+			if v.Team == "" {
+				return []flo.ValidationError{{Message: "flo has no team"}}
+			}
+			return nil
+		})))
+		require.ErrorContains(t, g.Render(context.Background(), &bytes.Buffer{}), "flo has no team")
+		require.NoError(t, g.SetOwner("", "@acme/audit"))
+		require.NoError(t, g.Render(context.Background(), &bytes.Buffer{}))
+
+		// Rules only apply to the flos matching When.
+		require.NoError(t, g.AddPolicy(flo.Rule{
+			Name:    "audit payments",
+			When:    flo.Match{PkgPath: "example.com/payments"},
+			Require: flo.Match{Name: "Log"},
+		}))
+		require.Empty(t, g.Validate(context.Background()))
+	})
+	t.Run("Sub-flos", func(t *testing.T) {
+		sub := newTestFlo(t)
+		require.NoError(t, sub.SetEffects(componentNamed(sub, "CompA").ID, flo.EffectNetwork))
+
+		g, err := flo.NewFlo("Pipeline", "Pipeline", "Pipeline Description", "flo", "Pipeline Package")
+		require.NoError(t, err)
+		c, err := g.AddFlo(sub)
+		require.NoError(t, err)
+		require.NoError(t, g.AddPolicy(audit))
+		require.NoError(t, g.AddPolicy(flo.Rule{
+			Name:   "no network",
+			Forbid: flo.Match{Effects: flo.EffectNetwork},
+		}))
+
+		errs := g.Validate(context.Background())
+		messages := lo.Map(errs, func(err flo.ValidationError, _ int) string { return err.Message })
+		require.Contains(t, messages, `policy "audit tera": sub-flo "TestSync": component requires another one the flo does not have`)
+		require.Contains(t, messages, `policy "no network": sub-flo "TestSync": component is forbidden`)
+		for _, err := range errs {
+			if strings.HasPrefix(err.Message, "policy") {
+				require.Equal(t, c.ID, err.ComponentID)
+			}
+		}
+	})
+}
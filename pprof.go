@@ -0,0 +1,47 @@
+package flo
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// WithPprofLabels labels the goroutine running the generated function with
+// the component being called, so that CPU profiles attribute time to the
+// components of the flo:
+//
+//	flo=<flo name> flo.component=<component label>
+//
+// The labels are added to those of the context param of the flo, if any,
+// and restored when the function returns.
+func WithPprofLabels() RenderOption {
+	return func(o *renderOptions) {
+		o.pprofLabels = true
+	}
+}
+
+// paramContext is the context param of the flo when its body uses it, e.g.
+// for the labels of the flo to derive from, context.Background() otherwise.
+func (f *Flo) paramContext() jen.Code {
+	if param := f.contextParam(); f.usesContextParam(param) {
+		return jen.Id(param.Name)
+	}
+
+	return jen.Qual("context", "Background").Call()
+}
+
+// pprofRestore restores the labels of the goroutine once the flo returns.
+func (f *Flo) pprofRestore() jen.Code {
+	return jen.Defer().Qual("runtime/pprof", "SetGoroutineLabels").Call(f.paramContext())
+}
+
+// pprofLabel labels the goroutine with c until the next component.
+func (f *Flo) pprofLabel(c *Component) jen.Code {
+	return jen.Qual("runtime/pprof", "SetGoroutineLabels").Call(
+		jen.Qual("runtime/pprof", "WithLabels").Call(
+			f.paramContext(),
+			jen.Qual("runtime/pprof", "Labels").Call(
+				jen.Lit("flo"), jen.Lit(f.Name),
+				jen.Lit("flo.component"), jen.Lit(c.Label),
+			),
+		),
+	)
+}
@@ -0,0 +1,72 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWithPprofLabels(t *testing.T) {
+	f := newTestFlo(t)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithPprofLabels()))
+	require.Contains(t, out.String(), `	"runtime/pprof"`)
+	require.Contains(t, out.String(), `func TestSync(ctx context.Context, in int, _ int) (int, error) {
+	defer pprof.SetGoroutineLabels(ctx)
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels("flo", "TestSync", "flo.component", "Test Comp A Label")))
+	// Test Comp A Description
+`)
+	require.Equal(t, len(f.Components), strings.Count(out.String(), `"flo.component"`))
+
+	t.Run("Without context", func(t *testing.T) {
+		f, err := flo.NewFlo("TestPprof", "Test Pprof", "Test Pprof Description", "flo", "Test Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(length))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, length.ID, length.IOs[0].ID))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithPprofLabels()))
+		require.Contains(t, out.String(), `
+	defer pprof.SetGoroutineLabels(context.Background())
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("flo", "TestPprof", "flo.component", "Len")))
+`)
+	})
+}
+
+func TestExecuteWithPprofLabels(t *testing.T) {
+	f, err := flo.NewFlo("TestPprof", "Test Pprof", "Test Pprof Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pOut))
+
+	// The goroutine profile lists the labels of the goroutines.
+	profile := func() string {
+		var b strings.Builder
+		_ = pprof.Lookup("goroutine").WriteTo(&b, 1)
+		return b.String()
+	}
+	c, err := flo.NewComponent("Profile", "githab.com/testuf/tera", "Profile", "Profile Description", profile)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(c))
+	require.NoError(t, f.ConnectComponent(c.ID, c.IOs[0].ID, f.ID, pOut.ID))
+
+	outputs, err := f.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	require.Contains(t, outputs["out"], `"flo":"TestPprof", "flo.component":"Profile"`)
+}
@@ -0,0 +1,64 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// RenderComponentPreview writes the code generated for the component id
+// alone, with the same variable names as in the rendered flo, so that editors
+// can preview the code of each node while it is being edited.
+func (f *Flo) RenderComponentPreview(
+	ctx context.Context,
+	w io.Writer,
+	componentID uuid.UUID,
+	opts ...RenderOption,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, found := f.Components[componentID]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", componentID)
+	}
+
+	ctx = withRenderOptions(ctx, newRenderOptions(opts))
+	f.syncDefinitions()
+	ctx = f.withFoldedCalls(ctx)
+	defer f.orderComponents()()
+
+	// Pretend everything else is rendered so only c ends up in the preview.
+	rendered := make(map[uuid.UUID]struct{}, len(f.Components))
+	for id := range f.Components {
+		if id != c.ID {
+			rendered[id] = struct{}{}
+		}
+	}
+	if c.Flag != nil {
+		ctx = withFlagGate(ctx, *c.Flag)
+	}
+
+	var renderErr error
+	code := jen.CustomFunc(jen.Options{Separator: "\n"}, func(g *jen.Group) {
+		renderErr = f.RenderComponent(ctx, g, c, rendered)
+	})
+	if renderErr != nil {
+		return fmt.Errorf("failed to render component: %v", renderErr)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := code.Render(buf); err != nil {
+		return err
+	}
+
+	// Statement lists keep the surrounding blank lines when formatted.
+	src := append(bytes.TrimSpace(trimBlockEnds(buf.Bytes())), '\n')
+	_, err := w.Write(src)
+
+	return err
+}
@@ -0,0 +1,48 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderComponentPreview(t *testing.T) {
+	f := newTestFlo(t)
+	ctx := context.Background()
+
+	t.Run("Unknown component", func(t *testing.T) {
+		err := f.RenderComponentPreview(ctx, &bytes.Buffer{}, uuid.New())
+		require.ErrorContains(t, err, "no component id")
+	})
+
+	t.Run("Component", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.RenderComponentPreview(ctx, out, componentNamed(f, "CompC").ID))
+
+		full := &bytes.Buffer{}
+		require.NoError(t, f.Render(ctx, full))
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		require.Len(t, lines, 5)
+		require.Equal(t, "// Test Comp C Description", lines[0])
+		require.Contains(t, lines[1], "err := tera.CompC(ctx, ")
+		for _, line := range lines {
+			require.Contains(t, full.String(), "\t"+line+"\n")
+		}
+	})
+
+	t.Run("Gated component", func(t *testing.T) {
+		compE := componentNamed(f, "CompE")
+		require.NoError(t, f.SetFlag(compE.ID, &flo.FlagGate{Name: "new-e"}))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.RenderComponentPreview(ctx, out, compE.ID))
+		require.NotContains(t, out.String(), "Enabled")
+		require.Contains(t, out.String(), "teag.CompE(")
+	})
+}
@@ -0,0 +1,173 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// Receiver renders a component as a call to the method of an instance, e.g.
+// repo.Find(id), instead of a call to a package-level function. The method
+// called is named after the component.
+//
+// The instance is created when the flo starts by Constructor when set, and
+// is a param of the flo otherwise. Components sharing the name of their
+// receiver share the instance.
+type Receiver struct {
+	Name        string       // Variable holding the instance, e.g. "repo".
+	Type        reflect.Type // Type of the instance, e.g. *store.Repo.
+	Constructor string       // Function creating the instance, e.g. "github.com/acme/store.NewRepo".
+}
+
+// SetReceiver renders the component id as a method call on the instance
+// described by r, or as a function call again when r is nil.
+func (f *Flo) SetReceiver(id uuid.UUID, r *Receiver) error {
+	if id == uuid.Nil {
+		return errors.New("invalid component id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	c, found := f.Components[id]
+	if !found {
+		return fmt.Errorf("no component id %q found in flo", id)
+	}
+	if r != nil {
+		if err := checkReceiver(c, r); err != nil {
+			return err
+		}
+		if f.nameTaken(r.Name) {
+			return fmt.Errorf("receiver name %q is already a variable of the flo", r.Name)
+		}
+		for _, other := range f.Components {
+			o := other.Receiver
+			if other == c || o == nil || o.Name != r.Name {
+				continue
+			}
+			if o.Type != r.Type || o.Constructor != r.Constructor {
+				return fmt.Errorf("receiver %q is already of type %s", r.Name, o.Type)
+			}
+		}
+		r = &Receiver{Name: r.Name, Type: r.Type, Constructor: r.Constructor}
+	}
+
+	c.Receiver = r
+	// Receivers may change the signature of the flo.
+	f.markAllDirty()
+
+	return nil
+}
+
+// checkReceiver checks that the method of r called by c exists and matches
+// the ios of c.
+func checkReceiver(c *Component, r *Receiver) error {
+	if !token.IsIdentifier(r.Name) || r.Name == "_" {
+		return fmt.Errorf("invalid receiver name %q", r.Name)
+	}
+	if r.Type == nil {
+		return errors.New("missing receiver type")
+	}
+	if err := checkWritable(r.Type); err != nil {
+		return fmt.Errorf("receiver cannot be rendered: %v", err)
+	}
+	if r.Constructor != "" {
+		if _, _, err := splitQualified(r.Constructor); err != nil {
+			return fmt.Errorf("invalid receiver constructor: %v", err)
+		}
+	}
+
+	m, found := r.Type.MethodByName(c.Name)
+	if !found {
+		return fmt.Errorf("type %s has no method %s", r.Type, c.Name)
+	}
+	if c.IsBound() && !signatureMatches(m.Type, c.Value.Type()) {
+		return fmt.Errorf("method %s of type %s does not match component id %q", c.Name, r.Type, c.ID)
+	}
+
+	return nil
+}
+
+// nameTaken reports whether an io of the flo or of its components is named
+// name.
+func (f *Flo) nameTaken(name string) bool {
+	for _, io := range f.IOs {
+		if io.Name == name {
+			return true
+		}
+	}
+	for _, c := range f.Components {
+		for _, io := range c.IOs {
+			if io.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// splitQualified splits a qualified name such as "github.com/acme/store.New"
+// into its package path and name.
+func splitQualified(qualified string) (string, string, error) {
+	i := strings.LastIndex(qualified, ".")
+	if i <= 0 || i == len(qualified)-1 || strings.HasSuffix(qualified[:i], "/") {
+		return "", "", fmt.Errorf("%q is not of the form pkgPath.Name", qualified)
+	}
+
+	return qualified[:i], qualified[i+1:], nil
+}
+
+// receivers returns the distinct receivers of the components.
+func (f *Flo) receivers() []*Receiver {
+	var (
+		res  []*Receiver
+		seen = make(map[string]struct{})
+	)
+	for _, c := range f.orderedComponents() {
+		if c.Receiver == nil {
+			continue
+		}
+		if _, found := seen[c.Receiver.Name]; found {
+			continue
+		}
+		seen[c.Receiver.Name] = struct{}{}
+		res = append(res, c.Receiver)
+	}
+
+	return res
+}
+
+// receiverParams returns the receivers that are params of the flo.
+func (f *Flo) receiverParams() []*Receiver {
+	var params []*Receiver
+	for _, r := range f.receivers() {
+		if r.Constructor == "" {
+			params = append(params, r)
+		}
+	}
+
+	return params
+}
+
+// constructReceivers creates, in g, the receivers having a constructor.
+func (f *Flo) constructReceivers(g *jen.Group) {
+	var constructed bool
+	for _, r := range f.receivers() {
+		if r.Constructor == "" {
+			continue
+		}
+		pkgPath, name, _ := splitQualified(r.Constructor)
+		g.Id(r.Name).Op(":=").Add(qual(pkgPath, name)).Call()
+		constructed = true
+	}
+	if constructed {
+		g.Line()
+	}
+}
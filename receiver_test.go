@@ -0,0 +1,122 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Add(v int) int {
+	c.n += v
+	return c.n
+}
+
+func (c *Counter) Reset() {
+	c.n = 0
+}
+
+func TestSetReceiver(t *testing.T) {
+	counter := &Counter{}
+
+	f, err := flo.NewFlo("Count", "Count", "Count Description", "flo", "Count Package")
+	require.NoError(t, err)
+
+	pV, err := flo.NewComponentIO("v", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pV))
+
+	rTotal, err := flo.NewComponentIO("total", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rTotal))
+
+	add, err := flo.NewComponent("Add", "githab.com/testuf/tera", "Add", "Adds v.", counter.Add)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(add))
+
+	reset, err := flo.NewComponent("Reset", "githab.com/testuf/tera", "Reset", "Resets the count.", counter.Reset)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(reset))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pV.ID, add.ID, add.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(add.ID, add.IOs[1].ID, f.ID, rTotal.ID))
+	require.NoError(t, f.ConnectSequence(reset.ID, add.ID))
+
+	counterType := reflect.TypeFor[*Counter]()
+
+	t.Run("Invalid", func(t *testing.T) {
+		err := f.SetReceiver(add.ID, &flo.Receiver{Name: "type", Type: counterType})
+		require.ErrorContains(t, err, "invalid receiver name")
+
+		err = f.SetReceiver(add.ID, &flo.Receiver{Name: "v", Type: counterType})
+		require.ErrorContains(t, err, "already a variable of the flo")
+
+		err = f.SetReceiver(add.ID, &flo.Receiver{Name: "c", Type: reflect.TypeFor[Counter]()})
+		require.ErrorContains(t, err, "has no method Add")
+
+		err = f.SetReceiver(add.ID, &flo.Receiver{Name: "c", Type: counterType, Constructor: "NewCounter"})
+		require.ErrorContains(t, err, "not of the form pkgPath.Name")
+	})
+
+	t.Run("Param", func(t *testing.T) {
+		require.NoError(t, f.SetReceiver(add.ID, &flo.Receiver{Name: "counter", Type: counterType}))
+		require.NoError(t, f.SetReceiver(reset.ID, &flo.Receiver{Name: "counter", Type: counterType}))
+
+		err := f.SetReceiver(reset.ID, &flo.Receiver{Name: "counter", Type: counterType, Constructor: "github.com/mgjules/flo_test.NewCounter"})
+		require.ErrorContains(t, err, `receiver "counter" is already of type`)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Count Package
+package flo
+
+import flotest "github.com/mgjules/flo_test"
+
+func Count(v int, counter *flotest.Counter) int {
+	// Resets the count.
+	counter.Reset()
+
+	// Adds v.
+	iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3 := counter.Add(v)
+
+	return iof89Ea79Efb26Da54E6Bc5165E9840182De8A45B3
+}
+`, out.String())
+	})
+
+	t.Run("Constructor", func(t *testing.T) {
+		r := &flo.Receiver{Name: "counter", Type: counterType, Constructor: "github.com/mgjules/flo_test.NewCounter"}
+		require.NoError(t, f.SetReceiver(add.ID, nil))
+		require.NoError(t, f.SetReceiver(reset.ID, r))
+		require.NoError(t, f.SetReceiver(add.ID, r))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "func Count(v int) int {\n\tcounter := flotest.NewCounter()\n\n\t// Resets the count.\n\tcounter.Reset()\n")
+		require.Contains(t, out.String(), "counter.Add(v)")
+
+		types := flo.NewTypeRegistry()
+		require.NoError(t, types.Register(flo.TypeName(reflect.TypeFor[Counter]()), reflect.TypeFor[Counter]()))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeJSON(buf, types))
+		decoded, err := flo.DecodeJSON(buf, types, func(_, name string) (any, error) {
+			if name == "Add" {
+				return counter.Add, nil
+			}
+			return counter.Reset, nil
+		})
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.Equal(t, f.Fingerprint(), decoded.Fingerprint())
+	})
+}
@@ -0,0 +1,159 @@
+package flo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Scalability targets, with V components and E connections:
+//
+//   - Render is O(V+E), i.e. ns/component stays flat from 1k to 100k
+//     components.
+//   - ConnectComponent is O(A) where A is the number of ancestors of the out
+//     component, walked by its cycle check. It does not grow with V in
+//     graphs of short chains (depth=16) but is O(V) at the end of a single
+//     chain (depth=V).
+//   - DeleteConnection does not grow with V.
+//
+// Run with: go test -run '^$' -bench 'Render|Connect' -benchmem
+var benchSizes = []int{1000, 10000, 100000}
+
+// benchDepth is the length of the chains of the wide synthetic graphs.
+const benchDepth = 16
+
+func benchAddFn(a, b int) int {
+	return a + b
+}
+
+// newBenchGraph builds a flo of n components split into chains of depth,
+// each component adding the results of its predecessor and of the head of
+// its chain, which gives both chains and fan-outs.
+func newBenchGraph(b *testing.B, n, depth int) *Flo {
+	b.Helper()
+
+	f, err := NewFlo("BenchGraph", "Bench Graph", "Bench Graph", "bench", "Bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	in, err := NewComponentIO("in", ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := f.AddIO(in); err != nil {
+		b.Fatal(err)
+	}
+	out, err := NewComponentIO("out", ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := f.AddIO(out); err != nil {
+		b.Fatal(err)
+	}
+
+	components := make([]*Component, n)
+	for i := range components {
+		c, err := NewComponent(fmt.Sprintf("Add%d", i), "bench.io/add", "Add", "Add", benchAddFn)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := f.AddComponent(c); err != nil {
+			b.Fatal(err)
+		}
+		components[i] = c
+	}
+	// Connected from the end so that the cycle checks have no ancestors to
+	// walk, which would make building deep graphs quadratic.
+	for i := n - 1; i >= 0; i-- {
+		c := components[i]
+		pos := i % depth
+		for j, prev := range []int{i - 1, i - pos} {
+			if pos == 0 {
+				err = f.ConnectComponent(f.ID, in.ID, c.ID, c.IOs[j].ID)
+			} else {
+				err = f.ConnectComponent(components[prev].ID, components[prev].IOs[2].ID, c.ID, c.IOs[j].ID)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	last := components[n-1]
+	if err := f.ConnectComponent(last.ID, last.IOs[2].ID, f.ID, out.ID); err != nil {
+		b.Fatal(err)
+	}
+
+	return f
+}
+
+var benchGraphs = make(map[[2]int]*Flo)
+
+// benchGraph caches the graphs as sub-benchmarks are run several times.
+func benchGraph(b *testing.B, n, depth int) *Flo {
+	b.Helper()
+
+	f, found := benchGraphs[[2]int{n, depth}]
+	if !found {
+		f = newBenchGraph(b, n, depth)
+		benchGraphs[[2]int{n, depth}] = f
+	}
+	b.ResetTimer()
+
+	return f
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			f := benchGraph(b, n, benchDepth)
+
+			for i := 0; i < b.N; i++ {
+				if err := f.Render(context.Background(), io.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/component")
+		})
+	}
+}
+
+func BenchmarkConnect(b *testing.B) {
+	for _, n := range benchSizes {
+		for _, depth := range []int{benchDepth, n} {
+			b.Run(fmt.Sprintf("%d/depth=%d", n, depth), func(b *testing.B) {
+				f := benchGraph(b, n, depth)
+
+				b.StopTimer()
+				c, err := NewComponent("Extra", "bench.io/add", "Extra", "Extra", benchAddFn)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := f.AddComponent(c); err != nil {
+					b.Fatal(err)
+				}
+				defer func() {
+					b.StopTimer()
+					if err := f.DeleteComponent(c.ID); err != nil {
+						b.Fatal(err)
+					}
+				}()
+				// The end of a chain, with the most ancestors.
+				tail := f.componentOrder[n/2/depth*depth+depth-1]
+				b.StartTimer()
+
+				for i := 0; i < b.N; i++ {
+					if err := f.ConnectComponent(tail, f.Components[tail].IOs[2].ID, c.ID, c.IOs[0].ID); err != nil {
+						b.Fatal(err)
+					}
+					if err := f.DeleteConnection(c.IOs[0].Connections[0].ID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"golang.org/x/tools/imports"
+)
+
+// RenderOption configures Render.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	goImports       bool
+	beforeComponent []ComponentHook
+	afterComponent  []ComponentHook
+	skeleton        *template.Template
+	pkgPath         string
+	files           []renderFile
+	fingerprint     bool
+	foldConstants   bool
+	pprofLabels     bool
+	namedResults    bool
+	inlineFlos      bool
+	samePackage     bool
+	parallel        bool
+	commentTemplate *template.Template
+	variant         string
+	buildConstraint string
+	budget          time.Duration
+	budgetSteps     map[uuid.UUID]int // Components given a share of the budget, by step.
+	budgetVar       string
+	stubs           map[uuid.UUID]string         // Functions called instead of components.
+	folded          map[uuid.UUID]*jen.Statement // Calls folded into constants.
+}
+
+type renderFile struct {
+	name     string
+	renderer Renderer
+}
+
+// ComponentHook injects custom statements into g around the call of c.
+type ComponentHook func(ctx context.Context, c *Component, g *jen.Group) error
+
+// WithGoImports pipes the rendered code through goimports, fixing the
+// import block and formatting the output exactly like gofmt.
+func WithGoImports() RenderOption {
+	return func(o *renderOptions) {
+		o.goImports = true
+	}
+}
+
+// WithBeforeComponent runs h right before each component call is rendered,
+// once the components it depends on have been rendered.
+func WithBeforeComponent(h ComponentHook) RenderOption {
+	return func(o *renderOptions) {
+		o.beforeComponent = append(o.beforeComponent, h)
+	}
+}
+
+// WithAfterComponent runs h right after each component call, and its error
+// handling, is rendered.
+func WithAfterComponent(h ComponentHook) RenderOption {
+	return func(o *renderOptions) {
+		o.afterComponent = append(o.afterComponent, h)
+	}
+}
+
+// WithPkgPath sets the import path of the package the flo is rendered in,
+// so that components of that same package are not imported, and components
+// of internal packages are only allowed when importable from there.
+func WithPkgPath(pkgPath string) RenderOption {
+	return func(o *renderOptions) {
+		o.pkgPath = pkgPath
+	}
+}
+
+// WithSamePackage renders the flo inside the package of its components, so
+// that unexported functions can be components, called by their bare name.
+// The import path of that package is the one set with WithPkgPath, or the
+// one of the unexported components otherwise.
+func WithSamePackage() RenderOption {
+	return func(o *renderOptions) {
+		o.samePackage = true
+	}
+}
+
+// WithFile adds a file rendered by r to the output of RenderFS.
+func WithFile(name string, r Renderer) RenderOption {
+	return func(o *renderOptions) {
+		o.files = append(o.files, renderFile{name: name, renderer: r})
+	}
+}
+
+// WithFingerprint embeds the flo Fingerprint in the header of the generated
+// code, e.g. to detect stale generated files.
+func WithFingerprint() RenderOption {
+	return func(o *renderOptions) {
+		o.fingerprint = true
+	}
+}
+
+func newRenderOptions(opts []RenderOption) renderOptions {
+	var o renderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// cacheKey describes o, false when it can't, i.e. with component hooks
+// whose behaviour can't be compared.
+func (o renderOptions) cacheKey() (string, bool) {
+	if len(o.beforeComponent) > 0 || len(o.afterComponent) > 0 {
+		return "", false
+	}
+
+	plain := o
+	plain.skeleton, plain.commentTemplate, plain.files = nil, nil, nil
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%+v\x00%s\x00%s", plain, templateKey(o.skeleton), templateKey(o.commentTemplate))
+	for _, file := range o.files {
+		key := rendererKey(file.renderer)
+		if key == "" {
+			return "", false
+		}
+		fmt.Fprintf(&sb, "\x00file %s %s", file.name, key)
+	}
+
+	return sb.String(), true
+}
+
+// postProcess writes src to w, applying the post-processing options.
+func (o renderOptions) postProcess(w io.Writer, filename string, src []byte) error {
+	if o.goImports {
+		var err error
+		src, err = imports.Process(filename, src, &imports.Options{
+			Comments:  true,
+			TabIndent: true,
+			TabWidth:  8,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot run goimports: %v", err)
+		}
+	}
+
+	_, err := io.Copy(w, bytes.NewReader(src))
+	return err
+}
+
+// callee is the function called for c.
+func callee(c *Component, o renderOptions) *jen.Statement {
+	if stub, found := o.stubs[c.ID]; found {
+		return jen.Id(stub)
+	}
+
+	s := qual(c.PkgPath, c.Name)
+	if c.Receiver != nil {
+		s = jen.Id(c.Receiver.Name).Dot(c.Name)
+	}
+	if len(c.TypeArgs) > 0 {
+		s.TypesFunc(func(g *jen.Group) {
+			for _, t := range c.TypeArgs {
+				g.Add(typeCode(t))
+			}
+		})
+	}
+
+	return s
+}
+
+type renderOptionsKey struct{}
+
+// withRenderOptions passes o down to RenderComponent.
+func withRenderOptions(ctx context.Context, o renderOptions) context.Context {
+	return context.WithValue(ctx, renderOptionsKey{}, o)
+}
+
+func renderOptionsFrom(ctx context.Context) renderOptions {
+	o, _ := ctx.Value(renderOptionsKey{}).(renderOptions)
+	return o
+}
+
+func runComponentHooks(ctx context.Context, hooks []ComponentHook, c *Component, g *jen.Group) error {
+	for _, h := range hooks {
+		if err := h(ctx, c, g); err != nil {
+			return fmt.Errorf("component id %q hook: %v", c.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/format"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWithGoImports(t *testing.T) {
+	f := newTestFlo(t)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithGoImports()))
+
+	formatted, err := format.Source(out.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), out.String())
+
+	require.Contains(t, out.String(), "func TestSync(ctx context.Context, in int, _ int) (int, error) {")
+}
+
+func TestRenderWithComponentHooks(t *testing.T) {
+	f := newTestFlo(t)
+
+	var calls []string
+	before := func(_ context.Context, c *flo.Component, g *jen.Group) error {
+		calls = append(calls, c.Name)
+		g.Qual("log", "Println").Call(jen.Lit("before " + c.Name))
+		return nil
+	}
+	after := func(_ context.Context, c *flo.Component, g *jen.Group) error {
+		g.Qual("log", "Println").Call(jen.Lit("after " + c.Name))
+		return nil
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(
+		context.Background(),
+		out,
+		flo.WithBeforeComponent(before),
+		flo.WithAfterComponent(after),
+	))
+	require.Len(t, calls, len(f.Components))
+	for _, c := range f.Components {
+		require.Contains(t, out.String(), `log.Println("before `+c.Name+`")`)
+		require.Contains(t, out.String(), `log.Println("after `+c.Name+`")`)
+	}
+
+	t.Run("Hook error", func(t *testing.T) {
+		failing := func(context.Context, *flo.Component, *jen.Group) error {
+			return errors.New("boom")
+		}
+		err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithAfterComponent(failing))
+		require.ErrorContains(t, err, "boom")
+	})
+}
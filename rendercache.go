@@ -0,0 +1,226 @@
+package flo
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	runtimedebug "runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// RenderCache is a Renderer remembering the output of another Renderer for
+// each version of a flo, so that rendering an unchanged flo again is free.
+// Entries are told apart by the flo fingerprint, the renderer and its
+// options, see CacheKeyer, and the version of flo.
+//
+// The last renderCacheSize entries are kept in memory and, when a directory
+// is given, every entry is kept on disk so that they survive restarts.
+type RenderCache struct {
+	renderer Renderer
+	dir      string
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recent  *list.List // Most recently used entries first.
+}
+
+// renderCacheSize is the number of entries a RenderCache keeps in memory.
+const renderCacheSize = 128
+
+// renderCacheVersion is bumped whenever the rendering of flos changes, so
+// that entries stored on disk by previous versions are not served.
+const renderCacheVersion = 1
+
+const floModule = "github.com/mgjules/flo"
+
+type renderCacheEntry struct {
+	key string
+	out []byte
+}
+
+var _ Renderer = (*RenderCache)(nil)
+
+// NewRenderCache caches the output of r. dir is optional.
+func NewRenderCache(r Renderer, dir string) (*RenderCache, error) {
+	if r == nil {
+		return nil, errors.New("missing renderer")
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("cannot create cache dir: %v", err)
+		}
+	}
+
+	return &RenderCache{
+		renderer: r,
+		dir:      dir,
+		entries:  make(map[string]*list.Element),
+		recent:   list.New(),
+	}, nil
+}
+
+// Render implements Renderer. Policies are evaluated even when the flo is
+// cached, as they may have changed since.
+func (c *RenderCache) Render(ctx context.Context, f *Flo, w io.Writer) error {
+	f.mu.Lock()
+	fingerprint := f.fingerprint()
+	err := f.checkPolicies(ctx)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	rkey := rendererKey(c.renderer)
+	if rkey == "" {
+		return c.renderer.Render(ctx, f, w)
+	}
+	h := sha256.New()
+	write(h, libraryVersion(), rkey, fingerprint)
+	key := hex.EncodeToString(h.Sum(nil))
+
+	if out, found := c.lookup(key); found {
+		_, err := w.Write(out)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := c.renderer.Render(ctx, f, buf); err != nil {
+		return err
+	}
+
+	if err := c.store(key, buf.Bytes()); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// libraryVersion identifies the version of flo rendering the flos, from the
+// build info when available.
+var libraryVersion = sync.OnceValue(func() string {
+	version := strconv.Itoa(renderCacheVersion)
+
+	info, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	mod := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == floModule {
+			mod = dep
+		}
+	}
+	if mod.Replace != nil {
+		mod = mod.Replace
+	}
+	version += " " + mod.Path + " " + mod.Version + " " + mod.Sum
+
+	// Development builds of flo itself have no version.
+	if mod == &info.Main {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				version += " " + setting.Value
+			}
+		}
+	}
+
+	return version
+})
+
+// Purge drops every entry.
+func (c *RenderCache) Purge() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.recent.Init()
+	if c.dir == "" {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.flo"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("cannot remove cache entry: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *RenderCache) lookup(key string) ([]byte, bool) {
+	c.mu.Lock()
+	e, found := c.entries[key]
+	if found {
+		c.recent.MoveToFront(e)
+	}
+	c.mu.Unlock()
+	if found {
+		return e.Value.(*renderCacheEntry).out, true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+
+	out, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.remember(key, out)
+	c.mu.Unlock()
+
+	return out, true
+}
+
+func (c *RenderCache) store(key string, out []byte) error {
+	out = bytes.Clone(out)
+
+	c.mu.Lock()
+	c.remember(key, out)
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(c.path(key), out, 0o644); err != nil {
+		return fmt.Errorf("cannot write cache entry: %v", err)
+	}
+
+	return nil
+}
+
+// remember keeps out in memory, evicting the least recently used entry
+// when full.
+func (c *RenderCache) remember(key string, out []byte) {
+	if e, found := c.entries[key]; found {
+		e.Value.(*renderCacheEntry).out = out
+		c.recent.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.recent.PushFront(&renderCacheEntry{key: key, out: out})
+	if c.recent.Len() > renderCacheSize {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderCacheEntry).key)
+	}
+}
+
+func (c *RenderCache) path(key string) string {
+	return filepath.Join(c.dir, key+".flo")
+}
@@ -0,0 +1,168 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRenderer counts how many times it actually renders.
+type countingRenderer struct {
+	calls int
+}
+
+func (r *countingRenderer) Render(ctx context.Context, f *flo.Flo, w io.Writer) error {
+	r.calls++
+	return f.Render(ctx, w)
+}
+
+func TestRenderCache(t *testing.T) {
+	f := newTestFlo(t)
+	dir := t.TempDir()
+
+	counter := &countingRenderer{}
+	cache, err := flo.NewRenderCache(counter, dir)
+	require.NoError(t, err)
+
+	render := func(t *testing.T, r flo.Renderer) string {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		require.NoError(t, r.Render(context.Background(), f, out))
+		return out.String()
+	}
+
+	first := render(t, cache)
+	require.Equal(t, first, render(t, cache))
+	require.Equal(t, 1, counter.calls)
+
+	t.Run("Changed flo", func(t *testing.T) {
+		for _, c := range f.Components {
+			if c.Name == "CompE" {
+				require.NoError(t, f.DeleteComponent(c.ID))
+			}
+		}
+
+		second := render(t, cache)
+		require.NotEqual(t, first, second)
+		require.Equal(t, 2, counter.calls)
+	})
+
+	t.Run("On disk", func(t *testing.T) {
+		counter := &countingRenderer{}
+		cache, err := flo.NewRenderCache(counter, dir)
+		require.NoError(t, err)
+
+		render(t, cache)
+		require.Equal(t, 0, counter.calls)
+
+		require.NoError(t, cache.Purge())
+		render(t, cache)
+		require.Equal(t, 1, counter.calls)
+	})
+
+	t.Run("Policies", func(t *testing.T) {
+		render(t, cache)
+		require.NoError(t, f.AddPolicy(flo.PolicyFunc(func(context.Context, flo.FloView) []flo.ValidationError {
+			return []flo.ValidationError{{Message: "not approved"}}
+		})))
+
+		err := cache.Render(context.Background(), f, &bytes.Buffer{})
+		require.EqualError(t, err, "flo rejected by policies: not approved")
+	})
+}
+
+func TestRenderCacheKeys(t *testing.T) {
+	newFlo := func(t *testing.T, result string) *flo.Flo {
+		t.Helper()
+
+		f, err := flo.NewFlo("Sum", "Sum", "Sum Description", "flo", "Sum Package")
+		require.NoError(t, err)
+
+		pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+
+		rOut, err := flo.NewComponentIO(result, flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(rOut))
+
+		length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(length))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, length.ID, length.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rOut.ID))
+
+		return f
+	}
+
+	render := func(t *testing.T, r flo.Renderer, f *flo.Flo) string {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		require.NoError(t, r.Render(context.Background(), f, out))
+		return out.String()
+	}
+
+	t.Run("Result names", func(t *testing.T) {
+		sum, total := newFlo(t, "sum"), newFlo(t, "total")
+		require.False(t, flo.Equal(sum, total))
+
+		cache, err := flo.NewRenderCache(flo.GoRenderer{Options: []flo.RenderOption{flo.WithNamedResults()}}, "")
+		require.NoError(t, err)
+		require.Contains(t, render(t, cache, sum), "(sum int)")
+		require.Contains(t, render(t, cache, total), "(total int)")
+	})
+
+	t.Run("Renderer options", func(t *testing.T) {
+		f := newFlo(t, "sum")
+		dir := t.TempDir()
+
+		plain, err := flo.NewRenderCache(flo.GoRenderer{}, dir)
+		require.NoError(t, err)
+		require.NotContains(t, render(t, plain, f), "(sum int)")
+
+		named, err := flo.NewRenderCache(flo.GoRenderer{Options: []flo.RenderOption{flo.WithNamedResults()}}, dir)
+		require.NoError(t, err)
+		require.Contains(t, render(t, named, f), "(sum int)")
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		f := newFlo(t, "sum")
+
+		calls := 0
+		cache, err := flo.NewRenderCache(flo.GoRenderer{Options: []flo.RenderOption{
+			flo.WithBeforeComponent(func(context.Context, *flo.Component, *jen.Group) error {
+				calls++
+				return nil
+			}),
+		}}, "")
+		require.NoError(t, err)
+		render(t, cache, f)
+		render(t, cache, f)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("Evicts", func(t *testing.T) {
+		counter := &countingRenderer{}
+		cache, err := flo.NewRenderCache(counter, "")
+		require.NoError(t, err)
+
+		first := newFlo(t, "first")
+		render(t, cache, first)
+		for i := range 128 {
+			render(t, cache, newFlo(t, "out"+strconv.Itoa(i)))
+		}
+		require.Equal(t, 129, counter.calls)
+
+		render(t, cache, first)
+		require.Equal(t, 130, counter.calls)
+	})
+}
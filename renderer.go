@@ -0,0 +1,292 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+)
+
+// Renderer produces an output from a flo.
+type Renderer interface {
+	Render(ctx context.Context, f *Flo, w io.Writer) error
+}
+
+// CacheKeyer is implemented by renderers whose output depends on more than
+// the flo, e.g. on options, so that RenderCache tells their outputs apart.
+// An empty key means the output can't be cached.
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// rendererKey identifies r and its options, empty when the output of r
+// can't be cached. Renderers not implementing CacheKeyer are only told apart
+// by their type.
+func rendererKey(r Renderer) string {
+	key := fmt.Sprintf("%T", r)
+	if k, ok := r.(CacheKeyer); ok {
+		ck := k.CacheKey()
+		if ck == "" {
+			return ""
+		}
+		key += "\x00" + ck
+	}
+
+	return key
+}
+
+// GoRenderer renders flos as Go code.
+type GoRenderer struct {
+	Options []RenderOption
+}
+
+var _ Renderer = GoRenderer{}
+
+// Render implements Renderer.
+func (r GoRenderer) Render(ctx context.Context, f *Flo, w io.Writer) error {
+	return f.Render(ctx, w, r.Options...)
+}
+
+// CacheKey implements CacheKeyer. Renderers with component hooks, which
+// can't be told apart, are not cached.
+func (r GoRenderer) CacheKey() string {
+	key, _ := newRenderOptions(r.Options).cacheKey()
+	return key
+}
+
+// TemplateRenderer renders flos through a text/template, e.g. to produce
+// pseudo-code, docs or shims for other languages.
+// The template is executed with a TemplateFlo.
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+var _ Renderer = (*TemplateRenderer)(nil)
+
+// NewTemplateRenderer parses text as the template of a TemplateRenderer.
+func NewTemplateRenderer(name, text string) (*TemplateRenderer, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+// NewTemplateRendererFS parses the template files of fsys matching patterns.
+// The first file is the one executed, the others can be used as
+// associated templates.
+func NewTemplateRendererFS(fsys fs.FS, patterns ...string) (*TemplateRenderer, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("missing template patterns")
+	}
+
+	tmpl, err := template.ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid templates: %v", err)
+	}
+
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+// Render implements Renderer.
+func (r *TemplateRenderer) Render(_ context.Context, f *Flo, w io.Writer) error {
+	f.mu.Lock()
+	data, err := f.templateData()
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := r.tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("cannot execute template: %v", err)
+	}
+
+	return nil
+}
+
+// CacheKey implements CacheKeyer.
+func (r *TemplateRenderer) CacheKey() string {
+	return templateKey(r.tmpl)
+}
+
+// templateKey describes tmpl and its associated templates.
+func templateKey(tmpl *template.Template) string {
+	if tmpl == nil {
+		return ""
+	}
+
+	ts := tmpl.Templates()
+	slices.SortFunc(ts, func(a, b *template.Template) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	var sb strings.Builder
+	sb.WriteString(tmpl.Name())
+	for _, t := range ts {
+		if t.Tree != nil {
+			fmt.Fprintf(&sb, "\x00%s %s", t.Name(), t.Tree.Root)
+		}
+	}
+
+	return sb.String()
+}
+
+// TemplateFlo is the view of a flo given to templates.
+type TemplateFlo struct {
+	Name           string
+	Label          string
+	Description    string
+	PkgName        string
+	PkgDescription string
+	Params         []TemplateIO
+	Results        []TemplateIO
+	// Components are sorted in execution order.
+	Components []TemplateComponent
+	Cost       CostEstimate
+}
+
+// TemplateComponent is the view of a component given to templates.
+type TemplateComponent struct {
+	ID          string
+	Name        string
+	PkgPath     string
+	Label       string
+	Description string
+	Kind        string
+	Ins         []TemplateIO
+	Outs        []TemplateIO
+	Cost        Cost
+}
+
+// TemplateIO is the view of an io given to templates.
+// Name is the name of the value flowing through the io.
+type TemplateIO struct {
+	Name      string
+	Type      string
+	Connected bool
+	IsError   bool
+	IsSignal  bool
+}
+
+func (f *Flo) templateData() (TemplateFlo, error) {
+	f.syncDefinitions()
+
+	data := TemplateFlo{
+		Name:           f.Name,
+		Label:          f.Label,
+		Description:    f.Description,
+		PkgName:        f.PkgName,
+		PkgDescription: f.PkgDescription,
+	}
+
+	ins, outs := f.IOs.SeparateINsOUTs()
+	data.Params = templateIOs(ins)
+	data.Results = templateIOs(outs)
+
+	order, err := f.executionOrder()
+	if err != nil {
+		return TemplateFlo{}, err
+	}
+
+	for _, c := range order {
+		ins, outs := c.IOs.SeparateINsOUTs()
+		data.Components = append(data.Components, TemplateComponent{
+			ID:          c.ID.String(),
+			Name:        c.Name,
+			PkgPath:     c.PkgPath,
+			Label:       c.Label,
+			Description: c.Description,
+			Kind:        c.Kind.String(),
+			Ins:         templateIOs(ins),
+			Outs:        templateIOs(outs),
+			Cost:        c.Cost,
+		})
+	}
+
+	if data.Cost, err = f.estimateCost(); err != nil {
+		return TemplateFlo{}, err
+	}
+
+	return data, nil
+}
+
+func templateIOs(ios IOs) []TemplateIO {
+	res := make([]TemplateIO, 0, len(ios))
+	for _, io := range ios {
+		res = append(res, TemplateIO{
+			Name:      io.Name,
+			Type:      TypeName(io.RType),
+			Connected: len(io.Connections) > 0,
+			IsError:   io.IsError,
+			IsSignal:  io.IsSignal,
+		})
+	}
+
+	return res
+}
+
+// executionOrder sorts the components the same way Render does: components
+// fed by the flo params first, then the remaining ones, each one after the
+// components it depends on.
+func (f *Flo) executionOrder() ([]*Component, error) {
+	defer f.orderComponents()()
+
+	order := make([]*Component, 0, len(f.Components))
+	visited := make(map[uuid.UUID]struct{}, len(f.Components))
+
+	var visit func(c *Component) error
+	visit = func(c *Component) error {
+		if _, found := visited[c.ID]; found {
+			return nil
+		}
+		visited[c.ID] = struct{}{}
+
+		for _, id := range f.predecessors(c) {
+			dep, found := f.Components[id]
+			if !found {
+				return fmt.Errorf(
+					"misconfigured connection: missing outgoing component %q for component %q",
+					id, c.ID,
+				)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		order = append(order, c)
+
+		return nil
+	}
+
+	ins, _ := f.IOs.SeparateINsOUTs()
+	for _, in := range ins {
+		for _, conn := range in.Connections {
+			c, found := f.Components[conn.InComponentID]
+			if !found {
+				return nil, fmt.Errorf(
+					"misconfigured connection id %q: missing ingoing component %q",
+					conn.ID, conn.InComponentID,
+				)
+			}
+			if err := visit(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, c := range f.orderedComponents() {
+		if err := visit(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,53 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	f := newTestFlo(t)
+
+	fsys := fstest.MapFS{
+		"flo.tmpl": {Data: []byte(`{{ .Label }}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}{{ end }})
+{{ range .Components }}{{ template "component" . }}{{ end -}}
+`)},
+		"component.tmpl": {Data: []byte(`{{ define "component" }}- {{ .Label }} [{{ .Kind }}]{{ range .Ins }} {{ .Type }}{{ end }}
+{{ end }}`)},
+	}
+
+	var r flo.Renderer
+	r, err := flo.NewTemplateRendererFS(fsys, "flo.tmpl", "component.tmpl")
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, r.Render(context.Background(), f, out))
+	require.Equal(t, `Test Flo Label(ctx, in, unused)
+- Test Comp A Label [FUNC] context.Context int
+- Test Comp D Label [FUNC]
+- Test Comp B Label [FUNC] int bool
+- Test Comp C Label [FUNC] context.Context int int
+- Test Comp E Label [FUNC]
+`, out.String())
+
+	t.Run("Go renderer", func(t *testing.T) {
+		var r flo.Renderer = flo.GoRenderer{}
+
+		out := &bytes.Buffer{}
+		require.NoError(t, r.Render(context.Background(), f, out))
+
+		expected := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), expected))
+		require.Equal(t, expected.String(), out.String())
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		_, err := flo.NewTemplateRenderer("broken", "{{ .Label ")
+		require.ErrorContains(t, err, "invalid template")
+	})
+}
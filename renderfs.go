@@ -0,0 +1,122 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/samber/lo"
+)
+
+// WriteFS is a file system files can be written to.
+type WriteFS interface {
+	WriteFile(name string, data []byte) error
+}
+
+// DirFS is a WriteFS writing into a directory of the OS file system.
+type DirFS string
+
+var _ WriteFS = DirFS("")
+
+// WriteFile implements WriteFS.
+func (dir DirFS) WriteFile(name string, data []byte) error {
+	path := filepath.Join(string(dir), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// RenderFS renders the flo as multiple files of the same package:
+//   - "<name>.go" holds the wrapper function, like Render.
+//   - "<sub>.go" holds the function of each sub-flo added with AddFlo living
+//     in the flo package, unless inlined.
+//   - "<name>_stubs.go" holds the stubs of the unbound components living in
+//     the flo package, when set with WithPkgPath or WithSamePackage.
+//   - the files added with WithFile.
+//
+// <name> is the snake cased name of the flo.
+func (f *Flo) RenderFS(
+	ctx context.Context,
+	fsys WriteFS,
+	opts ...RenderOption,
+) error {
+	if fsys == nil {
+		return errors.New("missing file system")
+	}
+
+	o := newRenderOptions(opts)
+	f.mu.Lock()
+	err := f.resolvePkgPath(&o)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	base := lo.SnakeCase(f.Name)
+
+	buf := &bytes.Buffer{}
+	if err := f.Render(ctx, buf, opts...); err != nil {
+		return err
+	}
+	if err := fsys.WriteFile(base+".go", buf.Bytes()); err != nil {
+		return fmt.Errorf("cannot write %q: %v", base+".go", err)
+	}
+	if !o.inlineFlos {
+		// The flo itself takes its name.
+		seen := map[string]string{f.Name: f.Fingerprint()}
+		if err := f.renderSubFlos(ctx, fsys, opts, seen); err != nil {
+			return err
+		}
+	}
+
+	if o.pkgPath != "" && f.hasUnbound(o.pkgPath) {
+		f.mu.Lock()
+		code, err := f.stubsCode(o.pkgPath, f.PkgName)
+		f.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		src := &bytes.Buffer{}
+		if err := code.Render(src); err != nil {
+			return err
+		}
+		buf.Reset()
+		if err := o.postProcess(buf, base+"_stubs.go", src.Bytes()); err != nil {
+			return err
+		}
+		if err := fsys.WriteFile(base+"_stubs.go", buf.Bytes()); err != nil {
+			return fmt.Errorf("cannot write %q: %v", base+"_stubs.go", err)
+		}
+	}
+
+	for _, file := range o.files {
+		buf.Reset()
+		if err := file.renderer.Render(ctx, f, buf); err != nil {
+			return fmt.Errorf("cannot render %q: %v", file.name, err)
+		}
+		if err := fsys.WriteFile(file.name, buf.Bytes()); err != nil {
+			return fmt.Errorf("cannot write %q: %v", file.name, err)
+		}
+	}
+
+	return nil
+}
+
+// hasUnbound reports whether some components of pkgPath are unbound.
+func (f *Flo) hasUnbound(pkgPath string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.Components {
+		if c.PkgPath == pkgPath && !c.IsBound() && c.Kind != ComponentKindJoin && c.Kind != ComponentKindGuard && c.Kind != ComponentKindLiteral {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package flo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+// memFS is an in-memory flo.WriteFS.
+type memFS map[string]string
+
+func (m memFS) WriteFile(name string, data []byte) error {
+	m[name] = string(data)
+	return nil
+}
+
+func TestRenderFS(t *testing.T) {
+	f, err := flo.NewFlo("TestSplit", "Test Split", "Test Split Description", "tera", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	count, err := flo.NewHeadlessComponent("Count", "githab.com/testuf/tera", "Count", "Count Description", "func(string) int", nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(count))
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, count.ID, count.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(count.ID, count.IOs[1].ID, f.ID, rOut.ID))
+
+	readme, err := flo.NewTemplateRenderer("readme", "# {{ .Label }}\n")
+	require.NoError(t, err)
+
+	fsys := memFS{}
+	require.NoError(t, f.RenderFS(
+		context.Background(),
+		fsys,
+		flo.WithPkgPath("githab.com/testuf/tera"),
+		flo.WithFile("README.md", readme),
+	))
+
+	require.Len(t, fsys, 3)
+	require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package tera
+
+func TestSplit(in string) int {
+	// Count Description
+	ioa531097E6639E0747Cf938Cd4E550Ac1A726461B := Count(in)
+
+	return ioa531097E6639E0747Cf938Cd4E550Ac1A726461B
+}
+`, fsys["test_split.go"])
+	require.Equal(t, `// Code generated by flo. Fill in the implementations and remove this comment.
+
+package tera
+
+// Count Description
+func Count(string) int {
+	panic("not implemented")
+}
+`, fsys["test_split_stubs.go"])
+	require.Equal(t, "# Test Split\n", fsys["README.md"])
+
+	t.Run("Dir", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, f.RenderFS(context.Background(), flo.DirFS(dir)))
+
+		data, err := os.ReadFile(filepath.Join(dir, "test_split.go"))
+		require.NoError(t, err)
+		require.Contains(t, string(data), "tera.Count(in)")
+	})
+}
@@ -0,0 +1,197 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+var (
+	closerRType = reflect.TypeFor[io.Closer]()
+	lockerRType = reflect.TypeFor[sync.Locker]()
+)
+
+// releaseMethod returns the method releasing the resources of type t, e.g.
+// files, connections or locks.
+func releaseMethod(t reflect.Type) (string, bool) {
+	switch {
+	case t.Implements(closerRType):
+		return "Close", true
+	case t.Implements(lockerRType):
+		return "Unlock", true
+	default:
+		return "", false
+	}
+}
+
+// IsResource reports whether the values of io must be released once used.
+func (io *ComponentIO) IsResource() bool {
+	_, ok := releaseMethod(io.RType)
+	return ok
+}
+
+// SetOwns marks the in io of a component as taking ownership of the
+// resource it receives, i.e. the component releases it.
+func (f *Flo) SetOwns(componentID, ioID uuid.UUID, owns bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	io, err := f.resourceIO(componentID, ioID, ComponentIOTypeIN)
+	if err != nil {
+		return err
+	}
+
+	f.markDirty(componentID)
+	io.Owns = owns
+
+	return nil
+}
+
+// SetDeferRelease makes the rendered code release the resource produced by
+// the out io of a component with a defer, e.g. "defer f.Close()".
+func (f *Flo) SetDeferRelease(componentID, ioID uuid.UUID, release bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	io, err := f.resourceIO(componentID, ioID, ComponentIOTypeOUT)
+	if err != nil {
+		return err
+	}
+
+	f.markDirty(componentID)
+	io.DeferRelease = release
+
+	return nil
+}
+
+func (f *Flo) resourceIO(componentID, ioID uuid.UUID, typ ComponentIOType) (*ComponentIO, error) {
+	if componentID == uuid.Nil {
+		return nil, errors.New("invalid component id")
+	}
+	if ioID == uuid.Nil {
+		return nil, errors.New("invalid io id")
+	}
+
+	c, found := f.Components[componentID]
+	if !found {
+		return nil, fmt.Errorf("no component id %q found in flo", componentID)
+	}
+	io, found := c.IOs.GetByID(ioID)
+	if !found {
+		return nil, fmt.Errorf("no component io id %q found on component id %q", ioID, componentID)
+	}
+	if io.Type != typ {
+		return nil, fmt.Errorf("component io id %q is not of type %s", ioID, strings.ToLower(typ.String()))
+	}
+	if !io.IsResource() {
+		return nil, fmt.Errorf("component io id %q of type %s is not a resource", ioID, io.RType)
+	}
+
+	return io, nil
+}
+
+// checkOwnership returns an error when the resources of the flo are not
+// released exactly once, so that no code leaking or double releasing them
+// is rendered.
+func (f *Flo) checkOwnership() error {
+	errs := f.validateOwnership()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid resource ownership: %w", errors.Join(lo.Map(errs, func(err ValidationError, _ int) error {
+		return err
+	})...))
+}
+
+// validateOwnership checks that every resource produced by a component is
+// released exactly once, either by a consumer owning it, by the caller of
+// the flo or by a deferred release, and that nothing uses it afterwards.
+func (f *Flo) validateOwnership() []ValidationError {
+	var errs []ValidationError
+
+	for _, c := range f.orderedComponents() {
+		ins, outs := c.IOs.SeparateINsOUTs()
+
+		for _, in := range ins {
+			if in.Owns && !in.IsResource() {
+				errs = append(errs, ValidationError{
+					ComponentID: c.ID,
+					IOID:        in.ID,
+					Message:     fmt.Sprintf("cannot own a value of type %s which is not a resource", in.RType),
+				})
+			}
+		}
+
+		for _, out := range outs {
+			if !out.IsResource() {
+				continue
+			}
+
+			var (
+				owners    []uuid.UUID
+				borrowers []uuid.UUID
+				released  int
+			)
+			if out.DeferRelease {
+				released++
+			}
+			for _, conn := range out.Connections {
+				if conn.InComponentID == f.ID {
+					// The caller gets the resource.
+					released++
+					continue
+				}
+				in, found := f.componentIO(conn.InComponentID, conn.InComponentIOID)
+				if !found {
+					continue
+				}
+				if in.Owns {
+					released++
+					owners = append(owners, conn.InComponentID)
+					continue
+				}
+				borrowers = append(borrowers, conn.InComponentID)
+			}
+
+			switch {
+			case released == 0:
+				errs = append(errs, ValidationError{
+					ComponentID: c.ID,
+					IOID:        out.ID,
+					Message:     fmt.Sprintf("resource of type %s is never released", out.RType),
+				})
+			case released > 1:
+				errs = append(errs, ValidationError{
+					ComponentID: c.ID,
+					IOID:        out.ID,
+					Message:     fmt.Sprintf("resource of type %s has %d owners", out.RType, released),
+				})
+			}
+
+			for _, owner := range owners {
+				for _, borrower := range borrowers {
+					if f.dependsOn(owner, borrower) {
+						continue
+					}
+					errs = append(errs, ValidationError{
+						ComponentID: borrower,
+						IOID:        out.ID,
+						Message: fmt.Sprintf(
+							"may use the resource after component id %q released it",
+							owner,
+						),
+					})
+				}
+			}
+		}
+	}
+
+	return errs
+}
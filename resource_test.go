@@ -0,0 +1,106 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func openFn(string) (*os.File, error) { return nil, nil }
+
+func readFn(*os.File) string { return "" }
+
+func closeFn(*os.File) error { return nil }
+
+func TestResourceOwnership(t *testing.T) {
+	f, err := flo.NewFlo("TestResource", "Test Resource", "Test Resource Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pName, err := flo.NewComponentIO("name", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pName))
+
+	rData, err := flo.NewComponentIO("data", flo.ComponentIOTypeOUT, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rData))
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	open, err := flo.NewComponent("Open", "githab.com/testuf/tera", "Open", "Open Description", openFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(open))
+
+	read, err := flo.NewComponent("Read", "githab.com/testuf/tera", "Read", "Read Description", readFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(read))
+
+	require.NoError(t, f.ConnectComponent(f.ID, pName.ID, open.ID, open.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(open.ID, open.IOs[1].ID, read.ID, read.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(read.ID, read.IOs[1].ID, f.ID, rData.ID))
+
+	ctx := context.Background()
+
+	t.Run("Not a resource", func(t *testing.T) {
+		err := f.SetOwns(read.ID, read.IOs[1].ID, true)
+		require.ErrorContains(t, err, "is not of type in")
+
+		err = f.SetDeferRelease(read.ID, read.IOs[1].ID, true)
+		require.ErrorContains(t, err, "is not a resource")
+	})
+
+	t.Run("Never released", func(t *testing.T) {
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 1)
+		require.Equal(t, open.ID, errs[0].ComponentID)
+		require.ErrorContains(t, errs[0], "never released")
+
+		err := f.Render(ctx, &bytes.Buffer{})
+		require.ErrorContains(t, err, "invalid resource ownership")
+		require.ErrorContains(t, err, "never released")
+	})
+
+	closer, err := flo.NewComponent("Close", "githab.com/testuf/tera", "Close", "Close Description", closeFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(closer))
+	require.NoError(t, f.ConnectComponent(open.ID, open.IOs[1].ID, closer.ID, closer.IOs[0].ID))
+	require.NoError(t, f.SetOwns(closer.ID, closer.IOs[0].ID, true))
+
+	t.Run("Use after release", func(t *testing.T) {
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 1)
+		require.Equal(t, read.ID, errs[0].ComponentID)
+		require.ErrorContains(t, errs[0], "after component id")
+
+		require.NoError(t, f.ConnectSequence(read.ID, closer.ID))
+		require.Empty(t, f.Validate(ctx))
+	})
+
+	t.Run("Released twice", func(t *testing.T) {
+		require.NoError(t, f.SetDeferRelease(open.ID, open.IOs[1].ID, true))
+		t.Cleanup(func() {
+			require.NoError(t, f.SetDeferRelease(open.ID, open.IOs[1].ID, false))
+		})
+
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 1)
+		require.ErrorContains(t, errs[0], "has 2 owners")
+		require.ErrorContains(t, f.Render(ctx, &bytes.Buffer{}), "has 2 owners")
+	})
+
+	t.Run("Defer release", func(t *testing.T) {
+		require.NoError(t, f.SetOwns(closer.ID, closer.IOs[0].ID, false))
+		require.NoError(t, f.SetDeferRelease(open.ID, open.IOs[1].ID, true))
+		require.Empty(t, f.Validate(ctx))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(ctx, out))
+		require.Contains(t, out.String(), "\tdefer "+open.IOs[1].Name+".Close()\n")
+	})
+}
@@ -0,0 +1,70 @@
+package flo
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/mgjules/flo/flosecret"
+)
+
+// flosecretPkg holds the functions the secret components render calls to.
+const flosecretPkg = "github.com/mgjules/flo/flosecret"
+
+var (
+	secretRType         = reflect.TypeFor[flosecret.Secret]()
+	secretProviderRType = reflect.TypeFor[flosecret.Provider]()
+)
+
+// NewSecretComponent creates a component resolving the secret name from an
+// injected provider, i.e.
+//
+//	func(ctx context.Context, provider flosecret.Provider, name string) (flosecret.Secret, error)
+//
+// Secrets are redacted whenever printed, logged or encoded; components
+// needing the value call Reveal on them, e.g. through the "$.Reveal()"
+// expression transform. Secrets can be neither literals nor fallbacks, nor
+// formatted into guard messages, so that their values never end up in a flo
+// nor in the code generated from it.
+func NewSecretComponent(name string, label, description string) (*Component, error) {
+	if name == "" {
+		return nil, errors.New("missing secret name")
+	}
+
+	c, err := NewComponent("Resolve", flosecretPkg, label, description, flosecret.Resolve)
+	if err != nil {
+		return nil, err
+	}
+	c.IOs[2].Literal = reflect.ValueOf(name)
+
+	return c, nil
+}
+
+// isSecret reports whether values of t hold secrets.
+func isSecret(t reflect.Type) bool {
+	return holdsSecret(t, make(map[reflect.Type]struct{}))
+}
+
+func holdsSecret(t reflect.Type, seen map[reflect.Type]struct{}) bool {
+	if _, found := seen[t]; found {
+		return false
+	}
+	seen[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+		return holdsSecret(t.Elem(), seen)
+	case reflect.Map:
+		return holdsSecret(t.Key(), seen) || holdsSecret(t.Elem(), seen)
+	case reflect.Struct:
+		if t == secretRType {
+			return true
+		}
+		for i := range t.NumField() {
+			if holdsSecret(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
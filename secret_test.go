@@ -0,0 +1,99 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flosecret"
+	"github.com/stretchr/testify/require"
+)
+
+type secrets map[string]string
+
+func (s secrets) Secret(_ context.Context, name string) (string, error) {
+	v, found := s[name]
+	if !found {
+		return "", flosecret.ErrNotFound
+	}
+
+	return v, nil
+}
+
+func TestSecretComponent(t *testing.T) {
+	_, err := flo.NewSecretComponent("", "Secret", "Secret")
+	require.ErrorContains(t, err, "missing secret name")
+
+	secret, err := flo.NewSecretComponent("DB_PASSWORD", "Password", "Resolve the database password")
+	require.NoError(t, err)
+
+	t.Run("Call", func(t *testing.T) {
+		res := secret.Value.Call([]reflect.Value{
+			reflect.ValueOf(context.Background()),
+			reflect.ValueOf(flosecret.Provider(secrets{"DB_PASSWORD": "hunter2"})),
+			secret.IOs[2].Literal,
+		})
+		require.True(t, res[1].IsNil())
+		s := res[0].Interface().(flosecret.Secret)
+		require.Equal(t, "hunter2", s.Reveal())
+		require.Equal(t, flosecret.Redacted, fmt.Sprint(s))
+	})
+
+	f, err := flo.NewFlo("TestSecret", "Test Secret", "Test Secret Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, provider := flo.In[context.Context]("ctx"), flo.In[flosecret.Provider]("provider")
+	password, rErr := flo.Out[string]("password"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, provider, password, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+	require.NoError(t, f.AddComponent(secret))
+	require.NoError(t, f.ConnectComponent(f.ID, ctx.ID, secret.ID, secret.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, provider.ID, secret.ID, secret.IOs[1].ID))
+
+	trim, err := flo.NewComponent("TrimSpace", "strings", "Trim", "Trim the password", strings.TrimSpace)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(trim))
+	reveal, err := flo.NewExprTransform("$.Reveal()", reflect.TypeFor[flosecret.Secret](), reflect.TypeFor[string]())
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectComponentWithTransform(secret.ID, secret.IOs[3].ID, trim.ID, trim.IOs[0].ID, reveal))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, f.ID, password.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `, err := flosecret.Resolve(ctx, provider, "DB_PASSWORD")`)
+		require.Contains(t, out.String(), `.Reveal())`)
+	})
+
+	t.Run("No literals", func(t *testing.T) {
+		check, err := flo.NewComponent("Check", "githab.com/testuf/tera", "Check", "Check", func(flosecret.Secret) bool { return true })
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(check))
+		defer func() { require.NoError(t, f.DeleteComponent(check.ID)) }()
+
+		err = f.SetLiteral(check.ID, check.IOs[0].ID, flosecret.New("hunter2"))
+		require.ErrorContains(t, err, "secrets cannot be literals")
+
+		dump := &bytes.Buffer{}
+		require.NoError(t, f.PrettyDump(dump))
+		require.NotContains(t, dump.String(), "hunter2")
+	})
+
+	t.Run("Not formatted by guards", func(t *testing.T) {
+		ok := flo.In[bool]("ok")
+		require.NoError(t, f.AddIO(ok))
+		guard, err := flo.NewGuardComponent("Guard", "Guard", "Guard", "bad password %v", reflect.TypeFor[flosecret.Secret]())
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(guard))
+		require.NoError(t, f.ConnectComponent(f.ID, ok.ID, guard.ID, guard.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(secret.ID, secret.IOs[3].ID, guard.ID, guard.IOs[1].ID))
+
+		err = f.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, "cannot format secret")
+	})
+}
@@ -0,0 +1,190 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ComponentConnectionKind int
+
+const (
+	// ComponentConnectionKindData carries a value from an OUT io to an IN io.
+	ComponentConnectionKindData ComponentConnectionKind = iota
+	// ComponentConnectionKindSequence carries no data and only constrains
+	// the out component to run before the in component.
+	ComponentConnectionKindSequence
+)
+
+// ConnectSequence makes sure the component beforeID runs before the
+// component afterID without any data flowing between them.
+func (f *Flo) ConnectSequence(beforeID, afterID uuid.UUID) error {
+	if beforeID == uuid.Nil {
+		return errors.New("invalid before component id")
+	}
+	if afterID == uuid.Nil {
+		return errors.New("invalid after component id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	return f.connectSequence(beforeID, afterID)
+}
+
+func (f *Flo) connectSequence(beforeID, afterID uuid.UUID) error {
+	if _, found := f.Components[beforeID]; !found {
+		return fmt.Errorf("no before component id %q found in flo", beforeID)
+	}
+	if _, found := f.Components[afterID]; !found {
+		return fmt.Errorf("no after component id %q found in flo", afterID)
+	}
+
+	if beforeID == afterID {
+		return fmt.Errorf("component id %q cannot connect to itself", beforeID)
+	}
+
+	if _, found := lo.Find(f.Sequences, func(conn *ComponentConnection) bool {
+		return conn.OutComponentID == beforeID && conn.InComponentID == afterID
+	}); found {
+		return fmt.Errorf(
+			"component id %q is already sequenced before component id %q",
+			beforeID,
+			afterID,
+		)
+	}
+
+	if f.dependsOn(beforeID, afterID) {
+		return fmt.Errorf(
+			"component id %q already depends on component id %q",
+			beforeID,
+			afterID,
+		)
+	}
+
+	conn, err := NewSequenceConnect(beforeID, afterID)
+	if err != nil {
+		return fmt.Errorf("cannot create sequence connection: %v", err)
+	}
+
+	f.Sequences = append(f.Sequences, conn)
+	f.markDirty(conn.OutComponentID, conn.InComponentID)
+	f.connectionIndex[conn.ID] = conn
+
+	return nil
+}
+
+func NewSequenceConnect(outComponentID, inComponentID uuid.UUID) (*ComponentConnection, error) {
+	if outComponentID == uuid.Nil {
+		return nil, errors.New("invalid out component id")
+	}
+	if inComponentID == uuid.Nil {
+		return nil, errors.New("invalid in component id")
+	}
+
+	return &ComponentConnection{
+		ID:             uuid.New(),
+		Kind:           ComponentConnectionKindSequence,
+		OutComponentID: outComponentID,
+		InComponentID:  inComponentID,
+	}, nil
+}
+
+// predecessors returns the ids of the components that must run before c,
+// whether through data or sequence connections, or because both write
+// state.
+func (f *Flo) predecessors(c *Component) []uuid.UUID {
+	var ids []uuid.UUID
+
+	ins, _ := c.IOs.SeparateINsOUTs()
+	for _, in := range ins {
+		for _, conn := range in.Connections {
+			if conn.OutComponentID == f.ID {
+				continue
+			}
+			ids = append(ids, conn.OutComponentID)
+		}
+	}
+
+	for _, conn := range f.Sequences {
+		if conn.InComponentID == c.ID {
+			ids = append(ids, conn.OutComponentID)
+		}
+	}
+
+	if id, found := f.writerOrder[c.ID]; found {
+		ids = append(ids, id)
+	}
+	ids = append(ids, f.hintOrder[c.ID]...)
+
+	return ids
+}
+
+// dependsOn reports whether the component id transitively needs the
+// component dependencyID to run first.
+func (f *Flo) dependsOn(id, dependencyID uuid.UUID) bool {
+	visited := make(map[uuid.UUID]struct{})
+	stack := []uuid.UUID{id}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current == dependencyID {
+			return true
+		}
+		if _, found := visited[current]; found {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		c, found := f.Components[current]
+		if !found {
+			continue
+		}
+		stack = append(stack, f.predecessors(c)...)
+	}
+
+	return false
+}
+
+// orderedComponents returns the components in the order they were added.
+// Components added by directly mutating Components come last, sorted by id.
+func (f *Flo) orderedComponents() []*Component {
+	components := make([]*Component, 0, len(f.Components))
+	seen := make(map[uuid.UUID]struct{}, len(f.Components))
+	for _, id := range f.componentOrder {
+		c, found := f.Components[id]
+		if !found {
+			continue
+		}
+		components = append(components, c)
+		seen[id] = struct{}{}
+	}
+
+	var rest []*Component
+	for id, c := range f.Components {
+		if _, found := seen[id]; !found {
+			rest = append(rest, c)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i].ID.String() < rest[j].ID.String()
+	})
+
+	return append(components, rest...)
+}
+
+func (k ComponentConnectionKind) String() string {
+	switch k {
+	case ComponentConnectionKindData:
+		return "DATA"
+	case ComponentConnectionKindSequence:
+		return "SEQUENCE"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,88 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectSequence(t *testing.T) {
+	f, err := flo.NewFlo("TestSequence", "Test Sequence", "Test Sequence Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	compD, err := flo.NewComponent("CompD", "githab.com/testam/taaar", "Comp D", "Comp D Description", compDFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compD))
+
+	compE, err := flo.NewComponent("CompE", "gitlub.com/testing/teag", "Comp E", "Comp E Description", compEFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compE))
+
+	t.Run("Cannot sequence unknown components", func(t *testing.T) {
+		err := f.ConnectSequence(f.ID, compE.ID)
+		require.ErrorContains(t, err, "no before component")
+	})
+
+	t.Run("Cannot sequence to self", func(t *testing.T) {
+		err := f.ConnectSequence(compE.ID, compE.ID)
+		require.ErrorContains(t, err, "cannot connect to itself")
+	})
+
+	t.Run("Render in insertion order", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Less(t, bytes.Index(out.Bytes(), []byte("taaar.CompD()")), bytes.Index(out.Bytes(), []byte("teag.CompE()")))
+	})
+
+	require.NoError(t, f.ConnectSequence(compE.ID, compD.ID))
+
+	t.Run("Cannot sequence twice", func(t *testing.T) {
+		err := f.ConnectSequence(compE.ID, compD.ID)
+		require.ErrorContains(t, err, "already sequenced")
+	})
+
+	t.Run("Cannot create a cycle", func(t *testing.T) {
+		err := f.ConnectSequence(compD.ID, compE.ID)
+		require.ErrorContains(t, err, "already depends on")
+	})
+
+	t.Run("Cannot delete sequenced component", func(t *testing.T) {
+		err := f.DeleteComponent(compE.ID)
+		require.ErrorContains(t, err, "has sequence connections")
+	})
+
+	t.Run("Render respects sequence", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	taaar "githab.com/testam/taaar"
+	teag "gitlub.com/testing/teag"
+)
+
+func TestSequence() {
+	// Comp E Description
+	teag.CompE()
+
+	// Comp D Description
+	taaar.CompD()
+
+	return
+}
+`, out.String())
+	})
+
+	t.Run("Delete sequence", func(t *testing.T) {
+		require.Len(t, f.Sequences, 1)
+		require.NoError(t, f.DeleteConnection(f.Sequences[0].ID))
+		require.Empty(t, f.Sequences)
+		require.NoError(t, f.DeleteComponent(compE.ID))
+	})
+}
@@ -0,0 +1,43 @@
+package flo
+
+import (
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/samber/lo"
+)
+
+// Signal is an io type carrying no value. It only tells that something
+// happened, e.g. that a component ran.
+//
+// A signal IN can be triggered by any OUT while a signal OUT can only
+// trigger signal INs.
+type Signal struct{}
+
+var signalRType = reflect.TypeFor[Signal]()
+
+func signalValue() *jen.Statement {
+	return jen.Qual(signalRType.PkgPath(), signalRType.Name()).Values()
+}
+
+// usesValue reports whether the value of the OUT io is needed by any of its
+// connections, i.e. whether it feeds something else than signals.
+func (f *Flo) usesValue(out *ComponentIO) bool {
+	if out.IsSignal {
+		return false
+	}
+
+	return lo.SomeBy(out.Connections, func(conn *ComponentConnection) bool {
+		ios := f.IOs
+		if conn.InComponentID != f.ID {
+			c, found := f.Components[conn.InComponentID]
+			if !found {
+				return true
+			}
+			ios = c.IOs
+		}
+
+		in, found := ios.GetByID(conn.InComponentIOID)
+		return !found || !in.IsSignal
+	})
+}
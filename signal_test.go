@@ -0,0 +1,82 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func pingFn() flo.Signal {
+	return flo.Signal{}
+}
+
+func onDoneFn(flo.Signal) {}
+
+func anyFn(any) {}
+
+func TestSignal(t *testing.T) {
+	f, err := flo.NewFlo("TestSignal", "Test Signal", "Test Signal Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	compD, err := flo.NewComponent("CompD", "githab.com/testam/taaar", "Comp D", "Comp D Description", compDFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(compD))
+
+	ping, err := flo.NewComponent("Ping", "githab.com/testam/sig", "Ping", "Ping Description", pingFn)
+	require.NoError(t, err)
+	require.True(t, ping.IOs[0].IsSignal)
+	require.NoError(t, f.AddComponent(ping))
+
+	onDone, err := flo.NewComponent("OnDone", "githab.com/testam/sig", "On Done", "On Done Description", onDoneFn)
+	require.NoError(t, err)
+	require.True(t, onDone.IOs[0].IsSignal)
+	require.NoError(t, f.AddComponent(onDone))
+
+	anyC, err := flo.NewComponent("Any", "githab.com/testam/sig", "Any", "Any Description", anyFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(anyC))
+
+	t.Run("Signal out can only connect to signal in", func(t *testing.T) {
+		err := f.ConnectComponent(ping.ID, ping.IOs[0].ID, anyC.ID, anyC.IOs[0].ID)
+		require.ErrorContains(t, err, "can only connect to a signal")
+	})
+
+	t.Run("Any out can trigger a signal in", func(t *testing.T) {
+		require.NoError(t, f.ConnectComponent(compD.ID, compD.IOs[0].ID, onDone.ID, onDone.IOs[0].ID))
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	sig "githab.com/testam/sig"
+	taaar "githab.com/testam/taaar"
+	flo "github.com/mgjules/flo"
+)
+
+func TestSignal() {
+	// Comp D Description
+	taaar.CompD()
+
+	// Ping Description
+	sig.Ping()
+
+	// On Done Description
+	sig.OnDone(flo.Signal{})
+
+	// Any Description
+	sig.Any()
+
+	return
+}
+`, out.String())
+	})
+}
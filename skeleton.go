@@ -0,0 +1,133 @@
+package flo
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Skeleton is the data given to the template of WithSkeleton.
+// Types and values are already written as Go code using the import names.
+type Skeleton struct {
+	PkgName        string
+	PkgDescription string
+	Name           string
+	Label          string
+	Description    string
+	Imports        []SkeletonImport
+	Params         []SkeletonField
+	Results        []SkeletonField
+	// Body holds the component calls, indented for a function body.
+	Body string
+}
+
+// SkeletonImport is an import of the generated code.
+type SkeletonImport struct {
+	Name string
+	Path string
+}
+
+// SkeletonField is a param or a result of the generated function.
+// Value is only set for results and holds the returned expression.
+type SkeletonField struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// WithSkeleton replaces the generated function skeleton, i.e. the header,
+// package clause, imports, signature and return statement, with tmpl.
+// flo still generates the component calls which are given as Skeleton.Body.
+func WithSkeleton(tmpl *template.Template) RenderOption {
+	return func(o *renderOptions) {
+		o.skeleton = tmpl
+	}
+}
+
+// applySkeleton re-renders src, the default rendering of f, through tmpl.
+func (f *Flo) applySkeleton(tmpl *template.Template, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rendered flo: %v", err)
+	}
+
+	var fn *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Name.Name == f.Name {
+			fn = d
+			break
+		}
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("cannot find rendered function %q", f.Name)
+	}
+
+	node := func(n ast.Node) string {
+		var buf bytes.Buffer
+		_ = printer.Fprint(&buf, fset, n)
+		return buf.String()
+	}
+
+	data := Skeleton{
+		PkgName:        f.PkgName,
+		PkgDescription: f.PkgDescription,
+		Name:           f.Name,
+		Label:          f.Label,
+		Description:    f.Description,
+	}
+
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imp := SkeletonImport{Path: path}
+		if spec.Name != nil {
+			imp.Name = spec.Name.Name
+		}
+		data.Imports = append(data.Imports, imp)
+	}
+
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			data.Params = append(data.Params, SkeletonField{
+				Name: name.Name,
+				Type: node(field.Type),
+			})
+		}
+	}
+
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			data.Results = append(data.Results, SkeletonField{Type: node(field.Type)})
+		}
+	}
+
+	bodyEnd := fn.Body.Rbrace
+	if n := len(fn.Body.List); n > 0 {
+		if ret, ok := fn.Body.List[n-1].(*ast.ReturnStmt); ok {
+			bodyEnd = ret.Pos()
+			for i, res := range ret.Results {
+				if i < len(data.Results) {
+					data.Results[i].Value = node(res)
+				}
+			}
+		}
+	}
+
+	start := fset.Position(fn.Body.Lbrace).Offset + 1
+	end := fset.Position(bodyEnd).Offset
+	data.Body = strings.Trim(string(src[start:end]), "\n")
+	data.Body = strings.TrimRight(data.Body, "\t\n")
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("cannot execute skeleton template: %v", err)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,50 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"text/template"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+var testSkeleton = template.Must(template.New("skeleton").Parse(`// Code generated by acme/flo. DO NOT EDIT.
+
+// Package {{ .PkgName }}: {{ .PkgDescription }}
+package {{ .PkgName }}
+
+import (
+{{- range .Imports }}
+	{{ with .Name }}{{ . }} {{ end }}"{{ .Path }}"
+{{- end }}
+)
+
+// {{ .Name }} implements "{{ .Label }}".
+func {{ .Name }}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }} {{ $p.Type }}{{ end }}) ({{ range $i, $r := .Results }}{{ if $i }}, {{ end }}{{ $r.Type }}{{ end }}) {
+{{ .Body }}
+
+	return {{ range $i, $r := .Results }}{{ if $i }}, {{ end }}{{ $r.Value }}{{ end }}
+}
+`))
+
+func TestRenderWithSkeleton(t *testing.T) {
+	f := newTestFlo(t)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out, flo.WithSkeleton(testSkeleton), flo.WithGoImports()))
+
+	src := out.String()
+	require.Contains(t, src, "// Code generated by acme/flo. DO NOT EDIT.\n")
+	require.Contains(t, src, "// Package flo: Test Package Flo Description\npackage flo\n")
+	require.Contains(t, src, `tera "githab.com/testuf/tera"`)
+	require.Contains(t, src, "// TestSync implements \"Test Flo Label\".\nfunc TestSync(ctx context.Context, in int, _ int) (int, error) {\n\t// Test Comp A Description\n")
+	require.Contains(t, src, "\tteag.CompE()\n\n\treturn ioaa5Ab25F0Cbe490A08347F8F66917A4Bd0899412, nil\n}\n")
+
+	t.Run("Template error", func(t *testing.T) {
+		tmpl := template.Must(template.New("broken").Parse("{{ .Nope }}"))
+		err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithSkeleton(tmpl))
+		require.ErrorContains(t, err, "cannot execute skeleton template")
+	})
+}
@@ -0,0 +1,49 @@
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// NewSourceComponent creates a component producing a stream of values.
+//
+// fn must take a context.Context as its first argument and return a
+// receive-only channel as its first result. The channel is expected to be
+// closed once the context is canceled; the rendered code takes care of
+// canceling it when the flo returns.
+func NewSourceComponent(
+	name, pkgPath string,
+	label, description string,
+	fn any,
+) (*Component, error) {
+	if err := checkSourceFunc(reflect.TypeOf(fn)); err != nil {
+		return nil, fmt.Errorf("invalid source: %v", err)
+	}
+
+	c, err := NewComponent(name, pkgPath, label, description, fn)
+	if err != nil {
+		return nil, err
+	}
+	c.Kind = ComponentKindSource
+
+	return c, nil
+}
+
+func checkSourceFunc(t reflect.Type) error {
+	if t == nil || t.Kind() != reflect.Func {
+		return errors.New("not a function")
+	}
+	if t.NumIn() == 0 || t.In(0) != reflect.TypeFor[context.Context]() {
+		return errors.New("first argument must be a context.Context")
+	}
+	if t.NumOut() == 0 {
+		return errors.New("missing stream result")
+	}
+	if out := t.Out(0); out.Kind() != reflect.Chan || out.ChanDir()&reflect.RecvDir == 0 {
+		return errors.New("first result must be a receivable channel")
+	}
+
+	return nil
+}
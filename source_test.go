@@ -0,0 +1,137 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func srcFn(ctx context.Context) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 3; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+func sumFn(ch <-chan int) int {
+	var sum int
+	for v := range ch {
+		sum += v
+	}
+
+	return sum
+}
+
+func TestSourceComponent(t *testing.T) {
+	t.Run("Invalid sources", func(t *testing.T) {
+		_, err := flo.NewSourceComponent("Src", "githab.com/testuf/src", "Src", "Src", compDFn)
+		require.ErrorContains(t, err, "context.Context")
+
+		_, err = flo.NewSourceComponent("Src", "githab.com/testuf/src", "Src", "Src", func(context.Context) int { return 0 })
+		require.ErrorContains(t, err, "receivable channel")
+
+		_, err = flo.NewSourceComponent("Src", "githab.com/testuf/src", "Src", "Src", func(context.Context) chan<- int { return nil })
+		require.ErrorContains(t, err, "receivable channel")
+	})
+
+	f, err := flo.NewFlo("TestSource", "Test Source", "Test Source Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pCtx, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pCtx))
+
+	rSum, err := flo.NewComponentIO("sum", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rSum))
+
+	src, err := flo.NewSourceComponent("Src", "githab.com/testuf/src", "Src Label", "Src Description", srcFn)
+	require.NoError(t, err)
+	require.Equal(t, flo.ComponentKindSource, src.Kind)
+	require.NoError(t, f.AddComponent(src))
+
+	sum, err := flo.NewComponent("Sum", "githab.com/testuf/src", "Sum Label", "Sum Description", sumFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(sum))
+
+	t.Run("Source defaults to the context of the flo", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), "func TestSource(ctx context.Context) int {\n")
+		require.Contains(t, out.String(), ":= context.WithCancel(ctx)\n")
+	})
+
+	require.NoError(t, f.ConnectComponent(f.ID, pCtx.ID, src.ID, src.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(src.ID, src.IOs[1].ID, sum.ID, sum.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(sum.ID, sum.IOs[1].ID, f.ID, rSum.ID))
+
+	t.Run("Same source twice", func(t *testing.T) {
+		g, err := flo.NewFlo("TestSources", "Test Sources", "Test Sources Description", "flo", "Test Package")
+		require.NoError(t, err)
+		require.NoError(t, g.AddIO(flo.In[context.Context]("ctx")))
+
+		for _, name := range []string{"a", "b"} {
+			rOut := flo.Out[int](name)
+			require.NoError(t, g.AddIO(rOut))
+
+			src, err := flo.NewSourceComponent("Src", "githab.com/testuf/src", "Src Label", "Src Description", srcFn)
+			require.NoError(t, err)
+			require.NoError(t, g.AddComponent(src))
+
+			sum, err := flo.NewComponent("Sum", "githab.com/testuf/src", "Sum Label", "Sum Description", sumFn)
+			require.NoError(t, err)
+			require.NoError(t, g.AddComponent(sum))
+
+			require.NoError(t, g.ConnectComponent(g.ID, g.IOs[0].ID, src.ID, src.IOs[0].ID))
+			require.NoError(t, g.ConnectComponent(src.ID, src.IOs[1].ID, sum.ID, sum.IOs[0].ID))
+			require.NoError(t, g.ConnectComponent(sum.ID, sum.IOs[1].ID, g.ID, rOut.ID))
+		}
+
+		out := &bytes.Buffer{}
+		require.NoError(t, g.Render(context.Background(), out))
+		cancels := regexp.MustCompile(`defer (cancel\w+)\(\)`).FindAllStringSubmatch(out.String(), -1)
+		require.Len(t, cancels, 2)
+		require.NotEqual(t, cancels[0][1], cancels[1][1])
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	"context"
+	src "githab.com/testuf/src"
+)
+
+func TestSource(ctx context.Context) int {
+	// Src Description
+	ctx7E106Ff011738539Ac0F38187Fb6911F4C14D03D, cancel7E106Ff011738539Ac0F38187Fb6911F4C14D03D := context.WithCancel(ctx)
+	defer cancel7E106Ff011738539Ac0F38187Fb6911F4C14D03D()
+	ioe5E00Bf1028A3Fe9E035C88E40798E5C466B48Df := src.Src(ctx7E106Ff011738539Ac0F38187Fb6911F4C14D03D)
+
+	// Sum Description
+	io7E0Ebbe128333453Ddd529C2D5145927D28D477E := src.Sum(ioe5E00Bf1028A3Fe9E035C88E40798E5C466B48Df)
+
+	return io7E0Ebbe128333453Ddd529C2D5145927D28D477E
+}
+`, out.String())
+	})
+}
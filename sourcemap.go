@@ -0,0 +1,91 @@
+package flo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// SourceSpan holds the lines, 1-based and inclusive, of the code rendered
+// for a component.
+type SourceSpan struct {
+	ComponentID uuid.UUID
+	StartLine   int
+	EndLine     int
+}
+
+const (
+	sourceMapBegin = "flo:begin "
+	sourceMapEnd   = "flo:end "
+)
+
+// SourceMap renders the flo with opts to w, like Render, and returns where
+// the code of each component ended up, in rendering order.
+func (f *Flo) SourceMap(ctx context.Context, w io.Writer, opts ...RenderOption) ([]SourceSpan, error) {
+	// Components are delimited by marker comments which are removed once
+	// their position is known. They take whole lines so removing them leaves
+	// the code as Render would have written it.
+	marker := func(prefix string) ComponentHook {
+		return func(_ context.Context, c *Component, g *jen.Group) error {
+			g.Comment(prefix + c.ID.String())
+			return nil
+		}
+	}
+	opts = append(opts, WithBeforeComponent(marker(sourceMapBegin)), WithAfterComponent(marker(sourceMapEnd)))
+
+	buf := &bytes.Buffer{}
+	if err := f.Render(ctx, buf, opts...); err != nil {
+		return nil, err
+	}
+
+	var (
+		spans []SourceSpan
+		open  = make(map[uuid.UUID]int)
+		line  int
+		last  int // Last non blank line.
+	)
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		text := scanner.Text()
+		comment := strings.TrimPrefix(strings.TrimSpace(text), "// ")
+		switch {
+		case strings.HasPrefix(comment, sourceMapBegin):
+			id, err := uuid.Parse(strings.TrimPrefix(comment, sourceMapBegin))
+			if err != nil {
+				return nil, fmt.Errorf("invalid source map marker: %v", err)
+			}
+			open[id] = line + 1
+		case strings.HasPrefix(comment, sourceMapEnd):
+			id, err := uuid.Parse(strings.TrimPrefix(comment, sourceMapEnd))
+			if err != nil {
+				return nil, fmt.Errorf("invalid source map marker: %v", err)
+			}
+			start, found := open[id]
+			if !found {
+				return nil, fmt.Errorf("component id %q ends before it begins", id)
+			}
+			delete(open, id)
+			spans = append(spans, SourceSpan{ComponentID: id, StartLine: start, EndLine: max(last, start)})
+		default:
+			line++
+			if strings.TrimSpace(text) != "" {
+				last = line
+			}
+			if _, err := io.WriteString(w, text+"\n"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return spans, nil
+}
@@ -0,0 +1,31 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceMap(t *testing.T) {
+	f := newTestFlo(t)
+
+	want := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), want))
+
+	out := &bytes.Buffer{}
+	spans, err := f.SourceMap(context.Background(), out)
+	require.NoError(t, err)
+	require.Equal(t, want.String(), out.String())
+	require.Len(t, spans, len(f.Components))
+
+	lines := strings.Split(out.String(), "\n")
+	for _, span := range spans {
+		c := f.Components[span.ComponentID]
+		require.Equal(t, "\t// "+c.Description, lines[span.StartLine-1])
+		require.Contains(t, strings.Join(lines[span.StartLine-1:span.EndLine], "\n"), "."+c.Name+"(")
+		require.NotEmpty(t, strings.TrimSpace(lines[span.EndLine-1]))
+	}
+}
@@ -0,0 +1,105 @@
+package flo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mgjules/flo/flosql"
+)
+
+// flosqlPkg holds the functions the database components render calls to.
+const flosqlPkg = "github.com/mgjules/flo/flosql"
+
+var (
+	dbRType   = reflect.TypeFor[*sql.DB]()
+	anysRType = reflect.TypeFor[[]any]()
+	sqlIns    = []reflect.Type{contextRType, dbRType, stringRType, anysRType}
+)
+
+// NewSQLQueryRowComponent creates a component running the parameterized
+// query against an injected *sql.DB and scanning the single row it
+// returns into a t, i.e.
+//
+//	func(ctx context.Context, db *sql.DB, query string, args []any) (t, error)
+//
+// Structs are scanned field by field. The args io accepts a connection per
+// query parameter.
+func NewSQLQueryRowComponent(query string, t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newSQLComponent("QueryRow", query, label, description, t)
+}
+
+// NewSQLQueryComponent is like NewSQLQueryRowComponent but scans every row
+// returned, i.e. func(context.Context, *sql.DB, string, []any) ([]t, error).
+func NewSQLQueryComponent(query string, t reflect.Type, label, description string) (*Component, error) {
+	if t == nil {
+		return nil, errors.New("missing type")
+	}
+
+	return newSQLComponent("Query", query, label, description, t)
+}
+
+// NewSQLExecComponent creates a component running the parameterized
+// statement query against an injected *sql.DB, returning the number of rows
+// affected, i.e. func(context.Context, *sql.DB, string, []any) (int64, error).
+func NewSQLExecComponent(query string, label, description string) (*Component, error) {
+	return newSQLComponent("Exec", query, label, description)
+}
+
+func newSQLComponent(name, query, label, description string, typeArgs ...reflect.Type) (*Component, error) {
+	if query == "" {
+		return nil, errors.New("missing query")
+	}
+
+	fn, err := sqlFunc(name, typeArgs)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewComponent(name, flosqlPkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = typeArgs
+	c.IOs[2].Literal = reflect.ValueOf(query)
+	c.IOs[3].Multi = true
+
+	return c, nil
+}
+
+// sqlFunc rebuilds the function backing a database component.
+func sqlFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	if name == "Exec" && len(typeArgs) == 0 {
+		return reflect.ValueOf(flosql.Exec), nil
+	}
+	if (name != "QueryRow" && name != "Query") || len(typeArgs) != 1 {
+		return reflect.Value{}, fmt.Errorf("unknown sql component %q with %d type arguments", name, len(typeArgs))
+	}
+	if err := flosql.Check(typeArgs[0]); err != nil {
+		return reflect.Value{}, err
+	}
+
+	t, query := typeArgs[0], flosql.QueryRowTo
+	if name == "Query" {
+		t, query = reflect.SliceOf(t), flosql.QueryTo
+	}
+
+	return makeFunc(sqlIns, []reflect.Type{t, errorRType},
+		func(args []reflect.Value) []reflect.Value {
+			ctx, ok := args[0].Interface().(context.Context)
+			if !ok {
+				ctx = context.Background()
+			}
+			v := reflect.New(t)
+			err := query(ctx, args[1].Interface().(*sql.DB), args[2].String(), args[3].Interface().([]any), v.Interface())
+			if err != nil {
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+			return []reflect.Value{v.Elem(), errorValue(nil)}
+		}), nil
+}
@@ -0,0 +1,67 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLComponents(t *testing.T) {
+	t.Run("Invalid components", func(t *testing.T) {
+		_, err := flo.NewSQLQueryRowComponent("", reflect.TypeFor[string](), "Query", "Query")
+		require.ErrorContains(t, err, "missing query")
+
+		_, err = flo.NewSQLQueryComponent("SELECT 1", nil, "Query", "Query")
+		require.ErrorContains(t, err, "missing type")
+
+		_, err = flo.NewSQLQueryComponent("SELECT 1", reflect.TypeFor[struct{ name string }](), "Query", "Query")
+		require.ErrorContains(t, err, "not exported")
+	})
+
+	f, err := flo.NewFlo("TestSQL", "Test SQL", "Test SQL Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, db, id := flo.In[context.Context]("ctx"), flo.In[*sql.DB]("db"), flo.In[int]("id")
+	deleted, rErr := flo.Out[int64]("deleted"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, db, id, deleted, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	name, err := flo.NewSQLQueryRowComponent("SELECT name FROM users WHERE id = $1", reflect.TypeFor[string](), "Name", "Find the name")
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(name))
+
+	del, err := flo.NewSQLExecComponent("DELETE FROM users WHERE name = $1", "Delete", "Delete the namesakes")
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(del))
+
+	for _, c := range []*flo.Component{name, del} {
+		require.NoError(t, f.ConnectComponent(f.ID, ctx.ID, c.ID, c.IOs[0].ID))
+		require.NoError(t, f.ConnectComponent(f.ID, db.ID, c.ID, c.IOs[1].ID))
+	}
+	require.NoError(t, f.ConnectComponent(f.ID, id.ID, name.ID, name.IOs[3].ID))
+	require.NoError(t, f.ConnectComponent(name.ID, name.IOs[4].ID, del.ID, del.IOs[3].ID))
+	require.NoError(t, f.ConnectComponent(del.ID, del.IOs[4].ID, f.ID, deleted.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `:= flosql.QueryRow[string](ctx, db, "SELECT name FROM users WHERE id = $1", []any{id})`)
+		require.Contains(t, out.String(), `:= flosql.Exec(ctx, db, "DELETE FROM users WHERE name = $1", []any{io`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		require.True(t, decoded.Components[name.ID].IsBound())
+		require.True(t, decoded.Components[del.ID].IsBound())
+	})
+}
@@ -0,0 +1,148 @@
+package flo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mgjules/flo/flostd"
+)
+
+// flostdPkg holds the generic functions the standard components render
+// calls to.
+const flostdPkg = "github.com/mgjules/flo/flostd"
+
+var intRType = reflect.TypeFor[int]()
+
+// stdArity is the number of type arguments of each standard component.
+var stdArity = map[string]int{
+	"Map":    2, // T, U
+	"Filter": 1, // T
+	"Reduce": 2, // T, A
+	"First":  1, // T
+	"Chunk":  1, // T
+	"Merge":  1, // T
+	"Zip":    2, // T, U
+}
+
+// NewStdComponent creates the standard component name of the flostd
+// package instantiated with typeArgs, e.g. Map with int and string:
+//
+//	func([]int, func(int) string) []string
+//
+// Standard components are Map, Filter, Reduce, First, Chunk, Merge and Zip.
+func NewStdComponent(name string, label, description string, typeArgs ...reflect.Type) (*Component, error) {
+	fn, err := stdFunc(name, typeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewComponent(name, flostdPkg, label, description, fn.Interface())
+	if err != nil {
+		return nil, err
+	}
+	c.TypeArgs = typeArgs
+
+	return c, nil
+}
+
+// stdFunc builds the function backing a standard component.
+func stdFunc(name string, typeArgs []reflect.Type) (reflect.Value, error) {
+	arity, found := stdArity[name]
+	if !found {
+		return reflect.Value{}, fmt.Errorf("unknown standard component %q", name)
+	}
+	if len(typeArgs) != arity {
+		return reflect.Value{}, fmt.Errorf("%s expects %d type arguments but got %d", name, arity, len(typeArgs))
+	}
+	for i, t := range typeArgs {
+		if t == nil {
+			return reflect.Value{}, fmt.Errorf("missing type argument %d", i+1)
+		}
+	}
+
+	t := typeArgs[0]
+	s := reflect.SliceOf(t)
+	switch name {
+	case "Map":
+		u := typeArgs[1]
+		return makeFunc([]reflect.Type{s, reflect.FuncOf([]reflect.Type{t}, []reflect.Type{u}, false)}, []reflect.Type{reflect.SliceOf(u)},
+			func(args []reflect.Value) []reflect.Value {
+				res := reflect.MakeSlice(reflect.SliceOf(u), args[0].Len(), args[0].Len())
+				for i := range args[0].Len() {
+					res.Index(i).Set(args[1].Call([]reflect.Value{args[0].Index(i)})[0])
+				}
+				return []reflect.Value{res}
+			}), nil
+	case "Filter":
+		return makeFunc([]reflect.Type{s, reflect.FuncOf([]reflect.Type{t}, []reflect.Type{boolRType}, false)}, []reflect.Type{s},
+			func(args []reflect.Value) []reflect.Value {
+				res := reflect.Zero(s)
+				for i := range args[0].Len() {
+					if v := args[0].Index(i); args[1].Call([]reflect.Value{v})[0].Bool() {
+						res = reflect.Append(res, v)
+					}
+				}
+				return []reflect.Value{res}
+			}), nil
+	case "Reduce":
+		a := typeArgs[1]
+		return makeFunc([]reflect.Type{s, a, reflect.FuncOf([]reflect.Type{a, t}, []reflect.Type{a}, false)}, []reflect.Type{a},
+			func(args []reflect.Value) []reflect.Value {
+				acc := args[1]
+				for i := range args[0].Len() {
+					acc = args[2].Call([]reflect.Value{acc, args[0].Index(i)})[0]
+				}
+				return []reflect.Value{acc}
+			}), nil
+	case "First":
+		return makeFunc([]reflect.Type{s}, []reflect.Type{t, boolRType},
+			func(args []reflect.Value) []reflect.Value {
+				if args[0].Len() == 0 {
+					return []reflect.Value{reflect.Zero(t), reflect.ValueOf(false)}
+				}
+				return []reflect.Value{args[0].Index(0), reflect.ValueOf(true)}
+			}), nil
+	case "Chunk":
+		return makeFunc([]reflect.Type{s, intRType}, []reflect.Type{reflect.SliceOf(s), errorRType},
+			func(args []reflect.Value) []reflect.Value {
+				res := reflect.Zero(reflect.SliceOf(s))
+				size := int(args[1].Int())
+				if size < 1 {
+					return []reflect.Value{res, errorValue(flostd.ErrChunkSize)}
+				}
+				for v := args[0]; v.Len() > 0; {
+					n := min(size, v.Len())
+					res = reflect.Append(res, v.Slice3(0, n, n))
+					v = v.Slice(n, v.Len())
+				}
+				return []reflect.Value{res, errorValue(nil)}
+			}), nil
+	case "Merge":
+		return makeFunc([]reflect.Type{s, s}, []reflect.Type{s},
+			func(args []reflect.Value) []reflect.Value {
+				res := reflect.MakeSlice(s, 0, args[0].Len()+args[1].Len())
+				res = reflect.AppendSlice(res, args[0])
+				return []reflect.Value{reflect.AppendSlice(res, args[1])}
+			}), nil
+	default: // Zip
+		u := typeArgs[1]
+		pairs := reflect.SliceOf(reflect.StructOf([]reflect.StructField{
+			{Name: "First", Type: t},
+			{Name: "Second", Type: u},
+		}))
+		return makeFunc([]reflect.Type{s, reflect.SliceOf(u)}, []reflect.Type{pairs},
+			func(args []reflect.Value) []reflect.Value {
+				n := min(args[0].Len(), args[1].Len())
+				res := reflect.MakeSlice(pairs, n, n)
+				for i := range n {
+					res.Index(i).Field(0).Set(args[0].Index(i))
+					res.Index(i).Field(1).Set(args[1].Index(i))
+				}
+				return []reflect.Value{res}
+			}), nil
+	}
+}
+
+func makeFunc(in, out []reflect.Type, fn func([]reflect.Value) []reflect.Value) reflect.Value {
+	return reflect.MakeFunc(reflect.FuncOf(in, out, false), fn)
+}
@@ -0,0 +1,103 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/mgjules/flo/flostd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdComponents(t *testing.T) {
+	intT, stringT := reflect.TypeFor[int](), reflect.TypeFor[string]()
+
+	t.Run("Invalid components", func(t *testing.T) {
+		_, err := flo.NewStdComponent("Sort", "Sort", "Sort", intT)
+		require.ErrorContains(t, err, `unknown standard component "Sort"`)
+
+		_, err = flo.NewStdComponent("Map", "Map", "Map", intT)
+		require.ErrorContains(t, err, "Map expects 2 type arguments but got 1")
+	})
+
+	// The components behave like their flostd counterparts.
+	s := []int{1, 2, 3, 4, 5}
+	isEven := func(v int) bool { return v%2 == 0 }
+	sum := func(acc, v int) int { return acc + v }
+	for _, tc := range []struct {
+		name     string
+		typeArgs []reflect.Type
+		args     []any
+		want     []any
+	}{
+		{"Map", []reflect.Type{intT, stringT}, []any{s, strconv.Itoa}, []any{flostd.Map(s, strconv.Itoa)}},
+		{"Filter", []reflect.Type{intT}, []any{s, isEven}, []any{flostd.Filter(s, isEven)}},
+		{"Filter", []reflect.Type{intT}, []any{s, func(int) bool { return false }}, []any{[]int(nil)}},
+		{"Reduce", []reflect.Type{intT, intT}, []any{s, 0, sum}, []any{flostd.Reduce(s, 0, sum)}},
+		{"First", []reflect.Type{intT}, []any{s}, []any{1, true}},
+		{"First", []reflect.Type{intT}, []any{[]int{}}, []any{0, false}},
+		{"Chunk", []reflect.Type{intT}, []any{s, 2}, []any{[][]int{{1, 2}, {3, 4}, {5}}, nil}},
+		{"Chunk", []reflect.Type{intT}, []any{s, 0}, []any{[][]int(nil), flostd.ErrChunkSize}},
+		{"Merge", []reflect.Type{intT}, []any{s, []int{6}}, []any{flostd.Merge(s, []int{6})}},
+		{"Zip", []reflect.Type{intT, stringT}, []any{s, []string{"a"}}, []any{flostd.Zip(s, []string{"a"})}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := flo.NewStdComponent(tc.name, tc.name, tc.name, tc.typeArgs...)
+			require.NoError(t, err)
+			require.Equal(t, tc.typeArgs, c.TypeArgs)
+
+			args := make([]reflect.Value, len(tc.args))
+			for i, arg := range tc.args {
+				args[i] = reflect.ValueOf(arg)
+			}
+			res := c.Value.Call(args)
+			require.Len(t, res, len(tc.want))
+			for i, want := range tc.want {
+				if want == nil {
+					require.True(t, res[i].IsNil())
+					continue
+				}
+				require.EqualValues(t, want, res[i].Interface())
+			}
+		})
+	}
+
+	f, err := flo.NewFlo("TestStd", "Test Std", "Test Std Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	values := flo.In[[]int]("values")
+	format := flo.In[func(int) string]("format")
+	strs := flo.Out[[]string]("strs")
+	for _, io := range []*flo.ComponentIO{values, format, strs} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	mapC, err := flo.NewStdComponent("Map", "Map", "Format the values", intT, stringT)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(mapC))
+	require.NoError(t, f.ConnectComponent(f.ID, values.ID, mapC.ID, mapC.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, format.ID, mapC.ID, mapC.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(mapC.ID, mapC.IOs[2].ID, f.ID, strs.ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Contains(t, out.String(), `import flostd "github.com/mgjules/flo/flostd"`)
+		require.Contains(t, out.String(), `:= flostd.Map[int, string](values, format)`)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+
+		c := decoded.Components[mapC.ID]
+		require.True(t, c.IsBound())
+		require.Equal(t, mapC.Value.Type(), c.Value.Type())
+	})
+}
@@ -0,0 +1,82 @@
+package flo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// RenderStubs writes stub declarations for the unbound components of the
+// package pkgPath, so that the rendered flo compiles right away while the
+// implementations are filled in.
+func (f *Flo) RenderStubs(
+	_ context.Context,
+	w io.Writer,
+	pkgPath string,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	code, err := f.stubsCode(pkgPath, packageName(pkgPath))
+	if err != nil {
+		return err
+	}
+
+	return code.Render(w)
+}
+
+// stubsCode generates the stubs of the package pkgPath named pkgName.
+func (f *Flo) stubsCode(pkgPath, pkgName string) (*jen.File, error) {
+	f.syncDefinitions()
+
+	code := jen.NewFilePathName(pkgPath, pkgName)
+	code.HeaderComment("Code generated by flo. Fill in the implementations and remove this comment.")
+
+	stubbed := make(map[string]struct{})
+	for _, c := range f.orderedComponents() {
+		if c.PkgPath != pkgPath || c.IsBound() || c.Kind == ComponentKindJoin || c.Kind == ComponentKindGuard || c.Kind == ComponentKindLiteral {
+			continue
+		}
+		if _, found := stubbed[c.Name]; found {
+			continue
+		}
+		if len(c.TypeArgs) > 0 {
+			return nil, fmt.Errorf("cannot stub generic component id %q", c.ID)
+		}
+		stubbed[c.Name] = struct{}{}
+
+		ins, outs := c.IOs.SeparateINsOUTs()
+		if c.Description != "" {
+			code.Comment(c.Description)
+		}
+		code.Func().Id(c.Name).
+			ParamsFunc(func(g *jen.Group) {
+				for _, in := range ins {
+					g.Add(typeCode(in.RType))
+				}
+			}).
+			Do(func(s *jen.Statement) {
+				if len(outs) == 1 {
+					s.Add(typeCode(outs[0].RType))
+					return
+				}
+				if len(outs) > 1 {
+					s.Parens(jen.ListFunc(func(g *jen.Group) {
+						for _, out := range outs {
+							g.Add(typeCode(out.RType))
+						}
+					}))
+				}
+			}).
+			Block(jen.Panic(jen.Lit("not implemented")))
+		code.Line()
+	}
+
+	if len(stubbed) == 0 {
+		return nil, fmt.Errorf("no unbound components in package %q", pkgPath)
+	}
+
+	return code, nil
+}
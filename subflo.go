@@ -0,0 +1,187 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// WithInlineFlos renders the body of the sub-flos added with AddFlo as
+// function literals called in place, instead of calls to their generated
+// functions, so that a single file holds the whole pipeline.
+func WithInlineFlos() RenderOption {
+	return func(o *renderOptions) {
+		o.inlineFlos = true
+	}
+}
+
+// AddFlo adds a snapshot of sub to f as a component whose ios are the ios of
+// sub, composing reusable pipelines. Later changes to sub are not seen by f.
+//
+// The component calls the function generated for sub, expected in the
+// package of f unless the PkgPath of the component is set. RenderFS renders
+// it along with f. The body of sub is inlined instead with WithInlineFlos.
+func (f *Flo) AddFlo(sub *Flo) (*Component, error) {
+	if sub == nil {
+		return nil, errors.New("missing flo")
+	}
+	if sub == f {
+		return nil, errors.New("flo cannot contain itself")
+	}
+
+	sub.mu.Lock()
+	snapshot := sub.clone(false)
+	estimate, err := sub.estimateCost()
+	sub.mu.Unlock()
+
+	if snapshot.containsFlo(f.ID) {
+		return nil, fmt.Errorf("flo %q already contains flo %q", sub.Name, f.Name)
+	}
+	if len(snapshot.receiverParams()) > 0 {
+		return nil, fmt.Errorf("flo %q takes receivers as params", sub.Name)
+	}
+
+	c := &Component{
+		ID:          uuid.New(),
+		Name:        sub.Name,
+		Label:       sub.Label,
+		Description: sub.Description,
+		Kind:        ComponentKindFlo,
+		Sub:         snapshot,
+	}
+	if err == nil && len(estimate.Unknown) == 0 {
+		c.Cost = estimate.Total
+	}
+
+	ins, outs := snapshot.IOs.SeparateINsOUTs()
+	c.IOs = make(IOs, 0, len(ins)+len(outs))
+	for i, in := range ins {
+		io, err := NewComponentIO("", ComponentIOTypeIN, in.RType, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error for param %d: %w", i+1, err)
+		}
+		c.IOs = append(c.IOs, io)
+	}
+	for i, out := range outs {
+		data := sha1.Sum([]byte(fmt.Sprintf("%s-%s-%d", sub.ID, sub.Name, i)))
+		io, err := NewComponentIO(fmt.Sprintf("io%x", data), ComponentIOTypeOUT, out.RType, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error for result %d: %w", i+1, err)
+		}
+		c.IOs = append(c.IOs, io)
+	}
+
+	if err := f.AddComponent(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// containsFlo reports whether the flo id is f or one of its sub-flos.
+func (f *Flo) containsFlo(id uuid.UUID) bool {
+	if f.ID == id {
+		return true
+	}
+
+	return lo.SomeBy(lo.Values(f.Components), func(c *Component) bool {
+		return c.Sub != nil && c.Sub.containsFlo(id)
+	})
+}
+
+// inlineCode returns the function literal of the sub-flo f.
+func (f *Flo) inlineCode(ctx context.Context) (*jen.Statement, error) {
+	f.syncDefinitions()
+	defer f.orderComponents()()
+
+	// Feature flags gating the caller do not gate the body.
+	ctx = withRenderOptions(context.Background(), renderOptionsFrom(ctx))
+
+	s := jen.Func()
+	if err := f.renderFuncCode(ctx, s, false); err != nil {
+		return nil, fmt.Errorf("flo %q: %v", f.Name, err)
+	}
+
+	return s, nil
+}
+
+// validateSubFlos reports the problems of the sub-flos.
+func (f *Flo) validateSubFlos() []ValidationError {
+	var errs []ValidationError
+	for _, c := range f.orderedComponents() {
+		if c.Sub == nil {
+			continue
+		}
+
+		sub := c.Sub
+		restore := sub.orderComponents()
+		var subErrs []ValidationError
+		subErrs = append(subErrs, sub.validateConnections()...)
+		subErrs = append(subErrs, sub.validateCycles()...)
+		subErrs = append(subErrs, sub.validateInputs()...)
+		subErrs = append(subErrs, sub.validateOwnership()...)
+		subErrs = append(subErrs, sub.validateMultiplicity()...)
+		subErrs = append(subErrs, sub.validateSubFlos()...)
+		restore()
+
+		for _, err := range subErrs {
+			errs = append(errs, ValidationError{
+				ComponentID: c.ID,
+				Message:     fmt.Sprintf("sub-flo %q: %v", sub.Name, err),
+			})
+		}
+	}
+
+	return errs
+}
+
+// renderSubFlos writes, into fsys, the function of each sub-flo living in
+// the package of f. Sub-flos are rendered once by name, seen holding the
+// fingerprints of those already rendered; different sub-flos sharing a name
+// are an error.
+func (f *Flo) renderSubFlos(ctx context.Context, fsys WriteFS, opts []RenderOption, seen map[string]string) error {
+	f.mu.Lock()
+	var subs []*Flo
+	for _, c := range f.orderedComponents() {
+		if c.Sub == nil || c.PkgPath != "" {
+			continue
+		}
+
+		sub := c.Sub.clone(false)
+		sub.PkgName, sub.PkgDescription = f.PkgName, ""
+		fingerprint := sub.fingerprint()
+		if seenFingerprint, found := seen[sub.Name]; found {
+			if seenFingerprint != fingerprint {
+				f.mu.Unlock()
+				return fmt.Errorf("different sub-flos are named %q", sub.Name)
+			}
+			continue
+		}
+		seen[sub.Name] = fingerprint
+		subs = append(subs, sub)
+	}
+	f.mu.Unlock()
+
+	for _, sub := range subs {
+		name := lo.SnakeCase(sub.Name) + ".go"
+
+		buf := &bytes.Buffer{}
+		if err := sub.Render(ctx, buf, opts...); err != nil {
+			return fmt.Errorf("cannot render sub-flo %q: %v", sub.Name, err)
+		}
+		if err := fsys.WriteFile(name, buf.Bytes()); err != nil {
+			return fmt.Errorf("cannot write %q: %v", name, err)
+		}
+		if err := sub.renderSubFlos(ctx, fsys, opts, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFlo(t *testing.T) {
+	sub := newTestFlo(t)
+
+	f, err := flo.NewFlo("Pipeline", "Pipeline", "Pipeline Description", "flo", "Pipeline Package")
+	require.NoError(t, err)
+
+	pCtx, err := flo.NewComponentIO("ctx", flo.ComponentIOTypeIN, reflect.TypeFor[context.Context](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pCtx))
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rResult, err := flo.NewComponentIO("result", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rResult))
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := f.AddFlo(nil)
+		require.ErrorContains(t, err, "missing flo")
+
+		_, err = f.AddFlo(f)
+		require.ErrorContains(t, err, "cannot contain itself")
+	})
+
+	c, err := f.AddFlo(sub)
+	require.NoError(t, err)
+	require.Equal(t, flo.ComponentKindFlo, c.Kind)
+	require.Equal(t, "TestSync", c.Name)
+	require.Len(t, c.IOs, 5)
+	require.True(t, c.IOs[4].IsError)
+
+	t.Run("Contained", func(t *testing.T) {
+		other, err := flo.NewFlo("Other", "Other", "Other Description", "flo", "")
+		require.NoError(t, err)
+		_, err = other.AddFlo(f)
+		require.NoError(t, err)
+
+		_, err = f.AddFlo(other)
+		require.ErrorContains(t, err, `flo "Other" already contains flo "Pipeline"`)
+	})
+
+	require.NoError(t, f.ConnectComponent(f.ID, pCtx.ID, c.ID, c.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, c.ID, c.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(c.ID, c.IOs[3].ID, f.ID, rResult.ID))
+
+	t.Run("Validate", func(t *testing.T) {
+		errs := f.Validate(context.Background())
+		require.Len(t, errs, 1)
+		require.Equal(t, c.ID, errs[0].ComponentID)
+		require.ErrorContains(t, errs[0], "neither connected nor set")
+
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[2].ID, 3))
+		require.Empty(t, f.Validate(context.Background()))
+	})
+
+	t.Run("Call", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, strings.ReplaceAll(`// Code generated by flo. Do not edit!
+
+// Pipeline Package
+package flo
+
+import "context"
+
+func Pipeline(ctx context.Context, in int) (int, error) {
+	// Test Flo Description
+	result, err := TestSync(ctx, in, 3)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+`, "result", c.IOs[3].Name), out.String())
+	})
+
+	t.Run("Inline", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out, flo.WithInlineFlos()))
+		require.Contains(t, out.String(), c.IOs[3].Name+", err := func(ctx context.Context, in int, _ int) (int, error) {\n")
+		require.Contains(t, out.String(), "\t\t\treturn 0, err\n")
+		require.Contains(t, out.String(), "\t}(ctx, in, 3)\n")
+		require.NotContains(t, out.String(), "TestSync(")
+	})
+
+	t.Run("RenderFS", func(t *testing.T) {
+		fsys := memFS{}
+		require.NoError(t, f.RenderFS(context.Background(), fsys))
+		require.Len(t, fsys, 2)
+		require.Contains(t, fsys["test_sync.go"], "package flo\n")
+		require.Contains(t, fsys["test_sync.go"], "func TestSync(ctx context.Context, in int, _ int) (int, error) {")
+		require.Contains(t, fsys["pipeline.go"], "func Pipeline(")
+
+		fsys = memFS{}
+		require.NoError(t, f.RenderFS(context.Background(), fsys, flo.WithInlineFlos()))
+		require.Len(t, fsys, 1)
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeJSON(buf, nil))
+
+		decoded, err := flo.DecodeJSON(buf, nil, resolveTestFunc)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+
+		want, got := &bytes.Buffer{}, &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), want, flo.WithInlineFlos()))
+		require.NoError(t, decoded.Render(context.Background(), got, flo.WithInlineFlos()))
+		require.Equal(t, want.String(), got.String())
+	})
+}
+
+func TestRenderFSSubFloNames(t *testing.T) {
+	f, err := flo.NewFlo("Pipeline", "Pipeline", "Pipeline Description", "flo", "Pipeline Package")
+	require.NoError(t, err)
+
+	addSub := func(sub *flo.Flo) {
+		c, err := f.AddFlo(sub)
+		require.NoError(t, err)
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[1].ID, 1))
+		require.NoError(t, f.SetLiteral(c.ID, c.IOs[2].ID, 2))
+	}
+	addSub(newTestFlo(t))
+	addSub(newTestFlo(t))
+
+	fsys := memFS{}
+	require.NoError(t, f.RenderFS(context.Background(), fsys))
+	require.Len(t, fsys, 2)
+	require.Contains(t, fsys, "test_sync.go")
+
+	other := newTestFlo(t)
+	require.NoError(t, other.SetComponentDescription(componentNamed(other, "CompB").ID, "Other Description"))
+	addSub(other)
+
+	err = f.RenderFS(context.Background(), memFS{})
+	require.EqualError(t, err, `different sub-flos are named "TestSync"`)
+}
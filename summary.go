@@ -0,0 +1,108 @@
+package flo
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// String returns a one line overview of the flo, e.g.
+//
+//	Sum(ctx context.Context, a int, b int) (sum int, err error): 3 components, 5 connections
+func (f *Flo) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return fmt.Sprintf("%s%s: %s", f.Name, f.signature(), f.counts())
+}
+
+// Summary writes a short overview of the flo to w: its name and label, its
+// signature, and its components in the order they were added.
+func (f *Flo) Summary(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s\n", f.Name, f.Label)
+	fmt.Fprintf(&sb, "  func%s\n", f.signature())
+	fmt.Fprintf(&sb, "  %s\n", f.counts())
+	for _, c := range f.orderedComponents() {
+		fmt.Fprintf(&sb, "    %s\n", c)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// signature returns the params and results of the generated function.
+func (f *Flo) signature() string {
+	ins, outs := f.IOs.SeparateINsOUTs()
+	params := make([]string, 0, len(ins))
+	for _, in := range ins {
+		params = append(params, in.Name+" "+in.RType.String())
+	}
+	for _, r := range f.receiverParams() {
+		params = append(params, r.Name+" "+r.Type.String())
+	}
+	results := make([]string, 0, len(outs))
+	for _, out := range outs {
+		results = append(results, out.ResultName+" "+out.RType.String())
+	}
+
+	sig := "(" + strings.Join(params, ", ") + ")"
+	if len(results) > 0 {
+		sig += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return sig
+}
+
+func (f *Flo) counts() string {
+	return fmt.Sprintf("%d components, %d connections", len(f.Components), len(f.connectionIndex))
+}
+
+// String returns a one line overview of the component, e.g.
+//
+//	strconv.Itoa(int) string
+//
+// prefixed with its kind unless it is a plain function call.
+func (c *Component) String() string {
+	var sb strings.Builder
+	if c.Kind != ComponentKindFunc {
+		sb.WriteString(c.Kind.String())
+		sb.WriteString(" ")
+	}
+	if c.PkgPath != "" {
+		sb.WriteString(path.Base(c.PkgPath))
+		sb.WriteString(".")
+	}
+	sb.WriteString(c.Name)
+
+	ins, outs := c.IOs.SeparateINsOUTs()
+	sb.WriteString("(")
+	for i, in := range ins {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(in.RType.String())
+	}
+	sb.WriteString(")")
+	switch len(outs) {
+	case 0:
+	case 1:
+		sb.WriteString(" ")
+		sb.WriteString(outs[0].RType.String())
+	default:
+		sb.WriteString(" (")
+		for i, out := range outs {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(out.RType.String())
+		}
+		sb.WriteString(")")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package flo_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	f, err := flo.NewFlo("Format", "Format", "Format n", "flo", "Test Package")
+	require.NoError(t, err)
+
+	n, s := flo.In[int]("n"), flo.Out[string]("s")
+	require.NoError(t, f.AddIO(n))
+	require.NoError(t, f.AddIO(s))
+
+	itoa, err := flo.NewComponent("Itoa", "strconv", "Itoa", "Format n", strconv.Itoa)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(itoa))
+	require.NoError(t, f.ConnectComponent(f.ID, n.ID, itoa.ID, itoa.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(itoa.ID, itoa.IOs[1].ID, f.ID, s.ID))
+
+	guard, err := flo.NewGuardComponent("Positive", "Positive", "Positive n", "%d is not positive", reflect.TypeFor[int]())
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(guard))
+
+	require.Equal(t, "strconv.Itoa(int) string", itoa.String())
+	require.Equal(t, "GUARD Positive(bool, int)", guard.String())
+	require.Equal(t, "Format(n int) (s string): 2 components, 2 connections", f.String())
+	require.Equal(t, f.String(), fmt.Sprint(f))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Summary(out))
+	require.Equal(t, `Format: Format
+  func(n int) (s string)
+  2 components, 2 connections
+    strconv.Itoa(int) string
+    GUARD Positive(bool, int)
+`, out.String())
+}
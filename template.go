@@ -0,0 +1,212 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// placeholderPkg marks placeholder components. It is not a valid import
+// path so that placeholders can't be mistaken for real functions.
+const placeholderPkg = "flo:placeholder"
+
+// NewPlaceholderComponent creates a typed placeholder for the function
+// param, making its flo a template: a reusable graph, e.g. a generic ETL
+// skeleton, whose placeholders are bound to real functions by Instantiate.
+//
+// fnType is the signature the bound functions must have. Several
+// placeholders may share the same param. Template flos can't be rendered.
+func NewPlaceholderComponent(param string, fnType reflect.Type, label, description string) (*Component, error) {
+	if param == "" {
+		return nil, errors.New("missing param")
+	}
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("placeholder %q needs a function type", param)
+	}
+
+	c := &Component{
+		ID:          uuid.New(),
+		Name:        param,
+		PkgPath:     placeholderPkg,
+		Label:       label,
+		Description: description,
+	}
+	if err := newComponentIOsFromType(c, fnType); err != nil {
+		return nil, fmt.Errorf("cannot generate component ios: %v", err)
+	}
+
+	return c, nil
+}
+
+// IsPlaceholder reports whether c is a placeholder of a template flo.
+func (c *Component) IsPlaceholder() bool {
+	return c.PkgPath == placeholderPkg
+}
+
+// Placeholders returns the sorted params of the placeholders of the flo,
+// empty unless it is a template.
+func (f *Flo) Placeholders() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var params []string
+	for _, c := range f.Components {
+		if c.IsPlaceholder() && !slices.Contains(params, c.Name) {
+			params = append(params, c.Name)
+		}
+	}
+	slices.Sort(params)
+
+	return params
+}
+
+// Instantiate creates the flo name out of the template flo, binding its
+// placeholders to the functions of components given by params, which maps
+// each param to the "pkgPath.Name" a function is registered as, e.g.
+//
+//	etl.Instantiate("ImportUsers", map[string]string{
+//		"extract": "example.com/users.Fetch",
+//		"load":    "example.com/users.Save",
+//	}, components)
+//
+// The new flo shares no component, io nor connection with the template.
+func (f *Flo) Instantiate(name string, params map[string]string, components *ComponentRegistry) (*Flo, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+	if components == nil {
+		return nil, errors.New("missing component registry")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	used := make(map[string]struct{}, len(params))
+	for _, c := range f.orderedComponents() {
+		if !c.IsPlaceholder() {
+			continue
+		}
+		if _, found := params[c.Name]; !found {
+			return nil, fmt.Errorf("missing param %q", c.Name)
+		}
+		used[c.Name] = struct{}{}
+	}
+	for param := range params {
+		if _, found := used[param]; !found {
+			return nil, fmt.Errorf("unknown param %q", param)
+		}
+	}
+
+	instance := f.clone(true)
+	instance.Name = name
+	for _, c := range instance.orderedComponents() {
+		if !c.IsPlaceholder() {
+			continue
+		}
+
+		target := params[c.Name]
+		i := strings.LastIndex(target, ".")
+		if i <= 0 || i == len(target)-1 {
+			return nil, fmt.Errorf("param %q: invalid function %q", c.Name, target)
+		}
+		fn, err := components.Resolve(target[:i], target[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("param %q: %v", c.Name, err)
+		}
+		if err := c.bindValue(reflect.ValueOf(fn)); err != nil {
+			return nil, fmt.Errorf("param %q: %v", c.Name, err)
+		}
+		c.PkgPath, c.Name = target[:i], target[i+1:]
+	}
+	instance.checkInvariants()
+
+	return instance, nil
+}
+
+// clone deep copies the flo, under new ids when renewIDs.
+func (f *Flo) clone(renewIDs bool) *Flo {
+	ids := make(map[uuid.UUID]uuid.UUID)
+	newID := func(id uuid.UUID) uuid.UUID {
+		if !renewIDs || id == uuid.Nil {
+			return id
+		}
+		if n, found := ids[id]; found {
+			return n
+		}
+		n := uuid.New()
+		ids[id] = n
+		return n
+	}
+
+	c := &Flo{
+		ID:              newID(f.ID),
+		Name:            f.Name,
+		Label:           f.Label,
+		Description:     f.Description,
+		PkgName:         f.PkgName,
+		PkgDescription:  f.PkgDescription,
+		Owner:           f.Owner,
+		Team:            f.Team,
+		Components:      make(map[uuid.UUID]*Component, len(f.Components)),
+		connectionIndex: make(map[uuid.UUID]*ComponentConnection, len(f.connectionIndex)),
+		policies:        slices.Clone(f.policies),
+	}
+	conns := make(map[uuid.UUID]*ComponentConnection, len(f.connectionIndex))
+	for id, conn := range f.connectionIndex {
+		cc := *conn
+		cc.ID = newID(conn.ID)
+		cc.OutComponentID, cc.OutComponentIOID = newID(conn.OutComponentID), newID(conn.OutComponentIOID)
+		cc.InComponentID, cc.InComponentIOID = newID(conn.InComponentID), newID(conn.InComponentIOID)
+		conns[id] = &cc
+		c.connectionIndex[cc.ID] = &cc
+	}
+	cloneIOs := func(parentID uuid.UUID, ios IOs) IOs {
+		res := make(IOs, 0, len(ios))
+		for _, io := range ios {
+			cio := *io
+			cio.ID = newID(io.ID)
+			cio.ParentID = parentID
+			cio.Connections = make([]*ComponentConnection, 0, len(io.Connections))
+			for _, conn := range io.Connections {
+				cio.Connections = append(cio.Connections, conns[conn.ID])
+			}
+			res = append(res, &cio)
+		}
+		return res
+	}
+
+	c.IOs = cloneIOs(c.ID, f.IOs)
+	for _, comp := range f.orderedComponents() {
+		cc := *comp
+		cc.ID = newID(comp.ID)
+		cc.IOs = cloneIOs(cc.ID, comp.IOs)
+		cc.TypeArgs = slices.Clone(comp.TypeArgs)
+		cc.Variants = slices.Clone(comp.Variants)
+		if comp.Cache != nil {
+			aside := *comp.Cache
+			cc.Cache = &aside
+		}
+		if comp.Flag != nil {
+			gate := *comp.Flag
+			cc.Flag = &gate
+		}
+		if comp.Receiver != nil {
+			r := *comp.Receiver
+			cc.Receiver = &r
+		}
+		if comp.Sub != nil {
+			cc.Sub = comp.Sub.clone(false)
+		}
+		c.Components[cc.ID] = &cc
+		c.componentOrder = append(c.componentOrder, cc.ID)
+	}
+	for _, conn := range f.Sequences {
+		c.Sequences = append(c.Sequences, conns[conn.ID])
+	}
+
+	return c
+}
@@ -0,0 +1,84 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	_, err := flo.NewPlaceholderComponent("", reflect.TypeFor[func(string) string](), "Map", "Map")
+	require.ErrorContains(t, err, "missing param")
+	_, err = flo.NewPlaceholderComponent("map", reflect.TypeFor[string](), "Map", "Map")
+	require.ErrorContains(t, err, `placeholder "map" needs a function type`)
+
+	tmpl, err := flo.NewFlo("Clean", "Clean", "Clean Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, out := flo.In[string]("s"), flo.Out[string]("res")
+	require.NoError(t, tmpl.AddIO(in))
+	require.NoError(t, tmpl.AddIO(out))
+
+	mapper, err := flo.NewPlaceholderComponent("map", reflect.TypeFor[func(string) string](), "Map", "Map Description")
+	require.NoError(t, err)
+	require.NoError(t, tmpl.AddComponent(mapper))
+	trim, err := flo.NewComponent("TrimSpace", "strings", "Trim", "Trim Description", strings.TrimSpace)
+	require.NoError(t, err)
+	require.NoError(t, tmpl.AddComponent(trim))
+
+	require.NoError(t, tmpl.ConnectComponent(tmpl.ID, in.ID, mapper.ID, mapper.IOs[0].ID))
+	require.NoError(t, tmpl.ConnectComponent(mapper.ID, mapper.IOs[1].ID, trim.ID, trim.IOs[0].ID))
+	require.NoError(t, tmpl.ConnectComponent(trim.ID, trim.IOs[1].ID, tmpl.ID, out.ID))
+	require.Equal(t, []string{"map"}, tmpl.Placeholders())
+
+	components := flo.NewComponentRegistry()
+	require.NoError(t, components.Register("strings", "ToUpper", strings.ToUpper))
+	require.NoError(t, components.Register("strings", "Fields", strings.Fields))
+
+	t.Run("Render template", func(t *testing.T) {
+		err := tmpl.Render(context.Background(), &bytes.Buffer{})
+		require.ErrorContains(t, err, `is the placeholder "map" of a template flo`)
+	})
+
+	t.Run("Instantiate", func(t *testing.T) {
+		f, err := tmpl.Instantiate("Upper", map[string]string{"map": "strings.ToUpper"}, components)
+		require.NoError(t, err)
+		require.NoError(t, f.Invariants())
+		require.Empty(t, f.Placeholders())
+		require.NotEqual(t, tmpl.ID, f.ID)
+		for id := range f.Components {
+			_, found := tmpl.Components[id]
+			require.False(t, found)
+		}
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), buf))
+		code := buf.String()
+		require.Contains(t, code, "func Upper(s string)")
+		require.Contains(t, code, "strings.ToUpper(s)")
+		require.Contains(t, code, "strings.TrimSpace(")
+
+		// The template is left untouched.
+		require.Equal(t, []string{"map"}, tmpl.Placeholders())
+		require.Equal(t, "Clean", tmpl.Name)
+		require.NoError(t, tmpl.Invariants())
+	})
+
+	t.Run("Invalid params", func(t *testing.T) {
+		_, err := tmpl.Instantiate("Upper", nil, components)
+		require.ErrorContains(t, err, `missing param "map"`)
+		_, err = tmpl.Instantiate("Upper", map[string]string{"map": "strings.ToUpper", "load": "strings.ToUpper"}, components)
+		require.ErrorContains(t, err, `unknown param "load"`)
+		_, err = tmpl.Instantiate("Upper", map[string]string{"map": "ToUpper"}, components)
+		require.ErrorContains(t, err, `invalid function "ToUpper"`)
+		_, err = tmpl.Instantiate("Upper", map[string]string{"map": "strings.ToLower"}, components)
+		require.ErrorContains(t, err, `param "map"`)
+		_, err = tmpl.Instantiate("Upper", map[string]string{"map": "strings.Fields"}, components)
+		require.ErrorContains(t, err, "result 1 is []string but component expects string")
+	})
+}
@@ -0,0 +1,120 @@
+package flo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/mgjules/flo/flotime"
+)
+
+// flotimePkg holds the functions backing the time components.
+const flotimePkg = "github.com/mgjules/flo/flotime"
+
+// timeComponent is a built-in time component.
+type timeComponent struct {
+	fn      any
+	effects Effects
+	// code renders the component inline given its args.
+	code func(args []jen.Code) jen.Code
+}
+
+var timeComponents = map[string]timeComponent{
+	"Now": {flotime.Now, EffectReadsState, func([]jen.Code) jen.Code {
+		return jen.Qual("time", "Now").Call()
+	}},
+	"Since": {flotime.Since, EffectReadsState, func(args []jen.Code) jen.Code {
+		return jen.Qual("time", "Since").Call(args...)
+	}},
+	"Until": {flotime.Until, EffectReadsState, func(args []jen.Code) jen.Code {
+		return jen.Qual("time", "Until").Call(args...)
+	}},
+	"Sleep": {flotime.Sleep, EffectReadsState, func(args []jen.Code) jen.Code {
+		return jen.Func().Params().Error().Block(
+			jen.Id("timer").Op(":=").Qual("time", "NewTimer").Call(args[1]),
+			jen.Defer().Id("timer").Dot("Stop").Call(),
+			jen.Select().Block(
+				jen.Case(jen.Op("<-").Id("timer").Dot("C")).Block(jen.Return(jen.Nil())),
+				jen.Case(jen.Op("<-").Add(args[0]).Dot("Done").Call()).Block(jen.Return(jen.Add(args[0]).Dot("Err").Call())),
+			),
+		).Call()
+	}},
+	"Deadline": {flotime.Deadline, EffectPure, func(args []jen.Code) jen.Code {
+		return jen.Add(args[0]).Dot("Deadline").Call()
+	}},
+	"Expired": {flotime.Expired, EffectReadsState, func(args []jen.Code) jen.Code {
+		return jen.Op("!").Qual("time", "Now").Call().Dot("Before").Call(args...)
+	}},
+	"Add": {flotime.Add, EffectPure, func(args []jen.Code) jen.Code {
+		return jen.Add(args[0]).Dot("Add").Call(args[1])
+	}},
+	"Sub": {flotime.Sub, EffectPure, func(args []jen.Code) jen.Code {
+		return jen.Add(args[0]).Dot("Sub").Call(args[1])
+	}},
+	"AddDuration": {flotime.AddDuration, EffectPure, func(args []jen.Code) jen.Code {
+		return jen.Add(args[0]).Op("+").Add(args[1])
+	}},
+	"ScaleDuration": {flotime.ScaleDuration, EffectPure, func(args []jen.Code) jen.Code {
+		return jen.Add(args[0]).Op("*").Add(typeCode(durationRType)).Call(args[1])
+	}},
+}
+
+// NewTimeComponent creates the time component name, rendered inline rather
+// than as a call so that flos need no trivial wrappers around the time
+// package:
+//
+//   - Now: time.Now()
+//   - Since, Until: time.Since(t), time.Until(t)
+//   - Sleep: pauses for a duration unless the context is done first
+//   - Deadline: ctx.Deadline()
+//   - Expired: whether a deadline has passed
+//   - Add, Sub: t.Add(d), t.Sub(u)
+//   - AddDuration, ScaleDuration: d + e, d * time.Duration(n)
+//
+// See the flotime package for their signatures.
+func NewTimeComponent(name string, label, description string) (*Component, error) {
+	tc, found := timeComponents[name]
+	if !found {
+		return nil, fmt.Errorf("unknown time component %q", name)
+	}
+
+	c, err := NewComponent(name, flotimePkg, label, description, tc.fn)
+	if err != nil {
+		return nil, err
+	}
+	c.Effects = tc.effects
+
+	return c, nil
+}
+
+// timeFunc returns the function backing a time component.
+func timeFunc(name string) (reflect.Value, error) {
+	tc, found := timeComponents[name]
+	if !found {
+		return reflect.Value{}, fmt.Errorf("unknown time component %q", name)
+	}
+
+	return reflect.ValueOf(tc.fn), nil
+}
+
+// timeCode renders c, a time component, inline. ok is false when c is not
+// a time component or is stubbed.
+func timeCode(c *Component, o renderOptions, ins IOs, args []jen.Code) (code jen.Code, ok bool) {
+	if _, stubbed := o.stubs[c.ID]; stubbed || c.PkgPath != flotimePkg {
+		return nil, false
+	}
+	tc, found := timeComponents[c.Name]
+	if !found {
+		return nil, false
+	}
+
+	operands := make([]jen.Code, len(args))
+	for i, arg := range args {
+		operands[i] = arg
+		if i < len(ins) && hasExprTransform(ins[i]) {
+			operands[i] = jen.Parens(arg)
+		}
+	}
+
+	return tc.code(operands), true
+}
@@ -0,0 +1,95 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeComponents(t *testing.T) {
+	_, err := flo.NewTimeComponent("Tomorrow", "Tomorrow", "Tomorrow")
+	require.ErrorContains(t, err, `unknown time component "Tomorrow"`)
+
+	f, err := flo.NewFlo("TestTime", "Test Time", "Test Time Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	ctx, start, delay := flo.In[context.Context]("ctx"), flo.In[time.Time]("start"), flo.In[time.Duration]("delay")
+	elapsed, total, rErr := flo.Out[time.Duration]("elapsed"), flo.Out[time.Duration]("total"), flo.Out[error]("err")
+	for _, io := range []*flo.ComponentIO{ctx, start, delay, elapsed, total, rErr} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	newTime := func(name string) *flo.Component {
+		c, err := flo.NewTimeComponent(name, name, name+" Description")
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		return c
+	}
+	sleep, now, sub, sum, expired := newTime("Sleep"), newTime("Now"), newTime("Sub"), newTime("AddDuration"), newTime("Expired")
+	require.Equal(t, flo.EffectPure, sum.Effects)
+	require.Equal(t, flo.EffectReadsState, now.Effects)
+
+	require.NoError(t, f.ConnectComponent(f.ID, ctx.ID, sleep.ID, sleep.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, delay.ID, sleep.ID, sleep.IOs[1].ID))
+	require.NoError(t, f.ConnectSequence(sleep.ID, now.ID))
+	require.NoError(t, f.ConnectComponent(now.ID, now.IOs[0].ID, sub.ID, sub.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(f.ID, start.ID, sub.ID, sub.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(sub.ID, sub.IOs[2].ID, f.ID, elapsed.ID))
+
+	double, err := flo.NewExprTransform("$ * 2", reflect.TypeFor[time.Duration](), reflect.TypeFor[time.Duration]())
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectComponentWithTransform(f.ID, delay.ID, sum.ID, sum.IOs[0].ID, double))
+	require.NoError(t, f.ConnectComponent(f.ID, delay.ID, sum.ID, sum.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(sum.ID, sum.IOs[2].ID, f.ID, total.ID))
+	require.NoError(t, f.ConnectComponent(f.ID, start.ID, expired.ID, expired.IOs[0].ID))
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		code := out.String()
+		require.NotContains(t, code, "flotime")
+		require.Contains(t, code, `err := func() error {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}()`)
+		require.Contains(t, code, ` := time.Now()`)
+		require.Contains(t, code, `.Sub(start)`)
+		require.Contains(t, code, ` := (delay * 2) + delay`)
+		require.Contains(t, code, `_ = !time.Now().Before(start)`)
+	})
+
+	t.Run("Compiles", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("runs the go tool")
+		}
+
+		res, err := f.CompileCheck(context.Background())
+		require.NoError(t, err)
+		require.True(t, res.OK(), res.Build.Output+res.Vet.Output)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeBinary(buf, nil))
+		decoded, err := flo.DecodeBinary(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+		for _, c := range decoded.Components {
+			require.True(t, c.IsBound())
+		}
+
+		res := decoded.Components[sum.ID].Value.Call([]reflect.Value{reflect.ValueOf(time.Second), reflect.ValueOf(time.Minute)})
+		require.Equal(t, time.Second+time.Minute, res[0].Interface())
+	})
+}
@@ -0,0 +1,300 @@
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/google/uuid"
+)
+
+// transformPlaceholder stands for the transformed value in expressions.
+const transformPlaceholder = "$"
+
+// Transform massages a value on its way through a connection, e.g. trimming
+// a string or converting units, without needing a full component.
+// It is either a function call or an inline expression.
+type Transform struct {
+	Name    string
+	PkgPath string
+	Expr    string
+	Imports []string // Packages Expr refers to by name, e.g. "time".
+	In      reflect.Type
+	Out     reflect.Type
+	Value   reflect.Value
+}
+
+// NewTransform creates a transform calling fn which must be of the form
+// func(A) B.
+func NewTransform(name, pkgPath string, fn any) (*Transform, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("value of kind %q is not a function", v.Kind())
+	}
+	vt := v.Type()
+	if vt.NumIn() != 1 || vt.NumOut() != 1 || vt.IsVariadic() {
+		return nil, errors.New("transform must take exactly one argument and return exactly one value")
+	}
+
+	return &Transform{
+		Name:    name,
+		PkgPath: pkgPath,
+		In:      vt.In(0),
+		Out:     vt.Out(0),
+		Value:   v,
+	}, nil
+}
+
+// NewExprTransform creates a transform rendered as the given Go expression
+// where "$" stands for the transformed value, e.g. "$ * 1000" or
+// "float64($)". The packages the expression refers to, e.g.
+// "time.Duration($)", are listed by import path in imports so that they
+// get imported.
+func NewExprTransform(expr string, in, out reflect.Type, imports ...string) (*Transform, error) {
+	tokens := exprTokens(expr)
+	var (
+		sb             strings.Builder
+		hasPlaceholder bool
+	)
+	for _, tok := range tokens {
+		if tok.isPlaceholder() {
+			hasPlaceholder = true
+			tok.lit = "v"
+		}
+		sb.WriteString(tok.lit)
+		sb.WriteString(" ")
+	}
+	if !hasPlaceholder {
+		return nil, fmt.Errorf("expression %q does not use %q", expr, transformPlaceholder)
+	}
+	if _, err := parser.ParseExpr(sb.String()); err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", expr, err)
+	}
+	if in == nil || out == nil {
+		return nil, errors.New("missing transform types")
+	}
+	for _, pkgPath := range imports {
+		if name := path.Base(pkgPath); !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("invalid import %q", pkgPath)
+		}
+	}
+
+	return &Transform{
+		Expr:    expr,
+		Imports: imports,
+		In:      in,
+		Out:     out,
+	}, nil
+}
+
+// exprToken is a token of an expression transform.
+type exprToken struct {
+	tok token.Token
+	lit string
+}
+
+func (t exprToken) isPlaceholder() bool {
+	return t.tok == token.ILLEGAL && t.lit == transformPlaceholder
+}
+
+// exprTokens splits expr into Go tokens, so that the placeholder is told
+// apart from the same character in string and rune literals.
+func exprTokens(expr string) []exprToken {
+	src := []byte(expr)
+	fset := token.NewFileSet()
+	var s scanner.Scanner
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+
+	var tokens []exprToken
+	for {
+		_, tok, lit := s.Scan()
+		switch {
+		case tok == token.EOF:
+			return tokens
+		case tok == token.SEMICOLON && lit == "\n":
+			// Inserted at the end of the expression.
+			continue
+		case lit == "":
+			lit = tok.String()
+		}
+		tokens = append(tokens, exprToken{tok: tok, lit: lit})
+	}
+}
+
+// ConnectComponentWithTransform is like ConnectComponent but passes the value
+// through the transform t.
+func (f *Flo) ConnectComponentWithTransform(
+	outComponentID, outComponentIOID uuid.UUID,
+	inComponentID, inComponentIOID uuid.UUID,
+	t *Transform,
+) error {
+	if outComponentID == uuid.Nil {
+		return errors.New("invalid out component id")
+	}
+	if outComponentIOID == uuid.Nil {
+		return errors.New("invalid out component io id")
+	}
+	if inComponentID == uuid.Nil {
+		return errors.New("invalid in component id")
+	}
+	if inComponentIOID == uuid.Nil {
+		return errors.New("invalid in component io id")
+	}
+	if t == nil {
+		return errors.New("missing transform")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.checkInvariants()
+
+	return f.connectComponent(
+		outComponentID, outComponentIOID,
+		inComponentID, inComponentIOID,
+		t,
+	)
+}
+
+// SetConnectionTransform replaces the transform of an existing connection.
+// A nil transform removes it.
+func (f *Flo) SetConnectionTransform(connectionID uuid.UUID, t *Transform) error {
+	if connectionID == uuid.Nil {
+		return errors.New("invalid connection id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	conn, found := f.connectionIndex[connectionID]
+	if !found {
+		return fmt.Errorf("unknown connection id %q", connectionID)
+	}
+	if conn.Kind != ComponentConnectionKindData {
+		return fmt.Errorf("connection id %q carries no data", connectionID)
+	}
+
+	outIO, found := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on out component id %q", conn.OutComponentIOID, conn.OutComponentID)
+	}
+	inIO, found := f.componentIO(conn.InComponentID, conn.InComponentIOID)
+	if !found {
+		return fmt.Errorf("no component io id %q found on in component id %q", conn.InComponentIOID, conn.InComponentID)
+	}
+
+	if t != nil {
+		if err := t.check(outIO, inIO); err != nil {
+			return fmt.Errorf("invalid transform for connection id %q: %v", connectionID, err)
+		}
+	} else if !inIO.IsSignal && !outIO.RType.AssignableTo(inIO.acceptedType()) {
+		return fmt.Errorf(
+			"out component io id %q cannot be assigned to component io id %q",
+			outIO.ID,
+			inIO.ID,
+		)
+	}
+
+	conn.Transform = t
+	f.markDirty(conn.InComponentID)
+
+	return nil
+}
+
+// componentIO finds an io of either a component or the flo itself.
+func (f *Flo) componentIO(componentID, ioID uuid.UUID) (*ComponentIO, bool) {
+	if componentID == f.ID {
+		return f.IOs.GetByID(ioID)
+	}
+
+	c, found := f.Components[componentID]
+	if !found {
+		return nil, false
+	}
+
+	return c.IOs.GetByID(ioID)
+}
+
+func (t *Transform) check(out, in *ComponentIO) error {
+	if out.IsSignal || in.IsSignal {
+		return errors.New("signals cannot be transformed")
+	}
+	if !out.RType.AssignableTo(t.In) {
+		return fmt.Errorf("transform expects %s but got %s", t.In, out.RType)
+	}
+	if !t.Out.AssignableTo(in.acceptedType()) {
+		return fmt.Errorf("transform returns %s but %s is expected", t.Out, in.acceptedType())
+	}
+
+	return nil
+}
+
+func (t *Transform) render(name string) jen.Code {
+	if t.Expr != "" {
+		return t.exprCode(name)
+	}
+
+	return qual(t.PkgPath, t.Name).Call(jen.Id(name))
+}
+
+// exprCode renders the expression of t applied to name, qualifying the
+// references to its imports.
+func (t *Transform) exprCode(name string) jen.Code {
+	pkgs := make(map[string]string, len(t.Imports))
+	for _, pkgPath := range t.Imports {
+		pkgs[path.Base(pkgPath)] = pkgPath
+	}
+
+	tokens := exprTokens(t.Expr)
+	code := jen.Null()
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		pkgPath, found := pkgs[tok.lit]
+		switch {
+		case tok.isPlaceholder():
+			code.Id(name)
+		case found && tok.tok == token.IDENT &&
+			(i == 0 || tokens[i-1].tok != token.PERIOD) &&
+			i+2 < len(tokens) && tokens[i+1].tok == token.PERIOD && tokens[i+2].tok == token.IDENT:
+			code.Qual(pkgPath, tokens[i+2].lit)
+			i += 2
+		default:
+			code.Op(tok.lit)
+		}
+	}
+
+	return code
+}
+
+// inValue is the code to use for the value of an IN io, applying the
+// transform of its connection if any.
+func inValue(in *ComponentIO) jen.Code {
+	for _, conn := range in.Connections {
+		if conn.Transform != nil {
+			return conn.Transform.render(in.Name)
+		}
+	}
+
+	return jen.Id(in.Name)
+}
+
+// hasExprTransform reports whether the value of in goes through an
+// expression transform, which needs parentheses to be used as an operand.
+func hasExprTransform(in *ComponentIO) bool {
+	for _, conn := range in.Connections {
+		if conn.Transform != nil && conn.Transform.Expr != "" {
+			return true
+		}
+	}
+
+	return false
+}
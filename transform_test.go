@@ -0,0 +1,149 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func lenFn(s string) int {
+	return len(s)
+}
+
+func TestTransform(t *testing.T) {
+	t.Run("Invalid transforms", func(t *testing.T) {
+		_, err := flo.NewTransform("Itoa", "strconv", 42)
+		require.ErrorContains(t, err, "not a function")
+
+		_, err = flo.NewTransform("Cut", "strings", strings.Cut)
+		require.ErrorContains(t, err, "exactly one argument")
+
+		_, err = flo.NewExprTransform("v * 1000", reflect.TypeFor[int](), reflect.TypeFor[int]())
+		require.ErrorContains(t, err, "does not use")
+
+		_, err = flo.NewExprTransform("$ * ", reflect.TypeFor[int](), reflect.TypeFor[int]())
+		require.ErrorContains(t, err, "invalid expression")
+
+		_, err = flo.NewExprTransform(`"$" + "!"`, reflect.TypeFor[string](), reflect.TypeFor[string]())
+		require.ErrorContains(t, err, "does not use")
+
+		_, err = flo.NewExprTransform("time.Duration($)", reflect.TypeFor[int](), reflect.TypeFor[int64](), "github.com/acme/my-time")
+		require.ErrorContains(t, err, `invalid import "github.com/acme/my-time"`)
+	})
+
+	f, err := flo.NewFlo("TestTransform", "Test Transform", "Test Transform Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[string](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+
+	trim, err := flo.NewTransform("TrimSpace", "strings", strings.TrimSpace)
+	require.NoError(t, err)
+
+	itoa, err := flo.NewTransform("Itoa", "strconv", strconv.Itoa)
+	require.NoError(t, err)
+
+	milli, err := flo.NewExprTransform("$ * 1000", reflect.TypeFor[int](), reflect.TypeFor[int]())
+	require.NoError(t, err)
+
+	t.Run("Cannot connect mismatching transform", func(t *testing.T) {
+		err := f.ConnectComponentWithTransform(f.ID, pIn.ID, length.ID, length.IOs[0].ID, itoa)
+		require.ErrorContains(t, err, "transform expects int but got string")
+	})
+
+	require.NoError(t, f.ConnectComponentWithTransform(f.ID, pIn.ID, length.ID, length.IOs[0].ID, trim))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rOut.ID))
+
+	t.Run("Set connection transform", func(t *testing.T) {
+		connID := rOut.Connections[0].ID
+
+		err := f.SetConnectionTransform(connID, itoa)
+		require.ErrorContains(t, err, "transform returns string but int is expected")
+
+		require.NoError(t, f.SetConnectionTransform(connID, milli))
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), out))
+		require.Equal(t, `// Code generated by flo. Do not edit!
+
+// Test Package
+package flo
+
+import (
+	tera "githab.com/testuf/tera"
+	"strings"
+)
+
+func TestTransform(in string) int {
+	// Len Description
+	io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd := tera.Len(strings.TrimSpace(in))
+
+	return io99Baa22D0Df0Cb83442Cabf035Ac214126910Edd * 1000
+}
+`, out.String())
+	})
+}
+
+func TestExprTransform(t *testing.T) {
+	f, err := flo.NewFlo("TestExpr", "Test Expr", "Test Expr Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pIn, err := flo.NewComponentIO("in", flo.ComponentIOTypeIN, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(pIn))
+
+	rOut, err := flo.NewComponentIO("out", flo.ComponentIOTypeOUT, reflect.TypeFor[int](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rOut))
+
+	length, err := flo.NewComponent("Len", "githab.com/testuf/tera", "Len", "Len Description", lenFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(length))
+	require.NoError(t, f.ConnectComponent(length.ID, length.IOs[1].ID, f.ID, rOut.ID))
+
+	format, err := flo.NewExprTransform(
+		`fmt.Sprintf("$%d '$'", $) + time.Duration($).String()`,
+		reflect.TypeFor[int](), reflect.TypeFor[string](),
+		"fmt", "time",
+	)
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectComponentWithTransform(f.ID, pIn.ID, length.ID, length.IOs[0].ID, format))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Contains(t, out.String(), `import (
+	"fmt"
+	tera "githab.com/testuf/tera"
+	"time"
+)`)
+	require.Contains(t, out.String(), `tera.Len(fmt.Sprintf("$%d '$'", in) + time.Duration(in).String())`)
+
+	t.Run("Decoded", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.EncodeJSON(buf, nil))
+		decoded, err := flo.DecodeJSON(bytes.NewReader(buf.Bytes()), nil, nil)
+		require.NoError(t, err)
+		require.True(t, flo.Equal(f, decoded))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, decoded.Render(context.Background(), out))
+		require.Contains(t, out.String(), `"time"`)
+	})
+}
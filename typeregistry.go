@@ -0,0 +1,557 @@
+package flo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mgjules/flo/flobroker"
+	"github.com/mgjules/flo/flocache"
+	"github.com/mgjules/flo/floflag"
+	"github.com/mgjules/flo/flosecret"
+)
+
+// TypeRegistry maps stable type names to reflect.Type so that types can be
+// persisted and resolved back later on.
+//
+// Only named types need to be registered: composite types such as
+// "[]time.Time" or "map[string]*net/url.URL" are resolved from their parts.
+type TypeRegistry struct {
+	mu      sync.RWMutex
+	types   map[string]reflect.Type
+	names   map[reflect.Type]string
+	aliases map[reflect.Type]typeAlias
+}
+
+// typeAlias is how a type is written in generated code instead of with its
+// own package and name.
+type typeAlias struct {
+	pkgPath string
+	name    string
+}
+
+// DefaultTypeRegistry is used by RegisterType.
+var DefaultTypeRegistry = NewTypeRegistry()
+
+// NewTypeRegistry creates a registry knowing about the builtin types and a
+// few commonly used stdlib types.
+func NewTypeRegistry() *TypeRegistry {
+	r := &TypeRegistry{
+		types:   make(map[string]reflect.Type),
+		names:   make(map[reflect.Type]string),
+		aliases: make(map[reflect.Type]typeAlias),
+	}
+
+	for _, t := range []reflect.Type{
+		reflect.TypeFor[bool](),
+		reflect.TypeFor[string](),
+		reflect.TypeFor[int](),
+		reflect.TypeFor[int8](),
+		reflect.TypeFor[int16](),
+		reflect.TypeFor[int32](),
+		reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](),
+		reflect.TypeFor[uint8](),
+		reflect.TypeFor[uint16](),
+		reflect.TypeFor[uint32](),
+		reflect.TypeFor[uint64](),
+		reflect.TypeFor[uintptr](),
+		reflect.TypeFor[float32](),
+		reflect.TypeFor[float64](),
+		reflect.TypeFor[complex64](),
+		reflect.TypeFor[complex128](),
+		reflect.TypeFor[any](),
+		reflect.TypeFor[error](),
+		reflect.TypeFor[context.Context](),
+		reflect.TypeFor[time.Time](),
+		reflect.TypeFor[time.Duration](),
+		reflect.TypeFor[io.Reader](),
+		reflect.TypeFor[io.Writer](),
+		reflect.TypeFor[io.ReadCloser](),
+		reflect.TypeFor[json.RawMessage](),
+		reflect.TypeFor[url.URL](),
+		reflect.TypeFor[http.Header](),
+		reflect.TypeFor[http.Request](),
+		reflect.TypeFor[http.Response](),
+		reflect.TypeFor[http.Client](),
+		reflect.TypeFor[sql.DB](),
+		reflect.TypeFor[flocache.Cache](),
+		reflect.TypeFor[flobroker.Broker](),
+		reflect.TypeFor[floflag.Provider](),
+		reflect.TypeFor[flosecret.Provider](),
+		reflect.TypeFor[flosecret.Secret](),
+		reflect.TypeFor[Signal](),
+	} {
+		// Builtins can't conflict.
+		_ = r.Register(TypeName(t), t)
+	}
+	_ = r.Register("byte", reflect.TypeFor[byte]())
+	_ = r.Register("rune", reflect.TypeFor[rune]())
+
+	return r
+}
+
+// RegisterType registers T in the DefaultTypeRegistry under its TypeName.
+func RegisterType[T any]() (string, error) {
+	t := reflect.TypeFor[T]()
+	name := TypeName(t)
+
+	return name, DefaultTypeRegistry.Register(name, t)
+}
+
+// RegisterAlias registers T in the DefaultTypeRegistry as the alias
+// pkgPath.name. See TypeRegistry.RegisterAlias.
+func RegisterAlias[T any](pkgPath, name string) error {
+	return DefaultTypeRegistry.RegisterAlias(pkgPath, name, reflect.TypeFor[T]())
+}
+
+// TypeName is the default stable name of t, e.g. "int", "[]time.Time" or
+// "github.com/mgjules/flo.Signal".
+func TypeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	switch {
+	case t == reflect.TypeFor[any]():
+		return "any"
+	case t == reflect.TypeFor[error]():
+		return "error"
+	case t.Name() != "" && t.PkgPath() != "":
+		return t.PkgPath() + "." + t.Name()
+	case t.Name() != "":
+		return t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + TypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + TypeName(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + TypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + TypeName(t.Key()) + "]" + TypeName(t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + TypeName(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + TypeName(t.Elem())
+		default:
+			return "chan " + TypeName(t.Elem())
+		}
+	default:
+		return t.String()
+	}
+}
+
+// Register makes t resolvable by name.
+func (r *TypeRegistry) Register(name string, t reflect.Type) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	if t == nil {
+		return errors.New("missing type")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, found := r.types[name]; found {
+		if existing == t {
+			return nil
+		}
+		return fmt.Errorf("type name %q already registered for %s", name, existing)
+	}
+
+	r.types[name] = t
+	if _, found := r.names[t]; !found {
+		r.names[t] = name
+	}
+
+	return nil
+}
+
+// RegisterAlias makes t resolvable as pkgPath.name, and makes the
+// generated code refer to t that way, for aliases such as
+//
+//	type Token = internal.Token
+//
+// whose target can't be imported, or isn't exported, where the generated
+// code lives. Aliases of DefaultTypeRegistry are used by Render.
+func (r *TypeRegistry) RegisterAlias(pkgPath, name string, t reflect.Type) error {
+	if pkgPath == "" {
+		return errors.New("missing pkg path")
+	}
+	if !token.IsExported(name) {
+		return fmt.Errorf("alias name %q is not exported", name)
+	}
+
+	r.mu.RLock()
+	existing, found := r.aliases[t]
+	r.mu.RUnlock()
+	alias := typeAlias{pkgPath: pkgPath, name: name}
+	if found && existing != alias {
+		return fmt.Errorf("type %s already aliased as %s.%s", t, existing.pkgPath, existing.name)
+	}
+
+	if err := r.Register(pkgPath+"."+name, t); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.aliases[t] = alias
+
+	return nil
+}
+
+// alias returns how t is written in generated code, when aliased.
+func (r *TypeRegistry) alias(t reflect.Type) (typeAlias, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alias, found := r.aliases[t]
+	return alias, found
+}
+
+// Lookup resolves a type by name.
+func (r *TypeRegistry) Lookup(name string) (reflect.Type, bool) {
+	t, err := r.Resolve(name)
+	return t, err == nil
+}
+
+// Resolve is like Lookup but tells why the type could not be resolved.
+func (r *TypeRegistry) Resolve(name string) (reflect.Type, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("missing type name")
+	}
+
+	r.mu.RLock()
+	t, found := r.types[name]
+	r.mu.RUnlock()
+	if found {
+		return t, nil
+	}
+
+	switch {
+	case strings.HasPrefix(name, "*"):
+		elem, err := r.Resolve(name[1:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+	case strings.HasPrefix(name, "[]"):
+		elem, err := r.Resolve(name[2:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case strings.HasPrefix(name, "["):
+		end := strings.Index(name, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid array type %q", name)
+		}
+		n, err := strconv.Atoi(name[1:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid array length in %q", name)
+		}
+		elem, err := r.Resolve(name[end+1:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ArrayOf(n, elem), nil
+	case strings.HasPrefix(name, "map["):
+		end := matchingBracket(name, len("map"))
+		if end < 0 {
+			return nil, fmt.Errorf("invalid map type %q", name)
+		}
+		key, err := r.Resolve(name[len("map["):end])
+		if err != nil {
+			return nil, err
+		}
+		elem, err := r.Resolve(name[end+1:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, elem), nil
+	case strings.HasPrefix(name, "<-chan "):
+		elem, err := r.Resolve(name[len("<-chan "):])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ChanOf(reflect.RecvDir, elem), nil
+	case strings.HasPrefix(name, "chan<- "):
+		elem, err := r.Resolve(name[len("chan<- "):])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ChanOf(reflect.SendDir, elem), nil
+	case strings.HasPrefix(name, "chan "):
+		elem, err := r.Resolve(name[len("chan "):])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ChanOf(reflect.BothDir, elem), nil
+	case strings.HasPrefix(name, "func("):
+		return r.resolveFunc(name)
+	case strings.HasPrefix(name, "struct {") && strings.HasSuffix(name, "}"):
+		return r.resolveStruct(name)
+	}
+
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+// Name returns the name under which t can be resolved.
+func (r *TypeRegistry) Name(t reflect.Type) (string, error) {
+	if t == nil {
+		return "", errors.New("missing type")
+	}
+
+	r.mu.RLock()
+	name, found := r.names[t]
+	r.mu.RUnlock()
+	if found {
+		return name, nil
+	}
+
+	if t.Name() != "" {
+		return "", fmt.Errorf("type %s is not registered", t)
+	}
+
+	var (
+		elem string
+		err  error
+	)
+	if t.Kind() == reflect.Pointer ||
+		t.Kind() == reflect.Slice ||
+		t.Kind() == reflect.Array ||
+		t.Kind() == reflect.Map ||
+		t.Kind() == reflect.Chan {
+		elem, err = r.Name(t.Elem())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + elem, nil
+	case reflect.Slice:
+		return "[]" + elem, nil
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + elem, nil
+	case reflect.Map:
+		key, err := r.Name(t.Key())
+		if err != nil {
+			return "", err
+		}
+		return "map[" + key + "]" + elem, nil
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem, nil
+		case reflect.SendDir:
+			return "chan<- " + elem, nil
+		default:
+			return "chan " + elem, nil
+		}
+	case reflect.Func:
+		return r.funcName(t)
+	case reflect.Struct:
+		return r.structName(t)
+	default:
+		return "", fmt.Errorf("type %s is not supported", t)
+	}
+}
+
+// funcName names the func type t, e.g. "func(int, ...string) (bool, error)".
+func (r *TypeRegistry) funcName(t reflect.Type) (string, error) {
+	names := func(n int, typ func(int) reflect.Type) ([]string, error) {
+		res := make([]string, 0, n)
+		for i := range n {
+			name, err := r.Name(typ(i))
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, name)
+		}
+		return res, nil
+	}
+
+	in, err := names(t.NumIn(), t.In)
+	if err != nil {
+		return "", err
+	}
+	if t.IsVariadic() {
+		elem, err := r.Name(t.In(t.NumIn() - 1).Elem())
+		if err != nil {
+			return "", err
+		}
+		in[len(in)-1] = "..." + elem
+	}
+	out, err := names(t.NumOut(), t.Out)
+	if err != nil {
+		return "", err
+	}
+
+	name := "func(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+		return name, nil
+	case 1:
+		return name + " " + out[0], nil
+	default:
+		return name + " (" + strings.Join(out, ", ") + ")", nil
+	}
+}
+
+// structName names the struct type t, e.g. "struct { A int; B string }".
+// Only exported fields without tags are supported.
+func (r *TypeRegistry) structName(t reflect.Type) (string, error) {
+	if t.NumField() == 0 {
+		return "struct {}", nil
+	}
+
+	fields := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous || field.Tag != "" {
+			return "", fmt.Errorf("type %s is not supported", t)
+		}
+		name, err := r.Name(field.Type)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, field.Name+" "+name)
+	}
+
+	return "struct { " + strings.Join(fields, "; ") + " }", nil
+}
+
+// resolveFunc resolves a func type named by funcName.
+func (r *TypeRegistry) resolveFunc(name string) (reflect.Type, error) {
+	end := matchingBracket(name, len("func"))
+	if end < 0 {
+		return nil, fmt.Errorf("invalid func type %q", name)
+	}
+
+	var (
+		in, out  []reflect.Type
+		variadic bool
+	)
+	for _, param := range splitTopLevel(name[len("func("):end], ", ") {
+		if variadic {
+			return nil, fmt.Errorf("invalid func type %q", name)
+		}
+		if elem, found := strings.CutPrefix(param, "..."); found {
+			variadic = true
+			param = "[]" + elem
+		}
+		t, err := r.Resolve(param)
+		if err != nil {
+			return nil, err
+		}
+		in = append(in, t)
+	}
+
+	results := strings.TrimSpace(name[end+1:])
+	if strings.HasPrefix(results, "(") {
+		if matchingBracket(results, 0) != len(results)-1 {
+			return nil, fmt.Errorf("invalid func type %q", name)
+		}
+		results = results[1 : len(results)-1]
+	}
+	for _, result := range splitTopLevel(results, ", ") {
+		t, err := r.Resolve(result)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+
+	return reflect.FuncOf(in, out, variadic), nil
+}
+
+// resolveStruct resolves a struct type named by structName.
+func (r *TypeRegistry) resolveStruct(name string) (reflect.Type, error) {
+	body := strings.TrimSpace(name[len("struct {") : len(name)-1])
+
+	var fields []reflect.StructField
+	for _, field := range splitTopLevel(body, "; ") {
+		fieldName, typ, found := strings.Cut(field, " ")
+		if !found {
+			return nil, fmt.Errorf("invalid struct type %q", name)
+		}
+		t, err := r.Resolve(typ)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, reflect.StructField{Name: fieldName, Type: t})
+	}
+
+	return structOf(fields)
+}
+
+// matchingBracket returns the index of the bracket, or parenthesis or
+// brace, closing the one at open.
+func matchingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitTopLevel splits s around the separators sep that are not nested in
+// brackets, parentheses or braces.
+func splitTopLevel(s, sep string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(s[i:], sep) {
+				parts = append(parts, s[start:i])
+				start = i + len(sep)
+				i += len(sep) - 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,115 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type registered struct{}
+
+type unregistered struct{}
+
+func TestTypeRegistry(t *testing.T) {
+	r := flo.NewTypeRegistry()
+	require.NoError(t, r.Register("test.registered", reflect.TypeFor[registered]()))
+
+	t.Run("Cannot register the same name twice", func(t *testing.T) {
+		require.NoError(t, r.Register("test.registered", reflect.TypeFor[registered]()))
+
+		err := r.Register("test.registered", reflect.TypeFor[unregistered]())
+		require.ErrorContains(t, err, "already registered")
+	})
+
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[int](),
+		reflect.TypeFor[any](),
+		reflect.TypeFor[error](),
+		reflect.TypeFor[context.Context](),
+		reflect.TypeFor[[]byte](),
+		reflect.TypeFor[[4]time.Duration](),
+		reflect.TypeFor[map[string][]*url.URL](),
+		reflect.TypeFor[map[[2]int]registered](),
+		reflect.TypeFor[<-chan time.Time](),
+		reflect.TypeFor[chan<- registered](),
+		reflect.TypeFor[chan flo.Signal](),
+		reflect.TypeFor[func()](),
+		reflect.TypeFor[func(context.Context, ...registered) error](),
+		reflect.TypeFor[func(map[string]int, func(int) bool) (*url.URL, error)](),
+		reflect.TypeFor[struct{}](),
+		reflect.TypeFor[[]struct {
+			First  int
+			Second func(time.Time) (int, error)
+		}](),
+	} {
+		t.Run("Round trip "+typ.String(), func(t *testing.T) {
+			name, err := r.Name(typ)
+			require.NoError(t, err)
+
+			resolved, err := r.Resolve(name)
+			require.NoError(t, err)
+			require.Equal(t, typ, resolved)
+		})
+	}
+
+	t.Run("Unknown types", func(t *testing.T) {
+		_, err := r.Name(reflect.TypeFor[[]unregistered]())
+		require.ErrorContains(t, err, "is not registered")
+
+		_, err = r.Name(reflect.TypeFor[struct {
+			A int `json:"a"`
+		}]())
+		require.ErrorContains(t, err, "is not supported")
+
+		_, found := r.Lookup("map[string]github.com/foo/bar.Baz")
+		require.False(t, found)
+	})
+
+	t.Run("Default registry", func(t *testing.T) {
+		name, err := flo.RegisterType[registered]()
+		require.NoError(t, err)
+		require.Equal(t, "github.com/mgjules/flo_test.registered", name)
+
+		typ, found := flo.DefaultTypeRegistry.Lookup("[]" + name)
+		require.True(t, found)
+		require.Equal(t, reflect.TypeFor[[]registered](), typ)
+	})
+}
+
+type secretToken struct {
+	Value string
+}
+
+func TestTypeAliases(t *testing.T) {
+	f, err := flo.NewFlo("TestAlias", "Test Alias", "Test Alias Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	pToken, err := flo.NewComponentIO("token", flo.ComponentIOTypeIN, reflect.TypeFor[secretToken](), f.ID)
+	require.NoError(t, err)
+	require.ErrorContains(t, f.AddIO(pToken), "type flo_test.secretToken is not exported")
+
+	require.ErrorContains(t, flo.RegisterAlias[secretToken]("example.com/app/auth", "token"), "is not exported")
+	require.NoError(t, flo.RegisterAlias[secretToken]("example.com/app/auth", "Token"))
+	require.NoError(t, flo.RegisterAlias[secretToken]("example.com/app/auth", "Token"))
+	require.ErrorContains(t, flo.RegisterAlias[secretToken]("example.com/app/auth", "Secret"), "already aliased as example.com/app/auth.Token")
+
+	require.NoError(t, f.AddIO(pToken))
+
+	// Headless components can refer to the alias too.
+	check, err := flo.NewHeadlessComponent("Check", "githab.com/testuf/tera", "Check", "Check Description", "func(auth.Token) bool", nil)
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeFor[secretToken](), check.IOs[0].RType)
+	require.NoError(t, f.AddComponent(check))
+	require.NoError(t, f.ConnectComponent(f.ID, pToken.ID, check.ID, check.IOs[0].ID))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+	require.Contains(t, out.String(), `auth "example.com/app/auth"`)
+	require.Contains(t, out.String(), "func TestAlias(token auth.Token) {")
+}
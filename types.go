@@ -0,0 +1,177 @@
+package flo
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// typeCode writes the Go type t.
+func typeCode(t reflect.Type) *jen.Statement {
+	if alias, found := DefaultTypeRegistry.alias(t); found {
+		return qual(alias.pkgPath, alias.name)
+	}
+	if t.Name() != "" {
+		return qual(t.PkgPath(), t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jen.Op("*").Add(typeCode(t.Elem()))
+	case reflect.Slice:
+		if t.Elem() == reflect.TypeFor[byte]() {
+			return jen.Index().Byte()
+		}
+		return jen.Index().Add(typeCode(t.Elem()))
+	case reflect.Array:
+		return jen.Index(jen.Lit(t.Len())).Add(typeCode(t.Elem()))
+	case reflect.Map:
+		return jen.Map(typeCode(t.Key())).Add(typeCode(t.Elem()))
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return jen.Op("<-").Chan().Add(typeCode(t.Elem()))
+		case reflect.SendDir:
+			return jen.Chan().Op("<-").Add(typeCode(t.Elem()))
+		default:
+			return jen.Chan().Add(typeCode(t.Elem()))
+		}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return jen.Any()
+		}
+		return jen.InterfaceFunc(func(g *jen.Group) {
+			for i := range t.NumMethod() {
+				m := t.Method(i)
+				g.Id(m.Name).Add(signatureCode(m.Type))
+			}
+		})
+	case reflect.Func:
+		return jen.Func().Add(signatureCode(t))
+	case reflect.Struct:
+		return jen.StructFunc(func(g *jen.Group) {
+			for i := range t.NumField() {
+				field := t.Field(i)
+				g.Do(func(s *jen.Statement) {
+					if !field.Anonymous {
+						s.Id(field.Name)
+					}
+				}).Add(typeCode(field.Type)).Do(func(s *jen.Statement) {
+					if field.Tag != "" {
+						s.Op("`" + string(field.Tag) + "`")
+					}
+				})
+			}
+		})
+	}
+
+	// Fallback for what we can't write properly yet.
+	return jen.Id(t.String())
+}
+
+// checkWritable makes sure typeCode writes a type identical to t, which is
+// not the case of unexported types, nor of unnamed types with unexported
+// fields or methods, as they belong to the package declaring them. Aliases
+// of DefaultTypeRegistry are written instead, when registered.
+func checkWritable(t reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+	if _, found := DefaultTypeRegistry.alias(t); found {
+		return nil
+	}
+	if t.Name() != "" {
+		if t.PkgPath() != "" && !token.IsExported(t.Name()) {
+			return fmt.Errorf("type %s is not exported", t)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+		return checkWritable(t.Elem())
+	case reflect.Map:
+		if err := checkWritable(t.Key()); err != nil {
+			return err
+		}
+		return checkWritable(t.Elem())
+	case reflect.Func:
+		for i := range t.NumIn() {
+			if err := checkWritable(t.In(i)); err != nil {
+				return err
+			}
+		}
+		for i := range t.NumOut() {
+			if err := checkWritable(t.Out(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				return fmt.Errorf("field %s of %s is not exported", field.Name, t)
+			}
+			if err := checkWritable(field.Type); err != nil {
+				return err
+			}
+		}
+	case reflect.Interface:
+		for i := range t.NumMethod() {
+			m := t.Method(i)
+			if !m.IsExported() {
+				return fmt.Errorf("method %s of %s is not exported", m.Name, t)
+			}
+			if err := checkWritable(m.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// signatureCode writes the params and results of the func type t.
+func signatureCode(t reflect.Type) *jen.Statement {
+	return jen.ParamsFunc(func(g *jen.Group) {
+		for i := range t.NumIn() {
+			if t.IsVariadic() && i == t.NumIn()-1 {
+				g.Op("...").Add(typeCode(t.In(i).Elem()))
+				continue
+			}
+			g.Add(typeCode(t.In(i)))
+		}
+	}).Do(func(s *jen.Statement) {
+		switch t.NumOut() {
+		case 0:
+		case 1:
+			s.Add(typeCode(t.Out(0)))
+		default:
+			s.Parens(jen.ListFunc(func(g *jen.Group) {
+				for i := range t.NumOut() {
+					g.Add(typeCode(t.Out(i)))
+				}
+			}))
+		}
+	})
+}
+
+// zeroCode writes the zero value of the Go type t.
+func zeroCode(t reflect.Type) *jen.Statement {
+	switch t.Kind() {
+	case reflect.Bool:
+		return jen.False()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return jen.Lit(0)
+	case reflect.String:
+		return jen.Lit("")
+	case reflect.Struct, reflect.Array:
+		return typeCode(t).Values()
+	default:
+		return jen.Nil()
+	}
+}
@@ -0,0 +1,71 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"go/format"
+	"reflect"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type retryConfig = struct {
+	Name    string `json:"name"`
+	Retries int
+}
+
+func retryFn(cfg retryConfig, cb func(string, ...int) error, s interface{ Len() int }) error {
+	return cb(cfg.Name, cfg.Retries, s.Len())
+}
+
+func TestInlineTypes(t *testing.T) {
+	f, err := flo.NewFlo("TestInline", "Test Inline", "Test Inline Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	retry, err := flo.NewComponent("Retry", "githab.com/testuf/tera", "Retry", "Retry Description", retryFn)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(retry))
+
+	for i, name := range []string{"cfg", "cb", "s"} {
+		pIn, err := flo.NewComponentIO(name, flo.ComponentIOTypeIN, retry.IOs[i].RType, f.ID)
+		require.NoError(t, err)
+		require.NoError(t, f.AddIO(pIn))
+		require.NoError(t, f.ConnectComponent(f.ID, pIn.ID, retry.ID, retry.IOs[i].ID))
+	}
+
+	rErr, err := flo.NewComponentIO("err", flo.ComponentIOTypeOUT, reflect.TypeFor[error](), f.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.AddIO(rErr))
+	require.NoError(t, f.ConnectComponent(retry.ID, retry.IOs[3].ID, f.ID, rErr.ID))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Render(context.Background(), out))
+
+	formatted, err := format.Source(out.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), out.String())
+	require.Contains(t, out.String(), "func TestInline(cfg struct {\n\tName    string `json:\"name\"`\n\tRetries int\n}, ")
+	require.Contains(t, out.String(), ", cb func(string, ...int) error, ")
+	require.Contains(t, out.String(), ", s interface {\n\tLen() int\n}) error {")
+
+	t.Run("Headless", func(t *testing.T) {
+		c, err := flo.NewHeadlessComponent(
+			"Retry", "githab.com/testuf/tera", "Retry", "",
+			"func(struct{ Name string `json:\"name\"`; Retries int }, func(string, ...int) error, any) error", nil,
+		)
+		require.NoError(t, err)
+		require.Equal(t, retry.IOs[0].RType, c.IOs[0].RType)
+		require.Equal(t, retry.IOs[1].RType, c.IOs[1].RType)
+
+		_, err = flo.NewHeadlessComponent("Bad", "githab.com/testuf/tera", "Bad", "", "func(struct{ name string })", nil)
+		require.ErrorContains(t, err, `field "name" is not exported`)
+	})
+
+	t.Run("Unexported fields", func(t *testing.T) {
+		pIn, err := flo.NewComponentIO("bad", flo.ComponentIOTypeIN, reflect.TypeFor[struct{ name string }](), f.ID)
+		require.NoError(t, err)
+		require.ErrorContains(t, f.AddIO(pIn), "field name of struct { name string } is not exported")
+	})
+}
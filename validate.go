@@ -0,0 +1,207 @@
+package flo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError is a problem found in a flo by Validate.
+type ValidationError struct {
+	ComponentID uuid.UUID // Nil when about the flo itself.
+	IOID        uuid.UUID // Nil when about the whole component.
+	Message     string
+}
+
+func (e ValidationError) Error() string {
+	switch {
+	case e.ComponentID == uuid.Nil:
+		return e.Message
+	case e.IOID == uuid.Nil:
+		return fmt.Sprintf("component id %q: %s", e.ComponentID, e.Message)
+	default:
+		return fmt.Sprintf("component id %q io id %q: %s", e.ComponentID, e.IOID, e.Message)
+	}
+}
+
+// Validate checks the whole flo: connections to missing components or ios,
+// values of the wrong type, cycles, unset inputs, resource ownership, multiplicity,
+// sub-flos and policies, and returns every problem found at once instead of failing on
+// the first one while rendering.
+func (f *Flo) Validate(ctx context.Context) []ValidationError {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.orderComponents()()
+
+	var errs []ValidationError
+	errs = append(errs, f.validateConnections()...)
+	errs = append(errs, f.validateCycles()...)
+	errs = append(errs, f.validateInputs()...)
+	errs = append(errs, f.validateOwnership()...)
+	errs = append(errs, f.validateMultiplicity()...)
+	errs = append(errs, f.validateSubFlos()...)
+	errs = append(errs, f.validatePolicies(ctx)...)
+
+	return errs
+}
+
+// validateConnections reports the connections to missing components or ios
+// and those carrying values of the wrong type.
+func (f *Flo) validateConnections() []ValidationError {
+	var errs []ValidationError
+
+	check := func(owner uuid.UUID, io *ComponentIO, conn *ComponentConnection) {
+		if _, found := f.connectionIndex[conn.ID]; !found {
+			errs = append(errs, ValidationError{
+				ComponentID: owner,
+				IOID:        io.ID,
+				Message:     fmt.Sprintf("connection id %q is unknown to the flo", conn.ID),
+			})
+		}
+
+		out, outFound := f.componentIO(conn.OutComponentID, conn.OutComponentIOID)
+		in, inFound := f.componentIO(conn.InComponentID, conn.InComponentIOID)
+		switch {
+		case !outFound:
+			errs = append(errs, ValidationError{
+				ComponentID: owner,
+				IOID:        io.ID,
+				Message:     fmt.Sprintf("connection id %q comes from missing io id %q of %q", conn.ID, conn.OutComponentIOID, conn.OutComponentID),
+			})
+		case !inFound:
+			errs = append(errs, ValidationError{
+				ComponentID: owner,
+				IOID:        io.ID,
+				Message:     fmt.Sprintf("connection id %q goes to missing io id %q of %q", conn.ID, conn.InComponentIOID, conn.InComponentID),
+			})
+		case io.Type == ComponentIOTypeIN && owner != f.ID, io.Type == ComponentIOTypeOUT && owner == f.ID:
+			// Checked once, from the out side.
+		case conn.Transform != nil:
+			if err := conn.Transform.check(out, in); err != nil {
+				errs = append(errs, ValidationError{
+					ComponentID: conn.InComponentID,
+					IOID:        in.ID,
+					Message:     fmt.Sprintf("connection id %q: %v", conn.ID, err),
+				})
+			}
+		case !in.IsSignal && !out.RType.AssignableTo(in.acceptedType()):
+			errs = append(errs, ValidationError{
+				ComponentID: conn.InComponentID,
+				IOID:        in.ID,
+				Message:     fmt.Sprintf("connection id %q: %v", conn.ID, assignError(out.RType, in.acceptedType())),
+			})
+		}
+	}
+
+	for _, io := range f.IOs {
+		for _, conn := range io.Connections {
+			check(f.ID, io, conn)
+		}
+	}
+	for _, c := range f.orderedComponents() {
+		for _, io := range c.IOs {
+			for _, conn := range io.Connections {
+				check(c.ID, io, conn)
+			}
+		}
+	}
+	for _, conn := range f.Sequences {
+		for _, id := range []uuid.UUID{conn.OutComponentID, conn.InComponentID} {
+			if _, found := f.Components[id]; !found {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("sequence connection id %q references missing component id %q", conn.ID, id),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateCycles reports the components depending on themselves through
+// other components, whether through data or sequence connections.
+func (f *Flo) validateCycles() []ValidationError {
+	next := make(map[uuid.UUID][]uuid.UUID, len(f.Components))
+	for _, c := range f.orderedComponents() {
+		_, outs := c.IOs.SeparateINsOUTs()
+		for _, out := range outs {
+			for _, conn := range out.Connections {
+				if _, found := f.Components[conn.InComponentID]; found {
+					next[c.ID] = append(next[c.ID], conn.InComponentID)
+				}
+			}
+		}
+	}
+	for _, conn := range f.Sequences {
+		next[conn.OutComponentID] = append(next[conn.OutComponentID], conn.InComponentID)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[uuid.UUID]int, len(f.Components))
+	var (
+		errs []ValidationError
+		path []uuid.UUID
+	)
+	var visit func(id uuid.UUID)
+	visit = func(id uuid.UUID) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, n := range next[id] {
+			switch state[n] {
+			case unvisited:
+				visit(n)
+			case visiting:
+				cycle := path[slices.Index(path, n):]
+				names := make([]string, 0, len(cycle)+1)
+				for _, id := range append(cycle, n) {
+					names = append(names, strconv.Quote(id.String()))
+				}
+				errs = append(errs, ValidationError{
+					ComponentID: n,
+					Message:     "cycle through components " + strings.Join(names, " -> "),
+				})
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+	}
+	for _, c := range f.orderedComponents() {
+		if state[c.ID] == unvisited {
+			visit(c.ID)
+		}
+	}
+
+	return errs
+}
+
+// validateInputs reports the in ios of components fed neither by a
+// connection nor by a literal.
+func (f *Flo) validateInputs() []ValidationError {
+	var errs []ValidationError
+
+	for _, c := range f.orderedComponents() {
+		ins, _ := c.IOs.SeparateINsOUTs()
+		for _, in := range ins {
+			// Signals carry no value, multi ios can be empty and contexts
+			// default to the one of the flo.
+			if in.IsSignal || in.Multi || in.Literal.IsValid() || len(in.Connections) > 0 || isImplicitContext(in) {
+				continue
+			}
+			errs = append(errs, ValidationError{
+				ComponentID: c.ID,
+				IOID:        in.ID,
+				Message:     fmt.Sprintf("%s input is neither connected nor set", in.RType),
+			})
+		}
+	}
+
+	return errs
+}
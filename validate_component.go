@@ -0,0 +1,44 @@
+package flo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mgjules/flo/flovalidate"
+)
+
+var flovalidatePkg = reflect.TypeFor[flovalidate.TagValidator]().PkgPath()
+
+// NewValidateComponent creates a component validating a struct of type T
+// using flovalidate. It emits the validated struct alongside an error.
+func NewValidateComponent[T any](label, description string) (*Component, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %s is not a struct", t)
+	}
+
+	return NewComponent(
+		"Struct",
+		flovalidatePkg,
+		label,
+		description,
+		flovalidate.Struct[T],
+	)
+}
+
+// validateFunc rebuilds the function backing a validate component for
+// values of type t.
+func validateFunc(t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{t}, []reflect.Type{t, errorRType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			if err := flovalidate.Default.Struct(args[0].Interface()); err != nil {
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+			return []reflect.Value{args[0], errorValue(nil)}
+		},
+	)
+}
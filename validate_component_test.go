@@ -0,0 +1,24 @@
+package flo_test
+
+import (
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	Name string `validate:"required"`
+}
+
+func TestNewValidateComponent(t *testing.T) {
+	_, err := flo.NewValidateComponent[int]("Validate", "Validate int")
+	require.ErrorContains(t, err, "not a struct")
+
+	c, err := flo.NewValidateComponent[*request]("Validate", "Validate request")
+	require.NoError(t, err)
+	require.Equal(t, "Struct", c.Name)
+	require.Equal(t, "github.com/mgjules/flo/flovalidate", c.PkgPath)
+	require.Len(t, c.IOs, 3)
+	require.True(t, c.IOs[2].IsError)
+}
@@ -0,0 +1,97 @@
+package flo_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid", func(t *testing.T) {
+		require.Empty(t, newTestFlo(t).Validate(ctx))
+	})
+
+	t.Run("Unset inputs", func(t *testing.T) {
+		f := newTestFlo(t)
+		comp, err := flo.NewComponent("CompA", "githab.com/testuf/tera", "Test Comp A", "Test Comp A", testFuncs["githab.com/testuf/tera.CompA"])
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(comp))
+
+		// The context defaults to the one of the flo.
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 1)
+		require.Equal(t, comp.ID, errs[0].ComponentID)
+		require.Equal(t, comp.IOs[1].ID, errs[0].IOID)
+		require.ErrorContains(t, errs[0], "int input is neither connected nor set")
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		f := newTestFlo(t)
+		compC := componentNamed(f, "CompC")
+		compC.IOs[1].RType = reflect.TypeFor[string]()
+
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 1)
+		require.Equal(t, compC.ID, errs[0].ComponentID)
+		require.Equal(t, compC.IOs[1].ID, errs[0].IOID)
+		require.ErrorContains(t, errs[0], "int cannot be assigned to string")
+	})
+
+	t.Run("Ghost connection", func(t *testing.T) {
+		f := newTestFlo(t)
+		result := f.IOs[3]
+		result.Connections = append(result.Connections, &flo.ComponentConnection{
+			ID:               uuid.New(),
+			OutComponentID:   uuid.New(),
+			OutComponentIOID: uuid.New(),
+			InComponentID:    f.ID,
+			InComponentIOID:  result.ID,
+		})
+
+		errs := f.Validate(ctx)
+		require.Len(t, errs, 2)
+		require.ErrorContains(t, errs[0], "is unknown to the flo")
+		require.ErrorContains(t, errs[1], "comes from missing io id")
+		require.Equal(t, result.ID, errs[1].IOID)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		f := newTestFlo(t)
+		compA, compC := componentNamed(f, "CompA"), componentNamed(f, "CompC")
+		conn := &flo.ComponentConnection{
+			ID:               uuid.New(),
+			OutComponentID:   compC.ID,
+			OutComponentIOID: compC.IOs[3].ID,
+			InComponentID:    compA.ID,
+			InComponentIOID:  compA.IOs[1].ID,
+		}
+		compC.IOs[3].Connections = append(compC.IOs[3].Connections, conn)
+		compA.IOs[1].Connections = append(compA.IOs[1].Connections, conn)
+
+		var cycles []flo.ValidationError
+		for _, err := range f.Validate(ctx) {
+			if err.ComponentID == compA.ID && err.IOID == uuid.Nil {
+				cycles = append(cycles, err)
+			}
+		}
+		require.Len(t, cycles, 1)
+		require.ErrorContains(t, cycles[0], "cycle through components")
+		require.ErrorContains(t, cycles[0], compC.ID.String())
+	})
+}
+
+func componentNamed(f *flo.Flo, name string) *flo.Component {
+	for _, c := range f.Components {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package flo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/build/constraint"
+	"regexp"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// variantRe matches the variant names usable as build tags.
+var variantRe = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// WithVariant renders only the components of the variant, e.g. "cloud" or
+// "onprem", and the untagged ones. A component of the variant can't
+// depend on the results of a left out component, while flo outs fed by
+// left out components return zero values.
+func WithVariant(name string) RenderOption {
+	return func(o *renderOptions) {
+		o.variant = name
+	}
+}
+
+// WithBuildConstraint adds the //go:build constraint expr, e.g.
+// "cloud && !race", to the header of the generated code.
+func WithBuildConstraint(expr string) RenderOption {
+	return func(o *renderOptions) {
+		o.buildConstraint = expr
+	}
+}
+
+// SetVariants restricts the component id to the variants, or lets it
+// belong to all the variants when empty.
+func (f *Flo) SetVariants(id uuid.UUID, variants ...string) error {
+	for _, v := range variants {
+		if !variantRe.MatchString(v) {
+			return fmt.Errorf("invalid variant %q", v)
+		}
+	}
+	variants = lo.Uniq(variants)
+	slices.Sort(variants)
+
+	return f.setComponent(id, func(c *Component) {
+		c.Variants = variants
+	})
+}
+
+// Variants returns the sorted variants the components of the flo belong to.
+func (f *Flo) Variants() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.variants()
+}
+
+func (f *Flo) variants() []string {
+	var variants []string
+	for _, c := range f.Components {
+		variants = append(variants, c.Variants...)
+	}
+	variants = lo.Uniq(variants)
+	slices.Sort(variants)
+
+	return variants
+}
+
+// InVariant reports whether c belongs to the variant.
+func (c *Component) InVariant(variant string) bool {
+	return len(c.Variants) == 0 || slices.Contains(c.Variants, variant)
+}
+
+// RenderVariants renders each variant of the flo to "<name>_<variant>.go"
+// in fsys, guarded by the variant build tag so that a build picks one of
+// them, e.g. with -tags cloud.
+//
+// <name> is the snake cased name of the flo.
+func (f *Flo) RenderVariants(
+	ctx context.Context,
+	fsys WriteFS,
+	opts ...RenderOption,
+) error {
+	if fsys == nil {
+		return errors.New("missing file system")
+	}
+
+	variants := f.Variants()
+	if len(variants) == 0 {
+		return errors.New("flo has no variants")
+	}
+
+	base := lo.SnakeCase(f.Name)
+	buf := &bytes.Buffer{}
+	for _, v := range variants {
+		buf.Reset()
+		vopts := append(slices.Clip(opts), WithVariant(v), WithBuildConstraint(v))
+		if err := f.Render(ctx, buf, vopts...); err != nil {
+			return fmt.Errorf("variant %q: %v", v, err)
+		}
+
+		name := base + "_" + v + ".go"
+		if err := fsys.WriteFile(name, buf.Bytes()); err != nil {
+			return fmt.Errorf("cannot write %q: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// project copies the flo without the components left out of the variant.
+func (f *Flo) project(variant string) (*Flo, error) {
+	if !variantRe.MatchString(variant) {
+		return nil, fmt.Errorf("invalid variant %q", variant)
+	}
+	if !slices.Contains(f.variants(), variant) {
+		return nil, fmt.Errorf("unknown variant %q", variant)
+	}
+
+	p := f.clone(false)
+	for _, c := range p.orderedComponents() {
+		if c.InVariant(variant) {
+			continue
+		}
+
+		for _, io := range c.IOs {
+			for _, conn := range slices.Clone(io.Connections) {
+				if io.Type == ComponentIOTypeOUT && conn.InComponentID != p.ID {
+					if in := p.Components[conn.InComponentID]; in.InVariant(variant) {
+						return nil, fmt.Errorf(
+							"component id %q of variant %q depends on left out component id %q",
+							in.ID, variant, c.ID,
+						)
+					}
+				}
+				if err := p.deleteConnection(conn.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, conn := range slices.Clone(p.Sequences) {
+			if conn.OutComponentID == c.ID || conn.InComponentID == c.ID {
+				if err := p.deleteConnection(conn.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		delete(p.Components, c.ID)
+		p.componentOrder = lo.Without(p.componentOrder, c.ID)
+	}
+
+	return p, nil
+}
+
+// buildConstraintCode checks the build constraint expr.
+func buildConstraintCode(expr string) (string, error) {
+	line := "//go:build " + expr
+	if _, err := constraint.Parse(line); err != nil {
+		return "", fmt.Errorf("invalid build constraint %q: %v", expr, err)
+	}
+
+	return line, nil
+}
@@ -0,0 +1,82 @@
+package flo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariants(t *testing.T) {
+	f, err := flo.NewFlo("Normalize", "Normalize", "Normalize Description", "flo", "Test Package")
+	require.NoError(t, err)
+
+	in, out, title := flo.In[string]("s"), flo.Out[string]("res"), flo.Out[string]("title")
+	for _, io := range []*flo.ComponentIO{in, out, title} {
+		require.NoError(t, f.AddIO(io))
+	}
+
+	newComponent := func(name string, fn any) *flo.Component {
+		c, err := flo.NewComponent(name, "strings", name, name+" Description", fn)
+		require.NoError(t, err)
+		require.NoError(t, f.AddComponent(c))
+		return c
+	}
+	trim, upper, lower, toTitle := newComponent("TrimSpace", strings.TrimSpace), newComponent("ToUpper", strings.ToUpper),
+		newComponent("ToLower", strings.ToLower), newComponent("ToTitle", strings.ToTitle)
+
+	require.NoError(t, f.ConnectComponent(f.ID, in.ID, trim.ID, trim.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, upper.ID, upper.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(upper.ID, upper.IOs[1].ID, f.ID, out.ID))
+	require.NoError(t, f.ConnectComponent(trim.ID, trim.IOs[1].ID, lower.ID, lower.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(lower.ID, lower.IOs[1].ID, toTitle.ID, toTitle.IOs[0].ID))
+	require.NoError(t, f.ConnectComponent(toTitle.ID, toTitle.IOs[1].ID, f.ID, title.ID))
+
+	require.ErrorContains(t, f.SetVariants(upper.ID, "on prem"), `invalid variant "on prem"`)
+	require.NoError(t, f.SetVariants(upper.ID, "cloud"))
+	require.NoError(t, f.SetVariants(lower.ID, "onprem", "onprem"))
+	require.NoError(t, f.SetVariants(toTitle.ID, "onprem"))
+	require.Equal(t, []string{"cloud", "onprem"}, f.Variants())
+
+	t.Run("Render", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, f.Render(context.Background(), buf, flo.WithVariant("cloud"), flo.WithBuildConstraint("cloud")))
+		code := buf.String()
+		require.True(t, strings.HasPrefix(code, "// Code generated by flo. Do not edit!\n//go:build cloud\n\n"))
+		require.Contains(t, code, "strings.TrimSpace(s)")
+		require.Contains(t, code, "strings.ToUpper(")
+		require.NotContains(t, code, "strings.ToLower(")
+		require.NotContains(t, code, "strings.ToTitle(")
+		require.Contains(t, code, `, ""`)
+
+		// Rendering a variant leaves the flo untouched.
+		buf.Reset()
+		require.NoError(t, f.Render(context.Background(), buf))
+		require.Contains(t, buf.String(), "strings.ToLower(")
+		require.NoError(t, f.Invariants())
+	})
+
+	t.Run("RenderVariants", func(t *testing.T) {
+		fsys := memFS{}
+		require.NoError(t, f.RenderVariants(context.Background(), fsys))
+		require.Len(t, fsys, 2)
+		require.Contains(t, fsys["normalize_cloud.go"], "//go:build cloud\n")
+		require.Contains(t, fsys["normalize_onprem.go"], "//go:build onprem\n")
+		require.Contains(t, fsys["normalize_onprem.go"], "strings.ToTitle(")
+		require.NotContains(t, fsys["normalize_onprem.go"], "strings.ToUpper(")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		err := f.Render(context.Background(), &bytes.Buffer{}, flo.WithVariant("edge"))
+		require.ErrorContains(t, err, `unknown variant "edge"`)
+		err = f.Render(context.Background(), &bytes.Buffer{}, flo.WithBuildConstraint("cloud &&"))
+		require.ErrorContains(t, err, `invalid build constraint "cloud &&"`)
+
+		require.NoError(t, f.SetVariants(toTitle.ID))
+		err = f.Render(context.Background(), &bytes.Buffer{}, flo.WithVariant("cloud"))
+		require.ErrorContains(t, err, "depends on left out component id")
+	})
+}
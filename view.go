@@ -0,0 +1,253 @@
+package flo
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FloView is a read-only snapshot of a flo for UIs and serializers. It
+// shares nothing with the flo so that it can be read while the flo
+// changes, unlike the exported fields of the flo.
+type FloView struct {
+	ID             uuid.UUID
+	Name           string
+	Label          string
+	Description    string
+	PkgName        string
+	PkgDescription string
+	Owner          string
+	Team           string
+	IOs            []IOView
+	Components     []ComponentView  // In the order they were added.
+	Connections    []ConnectionView // Sorted by id.
+}
+
+// ComponentView is a read-only snapshot of a component.
+type ComponentView struct {
+	ID           uuid.UUID
+	Name         string
+	PkgPath      string
+	Label        string
+	Description  string
+	Kind         ComponentKind
+	Bound        bool // Backed by a function.
+	IOs          []IOView
+	Branches     int
+	Message      string
+	ErrorPolicy  ErrorPolicy
+	Cache        *CacheAside
+	TypeArgs     []reflect.Type
+	Effects      Effects
+	AllowReorder bool
+	Position     Position
+	Variants     []string
+	Flag         *FlagGate
+	Owner        string
+	Team         string
+	Cost         Cost
+	Receiver     *Receiver
+	Sub          *FloView // Set for sub-flos.
+}
+
+// IOView is a read-only snapshot of an io.
+type IOView struct {
+	ID             uuid.UUID
+	ParentID       uuid.UUID
+	Name           string
+	Type           ComponentIOType
+	RType          reflect.Type
+	IsError        bool
+	IsSignal       bool
+	Literal        any // nil unless set.
+	Owns           bool
+	DeferRelease   bool
+	Multi          bool
+	MaxConnections int
+	Position       Position
+	Label          string
+	Description    string
+	ResultName     string
+	Connections    []uuid.UUID
+}
+
+// ConnectionView is a read-only snapshot of a connection.
+type ConnectionView struct {
+	ID               uuid.UUID
+	Kind             ComponentConnectionKind
+	OutComponentID   uuid.UUID
+	OutComponentIOID uuid.UUID
+	InComponentID    uuid.UUID
+	InComponentIOID  uuid.UUID
+	Transform        string // The expression or the function, empty when none.
+	Order            int
+	Fallback         any // nil unless set.
+}
+
+// View returns a snapshot of the flo.
+func (f *Flo) View() FloView {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.view()
+}
+
+func (f *Flo) view() FloView {
+	v := FloView{
+		ID:             f.ID,
+		Name:           f.Name,
+		Label:          f.Label,
+		Description:    f.Description,
+		PkgName:        f.PkgName,
+		PkgDescription: f.PkgDescription,
+		Owner:          f.Owner,
+		Team:           f.Team,
+		IOs:            viewIOs(f.IOs),
+		Components:     make([]ComponentView, 0, len(f.Components)),
+		Connections:    make([]ConnectionView, 0, len(f.connectionIndex)),
+	}
+	for _, c := range f.orderedComponents() {
+		v.Components = append(v.Components, c.view())
+	}
+	for _, conn := range f.connectionIndex {
+		v.Connections = append(v.Connections, conn.view())
+	}
+	slices.SortFunc(v.Connections, func(a, b ConnectionView) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	return v
+}
+
+func (c *Component) view() ComponentView {
+	v := ComponentView{
+		ID:           c.ID,
+		Name:         c.Name,
+		PkgPath:      c.PkgPath,
+		Label:        c.Label,
+		Description:  c.Description,
+		Kind:         c.Kind,
+		Bound:        c.IsBound(),
+		IOs:          viewIOs(c.IOs),
+		Branches:     c.Branches,
+		Message:      c.Message,
+		ErrorPolicy:  c.ErrorPolicy,
+		TypeArgs:     slices.Clone(c.TypeArgs),
+		Effects:      c.Effects,
+		AllowReorder: c.AllowReorder,
+		Position:     c.Position,
+		Variants:     slices.Clone(c.Variants),
+		Owner:        c.Owner,
+		Team:         c.Team,
+		Cost:         c.Cost,
+	}
+	if c.Cache != nil {
+		aside := *c.Cache
+		v.Cache = &aside
+	}
+	if c.Receiver != nil {
+		r := *c.Receiver
+		v.Receiver = &r
+	}
+	if c.Sub != nil {
+		sub := c.Sub.view()
+		v.Sub = &sub
+	}
+	if c.Flag != nil {
+		gate := *c.Flag
+		v.Flag = &gate
+	}
+
+	return v
+}
+
+func viewIOs(ios IOs) []IOView {
+	views := make([]IOView, 0, len(ios))
+	for _, io := range ios {
+		v := IOView{
+			ID:             io.ID,
+			ParentID:       io.ParentID,
+			Name:           io.Name,
+			Type:           io.Type,
+			RType:          io.RType,
+			IsError:        io.IsError,
+			IsSignal:       io.IsSignal,
+			Literal:        viewValue(io.Literal),
+			Owns:           io.Owns,
+			DeferRelease:   io.DeferRelease,
+			Multi:          io.Multi,
+			MaxConnections: io.MaxConnections,
+			Position:       io.Position,
+			Label:          io.Label,
+			Description:    io.Description,
+			ResultName:     io.ResultName,
+			Connections:    make([]uuid.UUID, 0, len(io.Connections)),
+		}
+		for _, conn := range io.Connections {
+			v.Connections = append(v.Connections, conn.ID)
+		}
+		views = append(views, v)
+	}
+
+	return views
+}
+
+func (conn *ComponentConnection) view() ConnectionView {
+	v := ConnectionView{
+		ID:               conn.ID,
+		Kind:             conn.Kind,
+		OutComponentID:   conn.OutComponentID,
+		OutComponentIOID: conn.OutComponentIOID,
+		InComponentID:    conn.InComponentID,
+		InComponentIOID:  conn.InComponentIOID,
+		Order:            conn.Order,
+		Fallback:         viewValue(conn.Fallback),
+	}
+	switch t := conn.Transform; {
+	case t == nil:
+	case t.Expr != "":
+		v.Transform = t.Expr
+	default:
+		v.Transform = t.PkgPath + "." + t.Name
+	}
+
+	return v
+}
+
+// viewValue deep copies v, a literal, so that views share nothing with the
+// flo.
+func viewValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	return copyValue(v).Interface()
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+	default:
+		return v
+	}
+}
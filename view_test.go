@@ -0,0 +1,53 @@
+package flo_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mgjules/flo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView(t *testing.T) {
+	f, err := flo.NewFlo("Join", "Join", "Join the words", "flo", "Test Package")
+	require.NoError(t, err)
+
+	sep, joined := flo.In[string]("sep"), flo.Out[string]("joined")
+	require.NoError(t, f.AddIO(sep))
+	require.NoError(t, f.AddIO(joined))
+
+	join, err := flo.NewComponent("Join", "strings", "Join", "Join the words", strings.Join)
+	require.NoError(t, err)
+	require.NoError(t, f.AddComponent(join))
+	require.NoError(t, f.SetLiteral(join.ID, join.IOs[0].ID, []string{"a", "b"}))
+	require.NoError(t, f.ConnectComponent(f.ID, sep.ID, join.ID, join.IOs[1].ID))
+	require.NoError(t, f.ConnectComponent(join.ID, join.IOs[2].ID, f.ID, joined.ID))
+
+	v := f.View()
+	require.Equal(t, f.ID, v.ID)
+	require.Equal(t, "Join", v.Name)
+	require.Len(t, v.IOs, 2)
+	require.Equal(t, reflect.TypeFor[string](), v.IOs[0].RType)
+	require.Len(t, v.Connections, 2)
+
+	require.Len(t, v.Components, 1)
+	c := v.Components[0]
+	require.Equal(t, join.ID, c.ID)
+	require.True(t, c.Bound)
+	require.Len(t, c.IOs, 3)
+	require.Equal(t, []string{"a", "b"}, c.IOs[0].Literal)
+	require.Nil(t, c.IOs[1].Literal)
+	require.Len(t, c.IOs[1].Connections, 1)
+	require.Contains(t, []uuid.UUID{v.Connections[0].ID, v.Connections[1].ID}, c.IOs[1].Connections[0])
+
+	t.Run("Shares nothing", func(t *testing.T) {
+		c.IOs[0].Literal.([]string)[0] = "z"
+		require.Equal(t, []string{"a", "b"}, join.IOs[0].Literal.Interface())
+
+		require.NoError(t, f.DeleteConnection(v.Connections[0].ID))
+		require.Len(t, v.Connections, 2)
+		require.Len(t, f.View().Connections, 1)
+	})
+}